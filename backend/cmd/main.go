@@ -1,7 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	_ "github.com/kulikovroman08/reviewlink-backend/docs"
 
@@ -9,6 +16,9 @@ import (
 	"github.com/kulikovroman08/reviewlink-backend/internal/app"
 )
 
+// shutdownTimeout — сколько ждать завершения текущих запросов после сигнала остановки
+const shutdownTimeout = 10 * time.Second
+
 // @title           Reviewlink API
 // @version         1.0
 // @description     API для пользователей, мест, отзывов и токенов
@@ -20,11 +30,37 @@ import (
 func main() {
 	cfg := configs.LoadConfig()
 
-	reviewLinkApp := app.InitApp(&cfg)
+	// Отменяется по SIGINT/SIGTERM: останавливает фоновые воркеры и HTTP-сервер
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	reviewLinkApp := app.InitApp(ctx, &cfg)
+
+	server := &http.Server{
+		Addr:    ":" + cfg.HTTPPort,
+		Handler: reviewLinkApp,
+	}
+
+	// ListenAndServe возвращается сразу после вызова Shutdown, поэтому main ждёт завершения запросов здесь
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("failed to shutdown server: %v", err)
+		}
+	}()
 
 	log.Println("Server running on :" + cfg.HTTPPort)
 
-	if err := reviewLinkApp.Run(":" + cfg.HTTPPort); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("failed to run server: %v", err)
 	}
+
+	<-stopped
+	log.Println("Server stopped")
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,7 +16,61 @@ type Config struct {
 	TokensAutoCount  int
 	TokensThreshold  int
 	TokensBatchSize  int
+	TokenTTL         time.Duration
 	BonusRequiredPts int
+	AccessTokenTTL   time.Duration
+	RefreshTokenTTL  time.Duration
+	PublicBaseURL    string
+	QRRecoveryLevel  string
+	LabelPageSize    string
+	LabelColumns     int
+	LabelRows        int
+	TokenAlphabet    string
+	TokenLength      int
+	TokenCheckChar   bool
+	KioskCodeStep    time.Duration
+	KioskCodeSkew    int
+
+	TokenRefillInterval    time.Duration
+	TokenRefillBackoff     time.Duration
+	TokenRefillMaxAttempts int
+
+	TokenJobPollInterval time.Duration
+	TokenJobChunkSize    int
+
+	POSSignatureTolerance time.Duration
+
+	ScanDedupWindow time.Duration
+
+	TrustedProxies []string
+
+	PINLength             int
+	PINAttemptWindow      time.Duration
+	PINMaxAttemptsPerUser int
+	PINMaxAttemptsPerIP   int
+
+	ReviewModerationMode string
+	ContentFilterRules   string
+
+	PhotoStorage       string
+	MediaDir           string
+	MaxReviewPhotos    int
+	PhotoMaxSize       int64
+	PhotoMaxDimension  int
+	PhotoThumbnailSize int
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PublicURL string
+
+	ReportAutoHideThreshold int
+	ReportRestrictionPeriod time.Duration
+
+	ReviewUndoDeleteWindow time.Duration
+	PhotoPurgeInterval     time.Duration
 }
 
 func LoadConfig() Config {
@@ -34,7 +90,61 @@ func LoadConfig() Config {
 		TokensAutoCount:  getEnvInt("TOKENS_AUTO_COUNT", 10),
 		TokensThreshold:  getEnvInt("TOKENS_THRESHOLD", 5),
 		TokensBatchSize:  getEnvInt("TOKENS_BATCH_SIZE", 10),
+		TokenTTL:         getEnvDuration("TOKEN_TTL", 72*time.Hour),
 		BonusRequiredPts: getEnvInt("BONUS_REQUIRED_POINTS", 50),
+		AccessTokenTTL:   getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:  getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		PublicBaseURL:    getEnv("PUBLIC_BASE_URL", "http://localhost:8080"),
+		QRRecoveryLevel:  getEnv("QR_RECOVERY_LEVEL", "medium"),
+		LabelPageSize:    getEnv("LABEL_PAGE_SIZE", "A4"),
+		LabelColumns:     getEnvInt("LABEL_COLUMNS", 3),
+		LabelRows:        getEnvInt("LABEL_ROWS", 7),
+		TokenAlphabet:    getEnv("TOKEN_ALPHABET", "ABCDEFGHJKMNPQRSTUVWXYZ23456789"),
+		TokenLength:      getEnvInt("TOKEN_LENGTH", 10),
+		TokenCheckChar:   getEnvBool("TOKEN_CHECK_CHAR", false),
+		KioskCodeStep:    getEnvDuration("KIOSK_CODE_STEP", time.Minute),
+		KioskCodeSkew:    getEnvInt("KIOSK_CODE_SKEW", 2),
+
+		TokenRefillInterval:    getEnvDuration("TOKEN_REFILL_INTERVAL", 5*time.Second),
+		TokenRefillBackoff:     getEnvDuration("TOKEN_REFILL_BACKOFF", 10*time.Second),
+		TokenRefillMaxAttempts: getEnvInt("TOKEN_REFILL_MAX_ATTEMPTS", 5),
+
+		TokenJobPollInterval: getEnvDuration("TOKEN_JOB_POLL_INTERVAL", 2*time.Second),
+		TokenJobChunkSize:    getEnvInt("TOKEN_JOB_CHUNK_SIZE", 1000),
+
+		POSSignatureTolerance: getEnvDuration("POS_SIGNATURE_TOLERANCE", 5*time.Minute),
+
+		ScanDedupWindow: getEnvDuration("SCAN_DEDUP_WINDOW", 30*time.Minute),
+
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		PINLength:             getEnvInt("PIN_LENGTH", 6),
+		PINAttemptWindow:      getEnvDuration("PIN_ATTEMPT_WINDOW", 15*time.Minute),
+		PINMaxAttemptsPerUser: getEnvInt("PIN_MAX_ATTEMPTS_PER_USER", 5),
+		PINMaxAttemptsPerIP:   getEnvInt("PIN_MAX_ATTEMPTS_PER_IP", 20),
+
+		ReviewModerationMode: getEnv("REVIEW_MODERATION_MODE", "post"),
+		ContentFilterRules:   getEnv("CONTENT_FILTER_RULES", ""),
+
+		PhotoStorage:       getEnv("PHOTO_STORAGE", "local"),
+		MediaDir:           getEnv("MEDIA_DIR", "./media"),
+		MaxReviewPhotos:    getEnvInt("MAX_REVIEW_PHOTOS", 5),
+		PhotoMaxSize:       int64(getEnvInt("PHOTO_MAX_SIZE", 5<<20)),
+		PhotoMaxDimension:  getEnvInt("PHOTO_MAX_DIMENSION", 2048),
+		PhotoThumbnailSize: getEnvInt("PHOTO_THUMBNAIL_SIZE", 320),
+
+		S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+		S3Region:    getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:    getEnv("S3_BUCKET", ""),
+		S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+		S3PublicURL: getEnv("S3_PUBLIC_URL", ""),
+
+		ReportAutoHideThreshold: getEnvInt("REPORT_AUTO_HIDE_THRESHOLD", 3),
+		ReportRestrictionPeriod: getEnvDuration("REPORT_RESTRICTION_PERIOD", 30*24*time.Hour),
+
+		ReviewUndoDeleteWindow: getEnvDuration("REVIEW_UNDO_DELETE_WINDOW", 24*time.Hour),
+		PhotoPurgeInterval:     getEnvDuration("PHOTO_PURGE_INTERVAL", time.Hour),
 	}
 
 	fmt.Println("APP_ENV:", os.Getenv("APP_ENV"))
@@ -54,3 +164,39 @@ func getEnvInt(key string, def int) int {
 	}
 	return def
 }
+
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			return d
+		}
+	}
+	return def
+}
+
+func getEnv(key, def string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return def
+}
+
+// getEnvList читает список через запятую; пустые элементы отбрасываются
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvBool(key string, def bool) bool {
+	if val := os.Getenv(key); val != "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return def
+}
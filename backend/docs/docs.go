@@ -15,26 +15,36 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/stats": {
+        "/admin/places/{id}/moderation": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Возвращает агрегированные данные: количество пользователей, отзывов, средний рейтинг и количество бонусов.",
+                "description": "mode — режим заведения (null — берётся из конфига), effective — действующий режим. Требуется право **reviews:moderate**.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admins"
                 ],
-                "summary": "Получение общей статистики (только для админов)",
+                "summary": "Режим модерации заведения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.AdminStatsResponse"
+                            "$ref": "#/definitions/dto.ModerationSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid place id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "403": {
@@ -43,23 +53,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "failed to load stats",
+                        "description": "failed to moderate review",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/admin/tokens": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Эндпоинт доступен только пользователям с ролью **admin**.",
+                ]
+            },
+            "put": {
+                "description": "pre — отзывы публикуются после одобрения, post — сразу; null возвращает режим из конфига. Уже оставленные отзывы статус не меняют. Требуется право **reviews:moderate**.",
                 "consumes": [
                     "application/json"
                 ],
@@ -69,15 +83,22 @@ const docTemplate = `{
                 "tags": [
                     "admins"
                 ],
-                "summary": "Генерация токенов (только для админов)",
+                "summary": "Изменение режима модерации заведения",
                 "parameters": [
                     {
-                        "description": "Данные для генерации токенов",
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Режим",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.GenerateTokensRequest"
+                            "$ref": "#/definitions/dto.ModerationModeRequest"
                         }
                     }
                 ],
@@ -85,122 +106,100 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.GenerateTokensResponse"
+                            "$ref": "#/definitions/dto.ModerationSettingsResponse"
                         }
                     },
                     "400": {
-                        "description": "invalid input",
+                        "description": "invalid input / invalid place id / invalid moderation mode",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "only admin can generate tokens",
+                        "description": "access denied",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "failed to generate tokens",
+                    "404": {
+                        "description": "place not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/bonuses": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "bonuses"
-                ],
-                "summary": "Получить список бонусов пользователя",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.BonusResponse"
-                            }
-                        }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "failed to moderate review",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/bonuses/redeem": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Пользователь может обменять баллы на одно из доступных вознаграждений",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/admin/places/{id}/token-policy": {
+            "get": {
+                "description": "Срок жизни токенов, стартовая пачка и параметры автодогенерации. custom — значения заведения (null — берётся из конфига), effective — действующие. Требуется право **tokens:generate**.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "bonuses"
+                    "admins"
                 ],
-                "summary": "Обмен баллов на бонус",
+                "summary": "Политика токенов заведения",
                 "parameters": [
                     {
-                        "description": "Данные для получения бонуса (reward_type: free_coffee, free_meal, discount_10)",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.BonusRedeemRequest"
-                        }
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.BonusRedeemResponse"
+                            "$ref": "#/definitions/dto.TokenPolicyResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "invalid place id",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage tokens",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/bonuses/validate": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Администратор активирует бонус по QR-токену",
+                ]
+            },
+            "put": {
+                "description": "Заменяет политику целиком: не переданные поля снова берутся из конфига. Требуется право **tokens:generate**.",
                 "consumes": [
                     "application/json"
                 ],
@@ -208,17 +207,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bonuses"
+                    "admins"
                 ],
-                "summary": "Валидация бонусного QR-кода",
+                "summary": "Изменение политики токенов заведения",
                 "parameters": [
                     {
-                        "description": "QR токен для активации бонуса",
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Политика токенов",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.BonusValidateRequest"
+                            "$ref": "#/definitions/dto.TokenPolicyRequest"
                         }
                     }
                 ],
@@ -226,253 +232,217 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.BonusValidateResponse"
+                            "$ref": "#/definitions/dto.TokenPolicyResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный запрос",
+                        "description": "invalid input / invalid place id / invalid token policy",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "QR не найден",
+                    "403": {
+                        "description": "access denied",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Бонус уже использован",
+                    "404": {
+                        "description": "place not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/leaderboard/bonuses": {
-            "get": {
-                "description": "Возвращает список пользователей, отсортированных по количеству полученных бонусов.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "leaderboard"
-                ],
-                "summary": "Получить топ пользователей по бонусам",
-                "responses": {
-                    "200": {
-                        "description": "Список пользователей",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.BonusLeaderboardEntry"
-                            }
-                        }
                     },
                     "500": {
-                        "description": "Внутренняя ошибка сервера",
+                        "description": "failed to manage tokens",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/leaderboard/places": {
+        "/admin/places/{id}/tokens": {
             "get": {
-                "description": "Возвращает список заведений, отсортированных по количеству отзывов и среднему рейтингу.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Токены с фильтром по статусу и пагинацией. Требуется право **tokens:generate**.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "leaderboard"
+                    "admins"
                 ],
-                "summary": "Получить топ заведений",
+                "summary": "Список токенов заведения",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Максимальное количество результатов (по умолчанию 10, максимум 100)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Сортировка: 'reviews' или 'rating' (по умолчанию 'reviews')",
-                        "name": "sort_by",
+                        "description": "active, used, expired или revoked",
+                        "name": "status",
                         "in": "query"
                     },
                     {
-                        "type": "number",
-                        "description": "Минимальный средний рейтинг (по умолчанию 0)",
-                        "name": "min_rating",
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 50, максимум 200)",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Минимальное количество отзывов (по умолчанию 0)",
-                        "name": "min_reviews",
+                        "description": "Смещение",
+                        "name": "offset",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список заведений",
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.LeaderboardEntry"
-                            }
+                            "$ref": "#/definitions/dto.TokenListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid place id / invalid token status",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Внутренняя ошибка сервера",
+                        "description": "failed to manage tokens",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/leaderboard/users": {
-            "get": {
-                "description": "Возвращает список пользователей, отсортированных по количеству отзывов и среднему рейтингу.",
+        "/admin/places/{id}/tokens/print": {
+            "post": {
+                "description": "Генерирует count новых токенов и возвращает PDF с сеткой наклеек: QR-код, название заведения, токен и срок действия. Требуется право **tokens:print**; владелец или менеджер — только для своего заведения. Формат страницы и сетка по умолчанию берутся из конфигурации.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/pdf"
                 ],
                 "tags": [
-                    "leaderboard"
+                    "admins"
                 ],
-                "summary": "Получить топ пользователей",
+                "summary": "Печать наклеек с QR-кодами",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Максимальное количество результатов (по умолчанию 10, максимум 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Сортировка: 'reviews' или 'rating' (по умолчанию 'reviews')",
-                        "name": "sort_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Минимальный средний рейтинг (по умолчанию 0)",
-                        "name": "min_rating",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Минимальное количество отзывов (по умолчанию 0)",
-                        "name": "min_reviews",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Список пользователей",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.LeaderboardEntry"
-                            }
-                        }
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/login": {
-            "post": {
-                "description": "Логин по email и паролю, возвращает JWT-токен",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Авторизация пользователя",
-                "parameters": [
                     {
-                        "description": "Данные для входа",
+                        "description": "Количество токенов и раскладка",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginRequest"
+                            "$ref": "#/definitions/dto.PrintTokensRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешный вход",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.AuthResponse"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "invalid input",
+                        "description": "invalid input / invalid place id / invalid label layout",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "user not found / invalid credentials",
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "login failed",
+                        "description": "failed to print tokens",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/places": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Возвращает список всех заведений. Доступ только для роли **admin**.",
+                ]
+            }
+        },
+        "/admin/places/{id}/tokens/stats": {
+            "get": {
+                "description": "Сколько токенов выпущено, использовано, истекло, активно и отозвано. Требуется право **tokens:generate**.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admins"
                 ],
-                "summary": "Получение списка мест (только для админов)",
+                "summary": "Статистика токенов заведения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.PlaceResponse"
-                            }
+                            "$ref": "#/definitions/dto.TokenStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid place id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "403": {
@@ -481,51 +451,74 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "failed to load places",
+                        "description": "failed to manage tokens",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Эндпоинт доступен только пользователям с ролью **admin**.",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/admin/places/{id}/tokens/{value}/qr": {
+            "get": {
+                "description": "Рисует на сервере QR-код со ссылкой на форму отзыва. Требуется право **tokens:generate**.",
                 "produces": [
-                    "application/json"
+                    "image/png",
+                    "image/svg+xml"
                 ],
                 "tags": [
                     "admins"
                 ],
-                "summary": "Создание места (только для админов)",
+                "summary": "QR-код токена отзыва",
                 "parameters": [
                     {
-                        "description": "Данные для создания места",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreatePlaceRequest"
-                        }
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Значение токена",
+                        "name": "value",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "png или svg (по умолчанию png)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер в пикселях, 64–1024 (по умолчанию 256)",
+                        "name": "size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.CreatePlaceResponse"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "invalid input / invalid place data",
+                        "description": "invalid qr parameters",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -536,52 +529,59 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "place already exists",
+                    "404": {
+                        "description": "invalid token",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "failed to create place",
+                        "description": "failed to render qr",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/places/{id}/reviews": {
+        "/admin/reports": {
             "get": {
-                "description": "Получение списка отзывов по placeID с фильтрацией и сортировкой",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Отзывы с жалобами в указанном статусе (по умолчанию open): сначала с наибольшим числом жалоб. Фильтр по заведению необязателен. Требуется право **reviews:moderate**.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "places"
+                    "admins"
                 ],
-                "summary": "Просмотр отзывов по заведению",
+                "summary": "Очередь жалоб на отзывы",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "open или resolved",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Place ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "name": "place_id",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Фильтр по рейтингу (1-5)",
-                        "name": "rating",
+                        "description": "Размер страницы (по умолчанию 50, максимум 200)",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Сортировка: date_asc или date_desc",
-                        "name": "sort",
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
                         "in": "query"
                     }
                 ],
@@ -591,112 +591,128 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dto.ReviewResponse"
+                                "$ref": "#/definitions/dto.ReportedReviewResponse"
                             }
                         }
                     },
                     "400": {
-                        "description": "invalid input",
+                        "description": "invalid input / invalid place id / invalid report status",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "place not found",
+                    "403": {
+                        "description": "access denied",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal error",
+                        "description": "failed to get reports",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/reviews": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Авторизованный пользователь может оставить отзыв на место, используя одноразовый токен.",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/admin/reviews": {
+            "get": {
+                "description": "Страница отзывов в указанном статусе (по умолчанию pending), старые первыми. Фильтр по заведению необязателен. Следующая страница запрашивается с cursor из заголовка X-Next-Cursor и теми же фильтрами. Требуется право **reviews:moderate**.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admins"
                 ],
-                "summary": "Отправка отзыва",
+                "summary": "Очередь модерации отзывов",
                 "parameters": [
                     {
-                        "description": "Данные отзыва",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.SubmitReviewRequest"
-                        }
+                        "type": "string",
+                        "description": "pending, published, rejected или hidden",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "place_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 20, максимум 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из заголовка X-Next-Cursor",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
-                    },
-                    "400": {
-                        "description": "invalid input",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ModeratedReviewResponse"
+                            }
+                        },
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Курсор следующей страницы; на последней странице отсутствует"
+                            },
+                            "X-Total-Count": {
+                                "type": "int",
+                                "description": "Число отзывов под фильтром"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "invalid user_id / invalid token",
+                    "400": {
+                        "description": "invalid input / invalid place id / invalid review status / invalid cursor",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "token expired / token already used",
+                        "description": "access denied",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "429": {
-                        "description": "too many reviews today",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "internal error",
+                    "500": {
+                        "description": "failed to moderate review",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/reviews/{id}": {
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Автор отзыва может удалить свой отзыв (soft delete)",
+                ]
+            }
+        },
+        "/admin/reviews/{id}/approve": {
+            "post": {
+                "description": "Публикует ожидающий отзыв или возвращает скрытый. При публикации автору начисляются баллы за отзыв. Требуется право **reviews:moderate**.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reviews"
+                    "admins"
                 ],
-                "summary": "Удаление отзыва",
+                "summary": "Одобрение отзыва",
                 "parameters": [
                     {
                         "type": "string",
@@ -708,38 +724,46 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "review deleted successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.MessageResponse"
+                            "$ref": "#/definitions/dto.ModeratedReviewResponse"
                         }
                     },
-                    "401": {
-                        "description": "invalid user_id / unauthorized",
+                    "403": {
+                        "description": "access denied",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "review not found or not author",
+                    "404": {
+                        "description": "review not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "review cannot be moved to this status",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "failed to delete review",
+                        "description": "failed to moderate review",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            },
-            "patch": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Автор отзыва может изменить контент и рейтинг",
+                ]
+            }
+        },
+        "/admin/reviews/{id}/hide": {
+            "post": {
+                "description": "Снимает опубликованный отзыв с публикации и списывает баллы автора; вернуть его можно одобрением. Причина необязательна. Требуется право **reviews:moderate**.",
                 "consumes": [
                     "application/json"
                 ],
@@ -747,9 +771,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reviews"
+                    "admins"
                 ],
-                "summary": "Редактирование отзыва",
+                "summary": "Скрытие отзыва",
                 "parameters": [
                     {
                         "type": "string",
@@ -759,52 +783,62 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Данные для обновления отзыва",
+                        "description": "Причина",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateReviewRequest"
+                            "$ref": "#/definitions/dto.ModerationDecisionRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "review updated successfully",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.MessageResponse"
+                            "$ref": "#/definitions/dto.ModeratedReviewResponse"
                         }
                     },
                     "400": {
-                        "description": "invalid input or rating",
+                        "description": "invalid input / invalid moderation reason",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "invalid user_id / unauthorized",
+                    "403": {
+                        "description": "access denied",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "review not found or not author",
+                    "404": {
+                        "description": "review not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "review cannot be moved to this status",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "failed to update review",
+                        "description": "failed to moderate review",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/signup": {
+        "/admin/reviews/{id}/reject": {
             "post": {
-                "description": "Создаёт нового пользователя и возвращает токен",
+                "description": "Окончательно отклоняет ожидающий, опубликованный или скрытый отзыв. Баллы за опубликованный отзыв списываются. Причина обязательна (до 500 символов). Требуется право **reviews:moderate**.",
                 "consumes": [
                     "application/json"
                 ],
@@ -812,17 +846,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admins"
                 ],
-                "summary": "Регистрация пользователя",
+                "summary": "Отклонение отзыва",
                 "parameters": [
                     {
-                        "description": "Данные для регистрации",
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.SignupRequest"
+                            "$ref": "#/definitions/dto.ModerationDecisionRequest"
                         }
                     }
                 ],
@@ -830,82 +871,105 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.AuthResponse"
+                            "$ref": "#/definitions/dto.ModeratedReviewResponse"
                         }
                     },
                     "400": {
-                        "description": "invalid input",
+                        "description": "invalid input / invalid moderation reason",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "review not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "409": {
-                        "description": "email already in use",
+                        "description": "review cannot be moved to this status",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "failed to signup",
+                        "description": "failed to moderate review",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/users": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Возвращает данные пользователя по user_id из токена",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/admin/reviews/{id}/reports": {
+            "get": {
+                "description": "Все жалобы на отзыв, включая разобранные, старые первыми. Требуется право **reviews:moderate**.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admins"
+                ],
+                "summary": "Жалобы на отзыв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Получение пользователя",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ReportResponse"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "authentication required",
+                    "403": {
+                        "description": "access denied",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "user not found",
+                        "description": "review not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "failed to get user",
+                        "description": "failed to get reports",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            },
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Обновляет имя, email или пароль пользователя по user_id из токена",
+                ]
+            }
+        },
+        "/admin/reviews/{id}/reports/resolve": {
+            "post": {
+                "description": "Закрывает все открытые жалобы на отзыв. dismiss — жалобы отклонены, отзыв не меняется (скрытый автоматически отзыв возвращается через approve); hide — отзыв скрывается; hide_and_restrict — вдобавок автору запрещается оставлять отзывы на REPORT_RESTRICTION_PERIOD. Требуется право **reviews:moderate**.",
                 "consumes": [
                     "application/json"
                 ],
@@ -913,17 +977,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admins"
                 ],
-                "summary": "Обновление пользователя",
+                "summary": "Решение по жалобам на отзыв",
                 "parameters": [
                     {
-                        "description": "Данные для обновления пользователя",
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Решение",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                            "$ref": "#/definitions/dto.ResolveReportsRequest"
                         }
                     }
                 ],
@@ -931,85 +1002,81 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserResponse"
+                            "$ref": "#/definitions/dto.ReportResolutionResponse"
                         }
                     },
                     "400": {
-                        "description": "invalid input / at least one field must be provided",
+                        "description": "invalid input / invalid report action",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "unauthorized",
+                    "403": {
+                        "description": "access denied",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "user not found",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "email already in use",
+                        "description": "review not found / no open reports for review",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "failed to update user",
+                        "description": "failed to resolve reports",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Удаляет пользователя по user_id из токена",
+                ]
+            }
+        },
+        "/admin/stats": {
+            "get": {
+                "description": "Возвращает агрегированные данные: количество пользователей, отзывов, средний рейтинг и количество бонусов. Требуется право **stats:read**.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admins"
                 ],
-                "summary": "Удаление пользователя",
+                "summary": "Получение общей статистики (только для админов)",
                 "responses": {
                     "200": {
-                        "description": "user deleted",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.MessageResponse"
+                            "$ref": "#/definitions/dto.AdminStatsResponse"
                         }
                     },
-                    "404": {
-                        "description": "user not found",
+                    "403": {
+                        "description": "access denied",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "failed to delete user",
+                        "description": "failed to load stats",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/users/stats": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Возвращает статистику активности текущего пользователя (user_id берётся из токена)",
+                ]
+            }
+        },
+        "/admin/token-jobs": {
+            "post": {
+                "description": "Ставит в очередь генерацию до 100 000 токенов. Токены создаются порциями в фоне, прогресс — в GET /admin/token-jobs/{id}. Срок действия берётся из политики заведения на момент постановки. Требуется право **tokens:generate**.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1017,335 +1084,4726 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "admins"
+                ],
+                "summary": "Фоновая генерация большой пачки токенов",
+                "parameters": [
+                    {
+                        "description": "Заведение и количество токенов",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateTokenJobRequest"
+                        }
+                    }
                 ],
-                "summary": "Получение статистики пользователя",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserStatsResponse"
+                            "$ref": "#/definitions/dto.TokenJobResponse"
                         }
                     },
-                    "401": {
-                        "description": "authentication required",
+                    "400": {
+                        "description": "invalid input / invalid place id / invalid token count",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "failed to get user stats",
+                        "description": "failed to manage tokens",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "dto.AdminStatsResponse": {
-            "type": "object",
-            "properties": {
-                "average_rating": {
-                    "type": "number"
-                },
-                "total_bonuses": {
-                    "type": "integer"
-                },
-                "total_reviews": {
-                    "type": "integer"
                 },
-                "total_users": {
-                    "type": "integer"
-                }
-            }
-        },
-        "dto.AuthResponse": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/token-jobs/{id}": {
+            "get": {
+                "description": "status: pending, running, done или failed; generated растёт по мере вставки порций. Требуется право **tokens:generate**.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admins"
+                ],
+                "summary": "Статус фоновой генерации токенов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TokenJobResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "token job not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage tokens",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/token-jobs/{id}/export": {
+            "get": {
+                "description": "csv — значения токенов, ссылки на форму отзыва и срок действия; zip — тот же tokens.csv и PNG с QR-кодом на каждый токен в папке qr/. Доступно после завершения задачи. Требуется право **tokens:generate**.",
+                "produces": [
+                    "text/csv",
+                    "application/zip"
+                ],
+                "tags": [
+                    "admins"
+                ],
+                "summary": "Выгрузка токенов фоновой задачи",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (по умолчанию) или zip",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid export format",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "token job not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "token job is not finished",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage tokens",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/tokens": {
+            "post": {
+                "description": "Срок действия токенов берётся из политики заведения. Необязательные метки staff_id (сотрудник заведения), table и campaign наследуются отзывами и попадают в отчёт GET /places/{id}/reports/labels. Больше 100 токенов — через фоновую задачу POST /admin/token-jobs. Требуется право **tokens:generate**.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admins"
+                ],
+                "summary": "Генерация токенов (только для админов)",
+                "parameters": [
+                    {
+                        "description": "Данные для генерации токенов",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GenerateTokensRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GenerateTokensResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid token labels",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to generate tokens",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/tokens/extend": {
+            "post": {
+                "description": "Продлевает срок действия неиспользованных и неотозванных токенов на hours часов; истёкшие — от текущего момента. Требуется право **tokens:generate**.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admins"
+                ],
+                "summary": "Продление токенов",
+                "parameters": [
+                    {
+                        "description": "ID токенов и срок продления",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExtendTokensRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkTokensResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage tokens",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/tokens/revoke": {
+            "post": {
+                "description": "Отзывает неиспользованные токены из списка. Требуется право **tokens:generate**.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admins"
+                ],
+                "summary": "Массовый отзыв токенов",
+                "parameters": [
+                    {
+                        "description": "ID токенов",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkTokensRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkTokensResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage tokens",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/tokens/{id}": {
+            "delete": {
+                "description": "Отзывает неиспользованный токен. Требуется право **tokens:generate**.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admins"
+                ],
+                "summary": "Отзыв токена",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "token revoked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "token not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage tokens",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/tokens/{id}/pin": {
+            "put": {
+                "description": "Выдаёт активному токену числовой PIN для ручного ввода в форме отзыва вместо QR-кода. PIN уникален среди активных токенов заведения; повторный вызов возвращает уже выданный PIN. Требуется право **tokens:generate**.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admins"
+                ],
+                "summary": "PIN-код токена",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TokenPINResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "token not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage tokens",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/users/{id}/role": {
+            "patch": {
+                "description": "Меняет роль пользователя (user, staff, place_owner, admin). Требуется право **users:manage**. Новая роль действует после обновления токена.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admins"
+                ],
+                "summary": "Назначение роли пользователю (только для админов)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая роль",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "role updated",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to update role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Отзывает текущую сессию: refresh-токены и выданные по ним access-токены перестают действовать",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Выход из системы",
+                "responses": {
+                    "200": {
+                        "description": "logged out",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to logout",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Обменивает refresh-токен на новую пару access/refresh. Повторное использование refresh-токена отзывает всю сессию.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Обновление токенов",
+                "parameters": [
+                    {
+                        "description": "Refresh-токен",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid refresh token / refresh token expired / reuse detected / session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bonuses": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bonuses"
+                ],
+                "summary": "Получить список бонусов пользователя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.BonusResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/bonuses/redeem": {
+            "post": {
+                "description": "Пользователь может обменять баллы на одно из доступных вознаграждений",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bonuses"
+                ],
+                "summary": "Обмен баллов на бонус",
+                "parameters": [
+                    {
+                        "description": "Данные для получения бонуса (reward_type: free_coffee, free_meal, discount_10)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BonusRedeemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BonusRedeemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/bonuses/validate": {
+            "post": {
+                "description": "Сотрудник активирует бонус по QR-токену. Требуется право **bonuses:validate** и членство в штате заведения бонуса.\nДля бонусов без привязки к заведению нужно передать place_id заведения, где бонус принимается.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bonuses"
+                ],
+                "summary": "Валидация бонусного QR-кода",
+                "parameters": [
+                    {
+                        "description": "QR токен для активации бонуса",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BonusValidateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BonusValidateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied / not a member of the bonus place / bonus belongs to another place",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "QR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Бонус уже использован",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/bonuses/{qr_token}/qr": {
+            "get": {
+                "description": "Рисует QR-код бонуса для предъявления кассиру. Доступен только владельцу бонуса.",
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "bonuses"
+                ],
+                "summary": "QR-код бонуса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "QR-токен бонуса",
+                        "name": "qr_token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "png или svg (по умолчанию png)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер в пикселях, 64–1024 (по умолчанию 256)",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid qr parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "bonus not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to render qr",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/leaderboard/bonuses": {
+            "get": {
+                "description": "Возвращает список пользователей, отсортированных по количеству полученных бонусов.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leaderboard"
+                ],
+                "summary": "Получить топ пользователей по бонусам",
+                "responses": {
+                    "200": {
+                        "description": "Список пользователей",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.BonusLeaderboardEntry"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboard/places": {
+            "get": {
+                "description": "Возвращает список заведений, отсортированных по количеству отзывов и среднему рейтингу.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leaderboard"
+                ],
+                "summary": "Получить топ заведений",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество результатов (по умолчанию 10, максимум 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка: 'reviews' или 'rating' (по умолчанию 'reviews')",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальный средний рейтинг (по умолчанию 0)",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальное количество отзывов (по умолчанию 0)",
+                        "name": "min_reviews",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список заведений",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.LeaderboardEntry"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboard/users": {
+            "get": {
+                "description": "Возвращает список пользователей, отсортированных по количеству отзывов и среднему рейтингу.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leaderboard"
+                ],
+                "summary": "Получить топ пользователей",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество результатов (по умолчанию 10, максимум 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка: 'reviews' или 'rating' (по умолчанию 'reviews')",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальный средний рейтинг (по умолчанию 0)",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальное количество отзывов (по умолчанию 0)",
+                        "name": "min_reviews",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список пользователей",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.LeaderboardEntry"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Логин по email и паролю, возвращает JWT-токен",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Авторизация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для входа",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешный вход",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "user not found / invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "login failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/{key}": {
+            "get": {
+                "description": "Отдаёт фото или миниатюру из хранилища по ключу из ссылок photos[].url и photos[].thumbnail_url. Ключи не повторяются, поэтому файл кэшируется надолго.",
+                "produces": [
+                    "image/jpeg",
+                    "image/png"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Файл фотографии",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ключ файла",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "photo not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/places": {
+            "get": {
+                "description": "Возвращает страницу заведений по названию. Следующая страница запрашивается с cursor из заголовка X-Next-Cursor. Требуется право **places:read**; админ видит все заведения, владелец — только свои.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admins"
+                ],
+                "summary": "Получение списка мест",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 20, максимум 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из заголовка X-Next-Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.PlaceResponse"
+                            }
+                        },
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Курсор следующей страницы; на последней странице отсутствует"
+                            },
+                            "X-Total-Count": {
+                                "type": "int",
+                                "description": "Число заведений"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid cursor",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to load places",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Требуется право **places:write**. Необязательная token_policy переопределяет настройки токенов из конфига; стартовая пачка токенов создаётся по ней.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admins"
+                ],
+                "summary": "Создание места (только для админов)",
+                "parameters": [
+                    {
+                        "description": "Данные для создания места",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePlaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePlaceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid place data / invalid token policy",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "place already exists",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to create place",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/funnel": {
+            "get": {
+                "description": "Считается по токенам, отсканированным за период: переходы, разные токены, входы, отзывы и баллы за опубликованные из них. Период по умолчанию — последние 30 дней, to включает весь день. Доступно админам, владельцам и менеджерам заведения; кассирам — нет.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "places"
+                ],
+                "summary": "Воронка «скан → вход → отзыв» заведения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FunnelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid place id / invalid period",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to get funnel",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/kiosk": {
+            "put": {
+                "description": "Включает планшет с QR-кодом, который меняется каждые step_seconds секунд (по умолчанию из конфига). Повторный вызов перевыпускает секрет заведения. Доступно админам и владельцам заведения.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kiosk"
+                ],
+                "summary": "Включение режима киоска",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Шаг смены кода",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.EnableKioskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.KioskResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid place id / invalid kiosk code step",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage kiosk",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Коды с планшета перестают приниматься сразу. Доступно админам и владельцам заведения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kiosk"
+                ],
+                "summary": "Выключение режима киоска",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "kiosk disabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid place id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found / kiosk mode is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage kiosk",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/kiosk/code": {
+            "get": {
+                "description": "Код текущего окна и ссылка на форму отзыва для показа на планшете. Доступно сотрудникам заведения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kiosk"
+                ],
+                "summary": "Текущий код киоска",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.KioskCodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid place id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found / kiosk mode is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage kiosk",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/kiosk/qr": {
+            "get": {
+                "description": "QR-код текущего окна со ссылкой на форму отзыва. Доступно сотрудникам заведения.",
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "kiosk"
+                ],
+                "summary": "QR-код киоска",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "png или svg (по умолчанию png)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер в пикселях, 64–1024 (по умолчанию 256)",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid qr parameters / invalid place id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found / kiosk mode is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to render qr",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/members": {
+            "get": {
+                "description": "Доступно админам и владельцам заведения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "members"
+                ],
+                "summary": "Список сотрудников заведения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.PlaceMemberResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid place id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage members",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Добавляет пользователя в штат заведения с ролью owner, manager или cashier. Доступно админам и владельцам заведения; назначать владельцев может только админ.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "members"
+                ],
+                "summary": "Приглашение сотрудника в заведение",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Email пользователя и роль",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddPlaceMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PlaceMemberResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid place id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found / user not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "member already exists",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage members",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/members/{user_id}": {
+            "delete": {
+                "description": "Доступно админам и владельцам заведения; исключать владельцев может только админ. Глобальная роль исключённого пересчитывается по оставшимся заведениям.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "members"
+                ],
+                "summary": "Исключение сотрудника из заведения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "member removed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid place id / invalid user_id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found / member not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage members",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/pos": {
+            "put": {
+                "description": "Выпускает секрет, которым касса подписывает события о покупках (HMAC-SHA256). Повторный вызов перевыпускает секрет, старый перестаёт приниматься. Секрет показывается только в этом ответе. Доступно админам и владельцам заведения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pos"
+                ],
+                "summary": "Подключение кассовой системы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.POSIntegrationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid place id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to manage pos integration",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/reply-templates": {
+            "get": {
+                "description": "Сохранённые шаблоны ответов на отзывы по названию. Доступно админам и сотрудникам заведения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "places"
+                ],
+                "summary": "Шаблоны ответов заведения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ReplyTemplateResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid place id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Добавляет шаблон ответа на отзывы; название уникально в пределах заведения. Доступно админам, владельцам и менеджерам заведения.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "places"
+                ],
+                "summary": "Сохранение шаблона ответа",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Шаблон",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplyTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplyTemplateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid place id / invalid reply template",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "reply template already exists",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to save reply template",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/reply-templates/{template_id}": {
+            "delete": {
+                "description": "Доступно админам, владельцам и менеджерам заведения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "places"
+                ],
+                "summary": "Удаление шаблона ответа",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template ID",
+                        "name": "template_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "reply template deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid place id",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found / reply template not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to save reply template",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/reports/labels": {
+            "get": {
+                "description": "Число отзывов и средняя оценка в разрезе меток токенов: by=staff — по сотрудникам (рейтинг персонала), table — по столам, campaign — по кампаниям. Отзывы без метки не учитываются. to включает весь день. Доступно админам, владельцам и менеджерам заведения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "places"
+                ],
+                "summary": "Отчёт по меткам токенов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "staff, table или campaign",
+                        "name": "by",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.LabelStatResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid place id / invalid report dimension / invalid period",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to get report",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/reports/replies": {
+            "get": {
+                "description": "По опубликованным отзывам за период: сколько получили ответ, доля ответов и медиана времени от отзыва до ответа в секундах. Период по умолчанию — последние 30 дней, to включает весь день. Доступно админам, владельцам и менеджерам заведения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "places"
+                ],
+                "summary": "Отчёт по ответам заведения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplyStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid place id / invalid period",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to get reply stats",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/places/{id}/reviews": {
+            "get": {
+                "description": "Получение страницы опубликованных отзывов по placeID с фильтрацией и сортировкой. Страницы листаются курсором: следующая запрашивается с cursor из заголовка X-Next-Cursor и теми же фильтрами и сортировкой. Ответ заведения на отзыв приходит в поле reply, ссылки на фото и миниатюры — в поле photos, число отметок «полезный» — в helpful_count. Отредактированные автором отзывы помечены полем edited и временем последней правки edited_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "places"
+                ],
+                "summary": "Просмотр отзывов по заведению",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Place ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Фильтр по рейтингу (1-5)",
+                        "name": "rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка: date_desc (по умолчанию), date_asc, rating_desc, rating_asc или helpful (сначала полезные)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (по умолчанию 20, максимум 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из заголовка X-Next-Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ReviewResponse"
+                            }
+                        },
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Курсор следующей страницы; на последней странице отсутствует"
+                            },
+                            "X-Total-Count": {
+                                "type": "int",
+                                "description": "Число отзывов под фильтром"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid review sort / invalid cursor",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "place not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pos/receipts": {
+            "post": {
+                "description": "Вызывается кассовой системой после покупки. Заголовок X-Reviewlink-Timestamp — unix-время в секундах, X-Reviewlink-Signature — \"sha256=\" + hex(HMAC-SHA256(секрет, timestamp + \".\" + тело запроса)). На один чек выпускается один токен: повтор того же события возвращает его же с кодом 200.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pos"
+                ],
+                "summary": "Токен для отзыва по чеку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unix-время подписи",
+                        "name": "X-Reviewlink-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подпись тела запроса",
+                        "name": "X-Reviewlink-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные чека",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReceiptEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "чек уже присылали",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReceiptTokenResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReceiptTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid receipt",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid signature",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "receipt already registered with different data",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to issue receipt token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/r/{code}": {
+            "get": {
+                "description": "Записывает переход (время и User-Agent) и перенаправляет на форму отзыва. В адрес формы добавляются параметры scan с ID перехода и scan_key с ключом сессии. Повторные переходы одного клиента (IP и User-Agent) по токену в окне SCAN_DEDUP_WINDOW считаются одним переходом.",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Переход по короткой ссылке из QR-кода",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Значение токена",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "404": {
+                        "description": "invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to track scan",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews": {
+            "post": {
+                "description": "Авторизованный пользователь может оставить отзыв на место, используя одноразовый токен, код с планшета заведения (K-…, нужен place_id) или числовой PIN-код токена (нужен place_id). Неудачные попытки ввода PIN ограничены на пользователя и IP. Отзыв по токену с чека кассы помечается как подтверждённая покупка. Текст проверяется фильтром: запрещённый текст отклоняется, подозрительный (ссылки, телефоны, капс) уходит на модерацию со статусом pending.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Отправка отзыва",
+                "parameters": [
+                    {
+                        "description": "Данные отзыва",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubmitReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubmitReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / review content rejected",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid user_id / invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "token expired / token revoked / token already used / token belongs to another place / posting reviews is restricted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "kiosk code already used",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "too many reviews today / too many pin attempts",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reviews/{id}": {
+            "delete": {
+                "description": "Автор отзыва может удалить свой отзыв (soft delete) и отменить удаление в течение REVIEW_UNDO_DELETE_WINDOW. Фотографии скрываются вместе с отзывом и возвращаются при отмене; файлы стираются после окна отмены.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Удаление отзыва",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "review deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid user_id / unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "review not found or not author",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to delete review",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Автор отзыва может изменить контент и рейтинг. Новый текст проверяется фильтром: запрещённый отклоняется, а подозрительный возвращает опубликованный отзыв в очередь модерации (статус pending, причина edit flagged by content filter rule …) и списывает баллы до одобрения. Каждая правка сохраняется отдельной редакцией.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Редактирование отзыва",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления отзыва",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "review updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input or rating / review content rejected",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid user_id / unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "review not found or not author",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to update review",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reviews/{id}/helpful": {
+            "post": {
+                "description": "Один пользователь ставит отзыву не больше одной отметки, повторный вызов ничего не меняет. Отмечать можно только опубликованные отзывы, свой отзыв отметить нельзя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Отметить отзыв как полезный",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReviewVoteResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid user_id / unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "cannot vote for own review",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "review not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to save vote",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Снимает отметку пользователя с отзыва; если отметки не было, возвращает текущий счётчик",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Снять отметку «полезный»",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReviewVoteResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid user_id / unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "review not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to save vote",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reviews/{id}/photos": {
+            "post": {
+                "description": "Автор отзыва прикрепляет фото (JPEG, PNG, WebP) в поле photos multipart-формы, можно несколько файлов за раз. Тип определяется по содержимому файла. Фото перекодируются без метаданных (EXIF, GPS), для каждого делается миниатюра. Лимиты на число фото у отзыва и размер файла задаются MAX_REVIEW_PHOTOS и PHOTO_MAX_SIZE; если хоть один файл не прошёл проверку, не сохраняется ни один.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Загрузка фотографий к отзыву",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файлы фотографий",
+                        "name": "photos",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UploadPhotosResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid photo / unsupported photo type / too many photos",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "review not found or not author",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "photo too large",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to save photo",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reviews/{id}/photos/{photo_id}": {
+            "delete": {
+                "description": "Автор отзыва удаляет одно фото вместе с миниатюрой",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Удаление фотографии отзыва",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Photo ID",
+                        "name": "photo_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "photo deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "review not found or not author",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "photo not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to delete photo",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reviews/{id}/reply": {
+            "put": {
+                "description": "Меняет текст ответа заведения. Доступно админам и сотрудникам заведения отзыва.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Редактирование ответа на отзыв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый текст ответа",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid reply",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "review not found / reply not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to save reply",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Публикует ответ на отзыв от имени заведения; на отзыв можно ответить один раз. Доступно админам и сотрудникам заведения отзыва.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Ответ заведения на отзыв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Текст ответа",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReplyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid reply",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "review not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "review already has a reply",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to save reply",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Удаляет ответ заведения; после этого на отзыв можно ответить заново. Доступно админам и сотрудникам заведения отзыва.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Удаление ответа на отзыв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "reply deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "review not found / reply not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to save reply",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reviews/{id}/reports": {
+            "post": {
+                "description": "Причина — spam, offensive, fake или off_topic, комментарий необязателен (до 500 символов). Пожаловаться можно один раз на опубликованный чужой отзыв. После REPORT_AUTO_HIDE_THRESHOLD открытых жалоб отзыв скрывается до решения модератора.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Жалоба на отзыв",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Жалоба",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / invalid report",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid user_id / unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "cannot report own review",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "review not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "review already reported",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to save report",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reviews/{id}/restore": {
+            "post": {
+                "description": "Автор может вернуть удалённый отзыв в течение REVIEW_UNDO_DELETE_WINDOW после удаления. Статус модерации и баллы не меняются, фотографии возвращаются вместе с отзывом.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Отмена удаления отзыва",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "review restored successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "invalid user_id / unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "review cannot be restored",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to restore review",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/reviews/{id}/revisions": {
+            "get": {
+                "description": "Редакции отзыва от первой (текст при создании) к последней. Доступно автору и админам.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "История правок отзыва",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ReviewRevisionResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "review not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to get revisions",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/scans/{id}/open": {
+            "post": {
+                "description": "Форма отзыва вызывает после входа пользователя, чтобы переход попал в воронку как «вход». Нужен ключ scan_key из адреса формы: без него или с чужим ключом переход не найдётся. Повторные вызовы ничего не меняют.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Вход после перехода по QR-коду",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ключ сессии перехода",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MarkScanOpenedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "scan opened",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "scan not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to track scan",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/signup": {
+            "post": {
+                "description": "Создаёт нового пользователя и возвращает токен",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Регистрация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для регистрации",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SignupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "email already in use",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to signup",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/{value}": {
+            "get": {
+                "description": "Публичный эндпоинт для страницы по QR-коду: заведение, статус токена и срок действия. Авторизация не требуется.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Предпроверка токена отзыва",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Значение токена",
+                        "name": "value",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TokenInfoResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to get token info",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Возвращает данные пользователя по user_id из токена",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение пользователя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to get user",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Обновляет имя, email или пароль пользователя по user_id из токена",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Обновление пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для обновления пользователя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid input / at least one field must be provided",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "email already in use",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to update user",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Удаляет пользователя по user_id из токена",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Удаление пользователя",
+                "responses": {
+                    "200": {
+                        "description": "user deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "user not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to delete user",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/stats": {
+            "get": {
+                "description": "Возвращает статистику активности текущего пользователя (user_id берётся из токена)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение статистики пользователя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserStatsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "failed to get user stats",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "dto.AddPlaceMemberRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "manager",
+                        "cashier"
+                    ]
+                }
+            }
+        },
+        "dto.AdminStatsResponse": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number"
+                },
+                "pending_reviews": {
+                    "description": "PendingReviews — отзывы в очереди модерации, в total_reviews не входят",
+                    "type": "integer"
+                },
+                "total_bonuses": {
+                    "type": "integer"
+                },
+                "total_reviews": {
+                    "type": "integer"
+                },
+                "total_users": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BonusLeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "bonuses_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "points_spent": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.BonusRedeemRequest": {
+            "type": "object",
+            "required": [
+                "reward_type"
+            ],
+            "properties": {
+                "reward_type": {
+                    "type": "string",
+                    "enum": [
+                        "free_coffee",
+                        "free_meal",
+                        "discount_10"
+                    ]
+                }
+            }
+        },
+        "dto.BonusRedeemResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "is_used": {
+                    "type": "boolean"
+                },
+                "qr_token": {
+                    "type": "string"
+                },
+                "required_points": {
+                    "type": "integer"
+                },
+                "reward_type": {
+                    "type": "string"
+                },
+                "used_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BonusResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "is_used": {
+                    "type": "boolean"
+                },
+                "place_id": {
+                    "type": "string"
+                },
+                "qr_token": {
+                    "type": "string"
+                },
+                "required_points": {
+                    "type": "integer"
+                },
+                "reward_type": {
+                    "type": "string"
+                },
+                "used_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BonusValidateRequest": {
+            "type": "object",
+            "required": [
+                "qr_token"
+            ],
+            "properties": {
+                "place_id": {
+                    "type": "string"
+                },
+                "qr_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BonusValidateResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BulkTokensRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.BulkTokensResponse": {
+            "type": "object",
+            "properties": {
+                "affected": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreatePlaceRequest": {
+            "type": "object",
+            "required": [
+                "address",
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "token_policy": {
+                    "$ref": "#/definitions/dto.TokenPolicyRequest"
+                }
+            }
+        },
+        "dto.CreatePlaceResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateReportRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateTokenJobRequest": {
+            "type": "object",
+            "required": [
+                "count",
+                "place_id"
+            ],
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": 1
+                },
+                "place_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.EnableKioskRequest": {
+            "type": "object",
+            "properties": {
+                "step_seconds": {
+                    "type": "integer",
+                    "maximum": 600,
+                    "minimum": 10
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ExtendTokensRequest": {
+            "type": "object",
+            "required": [
+                "hours",
+                "ids"
+            ],
+            "properties": {
+                "hours": {
+                    "type": "integer",
+                    "maximum": 8760,
+                    "minimum": 1
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.FunnelResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "login_rate": {
+                    "type": "number"
+                },
+                "logins": {
+                    "type": "integer"
+                },
+                "place_id": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "review_rate": {
+                    "type": "number"
+                },
+                "reviews": {
+                    "type": "integer"
+                },
+                "scanned_tokens": {
+                    "type": "integer"
+                },
+                "scans": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GenerateTokensRequest": {
+            "type": "object",
+            "required": [
+                "count",
+                "place_id"
+            ],
+            "properties": {
+                "campaign": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "count": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "place_id": {
+                    "type": "string"
+                },
+                "staff_id": {
+                    "description": "Необязательные метки атрибуции: сотрудник заведения, номер стола, тег кампании",
+                    "type": "string"
+                },
+                "table": {
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "dto.GenerateTokensResponse": {
+            "type": "object",
+            "properties": {
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.KioskCodeResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "step_seconds": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.KioskResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "place_id": {
+                    "type": "string"
+                },
+                "step_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LabelStatResponse": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "reviews": {
+                    "type": "integer"
+                },
+                "staff_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "avg_rating": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "reviews_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "dto.MarkScanOpenedRequest": {
+            "type": "object",
+            "required": [
+                "key"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "dto.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ModeratedReviewResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "filter_rule": {
+                    "description": "FilterRule — правило фильтра текста, из-за которого отзыв попал к модератору",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "moderated_at": {
+                    "type": "string"
+                },
+                "moderated_by": {
+                    "type": "string"
+                },
+                "moderation_reason": {
+                    "type": "string"
+                },
+                "place_id": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "verified_purchase": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ModerationDecisionRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ModerationModeRequest": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ModerationSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "effective": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "place_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.POSIntegrationResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "place_id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PhotoResponse": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PlaceMemberResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PlaceResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PrintTokensRequest": {
+            "type": "object",
+            "required": [
+                "count"
+            ],
+            "properties": {
+                "columns": {
+                    "type": "integer",
+                    "maximum": 6,
+                    "minimum": 1
+                },
+                "count": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "page_size": {
+                    "type": "string",
+                    "enum": [
+                        "A4",
+                        "Letter"
+                    ]
+                },
+                "rows": {
+                    "type": "integer",
+                    "maximum": 12,
+                    "minimum": 1
+                }
+            }
+        },
+        "dto.ReceiptEventRequest": {
+            "type": "object",
+            "required": [
+                "place_id",
+                "purchased_at",
+                "receipt_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "place_id": {
+                    "type": "string"
+                },
+                "purchased_at": {
+                    "type": "string"
+                },
+                "receipt_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "dto.ReceiptTokenResponse": {
             "type": "object",
             "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "pin": {
+                    "type": "string"
+                },
                 "token": {
                     "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "dto.BonusLeaderboardEntry": {
+        "dto.RefreshTokenRequest": {
             "type": "object",
+            "required": [
+                "refresh_token"
+            ],
             "properties": {
-                "bonuses_count": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReplyRequest": {
+            "type": "object",
+            "required": [
+                "content"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReplyResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "review_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReplyStatsResponse": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "median_response_seconds": {
                     "type": "integer"
                 },
-                "name": {
+                "place_id": {
                     "type": "string"
                 },
-                "points_spent": {
+                "replied": {
                     "type": "integer"
                 },
-                "rank": {
+                "reply_rate": {
+                    "type": "number"
+                },
+                "reviews": {
                     "type": "integer"
+                },
+                "to": {
+                    "type": "string"
                 }
             }
         },
-        "dto.BonusRedeemRequest": {
+        "dto.ReplyTemplateRequest": {
             "type": "object",
             "required": [
-                "reward_type"
+                "content",
+                "title"
             ],
             "properties": {
-                "reward_type": {
+                "content": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReplyTemplateResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "place_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReportResolutionResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "resolved": {
+                    "type": "integer"
+                },
+                "restricted_until": {
+                    "description": "RestrictedUntil — до какого момента автору запрещено оставлять отзывы",
+                    "type": "string"
+                },
+                "review_id": {
+                    "type": "string"
+                },
+                "review_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReportResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reporter_id": {
+                    "type": "string"
+                },
+                "resolution": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "type": "string"
+                },
+                "review_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReportedReviewResponse": {
+            "type": "object",
+            "properties": {
+                "first_reported_at": {
+                    "type": "string"
+                },
+                "last_reported_at": {
+                    "type": "string"
+                },
+                "reasons": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "reports": {
+                    "type": "integer"
+                },
+                "review": {
+                    "$ref": "#/definitions/dto.ModeratedReviewResponse"
+                }
+            }
+        },
+        "dto.ResolveReportsRequest": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReviewResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "edited": {
+                    "description": "Edited — автор правил отзыв после публикации, EditedAt — время последней правки",
+                    "type": "boolean"
+                },
+                "edited_at": {
+                    "type": "string"
+                },
+                "helpful_count": {
+                    "description": "HelpfulCount — число отметок «полезный отзыв»",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "photos": {
+                    "description": "Photos — фотографии к отзыву в порядке загрузки",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PhotoResponse"
+                    }
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "reply": {
+                    "description": "Reply — публичный ответ заведения, если он есть",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.ReplyResponse"
+                        }
+                    ]
+                },
+                "verified_purchase": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ReviewRevisionResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.ReviewVoteResponse": {
+            "type": "object",
+            "properties": {
+                "helpful_count": {
+                    "type": "integer"
+                },
+                "review_id": {
+                    "type": "string"
+                },
+                "voted": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.SignupRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "password": {
                     "type": "string",
-                    "enum": [
-                        "free_coffee",
-                        "free_meal",
-                        "discount_10"
-                    ]
+                    "minLength": 6
                 }
             }
         },
-        "dto.BonusRedeemResponse": {
+        "dto.SubmitReviewRequest": {
             "type": "object",
+            "required": [
+                "place_id",
+                "rating",
+                "token"
+            ],
             "properties": {
-                "id": {
+                "content": {
                     "type": "string"
                 },
-                "is_used": {
-                    "type": "boolean"
-                },
-                "qr_token": {
+                "place_id": {
                     "type": "string"
                 },
-                "required_points": {
-                    "type": "integer"
-                },
-                "reward_type": {
-                    "type": "string"
+                "rating": {
+                    "type": "integer",
+                    "maximum": 5,
+                    "minimum": 1
                 },
-                "used_at": {
+                "token": {
+                    "description": "Token — значение токена, код с планшета (K-…) или PIN-код для ручного ввода",
                     "type": "string"
                 }
             }
         },
-        "dto.BonusResponse": {
+        "dto.SubmitReviewResponse": {
             "type": "object",
             "properties": {
-                "id": {
+                "content": {
                     "type": "string"
                 },
-                "is_used": {
-                    "type": "boolean"
+                "created_at": {
+                    "type": "string"
                 },
-                "place_id": {
+                "id": {
                     "type": "string"
                 },
-                "qr_token": {
+                "place_id": {
                     "type": "string"
                 },
-                "required_points": {
+                "rating": {
                     "type": "integer"
                 },
-                "reward_type": {
+                "receipt_id": {
                     "type": "string"
                 },
-                "used_at": {
+                "status": {
+                    "description": "Status — pending, если заведение работает по премодерации",
                     "type": "string"
+                },
+                "verified_purchase": {
+                    "type": "boolean"
                 }
             }
         },
-        "dto.BonusValidateRequest": {
+        "dto.TokenInfoResponse": {
             "type": "object",
-            "required": [
-                "qr_token"
-            ],
             "properties": {
-                "qr_token": {
+                "expired": {
+                    "type": "boolean"
+                },
+                "expires_at": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.BonusValidateResponse": {
-            "type": "object",
-            "properties": {
-                "status": {
+                },
+                "place_id": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.CreatePlaceRequest": {
-            "type": "object",
-            "required": [
-                "address",
-                "name"
-            ],
-            "properties": {
-                "address": {
+                },
+                "place_name": {
                     "type": "string"
                 },
-                "name": {
+                "revoked": {
+                    "type": "boolean"
+                },
+                "token": {
                     "type": "string"
+                },
+                "used": {
+                    "type": "boolean"
+                },
+                "valid": {
+                    "type": "boolean"
                 }
             }
         },
-        "dto.CreatePlaceResponse": {
+        "dto.TokenJobResponse": {
             "type": "object",
             "properties": {
-                "id": {
+                "created_at": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.ErrorResponse": {
-            "type": "object",
-            "properties": {
+                },
                 "error": {
                     "type": "string"
-                }
-            }
-        },
-        "dto.GenerateTokensRequest": {
-            "type": "object",
-            "required": [
-                "count",
-                "place_id"
-            ],
-            "properties": {
-                "count": {
-                    "type": "integer",
-                    "maximum": 100,
-                    "minimum": 1
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "generated": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
                 },
                 "place_id": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.GenerateTokensResponse": {
+        "dto.TokenListResponse": {
             "type": "object",
             "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
                 "tokens": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/dto.TokenResponse"
                     }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.LeaderboardEntry": {
+        "dto.TokenPINResponse": {
             "type": "object",
             "properties": {
-                "avg_rating": {
-                    "type": "number"
-                },
-                "id": {
+                "pin": {
                     "type": "string"
                 },
-                "name": {
+                "token_id": {
                     "type": "string"
+                }
+            }
+        },
+        "dto.TokenPolicyOverrides": {
+            "type": "object",
+            "properties": {
+                "initial_count": {
+                    "type": "integer"
                 },
-                "rank": {
+                "refill_batch": {
                     "type": "integer"
                 },
-                "reviews_count": {
+                "refill_threshold": {
+                    "type": "integer"
+                },
+                "ttl_hours": {
                     "type": "integer"
                 }
             }
         },
-        "dto.LoginRequest": {
+        "dto.TokenPolicyRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "initial_count": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "refill_batch": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "refill_threshold": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0
+                },
+                "ttl_hours": {
+                    "type": "integer",
+                    "maximum": 8760,
+                    "minimum": 1
                 }
             }
         },
-        "dto.MessageResponse": {
+        "dto.TokenPolicyResponse": {
             "type": "object",
             "properties": {
-                "message": {
+                "custom": {
+                    "$ref": "#/definitions/dto.TokenPolicyOverrides"
+                },
+                "effective": {
+                    "$ref": "#/definitions/dto.TokenPolicyValues"
+                },
+                "place_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.PlaceResponse": {
+        "dto.TokenPolicyValues": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
+                "initial_count": {
+                    "type": "integer"
                 },
-                "created_at": {
-                    "type": "string"
+                "refill_batch": {
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "string"
+                "refill_threshold": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "ttl_hours": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.ReviewResponse": {
+        "dto.TokenResponse": {
             "type": "object",
             "properties": {
-                "content": {
+                "created_at": {
                     "type": "string"
                 },
-                "created_at": {
+                "expires_at": {
                     "type": "string"
                 },
-                "rating": {
-                    "type": "integer"
-                }
-            }
-        },
-        "dto.SignupRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password"
-            ],
-            "properties": {
-                "email": {
+                "id": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100
+                "revoked_at": {
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "status": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
                 }
             }
         },
-        "dto.SubmitReviewRequest": {
+        "dto.TokenStatsResponse": {
             "type": "object",
-            "required": [
-                "place_id",
-                "rating",
-                "token"
-            ],
             "properties": {
-                "content": {
-                    "type": "string"
+                "active": {
+                    "type": "integer"
                 },
-                "place_id": {
-                    "type": "string"
+                "expired": {
+                    "type": "integer"
                 },
-                "rating": {
-                    "type": "integer",
-                    "maximum": 5,
-                    "minimum": 1
+                "issued": {
+                    "type": "integer"
                 },
-                "token": {
-                    "type": "string"
+                "revoked": {
+                    "type": "integer"
+                },
+                "used": {
+                    "type": "integer"
                 }
             }
         },
@@ -1376,6 +5834,28 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdateUserRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UploadPhotosResponse": {
+            "type": "object",
+            "properties": {
+                "photos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PhotoResponse"
+                    }
+                }
+            }
+        },
         "dto.UserResponse": {
             "type": "object",
             "properties": {
@@ -1436,6 +5916,8 @@ var SwaggerInfo = &swag.Spec{
 	Description:      "API для пользователей, мест, отзывов и токенов",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
 }
 
 func init() {
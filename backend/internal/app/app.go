@@ -11,23 +11,49 @@ import (
 	"github.com/kulikovroman08/reviewlink-backend/internal/controller"
 	repoAdmin "github.com/kulikovroman08/reviewlink-backend/internal/repository/admin"
 	bonusRepo "github.com/kulikovroman08/reviewlink-backend/internal/repository/bonus"
+	repoKiosk "github.com/kulikovroman08/reviewlink-backend/internal/repository/kiosk"
 	repoLeaderboard "github.com/kulikovroman08/reviewlink-backend/internal/repository/leaderboard"
+	repoMember "github.com/kulikovroman08/reviewlink-backend/internal/repository/member"
+	repoPhoto "github.com/kulikovroman08/reviewlink-backend/internal/repository/photo"
+	repoPIN "github.com/kulikovroman08/reviewlink-backend/internal/repository/pin"
 	repoPlace "github.com/kulikovroman08/reviewlink-backend/internal/repository/place"
+	repoPOS "github.com/kulikovroman08/reviewlink-backend/internal/repository/pos"
+	repoRefill "github.com/kulikovroman08/reviewlink-backend/internal/repository/refill"
+	repoRefresh "github.com/kulikovroman08/reviewlink-backend/internal/repository/refresh"
+	repoReply "github.com/kulikovroman08/reviewlink-backend/internal/repository/reply"
+	repoReport "github.com/kulikovroman08/reviewlink-backend/internal/repository/report"
 	restrictionRepo "github.com/kulikovroman08/reviewlink-backend/internal/repository/restriction"
 	repoReview "github.com/kulikovroman08/reviewlink-backend/internal/repository/review"
+	repoRevision "github.com/kulikovroman08/reviewlink-backend/internal/repository/revision"
+	repoScan "github.com/kulikovroman08/reviewlink-backend/internal/repository/scan"
 	repoToken "github.com/kulikovroman08/reviewlink-backend/internal/repository/token"
+	repoTokenJob "github.com/kulikovroman08/reviewlink-backend/internal/repository/tokenjob"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
 	repoUser "github.com/kulikovroman08/reviewlink-backend/internal/repository/user"
+	repoVote "github.com/kulikovroman08/reviewlink-backend/internal/repository/vote"
 	svcAdmin "github.com/kulikovroman08/reviewlink-backend/internal/service/admin"
 	svcBonus "github.com/kulikovroman08/reviewlink-backend/internal/service/bonus"
+	svcKiosk "github.com/kulikovroman08/reviewlink-backend/internal/service/kiosk"
 	svcLeaderboard "github.com/kulikovroman08/reviewlink-backend/internal/service/leaderboard"
+	svcLink "github.com/kulikovroman08/reviewlink-backend/internal/service/link"
+	svcMember "github.com/kulikovroman08/reviewlink-backend/internal/service/member"
+	svcPhoto "github.com/kulikovroman08/reviewlink-backend/internal/service/photo"
 	svcPlace "github.com/kulikovroman08/reviewlink-backend/internal/service/place"
+	svcPOS "github.com/kulikovroman08/reviewlink-backend/internal/service/pos"
+	svcReply "github.com/kulikovroman08/reviewlink-backend/internal/service/reply"
+	svcReport "github.com/kulikovroman08/reviewlink-backend/internal/service/report"
 	svcReview "github.com/kulikovroman08/reviewlink-backend/internal/service/review"
 	svcToken "github.com/kulikovroman08/reviewlink-backend/internal/service/token"
 	svcUser "github.com/kulikovroman08/reviewlink-backend/internal/service/user"
+	svcVote "github.com/kulikovroman08/reviewlink-backend/internal/service/vote"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/blobstore"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/contentfilter"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/qr"
 )
 
-func InitApp(cfg *configs.Config) *gin.Engine {
-	dbpool, err := pgxpool.New(context.Background(), cfg.DBUrl)
+// InitApp собирает зависимости и запускает фоновые воркеры; воркеры останавливаются при отмене ctx
+func InitApp(ctx context.Context, cfg *configs.Config) *gin.Engine {
+	dbpool, err := pgxpool.New(ctx, cfg.DBUrl)
 	if err != nil {
 		log.Fatalf("error connecting to db: %v", err)
 	}
@@ -40,14 +66,81 @@ func InitApp(cfg *configs.Config) *gin.Engine {
 	leaderboardRepo := repoLeaderboard.NewRepository(dbpool)
 	bonusRepo := bonusRepo.NewPostgresBonusRepository(dbpool)
 	restrictionRepo := restrictionRepo.NewPostgresUserRestrictionRepository(dbpool)
+	refreshRepo := repoRefresh.NewPostgresRefreshTokenRepository(dbpool)
+	memberRepo := repoMember.NewPostgresMemberRepository(dbpool)
+	kioskRepo := repoKiosk.NewPostgresKioskRepository(dbpool)
+	refillRepo := repoRefill.NewPostgresRefillRepository(dbpool)
+	tokenJobRepo := repoTokenJob.NewPostgresTokenJobRepository(dbpool)
+	posRepo := repoPOS.NewPostgresPOSRepository(dbpool)
+	scanRepo := repoScan.NewPostgresScanRepository(dbpool)
+	pinRepo := repoPIN.NewPostgresPINAttemptRepository(dbpool)
+	replyRepo := repoReply.NewPostgresReplyRepository(dbpool)
+	photoRepo := repoPhoto.NewPostgresPhotoRepository(dbpool)
+	voteRepo := repoVote.NewPostgresVoteRepository(dbpool)
+	reportRepo := repoReport.NewPostgresReportRepository(dbpool)
+	revisionRepo := repoRevision.NewPostgresRevisionRepository(dbpool)
+	txManager := transaction.NewManager(dbpool)
 
-	tokenService := svcToken.NewTokenService(tokenRepo, cfg)
-	userService := svcUser.NewUserService(userRepo, reviewRepo, bonusRepo)
-	placeService := svcPlace.NewPlaceService(placeRepo, tokenService, cfg)
-	reviewService := svcReview.NewReviewService(reviewRepo, userRepo, placeRepo, tokenService, restrictionRepo)
+	qrRenderer, err := qr.NewRenderer(cfg.QRRecoveryLevel)
+	if err != nil {
+		log.Fatalf("error creating qr renderer: %v", err)
+	}
+
+	tokenGenerator, err := svcToken.NewRandomGenerator(cfg.TokenAlphabet, cfg.TokenLength, cfg.TokenCheckChar)
+	if err != nil {
+		log.Fatalf("error creating token generator: %v", err)
+	}
+
+	if err := svcToken.ValidatePINLength(cfg.PINLength, cfg.TokenLength, cfg.TokenCheckChar); err != nil {
+		log.Fatalf("error validating pin config: %v", err)
+	}
+
+	contentFilter, err := contentfilter.Load(cfg.ContentFilterRules)
+	if err != nil {
+		log.Fatalf("error loading content filter rules: %v", err)
+	}
+
+	photoStore, err := blobstore.New(blobstore.Config{
+		Backend:      cfg.PhotoStorage,
+		LocalDir:     cfg.MediaDir,
+		LocalBaseURL: cfg.PublicBaseURL + "/media",
+		S3: blobstore.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PublicURL: cfg.S3PublicURL,
+		},
+	})
+	if err != nil {
+		log.Fatalf("error creating photo store: %v", err)
+	}
+
+	tokenService := svcToken.NewTokenService(tokenRepo, placeRepo, memberRepo, refillRepo, tokenJobRepo, txManager, qrRenderer, tokenGenerator, cfg)
+	kioskService := svcKiosk.NewKioskService(kioskRepo, placeRepo, memberRepo, qrRenderer, cfg)
+	userService := svcUser.NewUserService(userRepo, reviewRepo, bonusRepo, refreshRepo, cfg)
+	placeService := svcPlace.NewPlaceService(placeRepo, tokenService)
+	photoService := svcPhoto.NewPhotoService(photoRepo, reviewRepo, photoStore, txManager, cfg)
+	reviewService := svcReview.NewReviewService(reviewRepo, userRepo, placeRepo, memberRepo, tokenService, kioskService, restrictionRepo, pinRepo, contentFilter, photoService, revisionRepo, txManager, cfg)
 	adminService := svcAdmin.NewAdminService(adminRepo)
 	leaderboardService := svcLeaderboard.NewService(leaderboardRepo)
-	bonusService := svcBonus.NewBonusService(userRepo, bonusRepo, cfg)
+	bonusService := svcBonus.NewBonusService(userRepo, bonusRepo, memberRepo, qrRenderer, cfg)
+	memberService := svcMember.NewMemberService(memberRepo, userRepo, placeRepo)
+	posService := svcPOS.NewPOSService(posRepo, placeRepo, memberRepo, tokenService, cfg)
+	linkService := svcLink.NewLinkService(scanRepo, placeRepo, memberRepo, cfg)
+	replyService := svcReply.NewReplyService(replyRepo, reviewRepo, placeRepo, memberRepo)
+	voteService := svcVote.NewVoteService(voteRepo, reviewRepo)
+	reportService := svcReport.NewReportService(reportRepo, reviewRepo, userRepo, restrictionRepo, txManager, cfg)
+
+	refillWorker := svcToken.NewRefillWorker(tokenService, refillRepo, cfg)
+	go refillWorker.Run(ctx)
+
+	batchJobWorker := svcToken.NewBatchJobWorker(tokenService, tokenJobRepo, cfg)
+	go batchJobWorker.Run(ctx)
+
+	photoPurgeWorker := svcPhoto.NewPurgeWorker(photoService, cfg)
+	go photoPurgeWorker.Run(ctx)
 
 	app := controller.NewApplication(userService,
 		placeService,
@@ -56,7 +149,20 @@ func InitApp(cfg *configs.Config) *gin.Engine {
 		adminService,
 		leaderboardService,
 		bonusService,
+		memberService,
+		kioskService,
+		posService,
+		linkService,
+		replyService,
+		photoService,
+		voteService,
+		reportService,
 	)
 
-	return controller.SetupRouter(app)
+	router, err := controller.SetupRouter(app, cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("error setting up router: %v", err)
+	}
+
+	return router
 }
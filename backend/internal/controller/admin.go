@@ -1,16 +1,18 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
 	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
 )
 
 // GetStats godoc
 // @Summary      Получение общей статистики (только для админов)
-// @Description  Возвращает агрегированные данные: количество пользователей, отзывов, средний рейтинг и количество бонусов.
+// @Description  Возвращает агрегированные данные: количество пользователей, отзывов, средний рейтинг и количество бонусов. Требуется право **stats:read**.
 // @Tags         admins
 // @Produce      json
 // @Success      200  {object}  dto.AdminStatsResponse
@@ -19,12 +21,6 @@ import (
 // @Router       /admin/stats [get]
 // @Security     BearerAuth
 func (h *Application) GetStats(c *gin.Context) {
-	role := c.GetString("role")
-	if role != "admin" {
-		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
-		return
-	}
-
 	stats, err := h.AdminService.GetStats(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedLoadStats})
@@ -36,7 +32,49 @@ func (h *Application) GetStats(c *gin.Context) {
 		TotalReviews:  stats.TotalReviews,
 		AverageRating: stats.AverageRating,
 		TotalBonuses:  stats.TotalBonuses,
+
+		PendingReviews: stats.PendingReviews,
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// UpdateUserRole godoc
+// @Summary      Назначение роли пользователю (только для админов)
+// @Description  Меняет роль пользователя (user, staff, place_owner, admin). Требуется право **users:manage**. Новая роль действует после обновления токена.
+// @Tags         admins
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                     true  "User ID"
+// @Param        request  body      dto.UpdateUserRoleRequest  true  "Новая роль"
+// @Success      200      {object}  dto.MessageResponse "role updated"
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid role"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "user not found"
+// @Failure      500      {object}  dto.ErrorResponse "failed to update role"
+// @Router       /admin/users/{id}/role [patch]
+// @Security     BearerAuth
+func (h *Application) UpdateUserRole(c *gin.Context) {
+	var req dto.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	err := h.UserService.ChangeRole(c.Request.Context(), c.Param("id"), req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidRole):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidRole})
+
+		case errors.Is(err, serviceErrors.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrUserNotFound})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedUpdateRole})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "role updated"})
+}
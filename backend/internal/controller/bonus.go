@@ -90,7 +90,8 @@ func (h *Application) GetUserBonuses(ctx *gin.Context) {
 
 // ValidateBonus godoc
 // @Summary Валидация бонусного QR-кода
-// @Description Администратор активирует бонус по QR-токену
+// @Description Сотрудник активирует бонус по QR-токену. Требуется право **bonuses:validate** и членство в штате заведения бонуса.
+// @Description Для бонусов без привязки к заведению нужно передать place_id заведения, где бонус принимается.
 // @Tags bonuses
 // @Accept json
 // @Produce json
@@ -98,6 +99,7 @@ func (h *Application) GetUserBonuses(ctx *gin.Context) {
 // @Security BearerAuth
 // @Success 200 {object} dto.BonusValidateResponse
 // @Failure 400 {object} dto.ErrorResponse "Некорректный запрос"
+// @Failure 403 {object} dto.ErrorResponse "access denied / not a member of the bonus place / bonus belongs to another place"
 // @Failure 404 {object} dto.ErrorResponse "QR не найден"
 // @Failure 409 {object} dto.ErrorResponse "Бонус уже использован"
 // @Router /bonuses/validate [post]
@@ -108,13 +110,19 @@ func (h *Application) ValidateBonus(ctx *gin.Context) {
 		return
 	}
 
-	err := h.BonusService.ValidateBonus(ctx, req.QRToken)
+	err := h.BonusService.ValidateBonus(ctx, ctx.GetString("user_id"), ctx.GetString("role"), req.PlaceID, req.QRToken)
 	if err != nil {
 		switch {
 		case errors.Is(err, srvErrors.ErrBonusNotFound):
 			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrBonusNotFound})
 		case errors.Is(err, srvErrors.ErrBonusAlreadyUsed):
 			ctx.JSON(http.StatusConflict, dto.ErrorResponse{Error: response.ErrBonusAlreadyUsed})
+		case errors.Is(err, srvErrors.ErrInvalidPlaceID):
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrPlaceIDRequired})
+		case errors.Is(err, srvErrors.ErrBonusPlaceMismatch):
+			ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrBonusWrongPlace})
+		case errors.Is(err, srvErrors.ErrNotPlaceMember):
+			ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrNotPlaceMember})
 		default:
 			ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrInternalError})
 		}
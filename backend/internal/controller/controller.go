@@ -12,6 +12,14 @@ type Application struct {
 	AdminService       service.AdminService
 	LeaderboardService service.LeaderboardService
 	BonusService       service.BonusService
+	MemberService      service.MemberService
+	KioskService       service.KioskService
+	POSService         service.POSService
+	LinkService        service.LinkService
+	ReplyService       service.ReplyService
+	PhotoService       service.PhotoService
+	VoteService        service.VoteService
+	ReportService      service.ReportService
 }
 
 func NewApplication(
@@ -22,6 +30,14 @@ func NewApplication(
 	admin service.AdminService,
 	leaderboard service.LeaderboardService,
 	bonus service.BonusService,
+	member service.MemberService,
+	kiosk service.KioskService,
+	pos service.POSService,
+	link service.LinkService,
+	reply service.ReplyService,
+	photo service.PhotoService,
+	vote service.VoteService,
+	report service.ReportService,
 ) *Application {
 	return &Application{
 		UserService:        user,
@@ -31,5 +47,13 @@ func NewApplication(
 		AdminService:       admin,
 		LeaderboardService: leaderboard,
 		BonusService:       bonus,
+		MemberService:      member,
+		KioskService:       kiosk,
+		POSService:         pos,
+		LinkService:        link,
+		ReplyService:       reply,
+		PhotoService:       photo,
+		VoteService:        vote,
+		ReportService:      report,
 	}
 }
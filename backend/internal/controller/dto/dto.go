@@ -14,7 +14,13 @@ type SignupRequest struct {
 }
 
 type AuthResponse struct {
-	Token string `json:"token"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 type LoginRequest struct {
@@ -37,9 +43,42 @@ type UserResponse struct {
 	Points int    `json:"points"`
 }
 
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
 type CreatePlaceRequest struct {
-	Name    string `json:"name" binding:"required"`
-	Address string `json:"address" binding:"required"`
+	Name        string              `json:"name" binding:"required"`
+	Address     string              `json:"address" binding:"required"`
+	TokenPolicy *TokenPolicyRequest `json:"token_policy"`
+}
+
+// TokenPolicyRequest — политика токенов заведения; не переданное поле берётся из глобального конфига
+type TokenPolicyRequest struct {
+	TTLHours        *int `json:"ttl_hours" binding:"omitempty,min=1,max=8760"`
+	InitialCount    *int `json:"initial_count" binding:"omitempty,min=0,max=100"`
+	RefillThreshold *int `json:"refill_threshold" binding:"omitempty,min=0,max=1000"`
+	RefillBatch     *int `json:"refill_batch" binding:"omitempty,min=1,max=100"`
+}
+
+type TokenPolicyOverrides struct {
+	TTLHours        *int `json:"ttl_hours"`
+	InitialCount    *int `json:"initial_count"`
+	RefillThreshold *int `json:"refill_threshold"`
+	RefillBatch     *int `json:"refill_batch"`
+}
+
+type TokenPolicyValues struct {
+	TTLHours        int `json:"ttl_hours"`
+	InitialCount    int `json:"initial_count"`
+	RefillThreshold int `json:"refill_threshold"`
+	RefillBatch     int `json:"refill_batch"`
+}
+
+type TokenPolicyResponse struct {
+	PlaceID   string               `json:"place_id"`
+	Custom    TokenPolicyOverrides `json:"custom"`
+	Effective TokenPolicyValues    `json:"effective"`
 }
 
 type CreatePlaceResponse struct {
@@ -54,6 +93,7 @@ type PlaceResponse struct {
 }
 
 type SubmitReviewRequest struct {
+	// Token — значение токена, код с планшета (K-…) или PIN-код для ручного ввода
 	Token   string    `json:"token" binding:"required"`
 	PlaceID uuid.UUID `json:"place_id" binding:"required"`
 	Rating  int       `json:"rating" binding:"required,min=1,max=5"`
@@ -65,25 +105,131 @@ type UpdateReviewRequest struct {
 	Rating  int    `json:"rating"`
 }
 
+type SubmitReviewResponse struct {
+	ID               string    `json:"id"`
+	PlaceID          string    `json:"place_id"`
+	Rating           int       `json:"rating"`
+	Content          string    `json:"content"`
+	CreatedAt        time.Time `json:"created_at"`
+	VerifiedPurchase bool      `json:"verified_purchase"`
+	ReceiptID        *string   `json:"receipt_id,omitempty"`
+	// Status — pending, если заведение работает по премодерации
+	Status string `json:"status"`
+}
+
 type ReviewResponse struct {
-	Rating    int       `json:"rating"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               string    `json:"id"`
+	Rating           int       `json:"rating"`
+	Content          string    `json:"content"`
+	CreatedAt        time.Time `json:"created_at"`
+	VerifiedPurchase bool      `json:"verified_purchase"`
+	// Reply — публичный ответ заведения, если он есть
+	Reply *ReplyResponse `json:"reply,omitempty"`
+	// Photos — фотографии к отзыву в порядке загрузки
+	Photos []PhotoResponse `json:"photos"`
+	// HelpfulCount — число отметок «полезный отзыв»
+	HelpfulCount int `json:"helpful_count"`
+	// Edited — автор правил отзыв после публикации, EditedAt — время последней правки
+	Edited   bool       `json:"edited"`
+	EditedAt *time.Time `json:"edited_at,omitempty"`
 }
 
 type GenerateTokensRequest struct {
 	PlaceID string `json:"place_id" binding:"required,uuid"`
 	Count   int    `json:"count" binding:"required,min=1,max=100"`
+	// Необязательные метки атрибуции: сотрудник заведения, номер стола, тег кампании
+	StaffID  string  `json:"staff_id" binding:"omitempty,uuid"`
+	Table    *string `json:"table" binding:"omitempty,max=32"`
+	Campaign *string `json:"campaign" binding:"omitempty,max=64"`
+}
+
+type PrintTokensRequest struct {
+	Count    int    `json:"count" binding:"required,min=1,max=100"`
+	PageSize string `json:"page_size" binding:"omitempty,oneof=A4 Letter"`
+	Columns  int    `json:"columns" binding:"omitempty,min=1,max=6"`
+	Rows     int    `json:"rows" binding:"omitempty,min=1,max=12"`
+}
+
+type TokenResponse struct {
+	ID        string     `json:"id"`
+	Token     string     `json:"token"`
+	Status    string     `json:"status"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type TokenListResponse struct {
+	Tokens []TokenResponse `json:"tokens"`
+	Total  int             `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+type TokenStatsResponse struct {
+	Issued  int `json:"issued"`
+	Used    int `json:"used"`
+	Expired int `json:"expired"`
+	Active  int `json:"active"`
+	Revoked int `json:"revoked"`
+}
+
+type BulkTokensRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=500,dive,uuid"`
+}
+
+type ExtendTokensRequest struct {
+	IDs   []string `json:"ids" binding:"required,min=1,max=500,dive,uuid"`
+	Hours int      `json:"hours" binding:"required,min=1,max=8760"`
+}
+
+type BulkTokensResponse struct {
+	Affected int `json:"affected"`
+}
+
+// CreateTokenJobRequest — фоновая генерация большой пачки; синхронный /admin/tokens ограничен 100 токенами
+type CreateTokenJobRequest struct {
+	PlaceID string `json:"place_id" binding:"required,uuid"`
+	Count   int    `json:"count" binding:"required,min=1,max=100000"`
+}
+
+type TokenJobResponse struct {
+	ID         string     `json:"id"`
+	PlaceID    string     `json:"place_id"`
+	Status     string     `json:"status"`
+	Total      int        `json:"total"`
+	Generated  int        `json:"generated"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	Error      *string    `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
 }
 
 type GenerateTokensResponse struct {
 	Tokens []string `json:"tokens"`
 }
 
+type TokenInfoResponse struct {
+	Token     string    `json:"token"`
+	PlaceID   string    `json:"place_id"`
+	PlaceName string    `json:"place_name"`
+	Valid     bool      `json:"valid"`
+	Used      bool      `json:"used"`
+	Revoked   bool      `json:"revoked"`
+	Expired   bool      `json:"expired"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// TokenPINResponse — PIN-код токена для ручного ввода в форме отзыва
+type TokenPINResponse struct {
+	TokenID string `json:"token_id"`
+	PIN     string `json:"pin"`
+}
+
 type MessageResponse struct {
 	Message string `json:"message"`
 }
@@ -93,6 +239,8 @@ type AdminStatsResponse struct {
 	TotalReviews  int     `json:"total_reviews"`
 	AverageRating float64 `json:"average_rating"`
 	TotalBonuses  int     `json:"total_bonuses"`
+	// PendingReviews — отзывы в очереди модерации, в total_reviews не входят
+	PendingReviews int `json:"pending_reviews"`
 }
 
 type LeaderboardEntry struct {
@@ -128,12 +276,43 @@ type BonusResponse struct {
 
 type BonusValidateRequest struct {
 	QRToken string `json:"qr_token" binding:"required"`
+	PlaceID string `json:"place_id" binding:"omitempty,uuid"`
 }
 
 type BonusValidateResponse struct {
 	Status string `json:"status"`
 }
 
+type AddPlaceMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=owner manager cashier"`
+}
+
+type PlaceMemberResponse struct {
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type EnableKioskRequest struct {
+	StepSeconds int `json:"step_seconds" binding:"omitempty,min=10,max=600"`
+}
+
+type KioskResponse struct {
+	PlaceID     string `json:"place_id"`
+	Enabled     bool   `json:"enabled"`
+	StepSeconds int    `json:"step_seconds"`
+}
+
+type KioskCodeResponse struct {
+	Code        string    `json:"code"`
+	URL         string    `json:"url"`
+	ValidUntil  time.Time `json:"valid_until"`
+	StepSeconds int       `json:"step_seconds"`
+}
+
 type UserRestrictionResponse struct {
 	RestrictionType string    `json:"restriction_type"`
 	Reason          string    `json:"reason"`
@@ -154,3 +333,193 @@ type BonusLeaderboardEntry struct {
 	BonusesCount int    `json:"bonuses_count"`
 	PointsSpent  int    `json:"points_spent"`
 }
+
+// ReceiptEventRequest — событие о покупке от кассовой системы. amount — в копейках.
+type ReceiptEventRequest struct {
+	PlaceID     string    `json:"place_id" binding:"required,uuid"`
+	ReceiptID   string    `json:"receipt_id" binding:"required,max=128"`
+	Amount      int64     `json:"amount" binding:"min=0"`
+	PurchasedAt time.Time `json:"purchased_at" binding:"required"`
+}
+
+type ReceiptTokenResponse struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	PIN       string    `json:"pin,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type POSIntegrationResponse struct {
+	PlaceID   string    `json:"place_id"`
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MarkScanOpenedRequest — ключ сессии перехода из параметра scan_key адреса формы
+type MarkScanOpenedRequest struct {
+	Key string `json:"key" binding:"required,max=64"`
+}
+
+// FunnelResponse — воронка по токенам, отсканированным в периоде. Доли считаются от scanned_tokens.
+type FunnelResponse struct {
+	PlaceID       string    `json:"place_id"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Scans         int       `json:"scans"`
+	ScannedTokens int       `json:"scanned_tokens"`
+	Logins        int       `json:"logins"`
+	Reviews       int       `json:"reviews"`
+	Points        int       `json:"points"`
+	LoginRate     float64   `json:"login_rate"`
+	ReviewRate    float64   `json:"review_rate"`
+}
+
+// LabelStatResponse — строка отчёта по меткам; staff_name только в разрезе по сотрудникам
+type LabelStatResponse struct {
+	Label         string  `json:"label"`
+	StaffName     string  `json:"staff_name,omitempty"`
+	Reviews       int     `json:"reviews"`
+	AverageRating float64 `json:"average_rating"`
+}
+
+// ModeratedReviewResponse — отзыв в очереди модерации или после решения модератора
+type ModeratedReviewResponse struct {
+	ID               string     `json:"id"`
+	PlaceID          string     `json:"place_id"`
+	UserID           string     `json:"user_id"`
+	Rating           int        `json:"rating"`
+	Content          string     `json:"content"`
+	CreatedAt        time.Time  `json:"created_at"`
+	VerifiedPurchase bool       `json:"verified_purchase"`
+	Points           int        `json:"points"`
+	Status           string     `json:"status"`
+	ModerationReason *string    `json:"moderation_reason,omitempty"`
+	ModeratedBy      *string    `json:"moderated_by,omitempty"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty"`
+	// FilterRule — правило фильтра текста, из-за которого отзыв попал к модератору
+	FilterRule *string `json:"filter_rule,omitempty"`
+}
+
+// ModerationDecisionRequest — причина решения: обязательна при отклонении, необязательна при скрытии
+type ModerationDecisionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ModerationModeRequest — режим модерации заведения: pre, post или null для режима из конфига
+type ModerationModeRequest struct {
+	Mode *string `json:"mode"`
+}
+
+type ModerationSettingsResponse struct {
+	PlaceID   string  `json:"place_id"`
+	Mode      *string `json:"mode"`
+	Effective string  `json:"effective"`
+}
+
+// ReplyRequest — текст ответа заведения на отзыв, до 1000 символов
+type ReplyRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+type ReplyResponse struct {
+	ID        string     `json:"id"`
+	ReviewID  string     `json:"review_id"`
+	Content   string     `json:"content"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// ReplyTemplateRequest — шаблон ответа: название до 64 символов, уникальное в заведении, и текст до 1000 символов
+type ReplyTemplateRequest struct {
+	Title   string `json:"title" binding:"required"`
+	Content string `json:"content" binding:"required"`
+}
+
+type ReplyTemplateResponse struct {
+	ID        string    `json:"id"`
+	PlaceID   string    `json:"place_id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReplyStatsResponse — ответы на опубликованные отзывы за период. reply_rate считается от reviews,
+// median_response_seconds отсутствует, если ответов нет.
+type ReplyStatsResponse struct {
+	PlaceID               string    `json:"place_id"`
+	From                  time.Time `json:"from"`
+	To                    time.Time `json:"to"`
+	Reviews               int       `json:"reviews"`
+	Replied               int       `json:"replied"`
+	ReplyRate             float64   `json:"reply_rate"`
+	MedianResponseSeconds *int64    `json:"median_response_seconds,omitempty"`
+}
+
+type PhotoResponse struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
+
+type UploadPhotosResponse struct {
+	Photos []PhotoResponse `json:"photos"`
+}
+
+type ReviewVoteResponse struct {
+	ReviewID     string `json:"review_id"`
+	HelpfulCount int    `json:"helpful_count"`
+	Voted        bool   `json:"voted"`
+}
+
+// CreateReportRequest — жалоба на отзыв: причина spam, offensive, fake или off_topic и необязательный комментарий до 500 символов
+type CreateReportRequest struct {
+	Reason  string `json:"reason" binding:"required"`
+	Comment string `json:"comment"`
+}
+
+type ReportResponse struct {
+	ID         string     `json:"id"`
+	ReviewID   string     `json:"review_id"`
+	ReporterID string     `json:"reporter_id"`
+	Reason     string     `json:"reason"`
+	Comment    *string    `json:"comment,omitempty"`
+	Status     string     `json:"status"`
+	Resolution *string    `json:"resolution,omitempty"`
+	ResolvedBy *string    `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ReportedReviewResponse — отзыв в очереди жалоб: число жалоб и их разбивка по причинам
+type ReportedReviewResponse struct {
+	Review          ModeratedReviewResponse `json:"review"`
+	Reports         int                     `json:"reports"`
+	Reasons         map[string]int          `json:"reasons"`
+	FirstReportedAt time.Time               `json:"first_reported_at"`
+	LastReportedAt  time.Time               `json:"last_reported_at"`
+}
+
+// ResolveReportsRequest — решение по жалобам: dismiss, hide или hide_and_restrict
+type ResolveReportsRequest struct {
+	Action string `json:"action" binding:"required"`
+}
+
+type ReportResolutionResponse struct {
+	ReviewID     string `json:"review_id"`
+	Action       string `json:"action"`
+	Resolved     int    `json:"resolved"`
+	ReviewStatus string `json:"review_status"`
+	// RestrictedUntil — до какого момента автору запрещено оставлять отзывы
+	RestrictedUntil *time.Time `json:"restricted_until,omitempty"`
+}
+
+// ReviewRevisionResponse — редакция отзыва; revision 1 — текст при создании
+type ReviewRevisionResponse struct {
+	Revision  int       `json:"revision"`
+	Content   string    `json:"content"`
+	Rating    int       `json:"rating"`
+	CreatedAt time.Time `json:"created_at"`
+}
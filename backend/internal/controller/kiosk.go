@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// EnableKiosk godoc
+// @Summary      Включение режима киоска
+// @Description  Включает планшет с QR-кодом, который меняется каждые step_seconds секунд (по умолчанию из конфига). Повторный вызов перевыпускает секрет заведения. Доступно админам и владельцам заведения.
+// @Tags         kiosk
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true   "Place ID"
+// @Param        request  body      dto.EnableKioskRequest  false  "Шаг смены кода"
+// @Success      200      {object}  dto.KioskResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid place id / invalid kiosk code step"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "place not found"
+// @Failure      500      {object}  dto.ErrorResponse "failed to manage kiosk"
+// @Router       /places/{id}/kiosk [put]
+// @Security     BearerAuth
+func (h *Application) EnableKiosk(c *gin.Context) {
+	var req dto.EnableKioskRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+			return
+		}
+	}
+
+	kiosk, err := h.KioskService.EnableKiosk(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+		time.Duration(req.StepSeconds)*time.Second,
+	)
+	if err != nil {
+		h.handleKioskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.KioskResponse{
+		PlaceID:     kiosk.PlaceID.String(),
+		Enabled:     kiosk.Enabled,
+		StepSeconds: int(kiosk.Step / time.Second),
+	})
+}
+
+// DisableKiosk godoc
+// @Summary      Выключение режима киоска
+// @Description  Коды с планшета перестают приниматься сразу. Доступно админам и владельцам заведения.
+// @Tags         kiosk
+// @Produce      json
+// @Param        id   path      string  true  "Place ID"
+// @Success      200  {object}  dto.MessageResponse "kiosk disabled"
+// @Failure      400  {object}  dto.ErrorResponse "invalid place id"
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "place not found / kiosk mode is not enabled"
+// @Failure      500  {object}  dto.ErrorResponse "failed to manage kiosk"
+// @Router       /places/{id}/kiosk [delete]
+// @Security     BearerAuth
+func (h *Application) DisableKiosk(c *gin.Context) {
+	err := h.KioskService.DisableKiosk(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+	)
+	if err != nil {
+		h.handleKioskError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "kiosk disabled"})
+}
+
+// GetKioskCode godoc
+// @Summary      Текущий код киоска
+// @Description  Код текущего окна и ссылка на форму отзыва для показа на планшете. Доступно сотрудникам заведения.
+// @Tags         kiosk
+// @Produce      json
+// @Param        id   path      string  true  "Place ID"
+// @Success      200  {object}  dto.KioskCodeResponse
+// @Failure      400  {object}  dto.ErrorResponse "invalid place id"
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "place not found / kiosk mode is not enabled"
+// @Failure      500  {object}  dto.ErrorResponse "failed to manage kiosk"
+// @Router       /places/{id}/kiosk/code [get]
+// @Security     BearerAuth
+func (h *Application) GetKioskCode(c *gin.Context) {
+	code, err := h.KioskService.CurrentCode(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+	)
+	if err != nil {
+		h.handleKioskError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, dto.KioskCodeResponse{
+		Code:        code.Code,
+		URL:         code.URL,
+		ValidUntil:  code.ValidUntil,
+		StepSeconds: int(code.Step / time.Second),
+	})
+}
+
+// GetKioskQR godoc
+// @Summary      QR-код киоска
+// @Description  QR-код текущего окна со ссылкой на форму отзыва. Доступно сотрудникам заведения.
+// @Tags         kiosk
+// @Produce      png
+// @Produce      image/svg+xml
+// @Param        id      path      string  true   "Place ID"
+// @Param        format  query     string  false  "png или svg (по умолчанию png)"
+// @Param        size    query     int     false  "Размер в пикселях, 64–1024 (по умолчанию 256)"
+// @Success      200     {file}    binary
+// @Failure      400     {object}  dto.ErrorResponse "invalid qr parameters / invalid place id"
+// @Failure      403     {object}  dto.ErrorResponse "access denied"
+// @Failure      404     {object}  dto.ErrorResponse "place not found / kiosk mode is not enabled"
+// @Failure      500     {object}  dto.ErrorResponse "failed to render qr"
+// @Router       /places/{id}/kiosk/qr [get]
+// @Security     BearerAuth
+func (h *Application) GetKioskQR(c *gin.Context) {
+	size, ok := parseQRSize(c)
+	if !ok {
+		return
+	}
+
+	img, err := h.KioskService.RenderCodeQR(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+		c.Query("format"),
+		size,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidPlaceID),
+			errors.Is(err, serviceErrors.ErrPlaceNotFound),
+			errors.Is(err, serviceErrors.ErrAccessDenied),
+			errors.Is(err, serviceErrors.ErrKioskNotEnabled):
+			h.handleKioskError(c, err)
+
+		default:
+			handleQRError(c, err)
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, img.ContentType, img.Data)
+}
+
+func (h *Application) handleKioskError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, serviceErrors.ErrInvalidPlaceID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+
+	case errors.Is(err, serviceErrors.ErrInvalidKioskStep):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidKioskStep})
+
+	case errors.Is(err, serviceErrors.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+	case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
+
+	case errors.Is(err, serviceErrors.ErrKioskNotEnabled):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrKioskNotEnabled})
+
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedManageKiosk})
+	}
+}
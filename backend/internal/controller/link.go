@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// FollowShortLink godoc
+// @Summary      Переход по короткой ссылке из QR-кода
+// @Description  Записывает переход (время и User-Agent) и перенаправляет на форму отзыва. В адрес формы добавляются параметры scan с ID перехода и scan_key с ключом сессии. Повторные переходы одного клиента (IP и User-Agent) по токену в окне SCAN_DEDUP_WINDOW считаются одним переходом.
+// @Tags         tokens
+// @Param        code  path  string  true  "Значение токена"
+// @Success      302
+// @Failure      404   {object}  dto.ErrorResponse "invalid token"
+// @Failure      500   {object}  dto.ErrorResponse "failed to track scan"
+// @Router       /r/{code} [get]
+func (h *Application) FollowShortLink(c *gin.Context) {
+	target, err := h.LinkService.Resolve(c.Request.Context(), c.Param("code"), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidToken):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrInvalidToken})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedTrackScan})
+		}
+		return
+	}
+
+	c.Redirect(http.StatusFound, target)
+}
+
+// MarkScanOpened godoc
+// @Summary      Вход после перехода по QR-коду
+// @Description  Форма отзыва вызывает после входа пользователя, чтобы переход попал в воронку как «вход». Нужен ключ scan_key из адреса формы: без него или с чужим ключом переход не найдётся. Повторные вызовы ничего не меняют.
+// @Tags         tokens
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                     true  "Scan ID"
+// @Param        request  body      dto.MarkScanOpenedRequest  true  "Ключ сессии перехода"
+// @Success      200      {object}  dto.MessageResponse "scan opened"
+// @Failure      400      {object}  dto.ErrorResponse "invalid input"
+// @Failure      404      {object}  dto.ErrorResponse "scan not found"
+// @Failure      500      {object}  dto.ErrorResponse "failed to track scan"
+// @Router       /scans/{id}/open [post]
+// @Security     BearerAuth
+func (h *Application) MarkScanOpened(c *gin.Context) {
+	var req dto.MarkScanOpenedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	err := h.LinkService.MarkOpened(c.Request.Context(), c.GetString("user_id"), c.Param("id"), req.Key)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrScanNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrScanNotFound})
+
+		case errors.Is(err, serviceErrors.ErrAccessDenied):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedTrackScan})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "scan opened"})
+}
+
+// GetPlaceFunnel godoc
+// @Summary      Воронка «скан → вход → отзыв» заведения
+// @Description  Считается по токенам, отсканированным за период: переходы, разные токены, входы, отзывы и баллы за опубликованные из них. Период по умолчанию — последние 30 дней, to включает весь день. Доступно админам, владельцам и менеджерам заведения; кассирам — нет.
+// @Tags         places
+// @Produce      json
+// @Param        id    path      string  true   "Place ID"
+// @Param        from  query     string  false  "Начало периода (YYYY-MM-DD)"
+// @Param        to    query     string  false  "Конец периода включительно (YYYY-MM-DD)"
+// @Success      200   {object}  dto.FunnelResponse
+// @Failure      400   {object}  dto.ErrorResponse "invalid input / invalid place id / invalid period"
+// @Failure      403   {object}  dto.ErrorResponse "access denied"
+// @Failure      404   {object}  dto.ErrorResponse "place not found"
+// @Failure      500   {object}  dto.ErrorResponse "failed to get funnel"
+// @Router       /places/{id}/funnel [get]
+// @Security     BearerAuth
+func (h *Application) GetPlaceFunnel(c *gin.Context) {
+	from, to, err := parsePeriod(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	funnel, err := h.LinkService.GetFunnel(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+		from,
+		to,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidPlaceID):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+
+		case errors.Is(err, serviceErrors.ErrInvalidPeriod):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPeriod})
+
+		case errors.Is(err, serviceErrors.ErrAccessDenied):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+		case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedGetFunnel})
+		}
+		return
+	}
+
+	resp := dto.FunnelResponse{
+		PlaceID:       funnel.PlaceID.String(),
+		From:          funnel.From,
+		To:            funnel.To,
+		Scans:         funnel.Scans,
+		ScannedTokens: funnel.ScannedTokens,
+		Logins:        funnel.Logins,
+		Reviews:       funnel.Reviews,
+		Points:        funnel.Points,
+	}
+	if funnel.ScannedTokens > 0 {
+		resp.LoginRate = float64(funnel.Logins) / float64(funnel.ScannedTokens)
+		resp.ReviewRate = float64(funnel.Reviews) / float64(funnel.ScannedTokens)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// parsePeriod читает необязательные from и to (YYYY-MM-DD) и возвращает полуинтервал [from, to):
+// день to входит в период целиком
+func parsePeriod(c *gin.Context) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid from date: %w", err)
+		}
+		from = &t
+	}
+
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid to date: %w", err)
+		}
+		t = t.AddDate(0, 0, 1)
+		to = &t
+	}
+
+	return from, to, nil
+}
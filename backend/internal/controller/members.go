@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// AddPlaceMember godoc
+// @Summary      Приглашение сотрудника в заведение
+// @Description  Добавляет пользователя в штат заведения с ролью owner, manager или cashier. Доступно админам и владельцам заведения; назначать владельцев может только админ.
+// @Tags         members
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                     true  "Place ID"
+// @Param        request  body      dto.AddPlaceMemberRequest  true  "Email пользователя и роль"
+// @Success      201      {object}  dto.PlaceMemberResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid place id"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "place not found / user not found"
+// @Failure      409      {object}  dto.ErrorResponse "member already exists"
+// @Failure      500      {object}  dto.ErrorResponse "failed to manage members"
+// @Router       /places/{id}/members [post]
+// @Security     BearerAuth
+func (h *Application) AddPlaceMember(c *gin.Context) {
+	var req dto.AddPlaceMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	member, err := h.MemberService.AddMember(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+		req.Email,
+		req.Role,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrUserNotFound})
+
+		case errors.Is(err, serviceErrors.ErrMemberAlreadyExists):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: response.ErrMemberAlreadyExists})
+
+		default:
+			h.handleMemberError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.PlaceMemberResponse{
+		UserID:    member.UserID.String(),
+		Name:      member.UserName,
+		Email:     member.UserEmail,
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+	})
+}
+
+// GetPlaceMembers godoc
+// @Summary      Список сотрудников заведения
+// @Description  Доступно админам и владельцам заведения.
+// @Tags         members
+// @Produce      json
+// @Param        id   path      string  true  "Place ID"
+// @Success      200  {array}   dto.PlaceMemberResponse
+// @Failure      400  {object}  dto.ErrorResponse "invalid place id"
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "place not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to manage members"
+// @Router       /places/{id}/members [get]
+// @Security     BearerAuth
+func (h *Application) GetPlaceMembers(c *gin.Context) {
+	members, err := h.MemberService.ListMembers(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+	)
+	if err != nil {
+		h.handleMemberError(c, err)
+		return
+	}
+
+	resp := make([]dto.PlaceMemberResponse, 0, len(members))
+	for _, m := range members {
+		resp = append(resp, dto.PlaceMemberResponse{
+			UserID:    m.UserID.String(),
+			Name:      m.UserName,
+			Email:     m.UserEmail,
+			Role:      m.Role,
+			CreatedAt: m.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RemovePlaceMember godoc
+// @Summary      Исключение сотрудника из заведения
+// @Description  Доступно админам и владельцам заведения; исключать владельцев может только админ. Глобальная роль исключённого пересчитывается по оставшимся заведениям.
+// @Tags         members
+// @Produce      json
+// @Param        id       path      string  true  "Place ID"
+// @Param        user_id  path      string  true  "User ID"
+// @Success      200      {object}  dto.MessageResponse "member removed"
+// @Failure      400      {object}  dto.ErrorResponse "invalid place id / invalid user_id"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "place not found / member not found"
+// @Failure      500      {object}  dto.ErrorResponse "failed to manage members"
+// @Router       /places/{id}/members/{user_id} [delete]
+// @Security     BearerAuth
+func (h *Application) RemovePlaceMember(c *gin.Context) {
+	err := h.MemberService.RemoveMember(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+		c.Param("user_id"),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidUserID):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidUserID})
+
+		case errors.Is(err, serviceErrors.ErrMemberNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrMemberNotFound})
+
+		default:
+			h.handleMemberError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "member removed"})
+}
+
+func (h *Application) handleMemberError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, serviceErrors.ErrInvalidPlaceID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+
+	case errors.Is(err, serviceErrors.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidRole})
+
+	case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
+
+	case errors.Is(err, serviceErrors.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedManageMembers})
+	}
+}
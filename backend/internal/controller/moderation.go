@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// GetModerationQueue godoc
+// @Summary      Очередь модерации отзывов
+// @Description  Страница отзывов в указанном статусе (по умолчанию pending), старые первыми. Фильтр по заведению необязателен. Следующая страница запрашивается с cursor из заголовка X-Next-Cursor и теми же фильтрами. Требуется право **reviews:moderate**.
+// @Tags         admins
+// @Produce      json
+// @Param        status    query     string  false  "pending, published, rejected или hidden"
+// @Param        place_id  query     string  false  "Place ID"
+// @Param        limit     query     int     false  "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param        cursor    query     string  false  "Курсор следующей страницы из заголовка X-Next-Cursor"
+// @Success      200       {array}   dto.ModeratedReviewResponse
+// @Header       200       {int}     X-Total-Count "Число отзывов под фильтром"
+// @Header       200       {string}  X-Next-Cursor "Курсор следующей страницы; на последней странице отсутствует"
+// @Failure      400       {object}  dto.ErrorResponse "invalid input / invalid place id / invalid review status / invalid cursor"
+// @Failure      403       {object}  dto.ErrorResponse "access denied"
+// @Failure      500       {object}  dto.ErrorResponse "failed to moderate review"
+// @Router       /admin/reviews [get]
+// @Security     BearerAuth
+func (h *Application) GetModerationQueue(c *gin.Context) {
+	page, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	filter := model.ModerationFilter{
+		Status: c.Query("status"),
+		Page:   page,
+	}
+	if v := c.Query("place_id"); v != "" {
+		placeID, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+			return
+		}
+		filter.PlaceID = &placeID
+	}
+
+	result, err := h.ReviewService.GetModerationQueue(c.Request.Context(), filter)
+	if err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	resp := make([]dto.ModeratedReviewResponse, 0, len(result.Items))
+	for i := range result.Items {
+		resp = append(resp, moderatedReviewResponse(&result.Items[i]))
+	}
+
+	setPageHeaders(c, result.Total, result.NextCursor)
+	c.JSON(http.StatusOK, resp)
+}
+
+// ApproveReview godoc
+// @Summary      Одобрение отзыва
+// @Description  Публикует ожидающий отзыв или возвращает скрытый. При публикации автору начисляются баллы за отзыв. Требуется право **reviews:moderate**.
+// @Tags         admins
+// @Produce      json
+// @Param        id   path      string  true  "Review ID"
+// @Success      200  {object}  dto.ModeratedReviewResponse
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "review not found"
+// @Failure      409  {object}  dto.ErrorResponse "review cannot be moved to this status"
+// @Failure      500  {object}  dto.ErrorResponse "failed to moderate review"
+// @Router       /admin/reviews/{id}/approve [post]
+// @Security     BearerAuth
+func (h *Application) ApproveReview(c *gin.Context) {
+	review, err := h.ReviewService.ApproveReview(c.Request.Context(), c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, moderatedReviewResponse(review))
+}
+
+// RejectReview godoc
+// @Summary      Отклонение отзыва
+// @Description  Окончательно отклоняет ожидающий, опубликованный или скрытый отзыв. Баллы за опубликованный отзыв списываются. Причина обязательна (до 500 символов). Требуется право **reviews:moderate**.
+// @Tags         admins
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                         true  "Review ID"
+// @Param        request  body      dto.ModerationDecisionRequest  true  "Причина"
+// @Success      200      {object}  dto.ModeratedReviewResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid moderation reason"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "review not found"
+// @Failure      409      {object}  dto.ErrorResponse "review cannot be moved to this status"
+// @Failure      500      {object}  dto.ErrorResponse "failed to moderate review"
+// @Router       /admin/reviews/{id}/reject [post]
+// @Security     BearerAuth
+func (h *Application) RejectReview(c *gin.Context) {
+	var req dto.ModerationDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	review, err := h.ReviewService.RejectReview(c.Request.Context(), c.GetString("user_id"), c.Param("id"), req.Reason)
+	if err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, moderatedReviewResponse(review))
+}
+
+// HideReview godoc
+// @Summary      Скрытие отзыва
+// @Description  Снимает опубликованный отзыв с публикации и списывает баллы автора; вернуть его можно одобрением. Причина необязательна. Требуется право **reviews:moderate**.
+// @Tags         admins
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                         true   "Review ID"
+// @Param        request  body      dto.ModerationDecisionRequest  false  "Причина"
+// @Success      200      {object}  dto.ModeratedReviewResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid moderation reason"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "review not found"
+// @Failure      409      {object}  dto.ErrorResponse "review cannot be moved to this status"
+// @Failure      500      {object}  dto.ErrorResponse "failed to moderate review"
+// @Router       /admin/reviews/{id}/hide [post]
+// @Security     BearerAuth
+func (h *Application) HideReview(c *gin.Context) {
+	var req dto.ModerationDecisionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+			return
+		}
+	}
+
+	review, err := h.ReviewService.HideReview(c.Request.Context(), c.GetString("user_id"), c.Param("id"), req.Reason)
+	if err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, moderatedReviewResponse(review))
+}
+
+// GetModerationSettings godoc
+// @Summary      Режим модерации заведения
+// @Description  mode — режим заведения (null — берётся из конфига), effective — действующий режим. Требуется право **reviews:moderate**.
+// @Tags         admins
+// @Produce      json
+// @Param        id   path      string  true  "Place ID"
+// @Success      200  {object}  dto.ModerationSettingsResponse
+// @Failure      400  {object}  dto.ErrorResponse "invalid place id"
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "place not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to moderate review"
+// @Router       /admin/places/{id}/moderation [get]
+// @Security     BearerAuth
+func (h *Application) GetModerationSettings(c *gin.Context) {
+	settings, err := h.ReviewService.GetModerationSettings(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, moderationSettingsResponse(c.Param("id"), settings))
+}
+
+// UpdateModerationSettings godoc
+// @Summary      Изменение режима модерации заведения
+// @Description  pre — отзывы публикуются после одобрения, post — сразу; null возвращает режим из конфига. Уже оставленные отзывы статус не меняют. Требуется право **reviews:moderate**.
+// @Tags         admins
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                     true  "Place ID"
+// @Param        request  body      dto.ModerationModeRequest  true  "Режим"
+// @Success      200      {object}  dto.ModerationSettingsResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid place id / invalid moderation mode"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "place not found"
+// @Failure      500      {object}  dto.ErrorResponse "failed to moderate review"
+// @Router       /admin/places/{id}/moderation [put]
+// @Security     BearerAuth
+func (h *Application) UpdateModerationSettings(c *gin.Context) {
+	var req dto.ModerationModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	settings, err := h.ReviewService.UpdateModerationMode(c.Request.Context(), c.Param("id"), req.Mode)
+	if err != nil {
+		handleModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, moderationSettingsResponse(c.Param("id"), settings))
+}
+
+func moderatedReviewResponse(review *model.Review) dto.ModeratedReviewResponse {
+	resp := dto.ModeratedReviewResponse{
+		ID:               review.ID.String(),
+		PlaceID:          review.PlaceID.String(),
+		UserID:           review.UserID.String(),
+		Rating:           review.Rating,
+		Content:          review.Content,
+		CreatedAt:        review.CreatedAt,
+		VerifiedPurchase: review.ReceiptID != nil,
+		Points:           review.Points,
+		Status:           review.Status,
+		ModerationReason: review.ModerationReason,
+		ModeratedAt:      review.ModeratedAt,
+		FilterRule:       review.FilterRule,
+	}
+	if review.ModeratedBy != nil {
+		moderatedBy := review.ModeratedBy.String()
+		resp.ModeratedBy = &moderatedBy
+	}
+	return resp
+}
+
+func moderationSettingsResponse(placeID string, settings *model.ModerationSettings) dto.ModerationSettingsResponse {
+	return dto.ModerationSettingsResponse{
+		PlaceID:   placeID,
+		Mode:      settings.Custom,
+		Effective: settings.Effective,
+	}
+}
+
+func handleModerationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, serviceErrors.ErrInvalidPlaceID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+
+	case errors.Is(err, serviceErrors.ErrInvalidReviewStatus):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidReviewStatus})
+
+	case errors.Is(err, serviceErrors.ErrInvalidModerationMode):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidModerationMode})
+
+	case errors.Is(err, serviceErrors.ErrInvalidModerationNote):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidModerationNote})
+
+	case errors.Is(err, serviceErrors.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidCursor})
+
+	case errors.Is(err, serviceErrors.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+	case errors.Is(err, serviceErrors.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrReviewNotFound})
+
+	case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
+
+	case errors.Is(err, serviceErrors.ErrInvalidModeration):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: response.ErrInvalidModeration})
+
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedModerateReview})
+	}
+}
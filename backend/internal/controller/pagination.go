@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kulikovroman08/reviewlink-backend/pkg/pagination"
+)
+
+const (
+	// headerTotalCount — число строк списка без учёта страниц
+	headerTotalCount = "X-Total-Count"
+	// headerNextCursor — курсор следующей страницы; на последней странице заголовка нет
+	headerNextCursor = "X-Next-Cursor"
+)
+
+// parsePage читает необязательные limit и cursor; размер по умолчанию и предел подставляет сервис
+func parsePage(c *gin.Context) (pagination.Params, error) {
+	var page pagination.Params
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return page, fmt.Errorf("invalid limit %q", v)
+		}
+		page.Limit = limit
+	}
+	page.Cursor = c.Query("cursor")
+
+	return page, nil
+}
+
+// parseOffsetPage читает limit и offset списков, которые листаются смещением, а не курсором.
+// Размер по умолчанию, предел и отрицательные значения поправляет сервис.
+func parseOffsetPage(c *gin.Context) (limit, offset int, err error) {
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid limit %q", c.Query("limit"))
+	}
+	offset, err = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid offset %q", c.Query("offset"))
+	}
+
+	return limit, offset, nil
+}
+
+// setPageHeaders передаёт общее число строк и курсор следующей страницы в заголовках ответа
+func setPageHeaders(c *gin.Context, total int, nextCursor string) {
+	c.Header(headerTotalCount, strconv.Itoa(total))
+	if nextCursor != "" {
+		c.Header(headerNextCursor, nextCursor)
+	}
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// photosFormField — поле multipart-формы с файлами фотографий
+const photosFormField = "photos"
+
+// multipartOverhead — запас на заголовки частей формы сверх суммарного размера файлов
+const multipartOverhead = 1 << 20
+
+// UploadReviewPhotos godoc
+// @Summary      Загрузка фотографий к отзыву
+// @Description  Автор отзыва прикрепляет фото (JPEG, PNG, WebP) в поле photos multipart-формы, можно несколько файлов за раз. Тип определяется по содержимому файла. Фото перекодируются без метаданных (EXIF, GPS), для каждого делается миниатюра. Лимиты на число фото у отзыва и размер файла задаются MAX_REVIEW_PHOTOS и PHOTO_MAX_SIZE; если хоть один файл не прошёл проверку, не сохраняется ни один.
+// @Tags         reviews
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id      path      string  true  "Review ID"
+// @Param        photos  formData  file    true  "Файлы фотографий"
+// @Success      201     {object}  dto.UploadPhotosResponse
+// @Failure      400     {object}  dto.ErrorResponse "invalid input / invalid photo / unsupported photo type / too many photos"
+// @Failure      403     {object}  dto.ErrorResponse "review not found or not author"
+// @Failure      413     {object}  dto.ErrorResponse "photo too large"
+// @Failure      500     {object}  dto.ErrorResponse "failed to save photo"
+// @Router       /reviews/{id}/photos [post]
+// @Security     BearerAuth
+func (h *Application) UploadReviewPhotos(c *gin.Context) {
+	maxBody := h.PhotoService.MaxUploadSize() + multipartOverhead
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBody)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{Error: response.ErrPhotoTooLarge})
+			return
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+	defer func() {
+		_ = form.RemoveAll()
+	}()
+
+	files := form.File[photosFormField]
+	uploads := make([]model.PhotoUpload, 0, len(files))
+	for _, fh := range files {
+		uploads = append(uploads, photoUpload(fh))
+	}
+
+	photos, err := h.PhotoService.UploadPhotos(c.Request.Context(), c.GetString("user_id"), c.Param("id"), uploads)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrReviewNotFound):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrReviewNotFound})
+
+		case errors.Is(err, serviceErrors.ErrInvalidPhoto):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPhoto})
+
+		case errors.Is(err, serviceErrors.ErrUnsupportedPhotoType):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrUnsupportedPhotoType})
+
+		case errors.Is(err, serviceErrors.ErrTooManyPhotos):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrTooManyPhotos})
+
+		case errors.Is(err, serviceErrors.ErrPhotoTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{Error: response.ErrPhotoTooLarge})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedSavePhoto})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.UploadPhotosResponse{Photos: photoResponses(photos)})
+}
+
+// DeleteReviewPhoto godoc
+// @Summary      Удаление фотографии отзыва
+// @Description  Автор отзыва удаляет одно фото вместе с миниатюрой
+// @Tags         reviews
+// @Produce      json
+// @Param        id        path      string  true  "Review ID"
+// @Param        photo_id  path      string  true  "Photo ID"
+// @Success      200       {object}  dto.MessageResponse "photo deleted"
+// @Failure      403       {object}  dto.ErrorResponse "review not found or not author"
+// @Failure      404       {object}  dto.ErrorResponse "photo not found"
+// @Failure      500       {object}  dto.ErrorResponse "failed to delete photo"
+// @Router       /reviews/{id}/photos/{photo_id} [delete]
+// @Security     BearerAuth
+func (h *Application) DeleteReviewPhoto(c *gin.Context) {
+	err := h.PhotoService.DeletePhoto(c.Request.Context(), c.GetString("user_id"), c.Param("id"), c.Param("photo_id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrReviewNotFound):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrReviewNotFound})
+
+		case errors.Is(err, serviceErrors.ErrPhotoNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPhotoNotFound})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedDeletePhoto})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "photo deleted"})
+}
+
+// GetMedia godoc
+// @Summary      Файл фотографии
+// @Description  Отдаёт фото или миниатюру из хранилища по ключу из ссылок photos[].url и photos[].thumbnail_url. Ключи не повторяются, поэтому файл кэшируется надолго.
+// @Tags         reviews
+// @Produce      image/jpeg
+// @Produce      image/png
+// @Param        key  path  string  true  "Ключ файла"
+// @Success      200
+// @Failure      404  {object}  dto.ErrorResponse "photo not found"
+// @Failure      500  {object}  dto.ErrorResponse "internal error"
+// @Router       /media/{key} [get]
+func (h *Application) GetMedia(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	rc, err := h.PhotoService.OpenMedia(c.Request.Context(), key)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrPhotoNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPhotoNotFound})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrInternalError})
+		}
+		return
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.DataFromReader(http.StatusOK, -1, contentType, rc, map[string]string{
+		"Cache-Control":          "public, max-age=31536000, immutable",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func photoUpload(fh *multipart.FileHeader) model.PhotoUpload {
+	return model.PhotoUpload{
+		Filename: fh.Filename,
+		Size:     fh.Size,
+		Open: func() (io.ReadCloser, error) {
+			return fh.Open()
+		},
+	}
+}
+
+func photoResponses(photos []model.ReviewPhoto) []dto.PhotoResponse {
+	resp := make([]dto.PhotoResponse, 0, len(photos))
+	for _, p := range photos {
+		resp = append(resp, dto.PhotoResponse{
+			ID:           p.ID.String(),
+			URL:          p.URL,
+			ThumbnailURL: p.ThumbnailURL,
+			Width:        p.Width,
+			Height:       p.Height,
+		})
+	}
+	return resp
+}
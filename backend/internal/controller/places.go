@@ -14,25 +14,19 @@ import (
 
 // CreatePlace godoc
 // @Summary      Создание места (только для админов)
-// @Description  Эндпоинт доступен только пользователям с ролью **admin**.
+// @Description  Требуется право **places:write**. Необязательная token_policy переопределяет настройки токенов из конфига; стартовая пачка токенов создаётся по ней.
 // @Tags         admins
 // @Accept       json
 // @Produce      json
 // @Param        request  body      dto.CreatePlaceRequest  true  "Данные для создания места"
 // @Success      201      {object}  dto.CreatePlaceResponse
-// @Failure 400 {object} dto.ErrorResponse "invalid input / invalid place data"
+// @Failure 400 {object} dto.ErrorResponse "invalid input / invalid place data / invalid token policy"
 // @Failure 403 {object} dto.ErrorResponse "access denied"
 // @Failure 409 {object} dto.ErrorResponse "place already exists"
 // @Failure 500 {object} dto.ErrorResponse "failed to create place"
 // @Router       /places [post]
 // @Security     BearerAuth
 func (h *Application) CreatePlace(c *gin.Context) {
-	role := c.GetString("role")
-	if role != "admin" {
-		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
-		return
-	}
-
 	var req dto.CreatePlaceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
@@ -43,6 +37,9 @@ func (h *Application) CreatePlace(c *gin.Context) {
 		Name:    req.Name,
 		Address: req.Address,
 	}
+	if req.TokenPolicy != nil {
+		place.TokenPolicy = tokenPolicyFromRequest(*req.TokenPolicy)
+	}
 
 	createdPlace, err := h.PlaceService.CreatePlace(c.Request.Context(), place)
 	if err != nil {
@@ -53,6 +50,9 @@ func (h *Application) CreatePlace(c *gin.Context) {
 		case errors.Is(err, serviceErrors.ErrInvalidPlaceData):
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceData})
 
+		case errors.Is(err, serviceErrors.ErrInvalidTokenPolicy):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidTokenPolicy})
+
 		default:
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedCreatePlace})
 		}
@@ -67,34 +67,51 @@ func (h *Application) CreatePlace(c *gin.Context) {
 }
 
 // GetPlaces godoc
-// @Summary      Получение списка мест (только для админов)
-// @Description  Возвращает список всех заведений. Доступ только для роли **admin**.
+// @Summary      Получение списка мест
+// @Description  Возвращает страницу заведений по названию. Следующая страница запрашивается с cursor из заголовка X-Next-Cursor. Требуется право **places:read**; админ видит все заведения, владелец — только свои.
 // @Tags         admins
 // @Produce      json
+// @Param        limit   query  int     false  "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param        cursor  query  string  false  "Курсор следующей страницы из заголовка X-Next-Cursor"
 // @Success 200 {array} dto.PlaceResponse
+// @Header  200 {int}    X-Total-Count "Число заведений"
+// @Header  200 {string} X-Next-Cursor "Курсор следующей страницы; на последней странице отсутствует"
+// @Failure 400 {object} dto.ErrorResponse "invalid input / invalid cursor"
 // @Failure 403 {object} dto.ErrorResponse "access denied"
 // @Failure 500 {object} dto.ErrorResponse "failed to load places"
 // @Router /places [get]
 // @Security     BearerAuth
 func (h *Application) GetPlaces(c *gin.Context) {
-	role := c.GetString("role")
-	if role != "admin" {
-		c.JSON(http.StatusForbidden, dto.ErrorResponse{
-			Error: response.ErrAccessDenied,
-		})
+	params, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
 		return
 	}
 
-	places, err := h.PlaceService.GetAllPlaces(c.Request.Context())
+	page, err := h.PlaceService.GetPlaces(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		params,
+	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error: response.ErrFailedGetPlaces,
-		})
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidCursor):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidCursor})
+
+		case errors.Is(err, serviceErrors.ErrAccessDenied):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error: response.ErrFailedGetPlaces,
+			})
+		}
 		return
 	}
 
-	resp := make([]dto.PlaceResponse, 0, len(places))
-	for _, p := range places {
+	resp := make([]dto.PlaceResponse, 0, len(page.Items))
+	for _, p := range page.Items {
 		resp = append(resp, dto.PlaceResponse{
 			ID:        p.ID.String(),
 			Name:      p.Name,
@@ -103,5 +120,6 @@ func (h *Application) GetPlaces(c *gin.Context) {
 		})
 	}
 
+	setPageHeaders(c, page.Total, page.NextCursor)
 	c.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+const (
+	posTimestampHeader = "X-Reviewlink-Timestamp"
+	posSignatureHeader = "X-Reviewlink-Signature"
+
+	// maxReceiptEventSize — предел тела события от кассы
+	maxReceiptEventSize = 16 << 10
+)
+
+// RotatePOSSecret godoc
+// @Summary      Подключение кассовой системы
+// @Description  Выпускает секрет, которым касса подписывает события о покупках (HMAC-SHA256). Повторный вызов перевыпускает секрет, старый перестаёт приниматься. Секрет показывается только в этом ответе. Доступно админам и владельцам заведения.
+// @Tags         pos
+// @Produce      json
+// @Param        id   path      string  true  "Place ID"
+// @Success      200  {object}  dto.POSIntegrationResponse
+// @Failure      400  {object}  dto.ErrorResponse "invalid place id"
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "place not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to manage pos integration"
+// @Router       /places/{id}/pos [put]
+// @Security     BearerAuth
+func (h *Application) RotatePOSSecret(c *gin.Context) {
+	integration, err := h.POSService.RotateSecret(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+	)
+	if err != nil {
+		h.handlePOSError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.POSIntegrationResponse{
+		PlaceID:   integration.PlaceID.String(),
+		Secret:    hex.EncodeToString(integration.Secret),
+		CreatedAt: integration.CreatedAt,
+		UpdatedAt: integration.UpdatedAt,
+	})
+}
+
+// IssueReceiptToken godoc
+// @Summary      Токен для отзыва по чеку
+// @Description  Вызывается кассовой системой после покупки. Заголовок X-Reviewlink-Timestamp — unix-время в секундах, X-Reviewlink-Signature — "sha256=" + hex(HMAC-SHA256(секрет, timestamp + "." + тело запроса)). На один чек выпускается один токен: повтор того же события возвращает его же с кодом 200.
+// @Tags         pos
+// @Accept       json
+// @Produce      json
+// @Param        X-Reviewlink-Timestamp  header    string                   true  "Unix-время подписи"
+// @Param        X-Reviewlink-Signature  header    string                   true  "Подпись тела запроса"
+// @Param        request                 body      dto.ReceiptEventRequest  true  "Данные чека"
+// @Success      201                     {object}  dto.ReceiptTokenResponse
+// @Success      200                     {object}  dto.ReceiptTokenResponse "чек уже присылали"
+// @Failure      400                     {object}  dto.ErrorResponse "invalid input / invalid receipt"
+// @Failure      401                     {object}  dto.ErrorResponse "invalid signature"
+// @Failure      409                     {object}  dto.ErrorResponse "receipt already registered with different data"
+// @Failure      500                     {object}  dto.ErrorResponse "failed to issue receipt token"
+// @Router       /pos/receipts [post]
+func (h *Application) IssueReceiptToken(c *gin.Context) {
+	// Подпись считается по исходным байтам, поэтому тело читается целиком до разбора
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxReceiptEventSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	var req dto.ReceiptEventRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	placeID, err := uuid.Parse(req.PlaceID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+		return
+	}
+
+	issued, err := h.POSService.IssueReceiptToken(c.Request.Context(), model.ReceiptEvent{
+		PlaceID: placeID,
+		Receipt: model.Receipt{
+			ID:          req.ReceiptID,
+			Amount:      req.Amount,
+			PurchasedAt: req.PurchasedAt,
+		},
+		Timestamp: c.GetHeader(posTimestampHeader),
+		Signature: c.GetHeader(posSignatureHeader),
+		Payload:   payload,
+	})
+	if err != nil {
+		h.handlePOSError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if issued.Created {
+		status = http.StatusCreated
+	}
+
+	c.JSON(status, dto.ReceiptTokenResponse{
+		Token:     issued.Token,
+		URL:       issued.URL,
+		PIN:       issued.PIN,
+		ExpiresAt: issued.ExpiresAt,
+	})
+}
+
+func (h *Application) handlePOSError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, serviceErrors.ErrInvalidPlaceID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+
+	case errors.Is(err, serviceErrors.ErrInvalidReceipt):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidReceipt})
+
+	case errors.Is(err, serviceErrors.ErrInvalidSignature):
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: response.ErrInvalidSignature})
+
+	case errors.Is(err, serviceErrors.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+	case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
+
+	case errors.Is(err, serviceErrors.ErrReceiptConflict):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: response.ErrReceiptConflict})
+
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedManagePOS})
+	}
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/qr"
+)
+
+// GetTokenQR godoc
+// @Summary      QR-код токена отзыва
+// @Description  Рисует на сервере QR-код со ссылкой на форму отзыва. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Produce      png
+// @Produce      image/svg+xml
+// @Param        id      path      string  true   "Place ID"
+// @Param        value   path      string  true   "Значение токена"
+// @Param        format  query     string  false  "png или svg (по умолчанию png)"
+// @Param        size    query     int     false  "Размер в пикселях, 64–1024 (по умолчанию 256)"
+// @Success      200     {file}    binary
+// @Failure      400     {object}  dto.ErrorResponse "invalid qr parameters"
+// @Failure      403     {object}  dto.ErrorResponse "access denied"
+// @Failure      404     {object}  dto.ErrorResponse "invalid token"
+// @Failure      500     {object}  dto.ErrorResponse "failed to render qr"
+// @Router       /admin/places/{id}/tokens/{value}/qr [get]
+// @Security     BearerAuth
+func (a *Application) GetTokenQR(c *gin.Context) {
+	size, ok := parseQRSize(c)
+	if !ok {
+		return
+	}
+
+	img, err := a.TokenService.RenderTokenQR(
+		c.Request.Context(),
+		c.Param("id"),
+		c.Param("value"),
+		c.Query("format"),
+		size,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidToken):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrInvalidToken})
+
+		default:
+			handleQRError(c, err)
+		}
+		return
+	}
+
+	c.Data(http.StatusOK, img.ContentType, img.Data)
+}
+
+// GetBonusQR godoc
+// @Summary      QR-код бонуса
+// @Description  Рисует QR-код бонуса для предъявления кассиру. Доступен только владельцу бонуса.
+// @Tags         bonuses
+// @Produce      png
+// @Produce      image/svg+xml
+// @Param        qr_token  path      string  true   "QR-токен бонуса"
+// @Param        format    query     string  false  "png или svg (по умолчанию png)"
+// @Param        size      query     int     false  "Размер в пикселях, 64–1024 (по умолчанию 256)"
+// @Success      200       {file}    binary
+// @Failure      400       {object}  dto.ErrorResponse "invalid qr parameters"
+// @Failure      404       {object}  dto.ErrorResponse "bonus not found"
+// @Failure      500       {object}  dto.ErrorResponse "failed to render qr"
+// @Router       /bonuses/{qr_token}/qr [get]
+// @Security     BearerAuth
+func (a *Application) GetBonusQR(c *gin.Context) {
+	size, ok := parseQRSize(c)
+	if !ok {
+		return
+	}
+
+	img, err := a.BonusService.RenderBonusQR(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.Param("qr_token"),
+		c.Query("format"),
+		size,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrBonusNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrBonusNotFound})
+
+		default:
+			handleQRError(c, err)
+		}
+		return
+	}
+
+	c.Data(http.StatusOK, img.ContentType, img.Data)
+}
+
+func parseQRSize(c *gin.Context) (int, bool) {
+	raw := c.Query("size")
+	if raw == "" {
+		return 0, true
+	}
+
+	size, err := strconv.Atoi(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidQRParams})
+		return 0, false
+	}
+
+	return size, true
+}
+
+func handleQRError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, qr.ErrUnsupportedFormat), errors.Is(err, qr.ErrInvalidSize):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidQRParams})
+
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedRenderQR})
+	}
+}
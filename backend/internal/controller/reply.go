@@ -0,0 +1,343 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// CreateReply godoc
+// @Summary      Ответ заведения на отзыв
+// @Description  Публикует ответ на отзыв от имени заведения; на отзыв можно ответить один раз. Доступно админам и сотрудникам заведения отзыва.
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string            true  "Review ID"
+// @Param        request  body      dto.ReplyRequest  true  "Текст ответа"
+// @Success      201      {object}  dto.ReplyResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid reply"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "review not found"
+// @Failure      409      {object}  dto.ErrorResponse "review already has a reply"
+// @Failure      500      {object}  dto.ErrorResponse "failed to save reply"
+// @Router       /reviews/{id}/reply [post]
+// @Security     BearerAuth
+func (h *Application) CreateReply(c *gin.Context) {
+	var req dto.ReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	reply, err := h.ReplyService.CreateReply(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+		req.Content,
+	)
+	if err != nil {
+		handleReplyError(c, err, response.ErrFailedSaveReply)
+		return
+	}
+
+	c.JSON(http.StatusCreated, replyResponse(reply))
+}
+
+// UpdateReply godoc
+// @Summary      Редактирование ответа на отзыв
+// @Description  Меняет текст ответа заведения. Доступно админам и сотрудникам заведения отзыва.
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string            true  "Review ID"
+// @Param        request  body      dto.ReplyRequest  true  "Новый текст ответа"
+// @Success      200      {object}  dto.ReplyResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid reply"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "review not found / reply not found"
+// @Failure      500      {object}  dto.ErrorResponse "failed to save reply"
+// @Router       /reviews/{id}/reply [put]
+// @Security     BearerAuth
+func (h *Application) UpdateReply(c *gin.Context) {
+	var req dto.ReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	reply, err := h.ReplyService.UpdateReply(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+		req.Content,
+	)
+	if err != nil {
+		handleReplyError(c, err, response.ErrFailedSaveReply)
+		return
+	}
+
+	c.JSON(http.StatusOK, replyResponse(reply))
+}
+
+// DeleteReply godoc
+// @Summary      Удаление ответа на отзыв
+// @Description  Удаляет ответ заведения; после этого на отзыв можно ответить заново. Доступно админам и сотрудникам заведения отзыва.
+// @Tags         reviews
+// @Produce      json
+// @Param        id   path      string  true  "Review ID"
+// @Success      200  {object}  dto.MessageResponse "reply deleted"
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "review not found / reply not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to save reply"
+// @Router       /reviews/{id}/reply [delete]
+// @Security     BearerAuth
+func (h *Application) DeleteReply(c *gin.Context) {
+	err := h.ReplyService.DeleteReply(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+	)
+	if err != nil {
+		handleReplyError(c, err, response.ErrFailedSaveReply)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "reply deleted"})
+}
+
+// GetReplyTemplates godoc
+// @Summary      Шаблоны ответов заведения
+// @Description  Сохранённые шаблоны ответов на отзывы по названию. Доступно админам и сотрудникам заведения.
+// @Tags         places
+// @Produce      json
+// @Param        id   path      string  true  "Place ID"
+// @Success      200  {array}   dto.ReplyTemplateResponse
+// @Failure      400  {object}  dto.ErrorResponse "invalid place id"
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "place not found"
+// @Failure      500  {object}  dto.ErrorResponse "internal error"
+// @Router       /places/{id}/reply-templates [get]
+// @Security     BearerAuth
+func (h *Application) GetReplyTemplates(c *gin.Context) {
+	templates, err := h.ReplyService.ListTemplates(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+	)
+	if err != nil {
+		handleReplyError(c, err, response.ErrInternalError)
+		return
+	}
+
+	resp := make([]dto.ReplyTemplateResponse, 0, len(templates))
+	for i := range templates {
+		resp = append(resp, replyTemplateResponse(&templates[i]))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateReplyTemplate godoc
+// @Summary      Сохранение шаблона ответа
+// @Description  Добавляет шаблон ответа на отзывы; название уникально в пределах заведения. Доступно админам, владельцам и менеджерам заведения.
+// @Tags         places
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                    true  "Place ID"
+// @Param        request  body      dto.ReplyTemplateRequest  true  "Шаблон"
+// @Success      201      {object}  dto.ReplyTemplateResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid place id / invalid reply template"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "place not found"
+// @Failure      409      {object}  dto.ErrorResponse "reply template already exists"
+// @Failure      500      {object}  dto.ErrorResponse "failed to save reply template"
+// @Router       /places/{id}/reply-templates [post]
+// @Security     BearerAuth
+func (h *Application) CreateReplyTemplate(c *gin.Context) {
+	var req dto.ReplyTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	template, err := h.ReplyService.CreateTemplate(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+		req.Title,
+		req.Content,
+	)
+	if err != nil {
+		handleReplyError(c, err, response.ErrFailedSaveTemplate)
+		return
+	}
+
+	c.JSON(http.StatusCreated, replyTemplateResponse(template))
+}
+
+// DeleteReplyTemplate godoc
+// @Summary      Удаление шаблона ответа
+// @Description  Доступно админам, владельцам и менеджерам заведения.
+// @Tags         places
+// @Produce      json
+// @Param        id           path      string  true  "Place ID"
+// @Param        template_id  path      string  true  "Template ID"
+// @Success      200          {object}  dto.MessageResponse "reply template deleted"
+// @Failure      400          {object}  dto.ErrorResponse "invalid place id"
+// @Failure      403          {object}  dto.ErrorResponse "access denied"
+// @Failure      404          {object}  dto.ErrorResponse "place not found / reply template not found"
+// @Failure      500          {object}  dto.ErrorResponse "failed to save reply template"
+// @Router       /places/{id}/reply-templates/{template_id} [delete]
+// @Security     BearerAuth
+func (h *Application) DeleteReplyTemplate(c *gin.Context) {
+	err := h.ReplyService.DeleteTemplate(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+		c.Param("template_id"),
+	)
+	if err != nil {
+		handleReplyError(c, err, response.ErrFailedSaveTemplate)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "reply template deleted"})
+}
+
+// GetReplyReport godoc
+// @Summary      Отчёт по ответам заведения
+// @Description  По опубликованным отзывам за период: сколько получили ответ, доля ответов и медиана времени от отзыва до ответа в секундах. Период по умолчанию — последние 30 дней, to включает весь день. Доступно админам, владельцам и менеджерам заведения.
+// @Tags         places
+// @Produce      json
+// @Param        id    path      string  true   "Place ID"
+// @Param        from  query     string  false  "Начало периода (YYYY-MM-DD)"
+// @Param        to    query     string  false  "Конец периода включительно (YYYY-MM-DD)"
+// @Success      200   {object}  dto.ReplyStatsResponse
+// @Failure      400   {object}  dto.ErrorResponse "invalid input / invalid place id / invalid period"
+// @Failure      403   {object}  dto.ErrorResponse "access denied"
+// @Failure      404   {object}  dto.ErrorResponse "place not found"
+// @Failure      500   {object}  dto.ErrorResponse "failed to get reply stats"
+// @Router       /places/{id}/reports/replies [get]
+// @Security     BearerAuth
+func (h *Application) GetReplyReport(c *gin.Context) {
+	from, to, err := parsePeriod(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	stats, err := h.ReplyService.GetReplyStats(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+		from,
+		to,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidPlaceID):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+
+		case errors.Is(err, serviceErrors.ErrInvalidPeriod):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPeriod})
+
+		case errors.Is(err, serviceErrors.ErrAccessDenied):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+		case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedGetReplyStats})
+		}
+		return
+	}
+
+	resp := dto.ReplyStatsResponse{
+		PlaceID: stats.PlaceID.String(),
+		From:    stats.From,
+		To:      stats.To,
+		Reviews: stats.Reviews,
+		Replied: stats.Replied,
+	}
+	if stats.Reviews > 0 {
+		resp.ReplyRate = float64(stats.Replied) / float64(stats.Reviews)
+	}
+	if stats.MedianResponse != nil {
+		seconds := int64(stats.MedianResponse.Seconds())
+		resp.MedianResponseSeconds = &seconds
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// handleReplyError отвечает на ошибку сервиса ответов; непредвиденная ошибка отдаётся как fallback
+func handleReplyError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, serviceErrors.ErrInvalidReply):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidReply})
+
+	case errors.Is(err, serviceErrors.ErrInvalidReplyTemplate):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidReplyTemplate})
+
+	case errors.Is(err, serviceErrors.ErrInvalidPlaceID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+
+	case errors.Is(err, serviceErrors.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+	case errors.Is(err, serviceErrors.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrReviewNotFound})
+
+	case errors.Is(err, serviceErrors.ErrReplyNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrReplyNotFound})
+
+	case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
+
+	case errors.Is(err, serviceErrors.ErrReplyTemplateNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrReplyTemplateNotFound})
+
+	case errors.Is(err, serviceErrors.ErrReplyExists):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: response.ErrReplyExists})
+
+	case errors.Is(err, serviceErrors.ErrReplyTemplateExists):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: response.ErrReplyTemplateExists})
+
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: fallback})
+	}
+}
+
+func replyResponse(reply *model.ReviewReply) dto.ReplyResponse {
+	return dto.ReplyResponse{
+		ID:        reply.ID.String(),
+		ReviewID:  reply.ReviewID.String(),
+		Content:   reply.Content,
+		CreatedAt: reply.CreatedAt,
+		UpdatedAt: reply.UpdatedAt,
+	}
+}
+
+func replyTemplateResponse(template *model.ReplyTemplate) dto.ReplyTemplateResponse {
+	return dto.ReplyTemplateResponse{
+		ID:        template.ID.String(),
+		PlaceID:   template.PlaceID.String(),
+		Title:     template.Title,
+		Content:   template.Content,
+		CreatedAt: template.CreatedAt,
+	}
+}
@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// ReportReview godoc
+// @Summary      Жалоба на отзыв
+// @Description  Причина — spam, offensive, fake или off_topic, комментарий необязателен (до 500 символов). Пожаловаться можно один раз на опубликованный чужой отзыв. После REPORT_AUTO_HIDE_THRESHOLD открытых жалоб отзыв скрывается до решения модератора.
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                   true  "Review ID"
+// @Param        request  body      dto.CreateReportRequest  true  "Жалоба"
+// @Success      201      {object}  dto.ReportResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid report"
+// @Failure      401      {object}  dto.ErrorResponse "invalid user_id / unauthorized"
+// @Failure      403      {object}  dto.ErrorResponse "cannot report own review"
+// @Failure      404      {object}  dto.ErrorResponse "review not found"
+// @Failure      409      {object}  dto.ErrorResponse "review already reported"
+// @Failure      500      {object}  dto.ErrorResponse "failed to save report"
+// @Router       /reviews/{id}/reports [post]
+// @Security     BearerAuth
+func (h *Application) ReportReview(c *gin.Context) {
+	var req dto.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	report, err := h.ReportService.CreateReport(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.Param("id"),
+		req.Reason,
+		req.Comment,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidReport):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidReport})
+
+		case errors.Is(err, serviceErrors.ErrAccessDenied):
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: response.ErrInvalidUserID})
+
+		case errors.Is(err, serviceErrors.ErrOwnReviewReport):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrOwnReviewReport})
+
+		case errors.Is(err, serviceErrors.ErrReviewNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrReviewNotFound})
+
+		case errors.Is(err, serviceErrors.ErrReportExists):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: response.ErrReportExists})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedSaveReport})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, reportResponse(report))
+}
+
+// GetReportedReviews godoc
+// @Summary      Очередь жалоб на отзывы
+// @Description  Отзывы с жалобами в указанном статусе (по умолчанию open): сначала с наибольшим числом жалоб. Фильтр по заведению необязателен. Требуется право **reviews:moderate**.
+// @Tags         admins
+// @Produce      json
+// @Param        status    query     string  false  "open или resolved"
+// @Param        place_id  query     string  false  "Place ID"
+// @Param        limit     query     int     false  "Размер страницы (по умолчанию 50, максимум 200)"
+// @Param        offset    query     int     false  "Смещение"
+// @Success      200       {array}   dto.ReportedReviewResponse
+// @Failure      400       {object}  dto.ErrorResponse "invalid input / invalid place id / invalid report status"
+// @Failure      403       {object}  dto.ErrorResponse "access denied"
+// @Failure      500       {object}  dto.ErrorResponse "failed to get reports"
+// @Router       /admin/reports [get]
+// @Security     BearerAuth
+func (h *Application) GetReportedReviews(c *gin.Context) {
+	limit, offset, err := parseOffsetPage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	filter := model.ReportFilter{
+		Status: c.Query("status"),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if v := c.Query("place_id"); v != "" {
+		placeID, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+			return
+		}
+		filter.PlaceID = &placeID
+	}
+
+	reviews, err := h.ReportService.ListReportedReviews(c.Request.Context(), filter)
+	if err != nil {
+		handleReportError(c, err, response.ErrFailedGetReports)
+		return
+	}
+
+	resp := make([]dto.ReportedReviewResponse, 0, len(reviews))
+	for i := range reviews {
+		resp = append(resp, dto.ReportedReviewResponse{
+			Review:          moderatedReviewResponse(&reviews[i].Review),
+			Reports:         reviews[i].Reports,
+			Reasons:         reviews[i].Reasons,
+			FirstReportedAt: reviews[i].FirstReportedAt,
+			LastReportedAt:  reviews[i].LastReportedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetReviewReports godoc
+// @Summary      Жалобы на отзыв
+// @Description  Все жалобы на отзыв, включая разобранные, старые первыми. Требуется право **reviews:moderate**.
+// @Tags         admins
+// @Produce      json
+// @Param        id   path      string  true  "Review ID"
+// @Success      200  {array}   dto.ReportResponse
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "review not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to get reports"
+// @Router       /admin/reviews/{id}/reports [get]
+// @Security     BearerAuth
+func (h *Application) GetReviewReports(c *gin.Context) {
+	reports, err := h.ReportService.ListReviewReports(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleReportError(c, err, response.ErrFailedGetReports)
+		return
+	}
+
+	resp := make([]dto.ReportResponse, 0, len(reports))
+	for i := range reports {
+		resp = append(resp, reportResponse(&reports[i]))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ResolveReviewReports godoc
+// @Summary      Решение по жалобам на отзыв
+// @Description  Закрывает все открытые жалобы на отзыв. dismiss — жалобы отклонены, отзыв не меняется (скрытый автоматически отзыв возвращается через approve); hide — отзыв скрывается; hide_and_restrict — вдобавок автору запрещается оставлять отзывы на REPORT_RESTRICTION_PERIOD. Требуется право **reviews:moderate**.
+// @Tags         admins
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                     true  "Review ID"
+// @Param        request  body      dto.ResolveReportsRequest  true  "Решение"
+// @Success      200      {object}  dto.ReportResolutionResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid report action"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "review not found / no open reports for review"
+// @Failure      500      {object}  dto.ErrorResponse "failed to resolve reports"
+// @Router       /admin/reviews/{id}/reports/resolve [post]
+// @Security     BearerAuth
+func (h *Application) ResolveReviewReports(c *gin.Context) {
+	var req dto.ResolveReportsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	result, err := h.ReportService.ResolveReports(c.Request.Context(), c.GetString("user_id"), c.Param("id"), req.Action)
+	if err != nil {
+		handleReportError(c, err, response.ErrFailedResolveReport)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ReportResolutionResponse{
+		ReviewID:        result.ReviewID.String(),
+		Action:          result.Action,
+		Resolved:        result.Resolved,
+		ReviewStatus:    result.ReviewStatus,
+		RestrictedUntil: result.RestrictedUntil,
+	})
+}
+
+func reportResponse(report *model.ReviewReport) dto.ReportResponse {
+	resp := dto.ReportResponse{
+		ID:         report.ID.String(),
+		ReviewID:   report.ReviewID.String(),
+		ReporterID: report.ReporterID.String(),
+		Reason:     report.Reason,
+		Comment:    report.Comment,
+		Status:     report.Status,
+		Resolution: report.Resolution,
+		ResolvedAt: report.ResolvedAt,
+		CreatedAt:  report.CreatedAt,
+	}
+	if report.ResolvedBy != nil {
+		resolvedBy := report.ResolvedBy.String()
+		resp.ResolvedBy = &resolvedBy
+	}
+	return resp
+}
+
+func handleReportError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, serviceErrors.ErrInvalidReportStatus):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidReportStatus})
+
+	case errors.Is(err, serviceErrors.ErrInvalidReportAction):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidReportAction})
+
+	case errors.Is(err, serviceErrors.ErrAccessDenied):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+	case errors.Is(err, serviceErrors.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrReviewNotFound})
+
+	case errors.Is(err, serviceErrors.ErrReportNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrReportNotFound})
+
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: fallback})
+	}
+}
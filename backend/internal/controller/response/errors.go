@@ -20,16 +20,78 @@ const (
 	ErrFailedDeleteUser   = "failed to delete user"
 	ErrUserDeleted        = "user deleted"
 	ErrFailedGetUserStats = "failed to get user stats"
+	ErrInvalidRole        = "invalid role"
+	ErrFailedUpdateRole   = "failed to update role"
+)
+
+// Auth
+const (
+	ErrInvalidRefreshToken = "invalid refresh token"
+	ErrRefreshTokenExpired = "refresh token expired"
+	ErrRefreshTokenReused  = "refresh token reuse detected"
+	ErrSessionRevoked      = "session revoked"
+	ErrFailedRefreshToken  = "failed to refresh token"
+	ErrFailedLogout        = "failed to logout"
+	MsgLoggedOut           = "logged out"
 )
 
 // Tokens
 const (
-	ErrOnlyAdminCanGenerateTokens = "only admin can generate tokens"
-	ErrFailedGenerateTokens       = "failed to generate tokens"
-	ErrInvalidPlaceID             = "invalid place id"
-	ErrInvalidToken               = "invalid token"
-	ErrTokenExpired               = "token expired"
-	ErrTokenAlreadyUsed           = "token already used"
+	ErrFailedGenerateTokens = "failed to generate tokens"
+	ErrInvalidPlaceID       = "invalid place id"
+	ErrInvalidToken         = "invalid token"
+	ErrTokenExpired         = "token expired"
+	ErrTokenAlreadyUsed     = "token already used"
+	ErrTokenPlaceMismatch   = "token belongs to another place"
+	ErrFailedGetTokenInfo   = "failed to get token info"
+	ErrInvalidQRParams      = "invalid qr parameters"
+	ErrFailedRenderQR       = "failed to render qr"
+	ErrInvalidLabelLayout   = "invalid label layout"
+	ErrFailedPrintTokens    = "failed to print tokens"
+	ErrTokenRevoked         = "token revoked"
+	ErrTokenNotFound        = "token not found"
+	ErrInvalidTokenStatus   = "invalid token status"
+	ErrFailedManageTokens   = "failed to manage tokens"
+	ErrInvalidTokenPolicy   = "invalid token policy"
+	ErrInvalidTokenCount    = "invalid token count"
+	ErrTokenJobNotFound     = "token job not found"
+	ErrTokenJobNotReady     = "token job is not finished"
+	ErrInvalidExportFormat  = "invalid export format"
+	ErrInvalidTokenLabels   = "invalid token labels"
+)
+
+// Kiosk
+const (
+	ErrKioskNotEnabled   = "kiosk mode is not enabled"
+	ErrInvalidKioskStep  = "invalid kiosk code step"
+	ErrKioskCodeUsed     = "kiosk code already used"
+	ErrFailedManageKiosk = "failed to manage kiosk"
+)
+
+// POS
+const (
+	ErrInvalidSignature = "invalid signature"
+	ErrInvalidReceipt   = "invalid receipt"
+	ErrReceiptConflict  = "receipt already registered with different data"
+	ErrFailedIssueToken = "failed to issue receipt token"
+	ErrFailedManagePOS  = "failed to manage pos integration"
+)
+
+// Short links
+const (
+	ErrScanNotFound    = "scan not found"
+	ErrInvalidPeriod   = "invalid period"
+	ErrFailedTrackScan = "failed to track scan"
+	ErrFailedGetFunnel = "failed to get funnel"
+)
+
+// Moderation
+const (
+	ErrInvalidReviewStatus   = "invalid review status"
+	ErrInvalidModeration     = "review cannot be moved to this status"
+	ErrInvalidModerationMode = "invalid moderation mode"
+	ErrInvalidModerationNote = "invalid moderation reason"
+	ErrFailedModerateReview  = "failed to moderate review"
 )
 
 // Places
@@ -44,11 +106,72 @@ const (
 
 // Reviews
 const (
-	ErrInvalidUserID      = "invalid user_id"
-	ErrInvalidRating      = "invalid rating"
-	ErrReviewNotFound     = "review not found"
-	ErrFailedUpdateReview = "failed to update review"
-	ErrTooManyReviews     = "too many reviews today"
+	ErrInvalidUserID          = "invalid user_id"
+	ErrInvalidRating          = "invalid rating"
+	ErrReviewNotFound         = "review not found"
+	ErrFailedUpdateReview     = "failed to update review"
+	ErrTooManyReviews         = "too many reviews today"
+	ErrInvalidReportDimension = "invalid report dimension"
+	ErrFailedGetReport        = "failed to get report"
+	ErrTooManyPINAttempts     = "too many pin attempts"
+	ErrContentRejected        = "review content rejected"
+)
+
+// Replies
+const (
+	ErrReplyNotFound         = "reply not found"
+	ErrReplyExists           = "review already has a reply"
+	ErrInvalidReply          = "invalid reply"
+	ErrFailedSaveReply       = "failed to save reply"
+	ErrReplyTemplateNotFound = "reply template not found"
+	ErrReplyTemplateExists   = "reply template already exists"
+	ErrInvalidReplyTemplate  = "invalid reply template"
+	ErrFailedSaveTemplate    = "failed to save reply template"
+	ErrFailedGetReplyStats   = "failed to get reply stats"
+)
+
+// Photos
+const (
+	ErrPhotoNotFound        = "photo not found"
+	ErrInvalidPhoto         = "invalid photo"
+	ErrUnsupportedPhotoType = "unsupported photo type"
+	ErrPhotoTooLarge        = "photo too large"
+	ErrTooManyPhotos        = "too many photos"
+	ErrFailedSavePhoto      = "failed to save photo"
+	ErrFailedDeletePhoto    = "failed to delete photo"
+)
+
+// Votes
+const (
+	ErrOwnReviewVote = "cannot vote for own review"
+	ErrFailedVote    = "failed to save vote"
+)
+
+// Reports
+const (
+	ErrInvalidReport       = "invalid report"
+	ErrReportExists        = "review already reported"
+	ErrOwnReviewReport     = "cannot report own review"
+	ErrReportNotFound      = "no open reports for review"
+	ErrInvalidReportAction = "invalid report action"
+	ErrInvalidReportStatus = "invalid report status"
+	ErrReviewsRestricted   = "posting reviews is restricted"
+	ErrFailedSaveReport    = "failed to save report"
+	ErrFailedGetReports    = "failed to get reports"
+	ErrFailedResolveReport = "failed to resolve reports"
+)
+
+// Pagination
+const (
+	ErrInvalidReviewSort = "invalid review sort"
+	ErrInvalidCursor     = "invalid cursor"
+)
+
+// Revisions
+const (
+	ErrReviewNotRestorable = "review cannot be restored"
+	ErrFailedGetRevisions  = "failed to get revisions"
+	ErrFailedRestoreReview = "failed to restore review"
 )
 
 // Admin
@@ -62,4 +185,14 @@ const (
 	ErrFailedCreateBonus = "failed to create bonus"
 	ErrBonusNotFound     = "bonus not found"
 	ErrBonusAlreadyUsed  = "bonus already used"
+	ErrNotPlaceMember    = "validator is not a member of the bonus place"
+	ErrBonusWrongPlace   = "bonus belongs to another place"
+	ErrPlaceIDRequired   = "place_id is required for this bonus"
+)
+
+// Place members
+const (
+	ErrMemberAlreadyExists = "member already exists"
+	ErrMemberNotFound      = "member not found"
+	ErrFailedManageMembers = "failed to manage members"
 )
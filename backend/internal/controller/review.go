@@ -3,6 +3,7 @@ package controller
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
@@ -18,16 +19,17 @@ import (
 
 // SubmitReview godoc
 // @Summary      Отправка отзыва
-// @Description  Авторизованный пользователь может оставить отзыв на место, используя одноразовый токен.
+// @Description  Авторизованный пользователь может оставить отзыв на место, используя одноразовый токен, код с планшета заведения (K-…, нужен place_id) или числовой PIN-код токена (нужен place_id). Неудачные попытки ввода PIN ограничены на пользователя и IP. Отзыв по токену с чека кассы помечается как подтверждённая покупка. Текст проверяется фильтром: запрещённый текст отклоняется, подозрительный (ссылки, телефоны, капс) уходит на модерацию со статусом pending.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Param        request  body      dto.SubmitReviewRequest  true  "Данные отзыва"
-// @Success      201
-// @Failure 400 {object} dto.ErrorResponse "invalid input"
-// @Failure 429 {object}  dto.ErrorResponse "too many reviews today"
+// @Success      201      {object}  dto.SubmitReviewResponse
+// @Failure 400 {object} dto.ErrorResponse "invalid input / review content rejected"
+// @Failure 429 {object}  dto.ErrorResponse "too many reviews today / too many pin attempts"
 // @Failure 401 {object} dto.ErrorResponse "invalid user_id / invalid token"
-// @Failure 403 {object} dto.ErrorResponse "token expired / token already used"
+// @Failure 403 {object} dto.ErrorResponse "token expired / token revoked / token already used / token belongs to another place / posting reviews is restricted"
+// @Failure 409 {object} dto.ErrorResponse "kiosk code already used"
 // @Failure 500 {object} dto.ErrorResponse "internal error"
 // @Router       /reviews [post]
 // @Security     BearerAuth
@@ -51,12 +53,30 @@ func (h *Application) SubmitReview(c *gin.Context) {
 		Rating:  req.Rating,
 	}
 
-	err = h.ReviewService.SubmitReview(c.Request.Context(), review, req.Token)
+	created, err := h.ReviewService.SubmitReview(c.Request.Context(), review, req.Token, c.ClientIP())
 	if err != nil {
 		switch {
 		case errors.Is(err, serviceErrors.ErrTooManyReviews):
 			c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{Error: response.ErrTooManyReviews})
 
+		case errors.Is(err, serviceErrors.ErrContentRejected):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrContentRejected})
+
+		case errors.Is(err, serviceErrors.ErrTooManyPINAttempts):
+			c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{Error: response.ErrTooManyPINAttempts})
+
+		case errors.Is(err, serviceErrors.ErrReviewsRestricted):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrReviewsRestricted})
+
+		case errors.Is(err, serviceErrors.ErrKioskCodeUsed):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: response.ErrKioskCodeUsed})
+
+		case errors.Is(err, serviceErrors.ErrTokenPlaceMismatch):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrTokenPlaceMismatch})
+
+		case errors.Is(err, serviceErrors.ErrTokenRevoked):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrTokenRevoked})
+
 		case errors.Is(err, serviceErrors.ErrTokenExpired):
 			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrTokenExpired})
 
@@ -72,20 +92,34 @@ func (h *Application) SubmitReview(c *gin.Context) {
 		}
 		return
 	}
-	c.Status(http.StatusCreated)
+
+	c.JSON(http.StatusCreated, dto.SubmitReviewResponse{
+		ID:               created.ID.String(),
+		PlaceID:          created.PlaceID.String(),
+		Rating:           created.Rating,
+		Content:          created.Content,
+		CreatedAt:        created.CreatedAt,
+		VerifiedPurchase: created.ReceiptID != nil,
+		ReceiptID:        created.ReceiptID,
+		Status:           created.Status,
+	})
 }
 
 // GetReviews godoc
 // @Summary      Просмотр отзывов по заведению
-// @Description  Получение списка отзывов по placeID с фильтрацией и сортировкой
+// @Description  Получение страницы опубликованных отзывов по placeID с фильтрацией и сортировкой. Страницы листаются курсором: следующая запрашивается с cursor из заголовка X-Next-Cursor и теми же фильтрами и сортировкой. Ответ заведения на отзыв приходит в поле reply, ссылки на фото и миниатюры — в поле photos, число отметок «полезный» — в helpful_count. Отредактированные автором отзывы помечены полем edited и временем последней правки edited_at.
 // @Tags         places
 // @Accept       json
 // @Produce      json
 // @Param        id     path      string  true   "Place ID"
 // @Param        rating query     int     false  "Фильтр по рейтингу (1-5)"
-// @Param        sort   query     string  false  "Сортировка: date_asc или date_desc"
+// @Param        sort   query     string  false  "Сортировка: date_desc (по умолчанию), date_asc, rating_desc, rating_asc или helpful (сначала полезные)"
+// @Param        limit  query     int     false  "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param        cursor query     string  false  "Курсор следующей страницы из заголовка X-Next-Cursor"
 // @Success 200 {array} dto.ReviewResponse
-// @Failure 400 {object} dto.ErrorResponse "invalid input"
+// @Header  200 {int}    X-Total-Count "Число отзывов под фильтром"
+// @Header  200 {string} X-Next-Cursor "Курсор следующей страницы; на последней странице отсутствует"
+// @Failure 400 {object} dto.ErrorResponse "invalid input / invalid review sort / invalid cursor"
 // @Failure 404 {object} dto.ErrorResponse "place not found"
 // @Failure 500 {object} dto.ErrorResponse "internal error"
 // @Router /places/{id}/reviews [get]
@@ -98,9 +132,15 @@ func (h *Application) GetReviews(c *gin.Context) {
 		return
 	}
 
-	reviews, err := h.ReviewService.GetReviews(c.Request.Context(), placeID, filter)
+	page, err := h.ReviewService.GetReviews(c.Request.Context(), placeID, filter)
 	if err != nil {
 		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidReviewSort):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidReviewSort})
+
+		case errors.Is(err, serviceErrors.ErrInvalidCursor):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidCursor})
+
 		case errors.Is(err, serviceErrors.ErrPlaceNotFound):
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
 		default:
@@ -109,15 +149,27 @@ func (h *Application) GetReviews(c *gin.Context) {
 		return
 	}
 
-	resp := make([]dto.ReviewResponse, 0, len(reviews))
-	for _, r := range reviews {
-		resp = append(resp, dto.ReviewResponse{
-			Rating:    r.Rating,
-			Content:   r.Content,
-			CreatedAt: r.CreatedAt,
-		})
+	resp := make([]dto.ReviewResponse, 0, len(page.Items))
+	for _, r := range page.Items {
+		item := dto.ReviewResponse{
+			ID:               r.ID.String(),
+			Rating:           r.Rating,
+			Content:          r.Content,
+			CreatedAt:        r.CreatedAt,
+			VerifiedPurchase: r.ReceiptID != nil,
+			Photos:           photoResponses(r.Photos),
+			HelpfulCount:     r.HelpfulCount,
+			Edited:           r.UpdatedAt != nil,
+			EditedAt:         r.UpdatedAt,
+		}
+		if r.Reply != nil {
+			reply := replyResponse(r.Reply)
+			item.Reply = &reply
+		}
+		resp = append(resp, item)
 	}
 
+	setPageHeaders(c, page.Total, page.NextCursor)
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -133,7 +185,13 @@ func parseReviewFilter(c *gin.Context) (model.ReviewFilter, error) {
 		f.HasRating = true
 	}
 
-	f.Sort = c.DefaultQuery("sort", "date_desc")
+	f.Sort = c.Query("sort")
+
+	page, err := parsePage(c)
+	if err != nil {
+		return f, err
+	}
+	f.Page = page
 
 	if from := c.Query("from"); from != "" {
 		t, err := time.Parse("2006-01-02", from)
@@ -156,14 +214,14 @@ func parseReviewFilter(c *gin.Context) (model.ReviewFilter, error) {
 
 // UpdateReview godoc
 // @Summary      Редактирование отзыва
-// @Description  Автор отзыва может изменить контент и рейтинг
+// @Description  Автор отзыва может изменить контент и рейтинг. Новый текст проверяется фильтром: запрещённый отклоняется, а подозрительный возвращает опубликованный отзыв в очередь модерации (статус pending, причина edit flagged by content filter rule …) и списывает баллы до одобрения. Каждая правка сохраняется отдельной редакцией.
 // @Tags         reviews
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "Review ID"
 // @Param        request body   dto.UpdateReviewRequest true "Данные для обновления отзыва"
 // @Success      200  {object}  dto.MessageResponse "review updated successfully"
-// @Failure      400  {object}  dto.ErrorResponse "invalid input or rating"
+// @Failure      400  {object}  dto.ErrorResponse "invalid input or rating / review content rejected"
 // @Failure      401  {object}  dto.ErrorResponse "invalid user_id / unauthorized"
 // @Failure      403  {object}  dto.ErrorResponse "review not found or not author"
 // @Failure      500  {object}  dto.ErrorResponse "failed to update review"
@@ -196,6 +254,9 @@ func (h *Application) UpdateReview(c *gin.Context) {
 		case errors.Is(err, serviceErrors.ErrInvalidRating):
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidRating})
 
+		case errors.Is(err, serviceErrors.ErrContentRejected):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrContentRejected})
+
 		case errors.Is(err, serviceErrors.ErrReviewNotFound):
 			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrReviewNotFound})
 
@@ -210,7 +271,7 @@ func (h *Application) UpdateReview(c *gin.Context) {
 
 // DeleteReview godoc
 // @Summary      Удаление отзыва
-// @Description  Автор отзыва может удалить свой отзыв (soft delete)
+// @Description  Автор отзыва может удалить свой отзыв (soft delete) и отменить удаление в течение REVIEW_UNDO_DELETE_WINDOW. Фотографии скрываются вместе с отзывом и возвращаются при отмене; файлы стираются после окна отмены.
 // @Tags         reviews
 // @Produce      json
 // @Param        id   path      string  true  "Review ID"
@@ -242,3 +303,71 @@ func (h *Application) DeleteReview(c *gin.Context) {
 
 	c.JSON(http.StatusOK, dto.MessageResponse{Message: "review deleted successfully"})
 }
+
+// GetLabelReport godoc
+// @Summary      Отчёт по меткам токенов
+// @Description  Число отзывов и средняя оценка в разрезе меток токенов: by=staff — по сотрудникам (рейтинг персонала), table — по столам, campaign — по кампаниям. Отзывы без метки не учитываются. to включает весь день. Доступно админам, владельцам и менеджерам заведения.
+// @Tags         places
+// @Produce      json
+// @Param        id    path      string  true   "Place ID"
+// @Param        by    query     string  true   "staff, table или campaign"
+// @Param        from  query     string  false  "Начало периода (YYYY-MM-DD)"
+// @Param        to    query     string  false  "Конец периода включительно (YYYY-MM-DD)"
+// @Success      200   {array}   dto.LabelStatResponse
+// @Failure      400   {object}  dto.ErrorResponse "invalid input / invalid place id / invalid report dimension / invalid period"
+// @Failure      403   {object}  dto.ErrorResponse "access denied"
+// @Failure      404   {object}  dto.ErrorResponse "place not found"
+// @Failure      500   {object}  dto.ErrorResponse "failed to get report"
+// @Router       /places/{id}/reports/labels [get]
+// @Security     BearerAuth
+func (h *Application) GetLabelReport(c *gin.Context) {
+	from, to, err := parsePeriod(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	stats, err := h.ReviewService.GetLabelReport(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+		c.Query("by"),
+		from,
+		to,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidPlaceID):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+
+		case errors.Is(err, serviceErrors.ErrInvalidReportDimension):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidReportDimension})
+
+		case errors.Is(err, serviceErrors.ErrInvalidPeriod):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPeriod})
+
+		case errors.Is(err, serviceErrors.ErrAccessDenied):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+		case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedGetReport})
+		}
+		return
+	}
+
+	resp := make([]dto.LabelStatResponse, 0, len(stats))
+	for _, st := range stats {
+		resp = append(resp, dto.LabelStatResponse{
+			Label:         st.Label,
+			StaffName:     st.StaffName,
+			Reviews:       st.Reviews,
+			AverageRating: math.Round(st.AverageRating*100) / 100,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// GetReviewRevisions godoc
+// @Summary      История правок отзыва
+// @Description  Редакции отзыва от первой (текст при создании) к последней. Доступно автору и админам.
+// @Tags         reviews
+// @Produce      json
+// @Param        id   path      string  true  "Review ID"
+// @Success      200  {array}   dto.ReviewRevisionResponse
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "review not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to get revisions"
+// @Router       /reviews/{id}/revisions [get]
+// @Security     BearerAuth
+func (h *Application) GetReviewRevisions(c *gin.Context) {
+	revisions, err := h.ReviewService.GetRevisions(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		c.Param("id"),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrAccessDenied):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+		case errors.Is(err, serviceErrors.ErrReviewNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrReviewNotFound})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedGetRevisions})
+		}
+		return
+	}
+
+	resp := make([]dto.ReviewRevisionResponse, 0, len(revisions))
+	for _, rev := range revisions {
+		resp = append(resp, dto.ReviewRevisionResponse{
+			Revision:  rev.Revision,
+			Content:   rev.Content,
+			Rating:    rev.Rating,
+			CreatedAt: rev.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RestoreReview godoc
+// @Summary      Отмена удаления отзыва
+// @Description  Автор может вернуть удалённый отзыв в течение REVIEW_UNDO_DELETE_WINDOW после удаления. Статус модерации и баллы не меняются, фотографии возвращаются вместе с отзывом.
+// @Tags         reviews
+// @Produce      json
+// @Param        id   path      string  true  "Review ID"
+// @Success      200  {object}  dto.MessageResponse "review restored successfully"
+// @Failure      401  {object}  dto.ErrorResponse "invalid user_id / unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse "review cannot be restored"
+// @Failure      500  {object}  dto.ErrorResponse "failed to restore review"
+// @Router       /reviews/{id}/restore [post]
+// @Security     BearerAuth
+func (h *Application) RestoreReview(c *gin.Context) {
+	err := h.ReviewService.RestoreReview(c.Request.Context(), c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrAccessDenied):
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: response.ErrInvalidUserID})
+
+		case errors.Is(err, serviceErrors.ErrReviewNotRestorable):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrReviewNotRestorable})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedRestoreReview})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "review restored successfully"})
+}
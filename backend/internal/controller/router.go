@@ -1,20 +1,32 @@
 package controller
 
 import (
+	"expvar"
+	"fmt"
+
 	"github.com/gin-gonic/gin"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model/rbac"
 	"github.com/kulikovroman08/reviewlink-backend/pkg/middleware"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(app *Application) *gin.Engine {
+// SetupRouter собирает маршруты. X-Forwarded-For учитывается только от trustedProxies:
+// иначе клиент подменяет свой IP заголовком и обходит лимиты по IP.
+func SetupRouter(app *Application, trustedProxies []string) (*gin.Engine, error) {
 	r := gin.Default()
 
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("set trusted proxies: %w", err)
+	}
+
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		// Без этого браузер не отдаст скрипту с другого origin заголовки пагинации
+		c.Writer.Header().Set("Access-Control-Expose-Headers", headerTotalCount+", "+headerNextCursor)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -34,9 +46,14 @@ func SetupRouter(app *Application) *gin.Engine {
 		})
 		public.POST("/signup", app.Signup)
 		public.POST("/login", app.Login)
+		public.POST("/auth/refresh", app.RefreshToken)
 
 		public.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 		public.GET("/places/:id/reviews", app.GetReviews)
+		public.GET("/tokens/:value", app.GetTokenInfo)
+		public.POST("/pos/receipts", app.IssueReceiptToken)
+		public.GET("/r/:code", app.FollowShortLink)
+		public.GET("/media/*key", app.GetMedia)
 
 		public.GET("/leaderboard/users", app.GetUserLeaderboard)
 		public.GET("/leaderboard/places", app.GetPlaceLeaderboard)
@@ -46,27 +63,105 @@ func SetupRouter(app *Application) *gin.Engine {
 
 	// Защищенные маршруты
 	protected := r.Group("/")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(app.UserService))
 	{
+		protected.POST("/auth/logout", app.Logout)
+
 		protected.GET("/users", app.GetUser)
 		protected.PUT("/users", app.UpdateUser)
 		protected.DELETE("/users", app.DeleteUser)
 		protected.GET("/users/stats", app.GetUserStats)
 
-		protected.POST("/places", app.CreatePlace)
-		protected.GET("/places", app.GetPlaces)
+		protected.POST("/places", middleware.RequirePermission(rbac.PermPlacesWrite), app.CreatePlace)
+		protected.GET("/places", middleware.RequirePermission(rbac.PermPlacesRead), app.GetPlaces)
+
+		members := protected.Group("/places/:id/members")
+		members.Use(middleware.RequirePermission(rbac.PermMembersManage))
+		{
+			members.GET("", app.GetPlaceMembers)
+			members.POST("", app.AddPlaceMember)
+			members.DELETE("/:user_id", app.RemovePlaceMember)
+		}
+
+		kiosk := protected.Group("/places/:id/kiosk")
+		{
+			kiosk.PUT("", middleware.RequirePermission(rbac.PermKioskManage), app.EnableKiosk)
+			kiosk.DELETE("", middleware.RequirePermission(rbac.PermKioskManage), app.DisableKiosk)
+			kiosk.GET("/code", middleware.RequirePermission(rbac.PermKioskDisplay), app.GetKioskCode)
+			kiosk.GET("/qr", middleware.RequirePermission(rbac.PermKioskDisplay), app.GetKioskQR)
+		}
+
+		protected.PUT("/places/:id/pos", middleware.RequirePermission(rbac.PermPOSManage), app.RotatePOSSecret)
+		protected.GET("/places/:id/funnel", middleware.RequirePermission(rbac.PermPlaceReports), app.GetPlaceFunnel)
+		protected.GET("/places/:id/reports/labels", middleware.RequirePermission(rbac.PermPlaceReports), app.GetLabelReport)
+		protected.GET("/places/:id/reports/replies", middleware.RequirePermission(rbac.PermPlaceReports), app.GetReplyReport)
+		protected.POST("/scans/:id/open", app.MarkScanOpened)
 
 		protected.POST("/reviews", app.SubmitReview)
 		protected.PATCH("/reviews/:id", app.UpdateReview)
 		protected.DELETE("/reviews/:id", app.DeleteReview)
+		protected.POST("/reviews/:id/restore", app.RestoreReview)
+		protected.GET("/reviews/:id/revisions", app.GetReviewRevisions)
+		protected.POST("/reviews/:id/photos", app.UploadReviewPhotos)
+		protected.DELETE("/reviews/:id/photos/:photo_id", app.DeleteReviewPhoto)
+		protected.POST("/reviews/:id/helpful", app.VoteReview)
+		protected.DELETE("/reviews/:id/helpful", app.UnvoteReview)
+		protected.POST("/reviews/:id/reports", app.ReportReview)
+
+		replies := protected.Group("/")
+		replies.Use(middleware.RequirePermission(rbac.PermReviewsReply))
+		{
+			replies.POST("/reviews/:id/reply", app.CreateReply)
+			replies.PUT("/reviews/:id/reply", app.UpdateReply)
+			replies.DELETE("/reviews/:id/reply", app.DeleteReply)
+			replies.GET("/places/:id/reply-templates", app.GetReplyTemplates)
+			replies.POST("/places/:id/reply-templates", app.CreateReplyTemplate)
+			replies.DELETE("/places/:id/reply-templates/:template_id", app.DeleteReplyTemplate)
+		}
+
+		protected.POST("/admin/tokens", middleware.RequirePermission(rbac.PermTokensGenerate), app.GenerateTokens)
+		protected.GET("/admin/places/:id/tokens/:value/qr", middleware.RequirePermission(rbac.PermTokensGenerate), app.GetTokenQR)
+		protected.POST("/admin/places/:id/tokens/print", middleware.RequirePermission(rbac.PermTokensPrint), app.PrintTokens)
+
+		tokens := protected.Group("/admin")
+		tokens.Use(middleware.RequirePermission(rbac.PermTokensGenerate))
+		{
+			tokens.GET("/places/:id/tokens", app.GetPlaceTokens)
+			tokens.GET("/places/:id/tokens/stats", app.GetPlaceTokenStats)
+			tokens.GET("/places/:id/token-policy", app.GetTokenPolicy)
+			tokens.PUT("/places/:id/token-policy", app.UpdateTokenPolicy)
+			tokens.DELETE("/tokens/:id", app.RevokeToken)
+			tokens.PUT("/tokens/:id/pin", app.AssignTokenPIN)
+			tokens.POST("/tokens/revoke", app.RevokeTokens)
+			tokens.POST("/tokens/extend", app.ExtendTokens)
+			tokens.POST("/token-jobs", app.CreateTokenJob)
+			tokens.GET("/token-jobs/:id", app.GetTokenJob)
+			tokens.GET("/token-jobs/:id/export", app.ExportTokenJob)
+		}
+
+		moderation := protected.Group("/admin")
+		moderation.Use(middleware.RequirePermission(rbac.PermReviewsModerate))
+		{
+			moderation.GET("/reviews", app.GetModerationQueue)
+			moderation.POST("/reviews/:id/approve", app.ApproveReview)
+			moderation.POST("/reviews/:id/reject", app.RejectReview)
+			moderation.POST("/reviews/:id/hide", app.HideReview)
+			moderation.GET("/reports", app.GetReportedReviews)
+			moderation.GET("/reviews/:id/reports", app.GetReviewReports)
+			moderation.POST("/reviews/:id/reports/resolve", app.ResolveReviewReports)
+			moderation.GET("/places/:id/moderation", app.GetModerationSettings)
+			moderation.PUT("/places/:id/moderation", app.UpdateModerationSettings)
+		}
 
-		protected.POST("/admin/tokens", app.GenerateTokens)
-		protected.GET("/admin/stats", app.GetStats)
+		protected.GET("/admin/stats", middleware.RequirePermission(rbac.PermStatsRead), app.GetStats)
+		protected.GET("/admin/debug/vars", middleware.RequirePermission(rbac.PermStatsRead), gin.WrapH(expvar.Handler()))
+		protected.PATCH("/admin/users/:id/role", middleware.RequirePermission(rbac.PermUsersManage), app.UpdateUserRole)
 
 		protected.POST("/bonuses/redeem", app.RedeemBonus)
 		protected.GET("/bonuses", app.GetUserBonuses)
-		protected.POST("/bonuses/validate", app.ValidateBonus)
+		protected.GET("/bonuses/:qr_token/qr", app.GetBonusQR)
+		protected.POST("/bonuses/validate", middleware.RequirePermission(rbac.PermBonusesValidate), app.ValidateBonus)
 	}
 
-	return r
+	return r, nil
 }
@@ -3,51 +3,468 @@ package controller
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
 	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/labels"
 )
 
 // GenerateTokens godoc
 // @Summary      Генерация токенов (только для админов)
-// @Description  Эндпоинт доступен только пользователям с ролью **admin**.
+// @Description  Срок действия токенов берётся из политики заведения. Необязательные метки staff_id (сотрудник заведения), table и campaign наследуются отзывами и попадают в отчёт GET /places/{id}/reports/labels. Больше 100 токенов — через фоновую задачу POST /admin/token-jobs. Требуется право **tokens:generate**.
 // @Tags         admins
 // @Accept       json
 // @Produce      json
 // @Param        request  body      dto.GenerateTokensRequest  true  "Данные для генерации токенов"
 // @Success      200      {object}  dto.GenerateTokensResponse
-// @Failure      400      {object}  dto.ErrorResponse "invalid input"
-// @Failure      403      {object}  dto.ErrorResponse "only admin can generate tokens"
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid token labels"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "place not found"
 // @Failure      500      {object}  dto.ErrorResponse "failed to generate tokens"
 // @Router       /admin/tokens [post]
 // @Security     BearerAuth
 func (a *Application) GenerateTokens(c *gin.Context) {
-	role := c.GetString("role")
-	if role != "admin" {
-		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrOnlyAdminCanGenerateTokens})
+	var req dto.GenerateTokensRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
 		return
 	}
 
-	var req dto.GenerateTokensRequest
+	labels := model.TokenLabels{Table: req.Table, Campaign: req.Campaign}
+	if req.StaffID != "" {
+		staffID, err := uuid.Parse(req.StaffID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+			return
+		}
+		labels.StaffID = &staffID
+	}
+
+	resp, err := a.TokenService.GenerateTokens(c.Request.Context(), req.PlaceID, req.Count, labels)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidPlaceID):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+
+		case errors.Is(err, serviceErrors.ErrInvalidTokenLabels):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidTokenLabels})
+
+		case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedGenerateTokens})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetTokenInfo godoc
+// @Summary      Предпроверка токена отзыва
+// @Description  Публичный эндпоинт для страницы по QR-коду: заведение, статус токена и срок действия. Авторизация не требуется.
+// @Tags         tokens
+// @Produce      json
+// @Param        value  path      string  true  "Значение токена"
+// @Success      200    {object}  dto.TokenInfoResponse
+// @Failure      404    {object}  dto.ErrorResponse "invalid token"
+// @Failure      500    {object}  dto.ErrorResponse "failed to get token info"
+// @Router       /tokens/{value} [get]
+func (a *Application) GetTokenInfo(c *gin.Context) {
+	info, err := a.TokenService.GetTokenInfo(c.Request.Context(), c.Param("value"))
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidToken):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrInvalidToken})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedGetTokenInfo})
+		}
+		return
+	}
+
+	expired := !info.ExpiresAt.After(time.Now())
+
+	c.JSON(http.StatusOK, dto.TokenInfoResponse{
+		Token:     info.Token,
+		PlaceID:   info.PlaceID.String(),
+		PlaceName: info.PlaceName,
+		Valid:     !info.IsUsed && !info.IsRevoked && !expired,
+		Used:      info.IsUsed,
+		Revoked:   info.IsRevoked,
+		Expired:   expired,
+		ExpiresAt: info.ExpiresAt,
+	})
+}
+
+// PrintTokens godoc
+// @Summary      Печать наклеек с QR-кодами
+// @Description  Генерирует count новых токенов и возвращает PDF с сеткой наклеек: QR-код, название заведения, токен и срок действия. Требуется право **tokens:print**; владелец или менеджер — только для своего заведения. Формат страницы и сетка по умолчанию берутся из конфигурации.
+// @Tags         admins
+// @Accept       json
+// @Produce      application/pdf
+// @Param        id       path      string                  true  "Place ID"
+// @Param        request  body      dto.PrintTokensRequest  true  "Количество токенов и раскладка"
+// @Success      200      {file}    binary
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid place id / invalid label layout"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "place not found"
+// @Failure      500      {object}  dto.ErrorResponse "failed to print tokens"
+// @Router       /admin/places/{id}/tokens/print [post]
+// @Security     BearerAuth
+func (a *Application) PrintTokens(c *gin.Context) {
+	var req dto.PrintTokensRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
 		return
 	}
 
-	resp, err := a.TokenService.GenerateTokens(c.Request.Context(), req.PlaceID, req.Count)
+	placeID := c.Param("id")
+
+	pdf, err := a.TokenService.PrintTokens(
+		c.Request.Context(),
+		c.GetString("user_id"),
+		c.GetString("role"),
+		placeID,
+		req.Count,
+		labels.Layout{PageSize: req.PageSize, Columns: req.Columns, Rows: req.Rows},
+	)
 	if err != nil {
 		switch {
 		case errors.Is(err, serviceErrors.ErrInvalidPlaceID):
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
 
+		case errors.Is(err, labels.ErrInvalidLayout):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidLabelLayout})
+
+		case errors.Is(err, serviceErrors.ErrAccessDenied):
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrAccessDenied})
+
+		case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
+
 		default:
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedGenerateTokens})
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedPrintTokens})
 		}
 		return
 	}
 
+	c.Header("Content-Disposition", `attachment; filename="tokens-`+placeID+`.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// GetPlaceTokens godoc
+// @Summary      Список токенов заведения
+// @Description  Токены с фильтром по статусу и пагинацией. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Produce      json
+// @Param        id      path      string  true   "Place ID"
+// @Param        status  query     string  false  "active, used, expired или revoked"
+// @Param        limit   query     int     false  "Размер страницы (по умолчанию 50, максимум 200)"
+// @Param        offset  query     int     false  "Смещение"
+// @Success      200     {object}  dto.TokenListResponse
+// @Failure      400     {object}  dto.ErrorResponse "invalid input / invalid place id / invalid token status"
+// @Failure      403     {object}  dto.ErrorResponse "access denied"
+// @Failure      404     {object}  dto.ErrorResponse "place not found"
+// @Failure      500     {object}  dto.ErrorResponse "failed to manage tokens"
+// @Router       /admin/places/{id}/tokens [get]
+// @Security     BearerAuth
+func (a *Application) GetPlaceTokens(c *gin.Context) {
+	limit, offset, err := parseOffsetPage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	filter := model.TokenFilter{
+		Status: c.Query("status"),
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	page, err := a.TokenService.ListTokens(c.Request.Context(), c.Param("id"), filter)
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	now := time.Now()
+	resp := dto.TokenListResponse{
+		Tokens: make([]dto.TokenResponse, 0, len(page.Tokens)),
+		Total:  page.Total,
+		Limit:  max(limit, 0),
+		Offset: max(offset, 0),
+	}
+	for _, t := range page.Tokens {
+		resp.Tokens = append(resp.Tokens, dto.TokenResponse{
+			ID:        t.ID.String(),
+			Token:     t.Token,
+			Status:    t.Status(now),
+			ExpiresAt: t.ExpiresAt,
+			CreatedAt: t.CreatedAt,
+			RevokedAt: t.RevokedAt,
+		})
+	}
+
 	c.JSON(http.StatusOK, resp)
 }
+
+// GetPlaceTokenStats godoc
+// @Summary      Статистика токенов заведения
+// @Description  Сколько токенов выпущено, использовано, истекло, активно и отозвано. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Produce      json
+// @Param        id   path      string  true  "Place ID"
+// @Success      200  {object}  dto.TokenStatsResponse
+// @Failure      400  {object}  dto.ErrorResponse "invalid place id"
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "place not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to manage tokens"
+// @Router       /admin/places/{id}/tokens/stats [get]
+// @Security     BearerAuth
+func (a *Application) GetPlaceTokenStats(c *gin.Context) {
+	stats, err := a.TokenService.GetTokenStats(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TokenStatsResponse{
+		Issued:  stats.Issued,
+		Used:    stats.Used,
+		Expired: stats.Expired,
+		Active:  stats.Active,
+		Revoked: stats.Revoked,
+	})
+}
+
+// RevokeToken godoc
+// @Summary      Отзыв токена
+// @Description  Отзывает неиспользованный токен. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Produce      json
+// @Param        id   path      string  true  "Token ID"
+// @Success      200  {object}  dto.MessageResponse "token revoked"
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "token not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to manage tokens"
+// @Router       /admin/tokens/{id} [delete]
+// @Security     BearerAuth
+func (a *Application) RevokeToken(c *gin.Context) {
+	if err := a.TokenService.RevokeToken(c.Request.Context(), c.Param("id")); err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "token revoked"})
+}
+
+// AssignTokenPIN godoc
+// @Summary      PIN-код токена
+// @Description  Выдаёт активному токену числовой PIN для ручного ввода в форме отзыва вместо QR-кода. PIN уникален среди активных токенов заведения; повторный вызов возвращает уже выданный PIN. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Produce      json
+// @Param        id   path      string  true  "Token ID"
+// @Success      200  {object}  dto.TokenPINResponse
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "token not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to manage tokens"
+// @Router       /admin/tokens/{id}/pin [put]
+// @Security     BearerAuth
+func (a *Application) AssignTokenPIN(c *gin.Context) {
+	pin, err := a.TokenService.AssignPIN(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TokenPINResponse{TokenID: c.Param("id"), PIN: pin})
+}
+
+// RevokeTokens godoc
+// @Summary      Массовый отзыв токенов
+// @Description  Отзывает неиспользованные токены из списка. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.BulkTokensRequest  true  "ID токенов"
+// @Success      200      {object}  dto.BulkTokensResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      500      {object}  dto.ErrorResponse "failed to manage tokens"
+// @Router       /admin/tokens/revoke [post]
+// @Security     BearerAuth
+func (a *Application) RevokeTokens(c *gin.Context) {
+	var req dto.BulkTokensRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	affected, err := a.TokenService.RevokeTokens(c.Request.Context(), req.IDs)
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.BulkTokensResponse{Affected: affected})
+}
+
+// ExtendTokens godoc
+// @Summary      Продление токенов
+// @Description  Продлевает срок действия неиспользованных и неотозванных токенов на hours часов; истёкшие — от текущего момента. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.ExtendTokensRequest  true  "ID токенов и срок продления"
+// @Success      200      {object}  dto.BulkTokensResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      500      {object}  dto.ErrorResponse "failed to manage tokens"
+// @Router       /admin/tokens/extend [post]
+// @Security     BearerAuth
+func (a *Application) ExtendTokens(c *gin.Context) {
+	var req dto.ExtendTokensRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	affected, err := a.TokenService.ExtendTokens(c.Request.Context(), req.IDs, time.Duration(req.Hours)*time.Hour)
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.BulkTokensResponse{Affected: affected})
+}
+
+// GetTokenPolicy godoc
+// @Summary      Политика токенов заведения
+// @Description  Срок жизни токенов, стартовая пачка и параметры автодогенерации. custom — значения заведения (null — берётся из конфига), effective — действующие. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Produce      json
+// @Param        id   path      string  true  "Place ID"
+// @Success      200  {object}  dto.TokenPolicyResponse
+// @Failure      400  {object}  dto.ErrorResponse "invalid place id"
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "place not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to manage tokens"
+// @Router       /admin/places/{id}/token-policy [get]
+// @Security     BearerAuth
+func (a *Application) GetTokenPolicy(c *gin.Context) {
+	settings, err := a.TokenService.GetTokenPolicy(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenPolicyResponse(c.Param("id"), settings))
+}
+
+// UpdateTokenPolicy godoc
+// @Summary      Изменение политики токенов заведения
+// @Description  Заменяет политику целиком: не переданные поля снова берутся из конфига. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true  "Place ID"
+// @Param        request  body      dto.TokenPolicyRequest  true  "Политика токенов"
+// @Success      200      {object}  dto.TokenPolicyResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid place id / invalid token policy"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "place not found"
+// @Failure      500      {object}  dto.ErrorResponse "failed to manage tokens"
+// @Router       /admin/places/{id}/token-policy [put]
+// @Security     BearerAuth
+func (a *Application) UpdateTokenPolicy(c *gin.Context) {
+	var req dto.TokenPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	settings, err := a.TokenService.UpdateTokenPolicy(c.Request.Context(), c.Param("id"), tokenPolicyFromRequest(req))
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenPolicyResponse(c.Param("id"), settings))
+}
+
+func tokenPolicyFromRequest(req dto.TokenPolicyRequest) model.TokenPolicy {
+	policy := model.TokenPolicy{
+		InitialCount:    req.InitialCount,
+		RefillThreshold: req.RefillThreshold,
+		RefillBatch:     req.RefillBatch,
+	}
+	if req.TTLHours != nil {
+		ttl := time.Duration(*req.TTLHours) * time.Hour
+		policy.TTL = &ttl
+	}
+	return policy
+}
+
+func tokenPolicyResponse(placeID string, settings *model.TokenPolicySettings) dto.TokenPolicyResponse {
+	resp := dto.TokenPolicyResponse{
+		PlaceID: placeID,
+		Custom: dto.TokenPolicyOverrides{
+			InitialCount:    settings.Custom.InitialCount,
+			RefillThreshold: settings.Custom.RefillThreshold,
+			RefillBatch:     settings.Custom.RefillBatch,
+		},
+		Effective: dto.TokenPolicyValues{
+			TTLHours:        int(settings.Effective.TTL / time.Hour),
+			InitialCount:    settings.Effective.InitialCount,
+			RefillThreshold: settings.Effective.RefillThreshold,
+			RefillBatch:     settings.Effective.RefillBatch,
+		},
+	}
+	if settings.Custom.TTL != nil {
+		hours := int(*settings.Custom.TTL / time.Hour)
+		resp.Custom.TTLHours = &hours
+	}
+	return resp
+}
+
+func handleTokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, serviceErrors.ErrInvalidPlaceID):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidPlaceID})
+
+	case errors.Is(err, serviceErrors.ErrInvalidTokenStatus):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidTokenStatus})
+
+	case errors.Is(err, serviceErrors.ErrInvalidTokenPolicy):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidTokenPolicy})
+
+	case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrPlaceNotFound})
+
+	case errors.Is(err, serviceErrors.ErrInvalidTokenCount):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidTokenCount})
+
+	case errors.Is(err, serviceErrors.ErrInvalidExportFormat):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidExportFormat})
+
+	case errors.Is(err, serviceErrors.ErrTokenNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrTokenNotFound})
+
+	case errors.Is(err, serviceErrors.ErrTokenJobNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrTokenJobNotFound})
+
+	case errors.Is(err, serviceErrors.ErrTokenJobNotReady):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: response.ErrTokenJobNotReady})
+
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedManageTokens})
+	}
+}
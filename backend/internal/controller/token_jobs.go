@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+)
+
+// CreateTokenJob godoc
+// @Summary      Фоновая генерация большой пачки токенов
+// @Description  Ставит в очередь генерацию до 100 000 токенов. Токены создаются порциями в фоне, прогресс — в GET /admin/token-jobs/{id}. Срок действия берётся из политики заведения на момент постановки. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.CreateTokenJobRequest  true  "Заведение и количество токенов"
+// @Success      202      {object}  dto.TokenJobResponse
+// @Failure      400      {object}  dto.ErrorResponse "invalid input / invalid place id / invalid token count"
+// @Failure      403      {object}  dto.ErrorResponse "access denied"
+// @Failure      404      {object}  dto.ErrorResponse "place not found"
+// @Failure      500      {object}  dto.ErrorResponse "failed to manage tokens"
+// @Router       /admin/token-jobs [post]
+// @Security     BearerAuth
+func (a *Application) CreateTokenJob(c *gin.Context) {
+	var req dto.CreateTokenJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	job, err := a.TokenService.CreateBatchJob(c.Request.Context(), c.GetString("user_id"), req.PlaceID, req.Count)
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, tokenJobResponse(job))
+}
+
+// GetTokenJob godoc
+// @Summary      Статус фоновой генерации токенов
+// @Description  status: pending, running, done или failed; generated растёт по мере вставки порций. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Produce      json
+// @Param        id   path      string  true  "Job ID"
+// @Success      200  {object}  dto.TokenJobResponse
+// @Failure      403  {object}  dto.ErrorResponse "access denied"
+// @Failure      404  {object}  dto.ErrorResponse "token job not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to manage tokens"
+// @Router       /admin/token-jobs/{id} [get]
+// @Security     BearerAuth
+func (a *Application) GetTokenJob(c *gin.Context) {
+	job, err := a.TokenService.GetBatchJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokenJobResponse(job))
+}
+
+// ExportTokenJob godoc
+// @Summary      Выгрузка токенов фоновой задачи
+// @Description  csv — значения токенов, ссылки на форму отзыва и срок действия; zip — тот же tokens.csv и PNG с QR-кодом на каждый токен в папке qr/. Доступно после завершения задачи. Требуется право **tokens:generate**.
+// @Tags         admins
+// @Produce      text/csv
+// @Produce      application/zip
+// @Param        id      path      string  true   "Job ID"
+// @Param        format  query     string  false  "csv (по умолчанию) или zip"
+// @Success      200     {file}    binary
+// @Failure      400     {object}  dto.ErrorResponse "invalid export format"
+// @Failure      403     {object}  dto.ErrorResponse "access denied"
+// @Failure      404     {object}  dto.ErrorResponse "token job not found"
+// @Failure      409     {object}  dto.ErrorResponse "token job is not finished"
+// @Failure      500     {object}  dto.ErrorResponse "failed to manage tokens"
+// @Router       /admin/token-jobs/{id}/export [get]
+// @Security     BearerAuth
+func (a *Application) ExportTokenJob(c *gin.Context) {
+	export, err := a.TokenService.ExportBatchJob(c.Request.Context(), c.Param("id"), c.Query("format"))
+	if err != nil {
+		handleTokenError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+export.Filename+`"`)
+	c.Header("Content-Type", export.ContentType)
+	c.Status(http.StatusOK)
+
+	// Заголовки уже отправлены: ошибку посреди потока можно только записать в лог запроса
+	if err := export.Write(c.Writer); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+func tokenJobResponse(job *model.TokenBatchJob) dto.TokenJobResponse {
+	return dto.TokenJobResponse{
+		ID:         job.ID.String(),
+		PlaceID:    job.PlaceID.String(),
+		Status:     job.Status,
+		Total:      job.Total,
+		Generated:  job.Generated,
+		ExpiresAt:  job.ExpiresAt,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
@@ -32,7 +32,7 @@ func (h *Application) Signup(c *gin.Context) {
 		return
 	}
 
-	token, err := h.UserService.Signup(c.Request.Context(), req.Name, req.Email, req.Password)
+	tokens, err := h.UserService.Signup(c.Request.Context(), req.Name, req.Email, req.Password)
 	if err != nil {
 		switch {
 		case errors.Is(err, serviceErrors.ErrEmailAlreadyUsed):
@@ -44,7 +44,7 @@ func (h *Application) Signup(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.AuthResponse{Token: token})
+	c.JSON(http.StatusOK, toAuthResponse(tokens))
 }
 
 // Login godoc
@@ -67,7 +67,7 @@ func (h *Application) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := h.UserService.Login(c.Request.Context(), req.Email, req.Password)
+	tokens, err := h.UserService.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
 		switch {
 		case errors.Is(err, serviceErrors.ErrUserNotFound):
@@ -81,7 +81,85 @@ func (h *Application) Login(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.AuthResponse{Token: token})
+	c.JSON(http.StatusOK, toAuthResponse(tokens))
+}
+
+// RefreshToken godoc
+// @Summary      Обновление токенов
+// @Description  Обменивает refresh-токен на новую пару access/refresh. Повторное использование refresh-токена отзывает всю сессию.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.RefreshTokenRequest true "Refresh-токен"
+// @Success      200 {object} dto.AuthResponse
+// @Failure      400 {object} dto.ErrorResponse "invalid input"
+// @Failure      401 {object} dto.ErrorResponse "invalid refresh token / refresh token expired / reuse detected / session revoked"
+// @Failure      500 {object} dto.ErrorResponse "failed to refresh token"
+// @Router       /auth/refresh [post]
+func (h *Application) RefreshToken(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: response.ErrInvalidInput})
+		return
+	}
+
+	tokens, err := h.UserService.RefreshTokens(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceErrors.ErrInvalidToken),
+			errors.Is(err, serviceErrors.ErrUserNotFound):
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: response.ErrInvalidRefreshToken})
+
+		case errors.Is(err, serviceErrors.ErrTokenExpired):
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: response.ErrRefreshTokenExpired})
+
+		case errors.Is(err, serviceErrors.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: response.ErrRefreshTokenReused})
+
+		case errors.Is(err, serviceErrors.ErrSessionRevoked):
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: response.ErrSessionRevoked})
+
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedRefreshToken})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, toAuthResponse(tokens))
+}
+
+// Logout godoc
+// @Summary      Выход из системы
+// @Description  Отзывает текущую сессию: refresh-токены и выданные по ним access-токены перестают действовать
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} dto.MessageResponse "logged out"
+// @Failure      401 {object} dto.ErrorResponse "authentication required"
+// @Failure      500 {object} dto.ErrorResponse "failed to logout"
+// @Router       /auth/logout [post]
+func (h *Application) Logout(c *gin.Context) {
+	sessionID := c.GetString("session_id")
+	if sessionID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: response.ErrUnauthorized})
+		return
+	}
+
+	if err := h.UserService.Logout(c.Request.Context(), sessionID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedLogout})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: response.MsgLoggedOut})
+}
+
+func toAuthResponse(tokens *model.AuthTokens) dto.AuthResponse {
+	return dto.AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.AccessExpiresAt,
+	}
 }
 
 // GetUser godoc
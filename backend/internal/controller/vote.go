@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/response"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// VoteReview godoc
+// @Summary      Отметить отзыв как полезный
+// @Description  Один пользователь ставит отзыву не больше одной отметки, повторный вызов ничего не меняет. Отмечать можно только опубликованные отзывы, свой отзыв отметить нельзя.
+// @Tags         reviews
+// @Produce      json
+// @Param        id   path      string  true  "Review ID"
+// @Success      200  {object}  dto.ReviewVoteResponse
+// @Failure      401  {object}  dto.ErrorResponse "invalid user_id / unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse "cannot vote for own review"
+// @Failure      404  {object}  dto.ErrorResponse "review not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to save vote"
+// @Router       /reviews/{id}/helpful [post]
+// @Security     BearerAuth
+func (h *Application) VoteReview(c *gin.Context) {
+	votes, err := h.VoteService.Vote(c.Request.Context(), c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		handleVoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviewVoteResponse(votes))
+}
+
+// UnvoteReview godoc
+// @Summary      Снять отметку «полезный»
+// @Description  Снимает отметку пользователя с отзыва; если отметки не было, возвращает текущий счётчик
+// @Tags         reviews
+// @Produce      json
+// @Param        id   path      string  true  "Review ID"
+// @Success      200  {object}  dto.ReviewVoteResponse
+// @Failure      401  {object}  dto.ErrorResponse "invalid user_id / unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse "review not found"
+// @Failure      500  {object}  dto.ErrorResponse "failed to save vote"
+// @Router       /reviews/{id}/helpful [delete]
+// @Security     BearerAuth
+func (h *Application) UnvoteReview(c *gin.Context) {
+	votes, err := h.VoteService.Unvote(c.Request.Context(), c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		handleVoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviewVoteResponse(votes))
+}
+
+func handleVoteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, serviceErrors.ErrAccessDenied):
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: response.ErrInvalidUserID})
+
+	case errors.Is(err, serviceErrors.ErrOwnReviewVote):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{Error: response.ErrOwnReviewVote})
+
+	case errors.Is(err, serviceErrors.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: response.ErrReviewNotFound})
+
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: response.ErrFailedVote})
+	}
+}
+
+func reviewVoteResponse(votes *model.ReviewVotes) dto.ReviewVoteResponse {
+	return dto.ReviewVoteResponse{
+		ReviewID:     votes.ReviewID.String(),
+		HelpfulCount: votes.HelpfulCount,
+		Voted:        votes.Voted,
+	}
+}
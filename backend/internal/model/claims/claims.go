@@ -4,7 +4,8 @@ import "github.com/golang-jwt/jwt/v5"
 
 // Claims — структура для работы с JWT
 type Claims struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID    string `json:"user_id"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
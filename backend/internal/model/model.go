@@ -1,9 +1,11 @@
 package model
 
 import (
+	"io"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/pagination"
 )
 
 type User struct {
@@ -18,11 +20,49 @@ type User struct {
 }
 
 type Place struct {
-	ID        uuid.UUID
-	Name      string
-	Address   string
-	CreatedAt time.Time
-	IsDeleted bool
+	ID          uuid.UUID
+	Name        string
+	Address     string
+	CreatedAt   time.Time
+	IsDeleted   bool
+	TokenPolicy TokenPolicy
+	// ModerationMode — режим модерации отзывов заведения; nil — режим из глобального конфига
+	ModerationMode *string
+}
+
+// TokenPolicy — настройки токенов заведения. Пустое поле означает значение из глобального конфига.
+type TokenPolicy struct {
+	TTL             *time.Duration
+	InitialCount    *int
+	RefillThreshold *int
+	RefillBatch     *int
+}
+
+// ResolvedTokenPolicy — политика заведения, дополненная значениями из конфига
+type ResolvedTokenPolicy struct {
+	TTL             time.Duration
+	InitialCount    int
+	RefillThreshold int
+	RefillBatch     int
+}
+
+type TokenPolicySettings struct {
+	Custom    TokenPolicy
+	Effective ResolvedTokenPolicy
+}
+
+// Режимы модерации отзывов
+const (
+	// ModerationModePre — отзыв ждёт одобрения модератора, баллы начисляются при публикации
+	ModerationModePre = "pre"
+	// ModerationModePost — отзыв публикуется сразу, модератор может скрыть или отклонить его позже
+	ModerationModePost = "post"
+)
+
+// ModerationSettings — режим модерации заведения: собственный (nil — не задан) и действующий
+type ModerationSettings struct {
+	Custom    *string
+	Effective string
 }
 
 type ReviewToken struct {
@@ -31,29 +71,174 @@ type ReviewToken struct {
 	Token     string
 	IsUsed    bool
 	ExpiresAt time.Time
+	CreatedAt time.Time
+	RevokedAt *time.Time
+	// BatchJobID — фоновая задача, которой создан токен; nil для обычной генерации
+	BatchJobID *uuid.UUID
+	// Receipt — покупка, по которой кассовая система выпустила токен; nil для обычных токенов
+	Receipt *Receipt
+	Labels  TokenLabels
+	// PIN — числовой код для ручного ввода в форме отзыва; nil, если не выдавался
+	PIN *string
+}
+
+// TokenLabels — атрибуция токена: кто из сотрудников выдал QR-код, за каким столом, по какой кампании.
+// Все поля необязательны; отзыв по токену наследует их.
+type TokenLabels struct {
+	StaffID  *uuid.UUID
+	Table    *string
+	Campaign *string
+}
+
+// Разрезы отчёта по меткам
+const (
+	LabelDimensionStaff    = "staff"
+	LabelDimensionTable    = "table"
+	LabelDimensionCampaign = "campaign"
+)
+
+// LabelStat — отзывы заведения с одним значением метки. StaffName заполняется только в разрезе по сотрудникам.
+type LabelStat struct {
+	Label         string
+	StaffName     string
+	Reviews       int
+	AverageRating float64
+}
+
+// Receipt — чек из кассовой системы заведения. Amount — в минимальных единицах валюты (копейках).
+type Receipt struct {
+	ID          string
+	Amount      int64
+	PurchasedAt time.Time
+}
+
+// Статусы токена отзыва
+const (
+	TokenStatusActive  = "active"
+	TokenStatusUsed    = "used"
+	TokenStatusExpired = "expired"
+	TokenStatusRevoked = "revoked"
+)
+
+// Status вычисляет статус токена: использованный важнее отозванного, отозванный — истёкшего
+func (t ReviewToken) Status(now time.Time) string {
+	switch {
+	case t.IsUsed:
+		return TokenStatusUsed
+	case t.RevokedAt != nil:
+		return TokenStatusRevoked
+	case !t.ExpiresAt.After(now):
+		return TokenStatusExpired
+	default:
+		return TokenStatusActive
+	}
+}
+
+type TokenFilter struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+type TokenPage struct {
+	Tokens []ReviewToken
+	Total  int
+}
+
+type TokenStats struct {
+	Issued  int
+	Used    int
+	Expired int
+	Active  int
+	Revoked int
+}
+
+// ReviewTokenInfo — публичные сведения о токене для страницы по QR-коду
+type ReviewTokenInfo struct {
+	Token     string
+	PlaceID   uuid.UUID
+	PlaceName string
+	IsUsed    bool
+	IsRevoked bool
+	ExpiresAt time.Time
 }
 
 type Review struct {
 	ID        uuid.UUID
 	UserID    uuid.UUID
 	PlaceID   uuid.UUID
-	TokenID   uuid.UUID
+	TokenID   *uuid.UUID
 	Content   string
 	Rating    int
 	CreatedAt time.Time
 	UpdatedAt *time.Time
+	// ReceiptID — чек токена, по которому оставлен отзыв: признак подтверждённой покупки
+	ReceiptID *string
+	// Points — баллы автору за отзыв; при премодерации начисляются только при публикации
+	Points int
+	Labels TokenLabels
+	// Status — статус модерации; в публичных списках и статистике учитываются только опубликованные
+	Status           string
+	ModerationReason *string
+	ModeratedBy      *uuid.UUID
+	ModeratedAt      *time.Time
+	// FilterRule — правило фильтра текста, сработавшее на отзыве
+	FilterRule *string
+	// Reply — ответ заведения; заполняется только в публичном списке отзывов
+	Reply *ReviewReply
+	// Photos — фотографии к отзыву; заполняются только в публичном списке отзывов
+	Photos []ReviewPhoto
+	// HelpfulCount — число отметок «полезный отзыв» от читателей
+	HelpfulCount int
+}
+
+// ReviewRevision — редакция отзыва. Revision 1 — текст при создании, каждая правка автора добавляет следующую.
+type ReviewRevision struct {
+	ID        uuid.UUID
+	ReviewID  uuid.UUID
+	Revision  int
+	Content   string
+	Rating    int
+	CreatedAt time.Time
 }
 
+// Статусы модерации отзыва
+const (
+	ReviewStatusPending   = "pending"
+	ReviewStatusPublished = "published"
+	ReviewStatusRejected  = "rejected"
+	ReviewStatusHidden    = "hidden"
+)
+
+// ModerationFilter — выборка очереди модерации; пустой Status означает ReviewStatusPending
+type ModerationFilter struct {
+	Status  string
+	PlaceID *uuid.UUID
+	Page    pagination.Params
+}
+
+// Режимы сортировки публичного списка отзывов
+const (
+	ReviewSortDateDesc   = "date_desc"
+	ReviewSortDateAsc    = "date_asc"
+	ReviewSortRatingDesc = "rating_desc"
+	ReviewSortRatingAsc  = "rating_asc"
+	ReviewSortHelpful    = "helpful"
+)
+
+// ReviewFilter — выборка публичного списка отзывов; пустой Sort означает ReviewSortDateDesc
 type ReviewFilter struct {
 	Rating    int
 	HasRating bool
 	Sort      string
 	FromDate  *time.Time
 	ToDate    *time.Time
+	Page      pagination.Params
 }
 
 type GenerateTokensResult struct {
-	Tokens []string
+	Tokens    []string
+	ExpiresAt time.Time
 }
 
 type AdminStats struct {
@@ -61,6 +246,8 @@ type AdminStats struct {
 	TotalReviews  int
 	AverageRating float64
 	TotalBonuses  int
+	// PendingReviews — отзывы в очереди модерации; в TotalReviews и AverageRating не входят
+	PendingReviews int
 }
 
 type LeaderboardEntry struct {
@@ -85,6 +272,8 @@ type BonusReward struct {
 	QRToken        string
 	IsUsed         bool
 	UsedAt         *time.Time
+	ValidatedBy    *uuid.UUID
+	ValidatedPlace *uuid.UUID
 }
 
 type UserRestriction struct {
@@ -96,6 +285,9 @@ type UserRestriction struct {
 	ExpiresAt       time.Time
 }
 
+// RestrictionTypeReviewBan — запрет оставлять отзывы, выдаётся по итогам разбора жалоб
+const RestrictionTypeReviewBan = "review_ban"
+
 type UserStats struct {
 	TotalReviews  int
 	AvgRating     float64
@@ -109,3 +301,280 @@ type BonusLeaderboardEntry struct {
 	BonusesCount int
 	PointsSpent  int
 }
+
+type RefreshToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	FamilyID  uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+}
+
+type AuthTokens struct {
+	AccessToken     string
+	RefreshToken    string
+	AccessExpiresAt time.Time
+}
+
+type PlaceMember struct {
+	ID        uuid.UUID
+	PlaceID   uuid.UUID
+	UserID    uuid.UUID
+	UserName  string
+	UserEmail string
+	Role      string
+	CreatedAt time.Time
+}
+
+// Kiosk — режим планшета в заведении: QR-код отзыва меняется каждые Step
+type Kiosk struct {
+	PlaceID   uuid.UUID
+	Secret    []byte
+	Step      time.Duration
+	Enabled   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type KioskCode struct {
+	Code       string
+	URL        string
+	ValidUntil time.Time
+	Step       time.Duration
+}
+
+// RefillJob — задача фонового воркера на догенерацию токенов заведения
+type RefillJob struct {
+	PlaceID   uuid.UUID
+	Requests  int
+	Attempts  int
+	RunAt     time.Time
+	LastError *string
+	CreatedAt time.Time
+}
+
+// Статусы фоновой задачи генерации токенов
+const (
+	TokenJobStatusPending = "pending"
+	TokenJobStatusRunning = "running"
+	TokenJobStatusDone    = "done"
+	TokenJobStatusFailed  = "failed"
+)
+
+// TokenBatchJob — фоновая генерация большой пачки токенов с последующей выгрузкой
+type TokenBatchJob struct {
+	ID          uuid.UUID
+	PlaceID     uuid.UUID
+	RequestedBy *uuid.UUID
+	Total       int
+	Generated   int
+	Status      string
+	ExpiresAt   time.Time
+	Error       *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	FinishedAt  *time.Time
+}
+
+// TokenExport — готовая к отдаче выгрузка токенов; Write пишет содержимое потоком
+type TokenExport struct {
+	Filename    string
+	ContentType string
+	Write       func(w io.Writer) error
+}
+
+// POSIntegration — подключение кассовой системы заведения; Secret подписывает события о чеках
+type POSIntegration struct {
+	PlaceID   uuid.UUID
+	Secret    []byte
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ReceiptEvent — подписанное событие о покупке от кассовой системы
+type ReceiptEvent struct {
+	PlaceID   uuid.UUID
+	Receipt   Receipt
+	Timestamp string
+	Signature string
+	Payload   []byte
+}
+
+// ReceiptToken — токен, выпущенный по чеку; Created=false, если чек уже присылали
+type ReceiptToken struct {
+	Token     string
+	URL       string
+	PIN       string
+	ExpiresAt time.Time
+	Created   bool
+}
+
+// TokenScan — переход по короткой ссылке токена
+type TokenScan struct {
+	ID        uuid.UUID
+	TokenID   uuid.UUID
+	PlaceID   uuid.UUID
+	UserAgent string
+	ScannedAt time.Time
+	// ClientKey — хеш IP и User-Agent клиента, по нему склеиваются повторные переходы
+	ClientKey string
+	// SessionKey передаётся в адресе формы и нужен, чтобы отметить вход
+	SessionKey string
+	// UserID и OpenedAt — вошедший пользователь, открывший форму после перехода
+	UserID   *uuid.UUID
+	OpenedAt *time.Time
+}
+
+// ScanFunnel — воронка заведения по токенам, отсканированным в периоде [From, To).
+// Logins, Reviews и Points считаются по этим же токенам, даже если отзыв оставлен позже;
+// Points — только по опубликованным отзывам.
+type ScanFunnel struct {
+	PlaceID       uuid.UUID
+	From          time.Time
+	To            time.Time
+	Scans         int
+	ScannedTokens int
+	Logins        int
+	Reviews       int
+	Points        int
+}
+
+// PINAttempt — попытка отправить отзыв по PIN-коду; неудачные попытки считаются в лимитах
+type PINAttempt struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	IP        string
+	PlaceID   uuid.UUID
+	PIN       string
+	Succeeded bool
+	CreatedAt time.Time
+}
+
+// ReviewReply — публичный ответ заведения на отзыв, не больше одного на отзыв.
+// AuthorID — сотрудник, написавший ответ; nil, если его аккаунт удалён.
+type ReviewReply struct {
+	ID        uuid.UUID
+	ReviewID  uuid.UUID
+	PlaceID   uuid.UUID
+	AuthorID  *uuid.UUID
+	Content   string
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+// ReviewVotes — отметки «полезный отзыв» после голоса или его отмены. Voted — есть ли отметка пользователя.
+type ReviewVotes struct {
+	ReviewID     uuid.UUID
+	HelpfulCount int
+	Voted        bool
+}
+
+// Причины жалоб на отзыв
+const (
+	ReportReasonSpam      = "spam"
+	ReportReasonOffensive = "offensive"
+	ReportReasonFake      = "fake"
+	ReportReasonOffTopic  = "off_topic"
+)
+
+// Статусы жалобы
+const (
+	ReportStatusOpen     = "open"
+	ReportStatusResolved = "resolved"
+)
+
+// Решения по жалобам на отзыв
+const (
+	ReportActionDismiss         = "dismiss"
+	ReportActionHide            = "hide"
+	ReportActionHideAndRestrict = "hide_and_restrict"
+)
+
+// ReviewReport — жалоба пользователя на отзыв. Resolution — решение модератора, общее для всех жалоб на отзыв.
+type ReviewReport struct {
+	ID         uuid.UUID
+	ReviewID   uuid.UUID
+	ReporterID uuid.UUID
+	Reason     string
+	Comment    *string
+	Status     string
+	Resolution *string
+	ResolvedBy *uuid.UUID
+	ResolvedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// ReportedReview — отзыв в очереди жалоб: число жалоб в статусе выборки и их разбивка по причинам
+type ReportedReview struct {
+	Review          Review
+	Reports         int
+	Reasons         map[string]int
+	FirstReportedAt time.Time
+	LastReportedAt  time.Time
+}
+
+// ReportFilter — выборка очереди жалоб; пустой Status означает ReportStatusOpen
+type ReportFilter struct {
+	Status  string
+	PlaceID *uuid.UUID
+	Limit   int
+	Offset  int
+}
+
+// ReportResolution — итог разбора жалоб на отзыв
+type ReportResolution struct {
+	ReviewID     uuid.UUID
+	Action       string
+	Resolved     int
+	ReviewStatus string
+	// RestrictedUntil — окончание запрета на отзывы для автора при hide_and_restrict
+	RestrictedUntil *time.Time
+}
+
+// ReviewPhoto — фотография к отзыву. Файлы лежат в хранилище по StorageKey и ThumbnailKey,
+// URL и ThumbnailURL заполняет сервис по настройкам хранилища.
+type ReviewPhoto struct {
+	ID           uuid.UUID
+	ReviewID     uuid.UUID
+	StorageKey   string
+	ThumbnailKey string
+	ContentType  string
+	SizeBytes    int64
+	Width        int
+	Height       int
+	Position     int
+	CreatedAt    time.Time
+	URL          string
+	ThumbnailURL string
+}
+
+// PhotoUpload — загружаемый файл фотографии до проверки
+type PhotoUpload struct {
+	Filename string
+	Size     int64
+	Open     func() (io.ReadCloser, error)
+}
+
+// ReplyTemplate — сохранённый шаблон ответа заведения
+type ReplyTemplate struct {
+	ID        uuid.UUID
+	PlaceID   uuid.UUID
+	Title     string
+	Content   string
+	CreatedBy *uuid.UUID
+	CreatedAt time.Time
+}
+
+// ReplyStats — ответы заведения на опубликованные отзывы, оставленные в периоде [From, To).
+// MedianResponse — медиана времени от отзыва до ответа; nil, если ответов нет.
+type ReplyStats struct {
+	PlaceID        uuid.UUID
+	From           time.Time
+	To             time.Time
+	Reviews        int
+	Replied        int
+	MedianResponse *time.Duration
+}
@@ -0,0 +1,116 @@
+package rbac
+
+// Роли пользователей
+const (
+	RoleUser       = "user"
+	RoleAdmin      = "admin"
+	RolePlaceOwner = "place_owner"
+	RoleStaff      = "staff"
+)
+
+// Роли сотрудника внутри заведения (place_members.role)
+const (
+	PlaceRoleOwner   = "owner"
+	PlaceRoleManager = "manager"
+	PlaceRoleCashier = "cashier"
+)
+
+// Permission — право на выполнение действия, проверяется в middleware
+type Permission string
+
+const (
+	PermPlacesRead      Permission = "places:read"
+	PermPlacesWrite     Permission = "places:write"
+	PermTokensGenerate  Permission = "tokens:generate"
+	PermTokensPrint     Permission = "tokens:print"
+	PermStatsRead       Permission = "stats:read"
+	PermBonusesValidate Permission = "bonuses:validate"
+	PermUsersManage     Permission = "users:manage"
+	PermMembersManage   Permission = "members:manage"
+	PermKioskManage     Permission = "kiosk:manage"
+	PermKioskDisplay    Permission = "kiosk:display"
+	PermPOSManage       Permission = "pos:manage"
+	PermReviewsModerate Permission = "reviews:moderate"
+	PermReviewsReply    Permission = "reviews:reply"
+	// PermPlaceReports открывает отчёты заведения сотрудникам; к какому заведению пускать
+	// и какие роли внутри него, решает сервис по членству
+	PermPlaceReports Permission = "places:reports"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleUser: {},
+	RoleStaff: {
+		// печатать может менеджер; кассиру печать закрывает сервис по роли в заведении
+		PermTokensPrint,
+		PermBonusesValidate,
+		PermKioskDisplay,
+		PermReviewsReply,
+		PermPlaceReports,
+	},
+	RolePlaceOwner: {
+		PermPlacesRead,
+		PermTokensPrint,
+		PermBonusesValidate,
+		PermMembersManage,
+		PermKioskManage,
+		PermKioskDisplay,
+		PermPOSManage,
+		PermReviewsReply,
+		PermPlaceReports,
+	},
+	RoleAdmin: {
+		PermPlacesRead,
+		PermPlacesWrite,
+		PermTokensGenerate,
+		PermTokensPrint,
+		PermStatsRead,
+		PermBonusesValidate,
+		PermUsersManage,
+		PermMembersManage,
+		PermKioskManage,
+		PermKioskDisplay,
+		PermPOSManage,
+		PermReviewsModerate,
+		PermReviewsReply,
+		PermPlaceReports,
+	},
+}
+
+// HasPermission сообщает, выдано ли роли указанное право. Неизвестные роли прав не имеют.
+func HasPermission(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidPlaceRole сообщает, допустима ли роль сотрудника заведения
+func IsValidPlaceRole(role string) bool {
+	switch role {
+	case PlaceRoleOwner, PlaceRoleManager, PlaceRoleCashier:
+		return true
+	}
+	return false
+}
+
+// GlobalRoleForPlaceRoles возвращает глобальную роль по ролям пользователя во всех его заведениях:
+// владелец хотя бы одного — place_owner, сотрудник — staff, без заведений — user
+func GlobalRoleForPlaceRoles(placeRoles []string) string {
+	if len(placeRoles) == 0 {
+		return RoleUser
+	}
+	for _, role := range placeRoles {
+		if role == PlaceRoleOwner {
+			return RolePlaceOwner
+		}
+	}
+	return RoleStaff
+}
+
+// IsValidRole сообщает, описана ли роль в модели доступа
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
@@ -7,6 +7,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
 )
 
 type PostgresAdminRepository struct {
@@ -24,19 +25,22 @@ func NewPostgresAdminRepository(db *pgxpool.Pool) *PostgresAdminRepository {
 func (r *PostgresAdminRepository) GetAdminStats(ctx context.Context) (*model.AdminStats, error) {
 	var stats model.AdminStats
 
+	// Отзывы и средняя оценка — только по опубликованным; очередь модерации считается отдельно
 	query := `
 		SELECT
 			(SELECT COUNT(*) FROM users WHERE is_deleted = false) AS total_users,
-			(SELECT COUNT(*) FROM reviews WHERE is_deleted = false) AS total_reviews,
-			COALESCE((SELECT AVG(rating)::float FROM reviews WHERE is_deleted = false), 0) AS average_rating,
-			COALESCE((SELECT COUNT(*) FROM bonus_rewards), 0) AS total_bonuses;
+			(SELECT COUNT(*) FROM reviews WHERE is_deleted = false AND status = 'published') AS total_reviews,
+			COALESCE((SELECT AVG(rating)::float FROM reviews WHERE is_deleted = false AND status = 'published'), 0) AS average_rating,
+			COALESCE((SELECT COUNT(*) FROM bonus_rewards), 0) AS total_bonuses,
+			(SELECT COUNT(*) FROM reviews WHERE is_deleted = false AND status = 'pending') AS pending_reviews;
 	`
 
-	if err := r.db.QueryRow(ctx, query).Scan(
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query).Scan(
 		&stats.TotalUsers,
 		&stats.TotalReviews,
 		&stats.AverageRating,
 		&stats.TotalBonuses,
+		&stats.PendingReviews,
 	); err != nil {
 		return nil, fmt.Errorf("get admin stats: %w", err)
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
 
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
 	srvErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
@@ -25,6 +26,8 @@ const (
 	bonusQRTokenColumn     = "qr_token"
 	bonusIsUsedColumn      = "is_used"
 	bonusUsedAtColumn      = "used_at"
+	bonusValidatedByColumn = "validated_by"
+	bonusValidatedPlaceCol = "validated_place_id"
 )
 
 type PostgresBonusRepository struct {
@@ -68,7 +71,7 @@ func (r *PostgresBonusRepository) CreateBonus(ctx context.Context, bonus *model.
 		return fmt.Errorf("build CreateBonus query: %w", err)
 	}
 
-	_, err = r.db.Exec(ctx, query, args...)
+	_, err = transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("exec CreateBonus: %w", err)
 	}
@@ -92,6 +95,8 @@ func (r *PostgresBonusRepository) GetBonusesByUser(ctx context.Context, userID s
 			bonusQRTokenColumn,
 			bonusIsUsedColumn,
 			bonusUsedAtColumn,
+			bonusValidatedByColumn,
+			bonusValidatedPlaceCol,
 		).
 		From(bonusTable).
 		Where(sq.Eq{bonusUserIDColumn: uid}).
@@ -101,7 +106,7 @@ func (r *PostgresBonusRepository) GetBonusesByUser(ctx context.Context, userID s
 		return nil, fmt.Errorf("build GetBonusesByUser query: %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("exec GetBonusesByUser: %w", err)
 	}
@@ -119,6 +124,8 @@ func (r *PostgresBonusRepository) GetBonusesByUser(ctx context.Context, userID s
 			&b.QRToken,
 			&b.IsUsed,
 			&b.UsedAt,
+			&b.ValidatedBy,
+			&b.ValidatedPlace,
 		); err != nil {
 			return nil, fmt.Errorf("scan GetBonusesByUser: %w", err)
 		}
@@ -128,22 +135,42 @@ func (r *PostgresBonusRepository) GetBonusesByUser(ctx context.Context, userID s
 	return bonuses, rows.Err()
 }
 
-func (r *PostgresBonusRepository) MarkBonusUsed(ctx context.Context, qrToken string) error {
+// MarkBonusUsed гасит бонус и фиксирует, кто и где его принял.
+// Если бонус уже погашен (в том числе параллельным запросом), возвращает ErrBonusAlreadyUsed.
+func (r *PostgresBonusRepository) MarkBonusUsed(ctx context.Context, qrToken, validatorID, placeID string) error {
+	validatorUID, err := uuid.Parse(validatorID)
+	if err != nil {
+		return fmt.Errorf("invalid validator id: %w", err)
+	}
+	placeUID, err := uuid.Parse(placeID)
+	if err != nil {
+		return fmt.Errorf("invalid place id: %w", err)
+	}
+
 	query, args, err := r.builder.
 		Update(bonusTable).
 		Set(bonusIsUsedColumn, true).
 		Set(bonusUsedAtColumn, time.Now()).
-		Where(sq.Eq{bonusQRTokenColumn: qrToken}).
+		Set(bonusValidatedByColumn, validatorUID).
+		Set(bonusValidatedPlaceCol, placeUID).
+		Where(sq.Eq{
+			bonusQRTokenColumn: qrToken,
+			bonusIsUsedColumn:  false,
+		}).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("build MarkBonusUsed query: %w", err)
 	}
 
-	_, err = r.db.Exec(ctx, query, args...)
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("exec MarkBonusUsed: %w", err)
 	}
 
+	if res.RowsAffected() == 0 {
+		return srvErrors.ErrBonusAlreadyUsed
+	}
+
 	return nil
 }
 
@@ -158,6 +185,8 @@ func (r *PostgresBonusRepository) GetByQRToken(ctx context.Context, qrToken stri
 			bonusQRTokenColumn,
 			bonusIsUsedColumn,
 			bonusUsedAtColumn,
+			bonusValidatedByColumn,
+			bonusValidatedPlaceCol,
 		).
 		From(bonusTable).
 		Where(sq.Eq{bonusQRTokenColumn: qrToken}).
@@ -167,7 +196,7 @@ func (r *PostgresBonusRepository) GetByQRToken(ctx context.Context, qrToken stri
 		return nil, fmt.Errorf("build GetByQRToken query: %w", err)
 	}
 
-	row := r.db.QueryRow(ctx, query, args...)
+	row := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...)
 
 	var b model.BonusReward
 	err = row.Scan(
@@ -179,6 +208,8 @@ func (r *PostgresBonusRepository) GetByQRToken(ctx context.Context, qrToken stri
 		&b.QRToken,
 		&b.IsUsed,
 		&b.UsedAt,
+		&b.ValidatedBy,
+		&b.ValidatedPlace,
 	)
 
 	if err != nil {
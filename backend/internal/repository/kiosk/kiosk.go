@@ -0,0 +1,165 @@
+package kiosk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+)
+
+const (
+	kioskTable           = "place_kiosks"
+	kioskPlaceIDColumn   = "place_id"
+	kioskSecretColumn    = "secret"
+	kioskStepColumn      = "step_seconds"
+	kioskEnabledColumn   = "enabled"
+	kioskCreatedAtColumn = "created_at"
+	kioskUpdatedAtColumn = "updated_at"
+
+	submissionTable        = "kiosk_submissions"
+	submissionPlaceIDCol   = "place_id"
+	submissionUserIDCol    = "user_id"
+	submissionWindowCol    = "time_window"
+	submissionCreatedAtCol = "created_at"
+)
+
+type PostgresKioskRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresKioskRepository(db *pgxpool.Pool) *PostgresKioskRepository {
+	return &PostgresKioskRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// UpsertKiosk включает киоск заведения; при повторном включении секрет и шаг заменяются
+func (r *PostgresKioskRepository) UpsertKiosk(ctx context.Context, kiosk *model.Kiosk) error {
+	query, args, err := r.builder.
+		Insert(kioskTable).
+		Columns(
+			kioskPlaceIDColumn,
+			kioskSecretColumn,
+			kioskStepColumn,
+			kioskEnabledColumn,
+			kioskCreatedAtColumn,
+			kioskUpdatedAtColumn,
+		).
+		Values(
+			kiosk.PlaceID,
+			kiosk.Secret,
+			int(kiosk.Step/time.Second),
+			kiosk.Enabled,
+			kiosk.CreatedAt,
+			kiosk.UpdatedAt,
+		).
+		Suffix("ON CONFLICT (" + kioskPlaceIDColumn + ") DO UPDATE SET " +
+			kioskSecretColumn + " = EXCLUDED." + kioskSecretColumn + ", " +
+			kioskStepColumn + " = EXCLUDED." + kioskStepColumn + ", " +
+			kioskEnabledColumn + " = EXCLUDED." + kioskEnabledColumn + ", " +
+			kioskUpdatedAtColumn + " = EXCLUDED." + kioskUpdatedAtColumn).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build UpsertKiosk query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec UpsertKiosk: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresKioskRepository) GetKiosk(ctx context.Context, placeID uuid.UUID) (*model.Kiosk, error) {
+	query, args, err := r.builder.
+		Select(
+			kioskPlaceIDColumn,
+			kioskSecretColumn,
+			kioskStepColumn,
+			kioskEnabledColumn,
+			kioskCreatedAtColumn,
+			kioskUpdatedAtColumn,
+		).
+		From(kioskTable).
+		Where(sq.Eq{kioskPlaceIDColumn: placeID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetKiosk query: %w", err)
+	}
+
+	var (
+		kiosk       model.Kiosk
+		stepSeconds int
+	)
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
+		&kiosk.PlaceID,
+		&kiosk.Secret,
+		&stepSeconds,
+		&kiosk.Enabled,
+		&kiosk.CreatedAt,
+		&kiosk.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("exec GetKiosk: %w", err)
+	}
+	kiosk.Step = time.Duration(stepSeconds) * time.Second
+
+	return &kiosk, nil
+}
+
+// DisableKiosk выключает киоск и возвращает false, если он не был включён
+func (r *PostgresKioskRepository) DisableKiosk(ctx context.Context, placeID uuid.UUID) (bool, error) {
+	query, args, err := r.builder.
+		Update(kioskTable).
+		Set(kioskEnabledColumn, false).
+		Set(kioskUpdatedAtColumn, time.Now()).
+		Where(sq.Eq{
+			kioskPlaceIDColumn: placeID,
+			kioskEnabledColumn: true,
+		}).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build DisableKiosk query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("exec DisableKiosk: %w", err)
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// ClaimWindow фиксирует отправку отзыва пользователем в окне кода.
+// Возвращает false, если в этом окне пользователь уже отправлял отзыв.
+func (r *PostgresKioskRepository) ClaimWindow(ctx context.Context, placeID, userID uuid.UUID, window int64) (bool, error) {
+	query, args, err := r.builder.
+		Insert(submissionTable).
+		Columns(
+			submissionPlaceIDCol,
+			submissionUserIDCol,
+			submissionWindowCol,
+			submissionCreatedAtCol,
+		).
+		Values(placeID, userID, window, time.Now()).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build ClaimWindow query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("exec ClaimWindow: %w", err)
+	}
+
+	return res.RowsAffected() > 0, nil
+}
@@ -7,6 +7,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
 )
 
 type Repository struct {
@@ -33,6 +34,7 @@ func (r *Repository) GetTopUsers(ctx context.Context, limit int, filter model.Le
 		Join("users ON users.id = reviews.user_id").
 		Where(sq.Eq{
 			"reviews.is_deleted": false,
+			"reviews.status":     model.ReviewStatusPublished,
 		}).
 		GroupBy("users.id", "users.name")
 
@@ -63,7 +65,7 @@ func (r *Repository) GetTopUsers(ctx context.Context, limit int, filter model.Le
 		return nil, fmt.Errorf("build SQL query: %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("execute query: %w", err)
 	}
@@ -98,6 +100,7 @@ func (r *Repository) GetTopPlaces(ctx context.Context, limit int, filter model.L
 		Join("places ON places.id = reviews.place_id").
 		Where(sq.Eq{
 			"reviews.is_deleted": false,
+			"reviews.status":     model.ReviewStatusPublished,
 		}).
 		GroupBy("places.id", "places.name")
 
@@ -128,7 +131,7 @@ func (r *Repository) GetTopPlaces(ctx context.Context, limit int, filter model.L
 		return nil, fmt.Errorf("build SQL query: %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("execute query: %w", err)
 	}
@@ -168,7 +171,7 @@ func (r *Repository) GetTopBonusUsers(ctx context.Context) ([]model.BonusLeaderb
 		return nil, fmt.Errorf("build bonus leaderboard SQL: %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("exec bonus leaderboard: %w", err)
 	}
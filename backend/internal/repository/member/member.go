@@ -0,0 +1,227 @@
+package member
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	srvErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+const (
+	memberTable           = "place_members"
+	memberIDColumn        = "place_members.id"
+	memberPlaceIDColumn   = "place_members.place_id"
+	memberUserIDColumn    = "place_members.user_id"
+	memberRoleColumn      = "place_members.role"
+	memberCreatedAtColumn = "place_members.created_at"
+
+	uniqueViolationCode = "23505"
+)
+
+type PostgresMemberRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresMemberRepository(db *pgxpool.Pool) *PostgresMemberRepository {
+	return &PostgresMemberRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *PostgresMemberRepository) AddMember(ctx context.Context, member *model.PlaceMember) error {
+	query, args, err := r.builder.
+		Insert(memberTable).
+		Columns("id", "place_id", "user_id", "role", "created_at").
+		Values(
+			member.ID,
+			member.PlaceID,
+			member.UserID,
+			member.Role,
+			member.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build AddMember query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return srvErrors.ErrMemberAlreadyExists
+		}
+		return fmt.Errorf("exec AddMember: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresMemberRepository) RemoveMember(ctx context.Context, placeID, userID string) error {
+	placeUID, err := uuid.Parse(placeID)
+	if err != nil {
+		return fmt.Errorf("invalid place id: %w", err)
+	}
+	userUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Delete(memberTable).
+		Where(sq.Eq{
+			"place_id": placeUID,
+			"user_id":  userUID,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build RemoveMember query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec RemoveMember: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PostgresMemberRepository) GetMember(ctx context.Context, placeID, userID string) (*model.PlaceMember, error) {
+	placeUID, err := uuid.Parse(placeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid place id: %w", err)
+	}
+	userUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+
+	query, args, err := r.selectMembers().
+		Where(sq.Eq{
+			memberPlaceIDColumn: placeUID,
+			memberUserIDColumn:  userUID,
+		}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetMember query: %w", err)
+	}
+
+	var m model.PlaceMember
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
+		&m.ID,
+		&m.PlaceID,
+		&m.UserID,
+		&m.UserName,
+		&m.UserEmail,
+		&m.Role,
+		&m.CreatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("scan GetMember: %w", err)
+	}
+
+	return &m, nil
+}
+
+func (r *PostgresMemberRepository) ListMembers(ctx context.Context, placeID string) ([]model.PlaceMember, error) {
+	placeUID, err := uuid.Parse(placeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid place id: %w", err)
+	}
+
+	query, args, err := r.selectMembers().
+		Where(sq.Eq{memberPlaceIDColumn: placeUID}).
+		OrderBy(memberCreatedAtColumn + " ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build ListMembers query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec ListMembers: %w", err)
+	}
+	defer rows.Close()
+
+	members := make([]model.PlaceMember, 0)
+	for rows.Next() {
+		var m model.PlaceMember
+		if err := rows.Scan(
+			&m.ID,
+			&m.PlaceID,
+			&m.UserID,
+			&m.UserName,
+			&m.UserEmail,
+			&m.Role,
+			&m.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan ListMembers: %w", err)
+		}
+		members = append(members, m)
+	}
+
+	return members, rows.Err()
+}
+
+// ListUserPlaceRoles возвращает различные роли пользователя во всех заведениях, где он в штате
+func (r *PostgresMemberRepository) ListUserPlaceRoles(ctx context.Context, userID string) ([]string, error) {
+	userUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Select(memberRoleColumn).
+		Distinct().
+		From(memberTable).
+		Where(sq.Eq{memberUserIDColumn: userUID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build ListUserPlaceRoles query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec ListUserPlaceRoles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make([]string, 0)
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("scan ListUserPlaceRoles: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+func (r *PostgresMemberRepository) selectMembers() sq.SelectBuilder {
+	return r.builder.
+		Select(
+			memberIDColumn,
+			memberPlaceIDColumn,
+			memberUserIDColumn,
+			"COALESCE(users.name, '')",
+			"users.email",
+			memberRoleColumn,
+			memberCreatedAtColumn,
+		).
+		From(memberTable).
+		Join("users ON users.id = place_members.user_id")
+}
@@ -0,0 +1,312 @@
+package photo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+)
+
+const (
+	photoTable              = "review_photos"
+	photoIDColumn           = "id"
+	photoReviewIDColumn     = "review_id"
+	photoStorageKeyColumn   = "storage_key"
+	photoThumbnailKeyColumn = "thumbnail_key"
+	photoContentTypeColumn  = "content_type"
+	photoSizeBytesColumn    = "size_bytes"
+	photoWidthColumn        = "width"
+	photoHeightColumn       = "height"
+	photoPositionColumn     = "position"
+	photoCreatedAtColumn    = "created_at"
+	photoDeletedAtColumn    = "deleted_at"
+)
+
+var photoColumns = []string{
+	photoIDColumn,
+	photoReviewIDColumn,
+	photoStorageKeyColumn,
+	photoThumbnailKeyColumn,
+	photoContentTypeColumn,
+	photoSizeBytesColumn,
+	photoWidthColumn,
+	photoHeightColumn,
+	photoPositionColumn,
+	photoCreatedAtColumn,
+}
+
+type PostgresPhotoRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresPhotoRepository(db *pgxpool.Pool) *PostgresPhotoRepository {
+	return &PostgresPhotoRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *PostgresPhotoRepository) CreatePhoto(ctx context.Context, photo *model.ReviewPhoto) error {
+	query, args, err := r.builder.
+		Insert(photoTable).
+		Columns(photoColumns...).
+		Values(
+			photo.ID,
+			photo.ReviewID,
+			photo.StorageKey,
+			photo.ThumbnailKey,
+			photo.ContentType,
+			photo.SizeBytes,
+			photo.Width,
+			photo.Height,
+			photo.Position,
+			photo.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build CreatePhoto query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec CreatePhoto: %w", err)
+	}
+
+	return nil
+}
+
+// LockReviewPhotos блокирует строку отзыва до конца транзакции и возвращает число и наибольшую позицию его фото:
+// параллельные загрузки к одному отзыву не превысят лимит
+func (r *PostgresPhotoRepository) LockReviewPhotos(ctx context.Context, reviewID uuid.UUID) (int, int, error) {
+	exec := transaction.Executor(ctx, r.db)
+
+	if _, err := exec.Exec(ctx, "SELECT 1 FROM reviews WHERE id = $1 FOR UPDATE", reviewID); err != nil {
+		return 0, 0, fmt.Errorf("exec LockReviewPhotos lock: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Select("COUNT(*)", "COALESCE(MAX("+photoPositionColumn+"), -1)").
+		From(photoTable).
+		Where(sq.Eq{
+			photoReviewIDColumn:  reviewID,
+			photoDeletedAtColumn: nil,
+		}).
+		ToSql()
+	if err != nil {
+		return 0, 0, fmt.Errorf("build LockReviewPhotos query: %w", err)
+	}
+
+	var count, lastPosition int
+	if err := exec.QueryRow(ctx, query, args...).Scan(&count, &lastPosition); err != nil {
+		return 0, 0, fmt.Errorf("exec LockReviewPhotos: %w", err)
+	}
+
+	return count, lastPosition, nil
+}
+
+// ListByReviews возвращает фото отзывов в порядке загрузки
+func (r *PostgresPhotoRepository) ListByReviews(ctx context.Context, reviewIDs []uuid.UUID) ([]model.ReviewPhoto, error) {
+	photos := make([]model.ReviewPhoto, 0)
+	if len(reviewIDs) == 0 {
+		return photos, nil
+	}
+
+	query, args, err := r.builder.
+		Select(photoColumns...).
+		From(photoTable).
+		Where(sq.Eq{
+			photoReviewIDColumn:  reviewIDs,
+			photoDeletedAtColumn: nil,
+		}).
+		OrderBy(photoReviewIDColumn, photoPositionColumn).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build ListByReviews query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec ListByReviews: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		photo, err := scanPhoto(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan ListByReviews row: %w", err)
+		}
+		photos = append(photos, *photo)
+	}
+
+	return photos, rows.Err()
+}
+
+// IsPublicKey сообщает, что key — файл или миниатюра неудалённого фото опубликованного неудалённого отзыва
+func (r *PostgresPhotoRepository) IsPublicKey(ctx context.Context, key string) (bool, error) {
+	query, args, err := r.builder.
+		Select("1").
+		From(photoTable + " p").
+		Join("reviews r ON r.id = p." + photoReviewIDColumn).
+		Where(sq.Or{
+			sq.Eq{"p." + photoStorageKeyColumn: key},
+			sq.Eq{"p." + photoThumbnailKeyColumn: key},
+		}).
+		Where(sq.Eq{
+			"p." + photoDeletedAtColumn: nil,
+			"r.status":                  model.ReviewStatusPublished,
+			"r.is_deleted":              false,
+		}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build IsPublicKey query: %w", err)
+	}
+
+	var one int
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&one); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("exec IsPublicKey: %w", err)
+	}
+
+	return true, nil
+}
+
+// DeletePhoto удаляет фото отзыва и возвращает его; если фото нет, возвращает pgx.ErrNoRows
+func (r *PostgresPhotoRepository) DeletePhoto(ctx context.Context, reviewID, photoID uuid.UUID) (*model.ReviewPhoto, error) {
+	query, args, err := r.builder.
+		Delete(photoTable).
+		Where(sq.Eq{
+			photoIDColumn:        photoID,
+			photoReviewIDColumn:  reviewID,
+			photoDeletedAtColumn: nil,
+		}).
+		Suffix("RETURNING " + strings.Join(photoColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build DeletePhoto query: %w", err)
+	}
+
+	photo, err := scanPhoto(transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("exec DeletePhoto: %w", err)
+	}
+
+	return photo, nil
+}
+
+// SoftDeleteByReview помечает фото отзыва удалёнными: файлы остаются до очистки, отмена удаления их вернёт
+func (r *PostgresPhotoRepository) SoftDeleteByReview(ctx context.Context, reviewID uuid.UUID, deletedAt time.Time) error {
+	query, args, err := r.builder.
+		Update(photoTable).
+		Set(photoDeletedAtColumn, deletedAt).
+		Where(sq.Eq{
+			photoReviewIDColumn:  reviewID,
+			photoDeletedAtColumn: nil,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build SoftDeleteByReview query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec SoftDeleteByReview: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreByReview снимает пометку удаления с фото отзыва
+func (r *PostgresPhotoRepository) RestoreByReview(ctx context.Context, reviewID uuid.UUID) error {
+	query, args, err := r.builder.
+		Update(photoTable).
+		Set(photoDeletedAtColumn, nil).
+		Where(sq.Eq{photoReviewIDColumn: reviewID}).
+		Where(sq.NotEq{photoDeletedAtColumn: nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build RestoreByReview query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec RestoreByReview: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeDeleted стирает до limit фото, помеченных удалёнными раньше deletedBefore, и возвращает их,
+// чтобы удалить файлы. Фото отзыва, который успели восстановить, не трогаются.
+func (r *PostgresPhotoRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]model.ReviewPhoto, error) {
+	// Подзапрос собирается с плейсхолдерами "?": внешний builder пронумерует их вместе со своими
+	due, dueArgs, err := sq.
+		Select("p." + photoIDColumn).
+		From(photoTable + " p").
+		Join("reviews r ON r.id = p." + photoReviewIDColumn).
+		Where(sq.Lt{"p." + photoDeletedAtColumn: deletedBefore}).
+		Where(sq.Eq{"r.is_deleted": true}).
+		OrderBy("p." + photoDeletedAtColumn).
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE OF p SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build PurgeDeleted subquery: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Delete(photoTable).
+		Where(sq.Expr(photoIDColumn+" IN ("+due+")", dueArgs...)).
+		Suffix("RETURNING " + strings.Join(photoColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build PurgeDeleted query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec PurgeDeleted: %w", err)
+	}
+	defer rows.Close()
+
+	photos := make([]model.ReviewPhoto, 0)
+	for rows.Next() {
+		photo, err := scanPhoto(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan PurgeDeleted row: %w", err)
+		}
+		photos = append(photos, *photo)
+	}
+
+	return photos, rows.Err()
+}
+
+func scanPhoto(row pgx.Row) (*model.ReviewPhoto, error) {
+	var photo model.ReviewPhoto
+	err := row.Scan(
+		&photo.ID,
+		&photo.ReviewID,
+		&photo.StorageKey,
+		&photo.ThumbnailKey,
+		&photo.ContentType,
+		&photo.SizeBytes,
+		&photo.Width,
+		&photo.Height,
+		&photo.Position,
+		&photo.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &photo, nil
+}
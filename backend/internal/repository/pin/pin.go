@@ -0,0 +1,121 @@
+package pin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+)
+
+const (
+	attemptsTable          = "pin_attempts"
+	attemptIDColumn        = "id"
+	attemptUserIDColumn    = "user_id"
+	attemptIPColumn        = "ip"
+	attemptPlaceIDColumn   = "place_id"
+	attemptPINColumn       = "pin"
+	attemptSucceededColumn = "succeeded"
+	attemptCreatedAtColumn = "created_at"
+)
+
+type PostgresPINAttemptRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresPINAttemptRepository(db *pgxpool.Pool) *PostgresPINAttemptRepository {
+	return &PostgresPINAttemptRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// RecordAttempt записывает попытку ввода PIN; до MarkSucceeded она считается неудачной
+func (r *PostgresPINAttemptRepository) RecordAttempt(ctx context.Context, attempt *model.PINAttempt) error {
+	query, args, err := r.builder.
+		Insert(attemptsTable).
+		Columns(
+			attemptIDColumn,
+			attemptUserIDColumn,
+			attemptIPColumn,
+			attemptPlaceIDColumn,
+			attemptPINColumn,
+			attemptSucceededColumn,
+			attemptCreatedAtColumn,
+		).
+		Values(
+			attempt.ID,
+			attempt.UserID,
+			attempt.IP,
+			attempt.PlaceID,
+			attempt.PIN,
+			attempt.Succeeded,
+			attempt.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build RecordAttempt query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec RecordAttempt: %w", err)
+	}
+
+	return nil
+}
+
+// MarkSucceeded отмечает, что PIN попытки совпал с активным токеном.
+// Если попытки нет, возвращает pgx.ErrNoRows.
+func (r *PostgresPINAttemptRepository) MarkSucceeded(ctx context.Context, attemptID uuid.UUID) error {
+	query, args, err := r.builder.
+		Update(attemptsTable).
+		Set(attemptSucceededColumn, true).
+		Where(sq.Eq{attemptIDColumn: attemptID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build MarkSucceeded query: %w", err)
+	}
+
+	tag, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec MarkSucceeded: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// CountFailed возвращает число неудачных попыток с момента since: отдельно по пользователю и по IP
+func (r *PostgresPINAttemptRepository) CountFailed(ctx context.Context, userID uuid.UUID, ip string, since time.Time) (int, int, error) {
+	query, args, err := r.builder.
+		Select().
+		Column("COUNT(*) FILTER (WHERE "+attemptUserIDColumn+" = ?)", userID).
+		Column("COUNT(*) FILTER (WHERE "+attemptIPColumn+" = ?)", ip).
+		From(attemptsTable).
+		Where(sq.Or{
+			sq.Eq{attemptUserIDColumn: userID},
+			sq.Eq{attemptIPColumn: ip},
+		}).
+		Where(sq.Eq{attemptSucceededColumn: false}).
+		Where(sq.GtOrEq{attemptCreatedAtColumn: since}).
+		ToSql()
+	if err != nil {
+		return 0, 0, fmt.Errorf("build CountFailed query: %w", err)
+	}
+
+	var byUser, byIP int
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&byUser, &byIP); err != nil {
+		return 0, 0, fmt.Errorf("exec CountFailed: %w", err)
+	}
+
+	return byUser, byIP, nil
+}
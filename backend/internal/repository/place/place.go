@@ -3,12 +3,16 @@ package place
 import (
 	"context"
 	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
 
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/pagination"
 )
 
 const (
@@ -18,6 +22,12 @@ const (
 	placeAddressColumn   = "address"
 	placeCreatedAtColumn = "created_at"
 	placeIsDeletedColumn = "is_deleted"
+
+	placeTokenTTLColumn              = "token_ttl_seconds"
+	placeTokensInitialCountColumn    = "tokens_initial_count"
+	placeTokensRefillThresholdColumn = "tokens_refill_threshold"
+	placeTokensRefillBatchColumn     = "tokens_refill_batch"
+	placeModerationModeColumn        = "moderation_mode"
 )
 
 type PostgresPlaceRepository struct {
@@ -39,11 +49,19 @@ func (r *PostgresPlaceRepository) CreatePlace(ctx context.Context, place *model.
 			placeIDColumn,
 			placeNameColumn,
 			placeAddressColumn,
+			placeTokenTTLColumn,
+			placeTokensInitialCountColumn,
+			placeTokensRefillThresholdColumn,
+			placeTokensRefillBatchColumn,
 		).
 		Values(
 			place.ID,
 			place.Name,
 			place.Address,
+			ttlSeconds(place.TokenPolicy.TTL),
+			place.TokenPolicy.InitialCount,
+			place.TokenPolicy.RefillThreshold,
+			place.TokenPolicy.RefillBatch,
 		).
 		Suffix("RETURNING created_at, is_deleted").
 		ToSql()
@@ -52,7 +70,7 @@ func (r *PostgresPlaceRepository) CreatePlace(ctx context.Context, place *model.
 		return fmt.Errorf("build CreatePlace query: %w", err)
 	}
 
-	err = r.db.QueryRow(ctx, query, args...).Scan(&place.CreatedAt, &place.IsDeleted)
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&place.CreatedAt, &place.IsDeleted)
 	if err != nil {
 		return fmt.Errorf("exec CreatePlace: %w", err)
 	}
@@ -73,6 +91,11 @@ func (r *PostgresPlaceRepository) GetByID(ctx context.Context, placeID string) (
 			placeAddressColumn,
 			placeCreatedAtColumn,
 			placeIsDeletedColumn,
+			placeTokenTTLColumn,
+			placeTokensInitialCountColumn,
+			placeTokensRefillThresholdColumn,
+			placeTokensRefillBatchColumn,
+			placeModerationModeColumn,
 		).
 		From(placeTable).
 		Where(sq.Eq{
@@ -84,54 +107,217 @@ func (r *PostgresPlaceRepository) GetByID(ctx context.Context, placeID string) (
 	if err != nil {
 		return nil, fmt.Errorf("build GetByID query: %w", err)
 	}
-	row := r.db.QueryRow(ctx, query, args...)
+	row := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...)
 
 	p := new(model.Place)
-	if err := row.Scan(&p.ID, &p.Name, &p.Address, &p.CreatedAt, &p.IsDeleted); err != nil {
+	if err := scanPlace(row, p); err != nil {
 		return nil, err
 	}
 
 	return p, nil
 }
 
-func (r *PostgresPlaceRepository) GetAllPlaces(ctx context.Context) ([]model.Place, error) {
-	query, args, err := r.builder.
+// placeSortKeys — порядок списка заведений: по названию, id различает одноимённые
+var placeSortKeys = []pagination.Key{
+	{Column: placeNameColumn},
+	{Column: placeIDColumn},
+}
+
+// GetPlaces возвращает страницу неудалённых заведений по названию и их общее число.
+// С memberID в список попадают только заведения, где этот пользователь — сотрудник.
+// Курсор кодирует название и id последнего заведения страницы.
+func (r *PostgresPlaceRepository) GetPlaces(ctx context.Context, memberID *uuid.UUID, page pagination.Params) (*pagination.Page[model.Place], error) {
+	var filter sq.Sqlizer = sq.Eq{placeIsDeletedColumn: false}
+	if memberID != nil {
+		filter = sq.And{
+			filter,
+			sq.Expr(placeIDColumn+" IN (SELECT place_id FROM place_members WHERE user_id = ?)", *memberID),
+		}
+	}
+
+	countQuery, countArgs, err := r.builder.
+		Select("COUNT(*)").
+		From(placeTable).
+		Where(filter).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetPlaces count query: %w", err)
+	}
+
+	var total int
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("exec GetPlaces count: %w", err)
+	}
+
+	builder := r.builder.
 		Select(
 			placeIDColumn,
 			placeNameColumn,
 			placeAddressColumn,
 			placeCreatedAtColumn,
 			placeIsDeletedColumn,
+			placeTokenTTLColumn,
+			placeTokensInitialCountColumn,
+			placeTokensRefillThresholdColumn,
+			placeTokensRefillBatchColumn,
+			placeModerationModeColumn,
 		).
 		From(placeTable).
-		Where(sq.Eq{placeIsDeletedColumn: false}).
-		OrderBy(placeNameColumn + " ASC").
-		ToSql()
+		Where(filter).
+		OrderBy(pagination.OrderBy(placeSortKeys)...).
+		Limit(uint64(page.Limit + 1))
+
+	if page.Cursor != "" {
+		cursor, err := pagination.DecodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			name string
+			id   uuid.UUID
+		)
+		if err := cursor.Scan(&name, &id); err != nil {
+			return nil, err
+		}
+		builder = builder.Where(pagination.After(placeSortKeys, []any{name, id}))
+	}
+
+	query, args, err := builder.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("build GetAllPlaces query: %w", err)
+		return nil, fmt.Errorf("build GetPlaces query: %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("exec GetAllPlaces: %w", err)
+		return nil, fmt.Errorf("exec GetPlaces: %w", err)
 	}
 	defer rows.Close()
 
-	var places []model.Place
-
+	places := make([]model.Place, 0)
 	for rows.Next() {
 		var p model.Place
-		if err := rows.Scan(
-			&p.ID,
-			&p.Name,
-			&p.Address,
-			&p.CreatedAt,
-			&p.IsDeleted,
-		); err != nil {
-			return nil, fmt.Errorf("scan GetAllPlaces: %w", err)
+		if err := scanPlace(rows, &p); err != nil {
+			return nil, fmt.Errorf("scan GetPlaces: %w", err)
 		}
 		places = append(places, p)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read GetPlaces rows: %w", err)
+	}
+
+	result := &pagination.Page[model.Place]{Items: places, Total: total}
+	if len(places) > page.Limit {
+		result.Items = places[:page.Limit]
+		last := result.Items[len(result.Items)-1]
+		result.NextCursor, err = pagination.EncodeCursor(last.Name, last.ID)
+		if err != nil {
+			return nil, fmt.Errorf("encode place cursor: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateTokenPolicy заменяет политику токенов заведения целиком: пустые поля сбрасываются к конфигу
+func (r *PostgresPlaceRepository) UpdateTokenPolicy(ctx context.Context, placeID string, policy model.TokenPolicy) error {
+	uid, err := uuid.Parse(placeID)
+	if err != nil {
+		return fmt.Errorf("invalid place id: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Update(placeTable).
+		Set(placeTokenTTLColumn, ttlSeconds(policy.TTL)).
+		Set(placeTokensInitialCountColumn, policy.InitialCount).
+		Set(placeTokensRefillThresholdColumn, policy.RefillThreshold).
+		Set(placeTokensRefillBatchColumn, policy.RefillBatch).
+		Where(sq.Eq{
+			placeIDColumn:        uid,
+			placeIsDeletedColumn: false,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build UpdateTokenPolicy query: %w", err)
+	}
 
-	return places, nil
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec UpdateTokenPolicy: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateModerationMode задаёт режим модерации заведения; nil возвращает режим из конфига
+func (r *PostgresPlaceRepository) UpdateModerationMode(ctx context.Context, placeID string, mode *string) error {
+	uid, err := uuid.Parse(placeID)
+	if err != nil {
+		return fmt.Errorf("invalid place id: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Update(placeTable).
+		Set(placeModerationModeColumn, mode).
+		Where(sq.Eq{
+			placeIDColumn:        uid,
+			placeIsDeletedColumn: false,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build UpdateModerationMode query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec UpdateModerationMode: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPlace(row rowScanner, p *model.Place) error {
+	var ttl *int
+	err := row.Scan(
+		&p.ID,
+		&p.Name,
+		&p.Address,
+		&p.CreatedAt,
+		&p.IsDeleted,
+		&ttl,
+		&p.TokenPolicy.InitialCount,
+		&p.TokenPolicy.RefillThreshold,
+		&p.TokenPolicy.RefillBatch,
+		&p.ModerationMode,
+	)
+	if err != nil {
+		return err
+	}
+
+	if ttl != nil {
+		d := time.Duration(*ttl) * time.Second
+		p.TokenPolicy.TTL = &d
+	}
+
+	return nil
+}
+
+func ttlSeconds(ttl *time.Duration) *int {
+	if ttl == nil {
+		return nil
+	}
+	seconds := int(*ttl / time.Second)
+	return &seconds
 }
@@ -0,0 +1,95 @@
+package pos
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+)
+
+const (
+	posTable           = "place_pos_integrations"
+	posPlaceIDColumn   = "place_id"
+	posSecretColumn    = "secret"
+	posCreatedAtColumn = "created_at"
+	posUpdatedAtColumn = "updated_at"
+)
+
+type PostgresPOSRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresPOSRepository(db *pgxpool.Pool) *PostgresPOSRepository {
+	return &PostgresPOSRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// UpsertIntegration подключает кассовую систему заведения; при повторном вызове секрет заменяется
+func (r *PostgresPOSRepository) UpsertIntegration(ctx context.Context, integration *model.POSIntegration) error {
+	query, args, err := r.builder.
+		Insert(posTable).
+		Columns(
+			posPlaceIDColumn,
+			posSecretColumn,
+			posCreatedAtColumn,
+			posUpdatedAtColumn,
+		).
+		Values(
+			integration.PlaceID,
+			integration.Secret,
+			integration.CreatedAt,
+			integration.UpdatedAt,
+		).
+		Suffix("ON CONFLICT (" + posPlaceIDColumn + ") DO UPDATE SET " +
+			posSecretColumn + " = EXCLUDED." + posSecretColumn + ", " +
+			posUpdatedAtColumn + " = EXCLUDED." + posUpdatedAtColumn +
+			" RETURNING " + posCreatedAtColumn).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build UpsertIntegration query: %w", err)
+	}
+
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&integration.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("exec UpsertIntegration: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresPOSRepository) GetIntegration(ctx context.Context, placeID uuid.UUID) (*model.POSIntegration, error) {
+	query, args, err := r.builder.
+		Select(
+			posPlaceIDColumn,
+			posSecretColumn,
+			posCreatedAtColumn,
+			posUpdatedAtColumn,
+		).
+		From(posTable).
+		Where(sq.Eq{posPlaceIDColumn: placeID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetIntegration query: %w", err)
+	}
+
+	var integration model.POSIntegration
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
+		&integration.PlaceID,
+		&integration.Secret,
+		&integration.CreatedAt,
+		&integration.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("exec GetIntegration: %w", err)
+	}
+
+	return &integration, nil
+}
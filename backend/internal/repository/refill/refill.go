@@ -0,0 +1,158 @@
+package refill
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+)
+
+const (
+	refillJobsTable        = "token_refill_jobs"
+	refillPlaceIDColumn    = "place_id"
+	refillRequestsColumn   = "requests"
+	refillAttemptsColumn   = "attempts"
+	refillRunAtColumn      = "run_at"
+	refillLastErrorColumn  = "last_error"
+	refillCreatedAtColumn  = "created_at"
+	refillAdvisoryLockName = "token_refill"
+)
+
+type PostgresRefillRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresRefillRepository(db *pgxpool.Pool) *PostgresRefillRepository {
+	return &PostgresRefillRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// EnqueueRefill ставит заведение в очередь догенерации. Если задача уже есть,
+// увеличивает счётчик запросов и не трогает время следующей попытки.
+func (r *PostgresRefillRepository) EnqueueRefill(ctx context.Context, placeID uuid.UUID) error {
+	query, args, err := r.builder.
+		Insert(refillJobsTable).
+		Columns(refillPlaceIDColumn).
+		Values(placeID).
+		Suffix("ON CONFLICT (" + refillPlaceIDColumn + ") DO UPDATE SET " +
+			refillRequestsColumn + " = " + refillJobsTable + "." + refillRequestsColumn + " + 1").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build EnqueueRefill query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec EnqueueRefill: %w", err)
+	}
+
+	return nil
+}
+
+// ListDueJobs возвращает задачи, время попытки которых уже наступило, начиная с самых старых
+func (r *PostgresRefillRepository) ListDueJobs(ctx context.Context, limit int) ([]model.RefillJob, error) {
+	query, args, err := r.builder.
+		Select(
+			refillPlaceIDColumn,
+			refillRequestsColumn,
+			refillAttemptsColumn,
+			refillRunAtColumn,
+			refillLastErrorColumn,
+			refillCreatedAtColumn,
+		).
+		From(refillJobsTable).
+		Where(refillRunAtColumn + " <= now()").
+		OrderBy(refillRunAtColumn + " ASC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build ListDueJobs query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec ListDueJobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []model.RefillJob
+	for rows.Next() {
+		var job model.RefillJob
+		if err := rows.Scan(
+			&job.PlaceID,
+			&job.Requests,
+			&job.Attempts,
+			&job.RunAt,
+			&job.LastError,
+			&job.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan ListDueJobs: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// TryLockPlace берёт транзакционную advisory-блокировку догенерации для заведения.
+// Вызывается только внутри транзакции; false — блокировку уже держит другой процесс.
+func (r *PostgresRefillRepository) TryLockPlace(ctx context.Context, placeID uuid.UUID) (bool, error) {
+	var locked bool
+	err := transaction.Executor(ctx, r.db).QueryRow(ctx,
+		"SELECT pg_try_advisory_xact_lock(hashtext($1), hashtext($2))",
+		refillAdvisoryLockName, placeID.String(),
+	).Scan(&locked)
+	if err != nil {
+		return false, fmt.Errorf("exec TryLockPlace: %w", err)
+	}
+
+	return locked, nil
+}
+
+// CompleteJob удаляет задачу, если после её выборки не приходило новых запросов
+func (r *PostgresRefillRepository) CompleteJob(ctx context.Context, job model.RefillJob) error {
+	query, args, err := r.builder.
+		Delete(refillJobsTable).
+		Where(sq.Eq{
+			refillPlaceIDColumn:  job.PlaceID,
+			refillRequestsColumn: job.Requests,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build CompleteJob query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec CompleteJob: %w", err)
+	}
+
+	return nil
+}
+
+// RetryJob откладывает задачу на delay и запоминает номер попытки и текст ошибки
+func (r *PostgresRefillRepository) RetryJob(ctx context.Context, placeID uuid.UUID, attempts int, delay time.Duration, lastErr string) error {
+	query, args, err := r.builder.
+		Update(refillJobsTable).
+		Set(refillAttemptsColumn, attempts).
+		Set(refillRunAtColumn, sq.Expr("now() + make_interval(secs => ?)", delay.Seconds())).
+		Set(refillLastErrorColumn, lastErr).
+		Where(sq.Eq{refillPlaceIDColumn: placeID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build RetryJob query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec RetryJob: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package refresh
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+)
+
+const (
+	refreshTokensTable       = "refresh_tokens"
+	refreshTokenIDColumn     = "id"
+	refreshTokenUserIDColumn = "user_id"
+	refreshTokenFamilyColumn = "family_id"
+	refreshTokenHashColumn   = "token_hash"
+	refreshTokenExpiresAt    = "expires_at"
+	refreshTokenCreatedAt    = "created_at"
+	refreshTokenRotatedAt    = "rotated_at"
+	refreshTokenRevokedAt    = "revoked_at"
+)
+
+type PostgresRefreshTokenRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresRefreshTokenRepository(db *pgxpool.Pool) *PostgresRefreshTokenRepository {
+	return &PostgresRefreshTokenRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *PostgresRefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error {
+	query, args, err := r.builder.
+		Insert(refreshTokensTable).
+		Columns(
+			refreshTokenIDColumn,
+			refreshTokenUserIDColumn,
+			refreshTokenFamilyColumn,
+			refreshTokenHashColumn,
+			refreshTokenExpiresAt,
+			refreshTokenCreatedAt,
+		).
+		Values(
+			token.ID,
+			token.UserID,
+			token.FamilyID,
+			token.TokenHash,
+			token.ExpiresAt,
+			token.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build CreateRefreshToken query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec CreateRefreshToken: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	query, args, err := r.builder.
+		Select(
+			refreshTokenIDColumn,
+			refreshTokenUserIDColumn,
+			refreshTokenFamilyColumn,
+			refreshTokenHashColumn,
+			refreshTokenExpiresAt,
+			refreshTokenCreatedAt,
+			refreshTokenRotatedAt,
+			refreshTokenRevokedAt,
+		).
+		From(refreshTokensTable).
+		Where(sq.Eq{refreshTokenHashColumn: tokenHash}).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetByHash query: %w", err)
+	}
+
+	var t model.RefreshToken
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
+		&t.ID,
+		&t.UserID,
+		&t.FamilyID,
+		&t.TokenHash,
+		&t.ExpiresAt,
+		&t.CreatedAt,
+		&t.RotatedAt,
+		&t.RevokedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scan GetByHash: %w", err)
+	}
+
+	return &t, nil
+}
+
+// MarkRotated помечает токен использованным. Если токен уже был ротирован
+// или отозван (например, параллельным запросом), возвращает sql.ErrNoRows.
+func (r *PostgresRefreshTokenRepository) MarkRotated(ctx context.Context, tokenID string) error {
+	uid, err := uuid.Parse(tokenID)
+	if err != nil {
+		return fmt.Errorf("invalid refresh token id: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Update(refreshTokensTable).
+		Set(refreshTokenRotatedAt, time.Now()).
+		Where(sq.Eq{
+			refreshTokenIDColumn:  uid,
+			refreshTokenRotatedAt: nil,
+			refreshTokenRevokedAt: nil,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build MarkRotated query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec MarkRotated: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PostgresRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	uid, err := uuid.Parse(familyID)
+	if err != nil {
+		return fmt.Errorf("invalid family id: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Update(refreshTokensTable).
+		Set(refreshTokenRevokedAt, time.Now()).
+		Where(sq.Eq{
+			refreshTokenFamilyColumn: uid,
+			refreshTokenRevokedAt:    nil,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build RevokeFamily query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec RevokeFamily: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRefreshTokenRepository) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	uid, err := uuid.Parse(familyID)
+	if err != nil {
+		return false, fmt.Errorf("invalid family id: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Select("1").
+		From(refreshTokensTable).
+		Where(sq.Eq{refreshTokenFamilyColumn: uid}).
+		Where(sq.NotEq{refreshTokenRevokedAt: nil}).
+		Limit(1).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build IsFamilyRevoked query: %w", err)
+	}
+
+	var revoked bool
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("exec IsFamilyRevoked: %w", err)
+	}
+
+	return revoked, nil
+}
@@ -0,0 +1,295 @@
+package reply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	srvErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+const (
+	replyTable           = "review_replies"
+	replyIDColumn        = "id"
+	replyReviewIDColumn  = "review_id"
+	replyPlaceIDColumn   = "place_id"
+	replyAuthorIDColumn  = "author_id"
+	replyContentColumn   = "content"
+	replyCreatedAtColumn = "created_at"
+	replyUpdatedAtColumn = "updated_at"
+
+	templateTable           = "reply_templates"
+	templateIDColumn        = "id"
+	templatePlaceIDColumn   = "place_id"
+	templateTitleColumn     = "title"
+	templateContentColumn   = "content"
+	templateCreatedByColumn = "created_by"
+	templateCreatedAtColumn = "created_at"
+
+	reviewsTable = "reviews"
+
+	uniqueViolationCode = "23505"
+)
+
+var replyColumns = []string{
+	replyIDColumn,
+	replyReviewIDColumn,
+	replyPlaceIDColumn,
+	replyAuthorIDColumn,
+	replyContentColumn,
+	replyCreatedAtColumn,
+	replyUpdatedAtColumn,
+}
+
+type PostgresReplyRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresReplyRepository(db *pgxpool.Pool) *PostgresReplyRepository {
+	return &PostgresReplyRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// CreateReply сохраняет ответ; если у отзыва уже есть ответ, возвращает ErrReplyExists
+func (r *PostgresReplyRepository) CreateReply(ctx context.Context, reply *model.ReviewReply) error {
+	query, args, err := r.builder.
+		Insert(replyTable).
+		Columns(
+			replyIDColumn,
+			replyReviewIDColumn,
+			replyPlaceIDColumn,
+			replyAuthorIDColumn,
+			replyContentColumn,
+			replyCreatedAtColumn,
+		).
+		Values(
+			reply.ID,
+			reply.ReviewID,
+			reply.PlaceID,
+			reply.AuthorID,
+			reply.Content,
+			reply.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build CreateReply query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return srvErrors.ErrReplyExists
+		}
+		return fmt.Errorf("exec CreateReply: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateReply меняет текст ответа на отзыв и возвращает ответ; если ответа нет, возвращает pgx.ErrNoRows
+func (r *PostgresReplyRepository) UpdateReply(ctx context.Context, reviewID uuid.UUID, content string) (*model.ReviewReply, error) {
+	query, args, err := r.builder.
+		Update(replyTable).
+		Set(replyContentColumn, content).
+		Set(replyUpdatedAtColumn, time.Now().UTC()).
+		Where(sq.Eq{replyReviewIDColumn: reviewID}).
+		Suffix("RETURNING " + strings.Join(replyColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build UpdateReply query: %w", err)
+	}
+
+	reply, err := scanReply(transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("exec UpdateReply: %w", err)
+	}
+
+	return reply, nil
+}
+
+// DeleteReply удаляет ответ на отзыв; если ответа нет, возвращает pgx.ErrNoRows
+func (r *PostgresReplyRepository) DeleteReply(ctx context.Context, reviewID uuid.UUID) error {
+	query, args, err := r.builder.
+		Delete(replyTable).
+		Where(sq.Eq{replyReviewIDColumn: reviewID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build DeleteReply query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec DeleteReply: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetReplyStats считает опубликованные отзывы заведения за [from, to), ответы на них
+// и медиану времени ответа. Время ответа считается от создания отзыва до первой версии ответа.
+func (r *PostgresReplyRepository) GetReplyStats(ctx context.Context, placeID uuid.UUID, from, to time.Time) (*model.ReplyStats, error) {
+	query, args, err := r.builder.
+		Select(
+			"COUNT(r.id)",
+			"COUNT(rr.id)",
+			"percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM rr.created_at - r.created_at)::float8)",
+		).
+		From(reviewsTable + " r").
+		LeftJoin(replyTable + " rr ON rr." + replyReviewIDColumn + " = r.id").
+		Where(sq.Eq{
+			"r.place_id":   placeID,
+			"r.is_deleted": false,
+			"r.status":     model.ReviewStatusPublished,
+		}).
+		Where(sq.GtOrEq{"r.created_at": from}).
+		Where(sq.Lt{"r.created_at": to}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetReplyStats query: %w", err)
+	}
+
+	stats := &model.ReplyStats{PlaceID: placeID, From: from, To: to}
+	var medianSeconds *float64
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&stats.Reviews, &stats.Replied, &medianSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("exec GetReplyStats: %w", err)
+	}
+
+	if medianSeconds != nil {
+		median := time.Duration(*medianSeconds * float64(time.Second))
+		stats.MedianResponse = &median
+	}
+
+	return stats, nil
+}
+
+// CreateTemplate сохраняет шаблон ответа; если в заведении уже есть шаблон с таким названием, возвращает ErrReplyTemplateExists
+func (r *PostgresReplyRepository) CreateTemplate(ctx context.Context, template *model.ReplyTemplate) error {
+	query, args, err := r.builder.
+		Insert(templateTable).
+		Columns(
+			templateIDColumn,
+			templatePlaceIDColumn,
+			templateTitleColumn,
+			templateContentColumn,
+			templateCreatedByColumn,
+			templateCreatedAtColumn,
+		).
+		Values(
+			template.ID,
+			template.PlaceID,
+			template.Title,
+			template.Content,
+			template.CreatedBy,
+			template.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build CreateTemplate query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return srvErrors.ErrReplyTemplateExists
+		}
+		return fmt.Errorf("exec CreateTemplate: %w", err)
+	}
+
+	return nil
+}
+
+// ListTemplates возвращает шаблоны ответов заведения по названию
+func (r *PostgresReplyRepository) ListTemplates(ctx context.Context, placeID uuid.UUID) ([]model.ReplyTemplate, error) {
+	query, args, err := r.builder.
+		Select(
+			templateIDColumn,
+			templatePlaceIDColumn,
+			templateTitleColumn,
+			templateContentColumn,
+			templateCreatedByColumn,
+			templateCreatedAtColumn,
+		).
+		From(templateTable).
+		Where(sq.Eq{templatePlaceIDColumn: placeID}).
+		OrderBy(templateTitleColumn + " ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build ListTemplates query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec ListTemplates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]model.ReplyTemplate, 0)
+	for rows.Next() {
+		var t model.ReplyTemplate
+		if err := rows.Scan(&t.ID, &t.PlaceID, &t.Title, &t.Content, &t.CreatedBy, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan ListTemplates row: %w", err)
+		}
+		templates = append(templates, t)
+	}
+
+	return templates, rows.Err()
+}
+
+// DeleteTemplate удаляет шаблон заведения; если его нет, возвращает pgx.ErrNoRows
+func (r *PostgresReplyRepository) DeleteTemplate(ctx context.Context, placeID, templateID uuid.UUID) error {
+	query, args, err := r.builder.
+		Delete(templateTable).
+		Where(sq.Eq{
+			templateIDColumn:      templateID,
+			templatePlaceIDColumn: placeID,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build DeleteTemplate query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec DeleteTemplate: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanReply(row pgx.Row) (*model.ReviewReply, error) {
+	var reply model.ReviewReply
+	err := row.Scan(
+		&reply.ID,
+		&reply.ReviewID,
+		&reply.PlaceID,
+		&reply.AuthorID,
+		&reply.Content,
+		&reply.CreatedAt,
+		&reply.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reply, nil
+}
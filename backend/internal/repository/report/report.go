@@ -0,0 +1,288 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	srvErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+const (
+	reportTable             = "review_reports"
+	reportIDColumn          = "id"
+	reportReviewIDColumn    = "review_id"
+	reportReporterIDColumn  = "reporter_id"
+	reportReasonColumn      = "reason"
+	reportCommentColumn     = "comment"
+	reportStatusColumn      = "status"
+	reportResolutionColumn  = "resolution"
+	reportResolvedByColumn  = "resolved_by"
+	reportResolvedAtColumn  = "resolved_at"
+	reportCreatedAtColumn   = "created_at"
+	reviewsTable            = "reviews"
+	uniqueViolationCode     = "23505"
+	reportReasonCountFormat = "COUNT(*) FILTER (WHERE rp.reason = '%s')"
+)
+
+// reportReasons — порядок причин в выборке очереди жалоб
+var reportReasons = []string{
+	model.ReportReasonSpam,
+	model.ReportReasonOffensive,
+	model.ReportReasonFake,
+	model.ReportReasonOffTopic,
+}
+
+type PostgresReportRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresReportRepository(db *pgxpool.Pool) *PostgresReportRepository {
+	return &PostgresReportRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// CreateReport сохраняет жалобу; если пользователь уже жаловался на отзыв, возвращает ErrReportExists
+func (r *PostgresReportRepository) CreateReport(ctx context.Context, report *model.ReviewReport) error {
+	query, args, err := r.builder.
+		Insert(reportTable).
+		Columns(
+			reportIDColumn,
+			reportReviewIDColumn,
+			reportReporterIDColumn,
+			reportReasonColumn,
+			reportCommentColumn,
+			reportStatusColumn,
+			reportCreatedAtColumn,
+		).
+		Values(
+			report.ID,
+			report.ReviewID,
+			report.ReporterID,
+			report.Reason,
+			report.Comment,
+			report.Status,
+			report.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build CreateReport query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return srvErrors.ErrReportExists
+		}
+		return fmt.Errorf("exec CreateReport: %w", err)
+	}
+
+	return nil
+}
+
+// CountOpenReports возвращает число неразобранных жалоб на отзыв
+func (r *PostgresReportRepository) CountOpenReports(ctx context.Context, reviewID uuid.UUID) (int, error) {
+	query, args, err := r.builder.
+		Select("COUNT(*)").
+		From(reportTable).
+		Where(sq.Eq{
+			reportReviewIDColumn: reviewID,
+			reportStatusColumn:   model.ReportStatusOpen,
+		}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build CountOpenReports query: %w", err)
+	}
+
+	var count int
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("exec CountOpenReports: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListReportedReviews возвращает неудалённые отзывы с жалобами в статусе filter.Status:
+// сначала отзывы с наибольшим числом жалоб, при равенстве — с самой старой жалобой
+func (r *PostgresReportRepository) ListReportedReviews(ctx context.Context, filter model.ReportFilter) ([]model.ReportedReview, error) {
+	columns := []string{
+		"r.id",
+		"r.user_id",
+		"r.place_id",
+		"r.content",
+		"r.rating",
+		"r.created_at",
+		"r.receipt_id",
+		"r.points",
+		"r.status",
+		"r.moderation_reason",
+		"r.moderated_by",
+		"r.moderated_at",
+		"r.filter_rule",
+		"COUNT(*)",
+		"MIN(rp." + reportCreatedAtColumn + ")",
+		"MAX(rp." + reportCreatedAtColumn + ")",
+	}
+	for _, reason := range reportReasons {
+		columns = append(columns, fmt.Sprintf(reportReasonCountFormat, reason))
+	}
+
+	builder := r.builder.
+		Select(columns...).
+		From(reportTable+" rp").
+		Join(reviewsTable+" r ON r.id = rp."+reportReviewIDColumn).
+		Where(sq.Eq{
+			"rp." + reportStatusColumn: filter.Status,
+			"r.is_deleted":             false,
+		}).
+		GroupBy("r.id").
+		OrderBy("COUNT(*) DESC", "MIN(rp."+reportCreatedAtColumn+") ASC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+
+	if filter.PlaceID != nil {
+		builder = builder.Where(sq.Eq{"r.place_id": *filter.PlaceID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build ListReportedReviews query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec ListReportedReviews: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]model.ReportedReview, 0)
+	for rows.Next() {
+		var (
+			item    model.ReportedReview
+			reasons = make([]int, len(reportReasons))
+		)
+		dest := []any{
+			&item.Review.ID,
+			&item.Review.UserID,
+			&item.Review.PlaceID,
+			&item.Review.Content,
+			&item.Review.Rating,
+			&item.Review.CreatedAt,
+			&item.Review.ReceiptID,
+			&item.Review.Points,
+			&item.Review.Status,
+			&item.Review.ModerationReason,
+			&item.Review.ModeratedBy,
+			&item.Review.ModeratedAt,
+			&item.Review.FilterRule,
+			&item.Reports,
+			&item.FirstReportedAt,
+			&item.LastReportedAt,
+		}
+		for i := range reasons {
+			dest = append(dest, &reasons[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scan ListReportedReviews row: %w", err)
+		}
+
+		item.Reasons = make(map[string]int, len(reportReasons))
+		for i, reason := range reportReasons {
+			if reasons[i] > 0 {
+				item.Reasons[reason] = reasons[i]
+			}
+		}
+		result = append(result, item)
+	}
+
+	return result, rows.Err()
+}
+
+// ListReports возвращает все жалобы на отзыв, старые первыми
+func (r *PostgresReportRepository) ListReports(ctx context.Context, reviewID uuid.UUID) ([]model.ReviewReport, error) {
+	query, args, err := r.builder.
+		Select(
+			reportIDColumn,
+			reportReviewIDColumn,
+			reportReporterIDColumn,
+			reportReasonColumn,
+			reportCommentColumn,
+			reportStatusColumn,
+			reportResolutionColumn,
+			reportResolvedByColumn,
+			reportResolvedAtColumn,
+			reportCreatedAtColumn,
+		).
+		From(reportTable).
+		Where(sq.Eq{reportReviewIDColumn: reviewID}).
+		OrderBy(reportCreatedAtColumn+" ASC", reportIDColumn+" ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build ListReports query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec ListReports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := make([]model.ReviewReport, 0)
+	for rows.Next() {
+		var rp model.ReviewReport
+		err := rows.Scan(
+			&rp.ID,
+			&rp.ReviewID,
+			&rp.ReporterID,
+			&rp.Reason,
+			&rp.Comment,
+			&rp.Status,
+			&rp.Resolution,
+			&rp.ResolvedBy,
+			&rp.ResolvedAt,
+			&rp.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan ListReports row: %w", err)
+		}
+		reports = append(reports, rp)
+	}
+
+	return reports, rows.Err()
+}
+
+// ResolveReports закрывает все открытые жалобы на отзыв одним решением и возвращает их число
+func (r *PostgresReportRepository) ResolveReports(ctx context.Context, reviewID uuid.UUID, resolution string, resolvedBy uuid.UUID) (int, error) {
+	query, args, err := r.builder.
+		Update(reportTable).
+		Set(reportStatusColumn, model.ReportStatusResolved).
+		Set(reportResolutionColumn, resolution).
+		Set(reportResolvedByColumn, resolvedBy).
+		Set(reportResolvedAtColumn, time.Now().UTC()).
+		Where(sq.Eq{
+			reportReviewIDColumn: reviewID,
+			reportStatusColumn:   model.ReportStatusOpen,
+		}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build ResolveReports query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("exec ResolveReports: %w", err)
+	}
+
+	return int(res.RowsAffected()), nil
+}
@@ -2,12 +2,20 @@ package repository
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/pagination"
 )
 
 //go:generate go run go.uber.org/mock/mockgen -source=repository.go -destination=../tests/integration/mocks/repository_mocks.go -package=mocks
 
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
 	FindByID(ctx context.Context, userID string) (*model.User, error)
@@ -16,31 +24,60 @@ type UserRepository interface {
 	UpdateUser(ctx context.Context, user *model.User) error
 	SoftDeleteUser(ctx context.Context, userID string) error
 	AddPoints(ctx context.Context, userID string, points int) error
+	RevokePoints(ctx context.Context, userID string, points int) error
+	LockUser(ctx context.Context, userID string) error
 	RedeemPoints(ctx context.Context, userID string, points int) error
+	UpdateRole(ctx context.Context, userID, role string) error
+}
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	MarkRotated(ctx context.Context, tokenID string) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	IsFamilyRevoked(ctx context.Context, familyID string) (bool, error)
 }
 
 type PlaceRepository interface {
 	CreatePlace(ctx context.Context, place *model.Place) error
 	GetByID(ctx context.Context, placeID string) (*model.Place, error)
-	GetAllPlaces(ctx context.Context) ([]model.Place, error)
+	GetPlaces(ctx context.Context, memberID *uuid.UUID, page pagination.Params) (*pagination.Page[model.Place], error)
+	UpdateTokenPolicy(ctx context.Context, placeID string, policy model.TokenPolicy) error
+	UpdateModerationMode(ctx context.Context, placeID string, mode *string) error
 }
 
 type ReviewRepository interface {
 	GetReviewToken(ctx context.Context, token string) (*model.ReviewToken, error)
-	MarkReviewTokenUsed(ctx context.Context, tokenID string) error
+	GetReviewTokenByPIN(ctx context.Context, placeID uuid.UUID, pin string) (*model.ReviewToken, error)
+	ClaimReviewToken(ctx context.Context, tokenID string) (*model.ReviewToken, error)
 	CreateReview(ctx context.Context, review model.Review) error
+	GetReviewByID(ctx context.Context, reviewID uuid.UUID) (*model.Review, error)
+	LockReviewByID(ctx context.Context, reviewID uuid.UUID) (*model.Review, error)
+	FindModerationQueue(ctx context.Context, filter model.ModerationFilter) (*pagination.Page[model.Review], error)
+	UpdateReviewStatus(ctx context.Context, reviewID uuid.UUID, from, to string, moderatorID *uuid.UUID, reason *string) error
 	HasReviewToday(ctx context.Context, userID, placeID string) (bool, error)
-	FindReviews(ctx context.Context, placeID string, filter model.ReviewFilter) ([]model.Review, error)
-	UpdateReview(ctx context.Context, reviewID, userID string, content string, rating int) error
+	FindReviews(ctx context.Context, placeID string, filter model.ReviewFilter) (*pagination.Page[model.Review], error)
+	UpdateReview(ctx context.Context, reviewID, userID string, content string, rating int, filterRule, flagReason *string) error
+	RestoreReview(ctx context.Context, reviewID, userID uuid.UUID, deletedSince time.Time) error
 	DeleteReview(ctx context.Context, reviewID, userID string) error
 	CountLowRatingReviews(ctx context.Context, userID string, days int) (int, error)
 	CountUserReviews(ctx context.Context, userID string) (int, error)
 	AvgUserRating(ctx context.Context, userID string) (float64, error)
+	GetLabelStats(ctx context.Context, placeID uuid.UUID, dimension string, from, to *time.Time) ([]model.LabelStat, error)
 }
 
 type TokenRepository interface {
-	CreateTokens(ctx context.Context, tokens []model.ReviewToken) error
+	CreateTokens(ctx context.Context, tokens []model.ReviewToken) ([]string, error)
+	CreateReceiptToken(ctx context.Context, token model.ReviewToken) (bool, error)
+	GetTokenByReceipt(ctx context.Context, placeID uuid.UUID, receiptID string) (*model.ReviewToken, error)
 	CountActiveTokens(ctx context.Context, placeID string) (int, error)
+	GetTokenInfo(ctx context.Context, tokenValue string) (*model.ReviewTokenInfo, error)
+	ListTokens(ctx context.Context, placeID string, filter model.TokenFilter) ([]model.ReviewToken, int, error)
+	RevokeTokens(ctx context.Context, tokenIDs []uuid.UUID) (int, error)
+	ExtendTokens(ctx context.Context, tokenIDs []uuid.UUID, ttl time.Duration) (int, error)
+	GetTokenStats(ctx context.Context, placeID string) (*model.TokenStats, error)
+	SetPIN(ctx context.Context, tokenID uuid.UUID, pin string) (string, bool, error)
+	ReleaseExpiredPIN(ctx context.Context, tokenID uuid.UUID, pin string) (int, error)
 }
 
 type AdminRepository interface {
@@ -56,10 +93,99 @@ type LeaderboardRepository interface {
 type BonusRepository interface {
 	CreateBonus(ctx context.Context, bonus *model.BonusReward) error
 	GetBonusesByUser(ctx context.Context, userID string) ([]model.BonusReward, error)
-	MarkBonusUsed(ctx context.Context, qrToken string) error
+	MarkBonusUsed(ctx context.Context, qrToken, validatorID, placeID string) error
 	GetByQRToken(ctx context.Context, qrToken string) (*model.BonusReward, error)
 }
 
+type PlaceMemberRepository interface {
+	AddMember(ctx context.Context, member *model.PlaceMember) error
+	RemoveMember(ctx context.Context, placeID, userID string) error
+	GetMember(ctx context.Context, placeID, userID string) (*model.PlaceMember, error)
+	ListMembers(ctx context.Context, placeID string) ([]model.PlaceMember, error)
+	ListUserPlaceRoles(ctx context.Context, userID string) ([]string, error)
+}
+
+type KioskRepository interface {
+	UpsertKiosk(ctx context.Context, kiosk *model.Kiosk) error
+	GetKiosk(ctx context.Context, placeID uuid.UUID) (*model.Kiosk, error)
+	DisableKiosk(ctx context.Context, placeID uuid.UUID) (bool, error)
+	ClaimWindow(ctx context.Context, placeID, userID uuid.UUID, window int64) (bool, error)
+}
+
+type POSRepository interface {
+	UpsertIntegration(ctx context.Context, integration *model.POSIntegration) error
+	GetIntegration(ctx context.Context, placeID uuid.UUID) (*model.POSIntegration, error)
+}
+
+type TokenRefillRepository interface {
+	EnqueueRefill(ctx context.Context, placeID uuid.UUID) error
+	ListDueJobs(ctx context.Context, limit int) ([]model.RefillJob, error)
+	TryLockPlace(ctx context.Context, placeID uuid.UUID) (bool, error)
+	CompleteJob(ctx context.Context, job model.RefillJob) error
+	RetryJob(ctx context.Context, placeID uuid.UUID, attempts int, delay time.Duration, lastErr string) error
+}
+
+type TokenJobRepository interface {
+	CreateJob(ctx context.Context, job *model.TokenBatchJob) error
+	GetJob(ctx context.Context, jobID uuid.UUID) (*model.TokenBatchJob, error)
+	ClaimJob(ctx context.Context, staleAfter time.Duration) (*model.TokenBatchJob, error)
+	AddProgress(ctx context.Context, jobID uuid.UUID, generated int) error
+	FinishJob(ctx context.Context, jobID uuid.UUID, status string, errText *string) error
+	ListJobTokens(ctx context.Context, jobID uuid.UUID, after string, limit int) ([]model.ReviewToken, error)
+}
+
+type ScanRepository interface {
+	CreateScan(ctx context.Context, scan *model.TokenScan, tokenValue string) error
+	FindRecentScan(ctx context.Context, tokenValue, clientKey string, since time.Time) (*model.TokenScan, error)
+	MarkOpened(ctx context.Context, scanID, userID uuid.UUID, sessionKey string) error
+	GetFunnel(ctx context.Context, placeID uuid.UUID, from, to time.Time) (*model.ScanFunnel, error)
+}
+
+type PINAttemptRepository interface {
+	RecordAttempt(ctx context.Context, attempt *model.PINAttempt) error
+	MarkSucceeded(ctx context.Context, attemptID uuid.UUID) error
+	CountFailed(ctx context.Context, userID uuid.UUID, ip string, since time.Time) (int, int, error)
+}
+
+type ReplyRepository interface {
+	CreateReply(ctx context.Context, reply *model.ReviewReply) error
+	UpdateReply(ctx context.Context, reviewID uuid.UUID, content string) (*model.ReviewReply, error)
+	DeleteReply(ctx context.Context, reviewID uuid.UUID) error
+	GetReplyStats(ctx context.Context, placeID uuid.UUID, from, to time.Time) (*model.ReplyStats, error)
+	CreateTemplate(ctx context.Context, template *model.ReplyTemplate) error
+	ListTemplates(ctx context.Context, placeID uuid.UUID) ([]model.ReplyTemplate, error)
+	DeleteTemplate(ctx context.Context, placeID, templateID uuid.UUID) error
+}
+
+type PhotoRepository interface {
+	CreatePhoto(ctx context.Context, photo *model.ReviewPhoto) error
+	LockReviewPhotos(ctx context.Context, reviewID uuid.UUID) (int, int, error)
+	ListByReviews(ctx context.Context, reviewIDs []uuid.UUID) ([]model.ReviewPhoto, error)
+	IsPublicKey(ctx context.Context, key string) (bool, error)
+	DeletePhoto(ctx context.Context, reviewID, photoID uuid.UUID) (*model.ReviewPhoto, error)
+	SoftDeleteByReview(ctx context.Context, reviewID uuid.UUID, deletedAt time.Time) error
+	RestoreByReview(ctx context.Context, reviewID uuid.UUID) error
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]model.ReviewPhoto, error)
+}
+
+type VoteRepository interface {
+	AddVote(ctx context.Context, reviewID, userID uuid.UUID) (int, error)
+	RemoveVote(ctx context.Context, reviewID, userID uuid.UUID) (int, error)
+}
+
+type RevisionRepository interface {
+	CreateRevision(ctx context.Context, rev *model.ReviewRevision) error
+	ListRevisions(ctx context.Context, reviewID uuid.UUID) ([]model.ReviewRevision, error)
+}
+
+type ReportRepository interface {
+	CreateReport(ctx context.Context, report *model.ReviewReport) error
+	CountOpenReports(ctx context.Context, reviewID uuid.UUID) (int, error)
+	ListReportedReviews(ctx context.Context, filter model.ReportFilter) ([]model.ReportedReview, error)
+	ListReports(ctx context.Context, reviewID uuid.UUID) ([]model.ReviewReport, error)
+	ResolveReports(ctx context.Context, reviewID uuid.UUID, resolution string, resolvedBy uuid.UUID) (int, error)
+}
+
 type UserRestrictionRepository interface {
 	HasActiveRestriction(ctx context.Context, userID, restrictionType string) (bool, error)
 	CreateRestriction(ctx context.Context, restriction *model.UserRestriction) error
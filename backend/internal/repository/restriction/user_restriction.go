@@ -2,10 +2,13 @@ package restriction
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
 )
 
 type PostgresUserRestrictionRepository struct {
@@ -29,10 +32,12 @@ func (r *PostgresUserRestrictionRepository) HasActiveRestriction(
 		)`
 
 	var exists bool
-	err := r.db.QueryRow(ctx, query, userID, restrictionType, time.Now()).Scan(&exists)
+	err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, userID, restrictionType, time.Now()).Scan(&exists)
 	return exists, err
 }
 
+// CreateRestriction создаёт ограничение; если запись того же типа уже есть, она не меняется.
+// В restriction.ExpiresAt записывается срок сохранённой записи.
 func (r *PostgresUserRestrictionRepository) CreateRestriction(
 	ctx context.Context,
 	restriction *model.UserRestriction,
@@ -40,12 +45,23 @@ func (r *PostgresUserRestrictionRepository) CreateRestriction(
 	query := `
 		INSERT INTO user_restrictions (id, user_id, restriction_type, reason, created_at, expires_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (user_id, restriction_type) DO NOTHING`
+		ON CONFLICT (user_id, restriction_type) DO NOTHING
+		RETURNING expires_at`
 
-	_, err := r.db.Exec(
+	exec := transaction.Executor(ctx, r.db)
+	err := exec.QueryRow(
 		ctx, query,
 		restriction.ID, restriction.UserID, restriction.RestrictionType,
 		restriction.Reason, restriction.CreatedAt, restriction.ExpiresAt,
-	)
-	return err
+	).Scan(&restriction.ExpiresAt)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	// Запись уже была — возвращаем её срок
+	return exec.QueryRow(ctx, `
+		SELECT expires_at FROM user_restrictions
+		WHERE user_id = $1 AND restriction_type = $2`,
+		restriction.UserID, restriction.RestrictionType,
+	).Scan(&restriction.ExpiresAt)
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,8 +14,10 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
 
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/pagination"
 )
 
 const (
@@ -24,6 +27,14 @@ const (
 	reviewTokenValue     = "token_value"
 	reviewTokenIsUsed    = "is_used"
 	reviewTokenExpiresAt = "expires_at"
+	reviewTokenRevokedAt = "revoked_at"
+	reviewTokenReceiptID = "receipt_id"
+	reviewTokenAmount    = "receipt_amount"
+	reviewTokenPurchased = "purchased_at"
+	reviewTokenStaffID   = "staff_id"
+	reviewTokenTableNo   = "table_label"
+	reviewTokenCampaign  = "campaign"
+	reviewTokenPIN       = "pin"
 
 	reviewTable        = "reviews"
 	reviewIDColumn     = "id"
@@ -34,6 +45,21 @@ const (
 	reviewRating       = "rating"
 	reviewCreatedAt    = "created_at"
 	reviewIsDeletedCol = "is_deleted"
+	reviewReceiptID    = "receipt_id"
+	reviewPoints       = "points"
+	reviewStaffID      = "staff_id"
+	reviewTableNo      = "table_label"
+	reviewCampaign     = "campaign"
+	reviewStatus       = "status"
+	reviewModReason    = "moderation_reason"
+	reviewModeratedBy  = "moderated_by"
+	reviewModeratedAt  = "moderated_at"
+	reviewFilterRule   = "filter_rule"
+	reviewHelpfulCount = "helpful_count"
+	reviewUpdatedAt    = "updated_at"
+	reviewDeletedAt    = "deleted_at"
+
+	replyTable = "review_replies"
 )
 
 type PostgresReviewRepository struct {
@@ -49,6 +75,31 @@ func NewPostgresReviewRepository(db *pgxpool.Pool) *PostgresReviewRepository {
 }
 
 func (r *PostgresReviewRepository) GetReviewToken(ctx context.Context, token string) (*model.ReviewToken, error) {
+	rt, err := r.findReviewToken(ctx, sq.Eq{reviewTokenValue: token})
+	if err != nil {
+		return nil, fmt.Errorf("scan GetReviewToken row: %w", err)
+	}
+
+	return rt, nil
+}
+
+// GetReviewTokenByPIN ищет неиспользованный и неотозванный токен заведения по PIN-коду.
+// Истёкший токен возвращается: его состояние проверяет сервис.
+func (r *PostgresReviewRepository) GetReviewTokenByPIN(ctx context.Context, placeID uuid.UUID, pin string) (*model.ReviewToken, error) {
+	rt, err := r.findReviewToken(ctx, sq.Eq{
+		reviewTokenPlaceID:   placeID,
+		reviewTokenPIN:       pin,
+		reviewTokenIsUsed:    false,
+		reviewTokenRevokedAt: nil,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan GetReviewTokenByPIN row: %w", err)
+	}
+
+	return rt, nil
+}
+
+func (r *PostgresReviewRepository) findReviewToken(ctx context.Context, where sq.Sqlizer) (*model.ReviewToken, error) {
 	query, args, err := r.builder.
 		Select(
 			reviewTokenIDColumn,
@@ -56,18 +107,30 @@ func (r *PostgresReviewRepository) GetReviewToken(ctx context.Context, token str
 			reviewTokenValue,
 			reviewTokenIsUsed,
 			reviewTokenExpiresAt,
+			reviewTokenRevokedAt,
+			reviewTokenReceiptID,
+			reviewTokenAmount,
+			reviewTokenPurchased,
+			reviewTokenStaffID,
+			reviewTokenTableNo,
+			reviewTokenCampaign,
+			reviewTokenPIN,
 		).
 		From(reviewTokenTable).
-		Where(sq.Eq{reviewTokenValue: token}).
+		Where(where).
 		ToSql()
-
 	if err != nil {
-		return nil, fmt.Errorf("build GetReviewToken query: %w", err)
+		return nil, fmt.Errorf("build query: %w", err)
 	}
 
-	row := r.db.QueryRow(ctx, query, args...)
+	row := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...)
 
-	var rt model.ReviewToken
+	var (
+		rt          model.ReviewToken
+		receiptID   *string
+		amount      *int64
+		purchasedAt *time.Time
+	)
 
 	err = row.Scan(
 		&rt.ID,
@@ -75,36 +138,75 @@ func (r *PostgresReviewRepository) GetReviewToken(ctx context.Context, token str
 		&rt.Token,
 		&rt.IsUsed,
 		&rt.ExpiresAt,
+		&rt.RevokedAt,
+		&receiptID,
+		&amount,
+		&purchasedAt,
+		&rt.Labels.StaffID,
+		&rt.Labels.Table,
+		&rt.Labels.Campaign,
+		&rt.PIN,
 	)
-
 	if err != nil {
-		return nil, fmt.Errorf("scan GetReviewToken row: %w", err)
+		return nil, err
+	}
+
+	if receiptID != nil {
+		rt.Receipt = &model.Receipt{ID: *receiptID}
+		if amount != nil {
+			rt.Receipt.Amount = *amount
+		}
+		if purchasedAt != nil {
+			rt.Receipt.PurchasedAt = *purchasedAt
+		}
 	}
 
 	return &rt, nil
 }
 
-func (r *PostgresReviewRepository) MarkReviewTokenUsed(ctx context.Context, tokenID string) error {
+// ClaimReviewToken атомарно помечает токен использованным. Если токен уже
+// использован, отозван или истёк, возвращает pgx.ErrNoRows.
+func (r *PostgresReviewRepository) ClaimReviewToken(ctx context.Context, tokenID string) (*model.ReviewToken, error) {
 	uuidID, err := uuid.Parse(tokenID)
 	if err != nil {
-		return fmt.Errorf("invalid token ID: %w", err)
+		return nil, fmt.Errorf("invalid token ID: %w", err)
 	}
 
 	query, args, err := r.builder.
 		Update(reviewTokenTable).
 		Set(reviewTokenIsUsed, true).
-		Where(sq.Eq{reviewTokenIDColumn: uuidID}).
+		Where(sq.Eq{
+			reviewTokenIDColumn:  uuidID,
+			reviewTokenIsUsed:    false,
+			reviewTokenRevokedAt: nil,
+		}).
+		Where(sq.Gt{reviewTokenExpiresAt: time.Now()}).
+		Suffix("RETURNING " + strings.Join([]string{
+			reviewTokenIDColumn,
+			reviewTokenPlaceID,
+			reviewTokenValue,
+			reviewTokenIsUsed,
+			reviewTokenExpiresAt,
+		}, ", ")).
 		ToSql()
 
 	if err != nil {
-		return fmt.Errorf("build MarkReviewTokenUsed query: %w", err)
+		return nil, fmt.Errorf("build ClaimReviewToken query: %w", err)
 	}
 
-	if _, err := r.db.Exec(ctx, query, args...); err != nil {
-		return fmt.Errorf("exec MarkReviewTokenUsed: %w", err)
+	var rt model.ReviewToken
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
+		&rt.ID,
+		&rt.PlaceID,
+		&rt.Token,
+		&rt.IsUsed,
+		&rt.ExpiresAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("exec ClaimReviewToken: %w", err)
 	}
 
-	return nil
+	return &rt, nil
 }
 
 func (r *PostgresReviewRepository) CreateReview(ctx context.Context, review model.Review) error {
@@ -118,6 +220,13 @@ func (r *PostgresReviewRepository) CreateReview(ctx context.Context, review mode
 			reviewContent,
 			reviewRating,
 			reviewCreatedAt,
+			reviewReceiptID,
+			reviewPoints,
+			reviewStaffID,
+			reviewTableNo,
+			reviewCampaign,
+			reviewStatus,
+			reviewFilterRule,
 		).
 		Values(
 			review.ID,
@@ -127,6 +236,13 @@ func (r *PostgresReviewRepository) CreateReview(ctx context.Context, review mode
 			review.Content,
 			review.Rating,
 			time.Now().UTC(),
+			review.ReceiptID,
+			review.Points,
+			review.Labels.StaffID,
+			review.Labels.Table,
+			review.Labels.Campaign,
+			review.Status,
+			review.FilterRule,
 		).
 		ToSql()
 
@@ -134,7 +250,7 @@ func (r *PostgresReviewRepository) CreateReview(ctx context.Context, review mode
 		return fmt.Errorf("build CreateReview query: %w", err)
 	}
 
-	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
 		return fmt.Errorf("exec CreateReview: %w", err)
 	}
 
@@ -160,7 +276,7 @@ func (r *PostgresReviewRepository) HasReviewToday(ctx context.Context, userID, p
 		return false, fmt.Errorf("build HasReviewToday query: %w", err)
 	}
 
-	row := r.db.QueryRow(ctx, query, args...)
+	row := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...)
 	var dummy int
 	err = row.Scan(&dummy)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -172,43 +288,101 @@ func (r *PostgresReviewRepository) HasReviewToday(ctx context.Context, userID, p
 	return true, nil
 }
 
-func (r *PostgresReviewRepository) FindReviews(ctx context.Context, placeID string, filter model.ReviewFilter) ([]model.Review, error) {
+// reviewSortKeys — порядок публичного списка для каждого режима сортировки.
+// created_at и id замыкают порядок, чтобы курсор однозначно указывал на строку.
+var reviewSortKeys = map[string][]pagination.Key{
+	model.ReviewSortDateDesc: {
+		{Column: "r." + reviewCreatedAt, Desc: true},
+		{Column: "r." + reviewIDColumn, Desc: true},
+	},
+	model.ReviewSortDateAsc: {
+		{Column: "r." + reviewCreatedAt},
+		{Column: "r." + reviewIDColumn},
+	},
+	model.ReviewSortRatingDesc: {
+		{Column: "r." + reviewRating, Desc: true},
+		{Column: "r." + reviewCreatedAt, Desc: true},
+		{Column: "r." + reviewIDColumn, Desc: true},
+	},
+	model.ReviewSortRatingAsc: {
+		{Column: "r." + reviewRating},
+		{Column: "r." + reviewCreatedAt, Desc: true},
+		{Column: "r." + reviewIDColumn, Desc: true},
+	},
+	model.ReviewSortHelpful: {
+		{Column: "r." + reviewHelpfulCount, Desc: true},
+		{Column: "r." + reviewCreatedAt, Desc: true},
+		{Column: "r." + reviewIDColumn, Desc: true},
+	},
+}
+
+// FindReviews возвращает страницу опубликованных отзывов заведения в порядке filter.Sort
+// и общее число отзывов под фильтром. Курсор кодирует режим сортировки и ключи последнего отзыва;
+// курсор другого режима или повреждённый даёт pagination.ErrInvalidCursor.
+func (r *PostgresReviewRepository) FindReviews(ctx context.Context, placeID string, filter model.ReviewFilter) (*pagination.Page[model.Review], error) {
 	uid, err := uuid.Parse(placeID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid place id: %w", err)
 	}
 
-	builder := r.builder.
-		Select(
-			reviewIDColumn,
-			reviewUserID,
-			reviewPlaceID,
-			reviewTokenID,
-			reviewContent,
-			reviewRating,
-			reviewCreatedAt,
-		).
-		From(reviewTable).
-		Where(sq.Eq{
-			reviewPlaceID:      uid,
-			reviewIsDeletedCol: false,
-		})
+	keys, ok := reviewSortKeys[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown review sort %q", filter.Sort)
+	}
 
+	conditions := sq.And{
+		sq.Eq{
+			"r." + reviewPlaceID:      uid,
+			"r." + reviewIsDeletedCol: false,
+			"r." + reviewStatus:       model.ReviewStatusPublished,
+		},
+	}
 	if filter.HasRating {
-		builder = builder.Where(sq.Eq{reviewRating: filter.Rating})
+		conditions = append(conditions, sq.Eq{"r." + reviewRating: filter.Rating})
 	}
 	if filter.FromDate != nil {
-		builder = builder.Where(sq.GtOrEq{reviewCreatedAt: *filter.FromDate})
+		conditions = append(conditions, sq.GtOrEq{"r." + reviewCreatedAt: *filter.FromDate})
 	}
 	if filter.ToDate != nil {
-		builder = builder.Where(sq.LtOrEq{reviewCreatedAt: *filter.ToDate})
+		conditions = append(conditions, sq.LtOrEq{"r." + reviewCreatedAt: *filter.ToDate})
 	}
 
-	switch filter.Sort {
-	case "date_asc":
-		builder = builder.OrderBy(reviewCreatedAt + " ASC")
-	default:
-		builder = builder.OrderBy(reviewCreatedAt + " DESC")
+	total, err := r.countReviews(ctx, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ответ заведения подтягивается тем же запросом
+	builder := r.builder.
+		Select(
+			"r."+reviewIDColumn,
+			"r."+reviewUserID,
+			"r."+reviewPlaceID,
+			"r."+reviewTokenID,
+			"r."+reviewContent,
+			"r."+reviewRating,
+			"r."+reviewCreatedAt,
+			"r."+reviewUpdatedAt,
+			"r."+reviewReceiptID,
+			"r."+reviewHelpfulCount,
+			"rr.id",
+			"rr.content",
+			"rr.created_at",
+			"rr.updated_at",
+		).
+		From(reviewTable + " r").
+		LeftJoin(replyTable + " rr ON rr.review_id = r." + reviewIDColumn).
+		Where(conditions).
+		OrderBy(pagination.OrderBy(keys)...).
+		// Лишняя строка показывает, есть ли следующая страница
+		Limit(uint64(filter.Page.Limit + 1))
+
+	if filter.Page.Cursor != "" {
+		values, err := decodeReviewCursor(filter.Page.Cursor, filter.Sort)
+		if err != nil {
+			return nil, err
+		}
+		builder = builder.Where(pagination.After(keys, values))
 	}
 
 	query, args, err := builder.ToSql()
@@ -216,7 +390,7 @@ func (r *PostgresReviewRepository) FindReviews(ctx context.Context, placeID stri
 		return nil, fmt.Errorf("build FindReviews query: %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("exec FindReviews: %w", err)
 	}
@@ -224,7 +398,13 @@ func (r *PostgresReviewRepository) FindReviews(ctx context.Context, placeID stri
 
 	reviews := make([]model.Review, 0)
 	for rows.Next() {
-		var rev model.Review
+		var (
+			rev            model.Review
+			replyID        *uuid.UUID
+			replyContent   *string
+			replyCreatedAt *time.Time
+			replyUpdatedAt *time.Time
+		)
 		err := rows.Scan(
 			&rev.ID,
 			&rev.UserID,
@@ -233,33 +413,153 @@ func (r *PostgresReviewRepository) FindReviews(ctx context.Context, placeID stri
 			&rev.Content,
 			&rev.Rating,
 			&rev.CreatedAt,
+			&rev.UpdatedAt,
+			&rev.ReceiptID,
+			&rev.HelpfulCount,
+			&replyID,
+			&replyContent,
+			&replyCreatedAt,
+			&replyUpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan FindReviews row: %w", err)
 		}
+		if replyID != nil {
+			rev.Reply = &model.ReviewReply{
+				ID:        *replyID,
+				ReviewID:  rev.ID,
+				PlaceID:   rev.PlaceID,
+				Content:   *replyContent,
+				CreatedAt: *replyCreatedAt,
+				UpdatedAt: replyUpdatedAt,
+			}
+		}
 		reviews = append(reviews, rev)
 	}
-	return reviews, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read FindReviews rows: %w", err)
+	}
+
+	page := &pagination.Page[model.Review]{Items: reviews, Total: total}
+	if len(reviews) > filter.Page.Limit {
+		page.Items = reviews[:filter.Page.Limit]
+		page.NextCursor, err = encodeReviewCursor(filter.Sort, &page.Items[len(page.Items)-1])
+		if err != nil {
+			return nil, fmt.Errorf("encode review cursor: %w", err)
+		}
+	}
+
+	return page, nil
 }
 
-func (r *PostgresReviewRepository) UpdateReview(ctx context.Context, reviewID, userID string, content string, rating int) error {
+func (r *PostgresReviewRepository) countReviews(ctx context.Context, conditions sq.Sqlizer) (int, error) {
+	query, args, err := r.builder.
+		Select("COUNT(*)").
+		From(reviewTable + " r").
+		Where(conditions).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build countReviews query: %w", err)
+	}
+
+	var total int
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("exec countReviews: %w", err)
+	}
+
+	return total, nil
+}
+
+// encodeReviewCursor кодирует режим сортировки и значения ключей reviewSortKeys для отзыва
+func encodeReviewCursor(sort string, rev *model.Review) (string, error) {
+	switch sort {
+	case model.ReviewSortRatingDesc, model.ReviewSortRatingAsc:
+		return pagination.EncodeCursor(sort, rev.Rating, rev.CreatedAt, rev.ID)
+	case model.ReviewSortHelpful:
+		return pagination.EncodeCursor(sort, rev.HelpfulCount, rev.CreatedAt, rev.ID)
+	default:
+		return pagination.EncodeCursor(sort, rev.CreatedAt, rev.ID)
+	}
+}
+
+// decodeReviewCursor возвращает значения ключей из курсора, выданного для того же режима сортировки
+func decodeReviewCursor(s, sort string) ([]any, error) {
+	cursor, err := pagination.DecodeCursor(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cursorSort string
+		key        int
+		createdAt  time.Time
+		id         uuid.UUID
+	)
+	switch sort {
+	case model.ReviewSortRatingDesc, model.ReviewSortRatingAsc, model.ReviewSortHelpful:
+		err = cursor.Scan(&cursorSort, &key, &createdAt, &id)
+	default:
+		err = cursor.Scan(&cursorSort, &createdAt, &id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cursorSort != sort {
+		return nil, pagination.ErrInvalidCursor
+	}
+
+	switch sort {
+	case model.ReviewSortRatingDesc, model.ReviewSortRatingAsc, model.ReviewSortHelpful:
+		return []any{key, createdAt, id}, nil
+	default:
+		return []any{createdAt, id}, nil
+	}
+}
+
+// UpdateReview меняет текст и оценку отзыва автора и запоминает сработавшее правило фильтра.
+// Непустой flagReason возвращает опубликованный отзыв в очередь ожидающих с этой причиной модерации.
+func (r *PostgresReviewRepository) UpdateReview(
+	ctx context.Context,
+	reviewID, userID string,
+	content string,
+	rating int,
+	filterRule *string,
+	flagReason *string,
+) error {
 	now := time.Now()
 
-	query, args, err := r.builder.
+	builder := r.builder.
 		Update(reviewTable).
 		Set(reviewContent, content).
 		Set(reviewRating, rating).
-		Set("updated_at", now).
+		Set(reviewFilterRule, filterRule).
+		Set(reviewUpdatedAt, now).
 		Where(sq.Eq{
-			reviewIDColumn: reviewID,
-			reviewUserID:   userID,
-		}).
-		ToSql()
+			reviewIDColumn:     reviewID,
+			reviewUserID:       userID,
+			reviewIsDeletedCol: false,
+		})
+
+	// Помеченная правка возвращает опубликованный отзыв в очередь модерации с причиной flagReason.
+	// Все CASE видят статус до обновления.
+	if flagReason != nil {
+		builder = builder.
+			Set(reviewStatus, sq.Expr(
+				"CASE WHEN "+reviewStatus+" = ? THEN ? ELSE "+reviewStatus+" END",
+				model.ReviewStatusPublished, model.ReviewStatusPending,
+			)).
+			Set(reviewModReason, sq.Expr(
+				"CASE WHEN "+reviewStatus+" = ? THEN ? ELSE "+reviewModReason+" END",
+				model.ReviewStatusPublished, *flagReason,
+			))
+	}
+
+	query, args, err := builder.ToSql()
 	if err != nil {
 		return err
 	}
 
-	res, err := r.db.Exec(ctx, query, args...)
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -271,20 +571,23 @@ func (r *PostgresReviewRepository) UpdateReview(ctx context.Context, reviewID, u
 	return nil
 }
 
+// DeleteReview помечает отзыв автора удалённым и запоминает момент удаления для отмены
 func (r *PostgresReviewRepository) DeleteReview(ctx context.Context, reviewID, userID string) error {
 	query, args, err := r.builder.
 		Update(reviewTable).
 		Set(reviewIsDeletedCol, true).
+		Set(reviewDeletedAt, time.Now().UTC()).
 		Where(sq.Eq{
-			reviewIDColumn: reviewID,
-			reviewUserID:   userID,
+			reviewIDColumn:     reviewID,
+			reviewUserID:       userID,
+			reviewIsDeletedCol: false,
 		}).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("build DeleteReview query: %w", err)
 	}
 
-	res, err := r.db.Exec(ctx, query, args...)
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("exec DeleteReview: %w", err)
 	}
@@ -296,6 +599,36 @@ func (r *PostgresReviewRepository) DeleteReview(ctx context.Context, reviewID, u
 	return nil
 }
 
+// RestoreReview снимает пометку удаления с отзыва автора, удалённого не раньше deletedSince.
+// Если такого отзыва нет, возвращает pgx.ErrNoRows.
+func (r *PostgresReviewRepository) RestoreReview(ctx context.Context, reviewID, userID uuid.UUID, deletedSince time.Time) error {
+	query, args, err := r.builder.
+		Update(reviewTable).
+		Set(reviewIsDeletedCol, false).
+		Set(reviewDeletedAt, nil).
+		Where(sq.Eq{
+			reviewIDColumn:     reviewID,
+			reviewUserID:       userID,
+			reviewIsDeletedCol: true,
+		}).
+		Where(sq.GtOrEq{reviewDeletedAt: deletedSince}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build RestoreReview query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec RestoreReview: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
 func (r *PostgresReviewRepository) CountLowRatingReviews(ctx context.Context, userID string, days int) (int, error) {
 	query := `
         SELECT COUNT(*)
@@ -306,7 +639,7 @@ func (r *PostgresReviewRepository) CountLowRatingReviews(ctx context.Context, us
           AND is_deleted = false`
 
 	var count int
-	err := r.db.QueryRow(ctx, query, userID, days).Scan(&count)
+	err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, userID, days).Scan(&count)
 	return count, err
 }
 
@@ -323,7 +656,7 @@ func (r *PostgresReviewRepository) CountUserReviews(ctx context.Context, userID
 		return 0, fmt.Errorf("build CountUserReviews query: %w", err)
 	}
 
-	row := r.db.QueryRow(ctx, query, args...)
+	row := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...)
 
 	var count int
 	if err := row.Scan(&count); err != nil {
@@ -346,7 +679,7 @@ func (r *PostgresReviewRepository) AvgUserRating(ctx context.Context, userID str
 		return 0, fmt.Errorf("build AvgUserRating query: %w", err)
 	}
 
-	row := r.db.QueryRow(ctx, query, args...)
+	row := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...)
 
 	var avg float64
 	if err := row.Scan(&avg); err != nil {
@@ -355,3 +688,274 @@ func (r *PostgresReviewRepository) AvgUserRating(ctx context.Context, userID str
 
 	return avg, nil
 }
+
+// GetReviewByID возвращает неудалённый отзыв в любом статусе модерации
+func (r *PostgresReviewRepository) GetReviewByID(ctx context.Context, reviewID uuid.UUID) (*model.Review, error) {
+	rev, err := r.getReviewByID(ctx, reviewID, "")
+	if err != nil {
+		return nil, fmt.Errorf("exec GetReviewByID: %w", err)
+	}
+
+	return rev, nil
+}
+
+// LockReviewByID как GetReviewByID, но блокирует строку отзыва до конца транзакции:
+// решения по одному отзыву (жалобы, скрытие) выполняются по очереди
+func (r *PostgresReviewRepository) LockReviewByID(ctx context.Context, reviewID uuid.UUID) (*model.Review, error) {
+	rev, err := r.getReviewByID(ctx, reviewID, "FOR UPDATE")
+	if err != nil {
+		return nil, fmt.Errorf("exec LockReviewByID: %w", err)
+	}
+
+	return rev, nil
+}
+
+func (r *PostgresReviewRepository) getReviewByID(ctx context.Context, reviewID uuid.UUID, suffix string) (*model.Review, error) {
+	query, args, err := r.builder.
+		Select(moderationColumns...).
+		From(reviewTable).
+		Where(sq.Eq{
+			reviewIDColumn:     reviewID,
+			reviewIsDeletedCol: false,
+		}).
+		Suffix(suffix).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build review by id query: %w", err)
+	}
+
+	return scanModeratedReview(transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...))
+}
+
+// moderationSortKeys — порядок очереди модерации: старые первыми, id различает одновременные
+var moderationSortKeys = []pagination.Key{
+	{Column: reviewCreatedAt},
+	{Column: reviewIDColumn},
+}
+
+// FindModerationQueue возвращает страницу неудалённых отзывов в статусе filter.Status, старые первыми,
+// и их общее число. Курсор кодирует created_at и id последнего отзыва страницы.
+func (r *PostgresReviewRepository) FindModerationQueue(ctx context.Context, filter model.ModerationFilter) (*pagination.Page[model.Review], error) {
+	conditions := sq.And{sq.Eq{
+		reviewStatus:       filter.Status,
+		reviewIsDeletedCol: false,
+	}}
+	if filter.PlaceID != nil {
+		conditions = append(conditions, sq.Eq{reviewPlaceID: *filter.PlaceID})
+	}
+
+	total, err := r.countReviews(ctx, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := r.builder.
+		Select(moderationColumns...).
+		From(reviewTable).
+		Where(conditions).
+		OrderBy(pagination.OrderBy(moderationSortKeys)...).
+		Limit(uint64(filter.Page.Limit + 1))
+
+	if filter.Page.Cursor != "" {
+		cursor, err := pagination.DecodeCursor(filter.Page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			createdAt time.Time
+			id        uuid.UUID
+		)
+		if err := cursor.Scan(&createdAt, &id); err != nil {
+			return nil, err
+		}
+		builder = builder.Where(pagination.After(moderationSortKeys, []any{createdAt, id}))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build FindModerationQueue query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec FindModerationQueue: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := make([]model.Review, 0)
+	for rows.Next() {
+		rev, err := scanModeratedReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan FindModerationQueue row: %w", err)
+		}
+		reviews = append(reviews, *rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read FindModerationQueue rows: %w", err)
+	}
+
+	page := &pagination.Page[model.Review]{Items: reviews, Total: total}
+	if len(reviews) > filter.Page.Limit {
+		page.Items = reviews[:filter.Page.Limit]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor, err = pagination.EncodeCursor(last.CreatedAt, last.ID)
+		if err != nil {
+			return nil, fmt.Errorf("encode moderation cursor: %w", err)
+		}
+	}
+
+	return page, nil
+}
+
+// UpdateReviewStatus переводит отзыв из статуса from в to и запоминает модератора и причину;
+// moderatorID равен nil, если решение принято автоматически.
+// Если отзыва нет или его статус уже не from, возвращает pgx.ErrNoRows.
+func (r *PostgresReviewRepository) UpdateReviewStatus(
+	ctx context.Context,
+	reviewID uuid.UUID,
+	from, to string,
+	moderatorID *uuid.UUID,
+	reason *string,
+) error {
+	query, args, err := r.builder.
+		Update(reviewTable).
+		Set(reviewStatus, to).
+		Set(reviewModReason, reason).
+		Set(reviewModeratedBy, moderatorID).
+		Set(reviewModeratedAt, time.Now().UTC()).
+		Where(sq.Eq{
+			reviewIDColumn:     reviewID,
+			reviewStatus:       from,
+			reviewIsDeletedCol: false,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build UpdateReviewStatus query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec UpdateReviewStatus: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+var moderationColumns = []string{
+	reviewIDColumn,
+	reviewUserID,
+	reviewPlaceID,
+	reviewTokenID,
+	reviewContent,
+	reviewRating,
+	reviewCreatedAt,
+	reviewReceiptID,
+	reviewPoints,
+	reviewStatus,
+	reviewModReason,
+	reviewModeratedBy,
+	reviewModeratedAt,
+	reviewFilterRule,
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanModeratedReview(row rowScanner) (*model.Review, error) {
+	var rev model.Review
+	err := row.Scan(
+		&rev.ID,
+		&rev.UserID,
+		&rev.PlaceID,
+		&rev.TokenID,
+		&rev.Content,
+		&rev.Rating,
+		&rev.CreatedAt,
+		&rev.ReceiptID,
+		&rev.Points,
+		&rev.Status,
+		&rev.ModerationReason,
+		&rev.ModeratedBy,
+		&rev.ModeratedAt,
+		&rev.FilterRule,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rev, nil
+}
+
+// GetLabelStats группирует отзывы заведения за [from, to) по метке dimension: число отзывов и средняя оценка.
+// Учитываются только опубликованные отзывы с меткой; лучшие значения идут первыми. Пустая граница периода не ограничивает.
+func (r *PostgresReviewRepository) GetLabelStats(ctx context.Context, placeID uuid.UUID, dimension string, from, to *time.Time) ([]model.LabelStat, error) {
+	var (
+		labelColumn string
+		nameColumn  = "''"
+	)
+	switch dimension {
+	case model.LabelDimensionStaff:
+		labelColumn = "r." + reviewStaffID
+		nameColumn = "COALESCE(u.name, '')"
+	case model.LabelDimensionTable:
+		labelColumn = "r." + reviewTableNo
+	case model.LabelDimensionCampaign:
+		labelColumn = "r." + reviewCampaign
+	default:
+		return nil, fmt.Errorf("unknown label dimension %q", dimension)
+	}
+
+	builder := r.builder.
+		Select(
+			labelColumn+"::text",
+			nameColumn,
+			"COUNT(*)",
+			"AVG(r."+reviewRating+")::float8",
+		).
+		From(reviewTable+" r").
+		Where(sq.Eq{
+			"r." + reviewPlaceID:      placeID,
+			"r." + reviewIsDeletedCol: false,
+			"r." + reviewStatus:       model.ReviewStatusPublished,
+		}).
+		Where(sq.NotEq{labelColumn: nil}).
+		GroupBy(labelColumn).
+		OrderBy("AVG(r."+reviewRating+") DESC", "COUNT(*) DESC", labelColumn+"::text ASC")
+
+	if dimension == model.LabelDimensionStaff {
+		builder = builder.LeftJoin("users u ON u.id = " + labelColumn).GroupBy("u.name")
+	}
+	if from != nil {
+		builder = builder.Where(sq.GtOrEq{"r." + reviewCreatedAt: *from})
+	}
+	if to != nil {
+		builder = builder.Where(sq.Lt{"r." + reviewCreatedAt: *to})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetLabelStats query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec GetLabelStats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []model.LabelStat
+	for rows.Next() {
+		var st model.LabelStat
+		if err := rows.Scan(&st.Label, &st.StaffName, &st.Reviews, &st.AverageRating); err != nil {
+			return nil, fmt.Errorf("scan GetLabelStats: %w", err)
+		}
+		stats = append(stats, st)
+	}
+
+	return stats, rows.Err()
+}
@@ -0,0 +1,101 @@
+package revision
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+)
+
+const (
+	revisionTable           = "review_revisions"
+	revisionIDColumn        = "id"
+	revisionReviewIDColumn  = "review_id"
+	revisionNumberColumn    = "revision"
+	revisionContentColumn   = "content"
+	revisionRatingColumn    = "rating"
+	revisionCreatedAtColumn = "created_at"
+)
+
+type PostgresRevisionRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresRevisionRepository(db *pgxpool.Pool) *PostgresRevisionRepository {
+	return &PostgresRevisionRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// CreateRevision сохраняет следующую по номеру редакцию отзыва и заполняет rev.Revision.
+// Вызывается в транзакции после изменения строки отзыва: её блокировка упорядочивает параллельные правки.
+func (r *PostgresRevisionRepository) CreateRevision(ctx context.Context, rev *model.ReviewRevision) error {
+	query := `
+		INSERT INTO review_revisions (id, review_id, revision, content, rating, created_at)
+		SELECT $1, $2, COALESCE(MAX(revision), 0) + 1, $3, $4, $5
+		FROM review_revisions
+		WHERE review_id = $2
+		RETURNING revision`
+
+	err := transaction.Executor(ctx, r.db).QueryRow(
+		ctx, query,
+		rev.ID, rev.ReviewID, rev.Content, rev.Rating, rev.CreatedAt,
+	).Scan(&rev.Revision)
+	if err != nil {
+		return fmt.Errorf("exec CreateRevision: %w", err)
+	}
+
+	return nil
+}
+
+// ListRevisions возвращает редакции отзыва от первой к последней
+func (r *PostgresRevisionRepository) ListRevisions(ctx context.Context, reviewID uuid.UUID) ([]model.ReviewRevision, error) {
+	query, args, err := r.builder.
+		Select(
+			revisionIDColumn,
+			revisionReviewIDColumn,
+			revisionNumberColumn,
+			revisionContentColumn,
+			revisionRatingColumn,
+			revisionCreatedAtColumn,
+		).
+		From(revisionTable).
+		Where(sq.Eq{revisionReviewIDColumn: reviewID}).
+		OrderBy(revisionNumberColumn + " ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build ListRevisions query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec ListRevisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := make([]model.ReviewRevision, 0)
+	for rows.Next() {
+		var rev model.ReviewRevision
+		err := rows.Scan(
+			&rev.ID,
+			&rev.ReviewID,
+			&rev.Revision,
+			&rev.Content,
+			&rev.Rating,
+			&rev.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan ListRevisions row: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, rows.Err()
+}
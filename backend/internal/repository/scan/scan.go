@@ -0,0 +1,227 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+)
+
+const (
+	scansTable          = "token_scans"
+	scanIDColumn        = "id"
+	scanTokenIDColumn   = "token_id"
+	scanPlaceIDColumn   = "place_id"
+	scanUserAgentColumn = "user_agent"
+	scanScannedAtColumn = "scanned_at"
+	scanUserIDColumn    = "user_id"
+	scanOpenedAtColumn  = "opened_at"
+	scanClientKeyColumn = "client_key"
+	scanSessionColumn   = "session_key"
+
+	reviewTokensTable = "review_tokens"
+	tokenIDColumn     = "id"
+	tokenPlaceID      = "place_id"
+	tokenValueColumn  = "token_value"
+
+	reviewsTable        = "reviews"
+	reviewTokenIDColumn = "token_id"
+	reviewPointsColumn  = "points"
+	reviewStatusColumn  = "status"
+	reviewIsDeleted     = "is_deleted"
+)
+
+type PostgresScanRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresScanRepository(db *pgxpool.Pool) *PostgresScanRepository {
+	return &PostgresScanRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// CreateScan записывает переход по токену tokenValue и заполняет TokenID и PlaceID.
+// Если такого токена нет, возвращает pgx.ErrNoRows.
+func (r *PostgresScanRepository) CreateScan(ctx context.Context, scan *model.TokenScan, tokenValue string) error {
+	// Токен ищется в том же запросе, что и вставка: неизвестный код просто не даёт строки
+	source := sq.
+		Select(tokenIDColumn, tokenPlaceID).
+		Column("?::uuid", scan.ID).
+		Column("?::text", scan.UserAgent).
+		Column("?::timestamp", scan.ScannedAt).
+		Column("?::text", scan.ClientKey).
+		Column("?::text", scan.SessionKey).
+		From(reviewTokensTable).
+		Where(sq.Eq{tokenValueColumn: tokenValue})
+
+	query, args, err := r.builder.
+		Insert(scansTable).
+		Columns(
+			scanTokenIDColumn,
+			scanPlaceIDColumn,
+			scanIDColumn,
+			scanUserAgentColumn,
+			scanScannedAtColumn,
+			scanClientKeyColumn,
+			scanSessionColumn,
+		).
+		Select(source).
+		Suffix("RETURNING " + scanTokenIDColumn + ", " + scanPlaceIDColumn).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build CreateScan query: %w", err)
+	}
+
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&scan.TokenID, &scan.PlaceID)
+	if err != nil {
+		return fmt.Errorf("exec CreateScan: %w", err)
+	}
+
+	return nil
+}
+
+// FindRecentScan возвращает последний переход клиента clientKey по токену tokenValue не раньше since.
+// Если такого нет, возвращает pgx.ErrNoRows.
+func (r *PostgresScanRepository) FindRecentScan(ctx context.Context, tokenValue, clientKey string, since time.Time) (*model.TokenScan, error) {
+	query, args, err := r.builder.
+		Select(
+			scansTable+"."+scanIDColumn,
+			scansTable+"."+scanTokenIDColumn,
+			scansTable+"."+scanPlaceIDColumn,
+			scansTable+"."+scanUserAgentColumn,
+			scansTable+"."+scanScannedAtColumn,
+			scansTable+"."+scanClientKeyColumn,
+			scansTable+"."+scanSessionColumn,
+		).
+		From(scansTable).
+		Join(reviewTokensTable + " ON " + reviewTokensTable + "." + tokenIDColumn + " = " + scansTable + "." + scanTokenIDColumn).
+		Where(sq.Eq{
+			reviewTokensTable + "." + tokenValueColumn: tokenValue,
+			scansTable + "." + scanClientKeyColumn:     clientKey,
+		}).
+		Where(sq.GtOrEq{scansTable + "." + scanScannedAtColumn: since}).
+		OrderBy(scansTable + "." + scanScannedAtColumn + " DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build FindRecentScan query: %w", err)
+	}
+
+	var scan model.TokenScan
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
+		&scan.ID,
+		&scan.TokenID,
+		&scan.PlaceID,
+		&scan.UserAgent,
+		&scan.ScannedAt,
+		&scan.ClientKey,
+		&scan.SessionKey,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scan FindRecentScan: %w", err)
+	}
+
+	return &scan, nil
+}
+
+// MarkOpened привязывает переход к вошедшему пользователю. Повторные вызовы ничего не меняют:
+// засчитывается первый вход. Если перехода с таким ключом сессии нет, возвращает pgx.ErrNoRows.
+func (r *PostgresScanRepository) MarkOpened(ctx context.Context, scanID, userID uuid.UUID, sessionKey string) error {
+	query, args, err := r.builder.
+		Update(scansTable).
+		Set(scanUserIDColumn, sq.Expr("COALESCE("+scanUserIDColumn+", ?)", userID)).
+		Set(scanOpenedAtColumn, sq.Expr("COALESCE("+scanOpenedAtColumn+", now())")).
+		Where(sq.Eq{
+			scanIDColumn:      scanID,
+			scanSessionColumn: sessionKey,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build MarkOpened query: %w", err)
+	}
+
+	tag, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec MarkOpened: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetFunnel считает воронку по токенам заведения, отсканированным в [from, to):
+// переходы, разные токены, входы и отзывы с баллами по этим токенам
+func (r *PostgresScanRepository) GetFunnel(ctx context.Context, placeID uuid.UUID, from, to time.Time) (*model.ScanFunnel, error) {
+	inPeriod := sq.And{
+		sq.Eq{scanPlaceIDColumn: placeID},
+		sq.GtOrEq{scanScannedAtColumn: from},
+		sq.Lt{scanScannedAtColumn: to},
+	}
+
+	query, args, err := r.builder.
+		Select(
+			"COUNT(*)",
+			"COUNT(DISTINCT "+scanTokenIDColumn+")",
+			"COUNT(DISTINCT "+scanTokenIDColumn+") FILTER (WHERE "+scanOpenedAtColumn+" IS NOT NULL)",
+		).
+		From(scansTable).
+		Where(inPeriod).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetFunnel query: %w", err)
+	}
+
+	funnel := model.ScanFunnel{PlaceID: placeID, From: from, To: to}
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
+		&funnel.Scans,
+		&funnel.ScannedTokens,
+		&funnel.Logins,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("exec GetFunnel: %w", err)
+	}
+
+	// Подзапрос собирается с плейсхолдерами "?": внешний builder пронумерует их вместе со своими
+	scanned, scannedArgs, err := sq.
+		Select(scanTokenIDColumn).
+		From(scansTable).
+		Where(inPeriod).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetFunnel reviews subquery: %w", err)
+	}
+
+	query, args, err = r.builder.
+		Select("COUNT(*)").
+		// Баллы есть только у опубликованных отзывов: ожидающие, скрытые и отклонённые их не приносят
+		Column(sq.Expr(
+			"COALESCE(SUM("+reviewPointsColumn+") FILTER (WHERE "+reviewStatusColumn+" = ?), 0)",
+			model.ReviewStatusPublished,
+		)).
+		From(reviewsTable).
+		Where(sq.Expr(reviewTokenIDColumn+" IN ("+scanned+")", scannedArgs...)).
+		Where(sq.Eq{reviewIsDeleted: false}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetFunnel reviews query: %w", err)
+	}
+
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&funnel.Reviews, &funnel.Points)
+	if err != nil {
+		return nil, fmt.Errorf("exec GetFunnel reviews: %w", err)
+	}
+
+	return &funnel, nil
+}
@@ -2,12 +2,16 @@ package token
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
 )
 
 const (
@@ -17,6 +21,19 @@ const (
 	reviewTokenValueColumn     = "token_value"
 	reviewTokenIsUsedColumn    = "is_used"
 	reviewTokenExpiresAtColumn = "expires_at"
+	reviewTokenCreatedAtColumn = "created_at"
+	reviewTokenRevokedAtColumn = "revoked_at"
+	reviewTokenBatchJobColumn  = "batch_job_id"
+	reviewTokenStaffIDColumn   = "staff_id"
+	reviewTokenTableColumn     = "table_label"
+	reviewTokenCampaignColumn  = "campaign"
+	reviewTokenPINColumn       = "pin"
+
+	reviewTokenReceiptIDColumn     = "receipt_id"
+	reviewTokenReceiptAmountColumn = "receipt_amount"
+	reviewTokenPurchasedAtColumn   = "purchased_at"
+
+	uniqueViolationCode = "23505"
 )
 
 type PostgresTokenRepository struct {
@@ -31,9 +48,10 @@ func NewPostgresTokenRepository(db *pgxpool.Pool) *PostgresTokenRepository {
 	}
 }
 
-func (r *PostgresTokenRepository) CreateTokens(ctx context.Context, tokens []model.ReviewToken) error {
+// CreateTokens вставляет токены, пропуская совпавшие по token_value, и возвращает реально вставленные значения
+func (r *PostgresTokenRepository) CreateTokens(ctx context.Context, tokens []model.ReviewToken) ([]string, error) {
 	if len(tokens) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	builder := r.psql.Insert(reviewTokensTable).
@@ -43,6 +61,10 @@ func (r *PostgresTokenRepository) CreateTokens(ctx context.Context, tokens []mod
 			reviewTokenValueColumn,
 			reviewTokenExpiresAtColumn,
 			reviewTokenIsUsedColumn,
+			reviewTokenBatchJobColumn,
+			reviewTokenStaffIDColumn,
+			reviewTokenTableColumn,
+			reviewTokenCampaignColumn,
 		)
 
 	for _, t := range tokens {
@@ -52,19 +74,188 @@ func (r *PostgresTokenRepository) CreateTokens(ctx context.Context, tokens []mod
 			t.Token,
 			t.ExpiresAt,
 			t.IsUsed,
+			t.BatchJobID,
+			t.Labels.StaffID,
+			t.Labels.Table,
+			t.Labels.Campaign,
 		)
 	}
 
-	query, args, err := builder.ToSql()
+	query, args, err := builder.
+		Suffix("ON CONFLICT (" + reviewTokenValueColumn + ") DO NOTHING RETURNING " + reviewTokenValueColumn).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build insert tokens: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("insert tokens: %w", err)
+	}
+	defer rows.Close()
+
+	inserted := make([]string, 0, len(tokens))
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("scan inserted token: %w", err)
+		}
+		inserted = append(inserted, value)
+	}
+
+	return inserted, rows.Err()
+}
+
+// CreateReceiptToken вставляет токен, выпущенный по чеку. Возвращает false, если вставки не было:
+// чек уже есть у заведения либо совпало значение токена.
+func (r *PostgresTokenRepository) CreateReceiptToken(ctx context.Context, token model.ReviewToken) (bool, error) {
+	query, args, err := r.psql.
+		Insert(reviewTokensTable).
+		Columns(
+			reviewTokenIDColumn,
+			reviewTokenPlaceIDColumn,
+			reviewTokenValueColumn,
+			reviewTokenExpiresAtColumn,
+			reviewTokenIsUsedColumn,
+			reviewTokenReceiptIDColumn,
+			reviewTokenReceiptAmountColumn,
+			reviewTokenPurchasedAtColumn,
+		).
+		Values(
+			token.ID,
+			token.PlaceID,
+			token.Token,
+			token.ExpiresAt,
+			false,
+			token.Receipt.ID,
+			token.Receipt.Amount,
+			token.Receipt.PurchasedAt,
+		).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("build CreateReceiptToken query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("exec CreateReceiptToken: %w", err)
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+// GetTokenByReceipt возвращает токен, выпущенный заведением по чеку receiptID
+func (r *PostgresTokenRepository) GetTokenByReceipt(ctx context.Context, placeID uuid.UUID, receiptID string) (*model.ReviewToken, error) {
+	query, args, err := r.psql.
+		Select(
+			reviewTokenIDColumn,
+			reviewTokenPlaceIDColumn,
+			reviewTokenValueColumn,
+			reviewTokenIsUsedColumn,
+			reviewTokenExpiresAtColumn,
+			reviewTokenCreatedAtColumn,
+			reviewTokenRevokedAtColumn,
+			reviewTokenReceiptIDColumn,
+			reviewTokenReceiptAmountColumn,
+			reviewTokenPurchasedAtColumn,
+			reviewTokenPINColumn,
+		).
+		From(reviewTokensTable).
+		Where(sq.Eq{
+			reviewTokenPlaceIDColumn:   placeID,
+			reviewTokenReceiptIDColumn: receiptID,
+		}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetTokenByReceipt query: %w", err)
+	}
+
+	var (
+		t       model.ReviewToken
+		receipt model.Receipt
+	)
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
+		&t.ID,
+		&t.PlaceID,
+		&t.Token,
+		&t.IsUsed,
+		&t.ExpiresAt,
+		&t.CreatedAt,
+		&t.RevokedAt,
+		&receipt.ID,
+		&receipt.Amount,
+		&receipt.PurchasedAt,
+		&t.PIN,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("exec GetTokenByReceipt: %w", err)
+	}
+	t.Receipt = &receipt
+
+	return &t, nil
+}
+
+// SetPIN выдаёт PIN неиспользованному и неотозванному токену и возвращает PIN токена: новый
+// или выданный ранее. ok=false — PIN занят другим активным токеном заведения;
+// pgx.ErrNoRows — токена нет, он использован или отозван.
+func (r *PostgresTokenRepository) SetPIN(ctx context.Context, tokenID uuid.UUID, pin string) (string, bool, error) {
+	query, args, err := r.psql.
+		Update(reviewTokensTable).
+		Set(reviewTokenPINColumn, sq.Expr("COALESCE("+reviewTokenPINColumn+", ?)", pin)).
+		Where(sq.Eq{
+			reviewTokenIDColumn:        tokenID,
+			reviewTokenIsUsedColumn:    false,
+			reviewTokenRevokedAtColumn: nil,
+		}).
+		Suffix("RETURNING " + reviewTokenPINColumn).
+		ToSql()
 	if err != nil {
-		return fmt.Errorf("build insert tokens: %w", err)
+		return "", false, fmt.Errorf("build SetPIN query: %w", err)
 	}
 
-	if _, err := r.db.Exec(ctx, query, args...); err != nil {
-		return fmt.Errorf("insert tokens: %w", err)
+	var assigned string
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&assigned); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("exec SetPIN: %w", err)
 	}
 
-	return nil
+	return assigned, true, nil
+}
+
+// ReleaseExpiredPIN снимает PIN с истёкших токенов заведения, которому принадлежит tokenID.
+// Индекс уникальности не знает о сроке действия, поэтому истёкший токен держит PIN до освобождения.
+func (r *PostgresTokenRepository) ReleaseExpiredPIN(ctx context.Context, tokenID uuid.UUID, pin string) (int, error) {
+	// Подзапрос собирается с плейсхолдерами "?": внешний builder пронумерует их вместе со своими
+	placeSQL, placeArgs, err := sq.
+		Select(reviewTokenPlaceIDColumn).
+		From(reviewTokensTable).
+		Where(sq.Eq{reviewTokenIDColumn: tokenID}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build ReleaseExpiredPIN subquery: %w", err)
+	}
+
+	query, args, err := r.psql.
+		Update(reviewTokensTable).
+		Set(reviewTokenPINColumn, nil).
+		Where(sq.Eq{reviewTokenPINColumn: pin}).
+		Where(sq.Lt{reviewTokenExpiresAtColumn: time.Now()}).
+		Where(sq.Expr(reviewTokenPlaceIDColumn+" = ("+placeSQL+")", placeArgs...)).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build ReleaseExpiredPIN query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("exec ReleaseExpiredPIN: %w", err)
+	}
+
+	return int(res.RowsAffected()), nil
 }
 
 func (r *PostgresTokenRepository) CountActiveTokens(ctx context.Context, placeID string) (int, error) {
@@ -76,17 +267,237 @@ func (r *PostgresTokenRepository) CountActiveTokens(ctx context.Context, placeID
 	query, args, err := r.psql.
 		Select("COUNT(*)").
 		From(reviewTokensTable).
-		Where(sq.Eq{
-			reviewTokenPlaceIDColumn: uid,
-			reviewTokenIsUsedColumn:  false,
-		}).ToSql()
+		Where(sq.Eq{reviewTokenPlaceIDColumn: uid}).
+		Where(statusCondition(model.TokenStatusActive, time.Now())).
+		ToSql()
 	if err != nil {
 		return 0, fmt.Errorf("build count query: %w", err)
 	}
 
 	var count int
-	if err := r.db.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&count); err != nil {
 		return 0, fmt.Errorf("execute count query: %w", err)
 	}
 	return count, nil
 }
+
+func (r *PostgresTokenRepository) GetTokenInfo(ctx context.Context, tokenValue string) (*model.ReviewTokenInfo, error) {
+	query, args, err := r.psql.
+		Select(
+			"review_tokens."+reviewTokenValueColumn,
+			"review_tokens."+reviewTokenPlaceIDColumn,
+			"places.name",
+			"review_tokens."+reviewTokenIsUsedColumn,
+			"review_tokens."+reviewTokenRevokedAtColumn+" IS NOT NULL",
+			"review_tokens."+reviewTokenExpiresAtColumn,
+		).
+		From(reviewTokensTable).
+		Join("places ON places.id = review_tokens.place_id").
+		Where(sq.Eq{"review_tokens." + reviewTokenValueColumn: tokenValue}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetTokenInfo query: %w", err)
+	}
+
+	var info model.ReviewTokenInfo
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
+		&info.Token,
+		&info.PlaceID,
+		&info.PlaceName,
+		&info.IsUsed,
+		&info.IsRevoked,
+		&info.ExpiresAt,
+	); err != nil {
+		return nil, fmt.Errorf("scan GetTokenInfo: %w", err)
+	}
+
+	return &info, nil
+}
+
+func (r *PostgresTokenRepository) ListTokens(ctx context.Context, placeID string, filter model.TokenFilter) ([]model.ReviewToken, int, error) {
+	uid, err := uuid.Parse(placeID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid placeID: %w", err)
+	}
+
+	where := sq.And{sq.Eq{reviewTokenPlaceIDColumn: uid}}
+	if filter.Status != "" {
+		where = append(where, statusCondition(filter.Status, time.Now()))
+	}
+
+	countQuery, countArgs, err := r.psql.
+		Select("COUNT(*)").
+		From(reviewTokensTable).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build ListTokens count query: %w", err)
+	}
+
+	var total int
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("exec ListTokens count query: %w", err)
+	}
+
+	query, args, err := r.psql.
+		Select(
+			reviewTokenIDColumn,
+			reviewTokenPlaceIDColumn,
+			reviewTokenValueColumn,
+			reviewTokenIsUsedColumn,
+			reviewTokenExpiresAtColumn,
+			reviewTokenCreatedAtColumn,
+			reviewTokenRevokedAtColumn,
+		).
+		From(reviewTokensTable).
+		Where(where).
+		OrderBy(reviewTokenCreatedAtColumn+" DESC", reviewTokenIDColumn).
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build ListTokens query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("exec ListTokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]model.ReviewToken, 0, filter.Limit)
+	for rows.Next() {
+		var t model.ReviewToken
+		if err := rows.Scan(
+			&t.ID,
+			&t.PlaceID,
+			&t.Token,
+			&t.IsUsed,
+			&t.ExpiresAt,
+			&t.CreatedAt,
+			&t.RevokedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan ListTokens: %w", err)
+		}
+		tokens = append(tokens, t)
+	}
+
+	return tokens, total, rows.Err()
+}
+
+// RevokeTokens отзывает неиспользованные токены и возвращает число затронутых строк
+func (r *PostgresTokenRepository) RevokeTokens(ctx context.Context, tokenIDs []uuid.UUID) (int, error) {
+	query, args, err := r.psql.
+		Update(reviewTokensTable).
+		Set(reviewTokenRevokedAtColumn, time.Now()).
+		Where(sq.Eq{
+			reviewTokenIDColumn:        tokenIDs,
+			reviewTokenIsUsedColumn:    false,
+			reviewTokenRevokedAtColumn: nil,
+		}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build RevokeTokens query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("exec RevokeTokens: %w", err)
+	}
+
+	return int(res.RowsAffected()), nil
+}
+
+// ExtendTokens продлевает неиспользованные и неотозванные токены на ttl.
+// Истёкшие токены продлеваются от текущего момента.
+func (r *PostgresTokenRepository) ExtendTokens(ctx context.Context, tokenIDs []uuid.UUID, ttl time.Duration) (int, error) {
+	query, args, err := r.psql.
+		Update(reviewTokensTable).
+		Set(reviewTokenExpiresAtColumn, sq.Expr("GREATEST("+reviewTokenExpiresAtColumn+", ?) + ?::interval", time.Now(), ttl)).
+		Where(sq.Eq{
+			reviewTokenIDColumn:        tokenIDs,
+			reviewTokenIsUsedColumn:    false,
+			reviewTokenRevokedAtColumn: nil,
+		}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("build ExtendTokens query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("exec ExtendTokens: %w", err)
+	}
+
+	return int(res.RowsAffected()), nil
+}
+
+func (r *PostgresTokenRepository) GetTokenStats(ctx context.Context, placeID string) (*model.TokenStats, error) {
+	uid, err := uuid.Parse(placeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid placeID: %w", err)
+	}
+
+	now := time.Now()
+	columns := []sq.Sqlizer{
+		sq.Expr("COUNT(*)"),
+		filteredCount(model.TokenStatusUsed, now),
+		filteredCount(model.TokenStatusExpired, now),
+		filteredCount(model.TokenStatusActive, now),
+		filteredCount(model.TokenStatusRevoked, now),
+	}
+
+	builder := r.psql.Select()
+	for _, col := range columns {
+		builder = builder.Column(col)
+	}
+
+	query, args, err := builder.
+		From(reviewTokensTable).
+		Where(sq.Eq{reviewTokenPlaceIDColumn: uid}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetTokenStats query: %w", err)
+	}
+
+	var stats model.TokenStats
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
+		&stats.Issued,
+		&stats.Used,
+		&stats.Expired,
+		&stats.Active,
+		&stats.Revoked,
+	); err != nil {
+		return nil, fmt.Errorf("scan GetTokenStats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// statusCondition — условие WHERE для статуса токена, согласованное с model.ReviewToken.Status
+func statusCondition(status string, now time.Time) sq.Sqlizer {
+	switch status {
+	case model.TokenStatusUsed:
+		return sq.Eq{reviewTokenIsUsedColumn: true}
+	case model.TokenStatusRevoked:
+		return sq.And{
+			sq.Eq{reviewTokenIsUsedColumn: false},
+			sq.NotEq{reviewTokenRevokedAtColumn: nil},
+		}
+	case model.TokenStatusExpired:
+		return sq.And{
+			sq.Eq{reviewTokenIsUsedColumn: false, reviewTokenRevokedAtColumn: nil},
+			sq.LtOrEq{reviewTokenExpiresAtColumn: now},
+		}
+	default:
+		return sq.And{
+			sq.Eq{reviewTokenIsUsedColumn: false, reviewTokenRevokedAtColumn: nil},
+			sq.Gt{reviewTokenExpiresAtColumn: now},
+		}
+	}
+}
+
+func filteredCount(status string, now time.Time) sq.Sqlizer {
+	cond, args, _ := statusCondition(status, now).ToSql()
+	return sq.Expr("COUNT(*) FILTER (WHERE "+cond+")", args...)
+}
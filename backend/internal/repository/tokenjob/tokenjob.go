@@ -0,0 +1,250 @@
+package tokenjob
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+)
+
+const (
+	jobsTable             = "token_batch_jobs"
+	jobIDColumn           = "id"
+	jobPlaceIDColumn      = "place_id"
+	jobRequestedByColumn  = "requested_by"
+	jobTotalColumn        = "total"
+	jobGeneratedColumn    = "generated"
+	jobStatusColumn       = "status"
+	jobExpiresAtColumn    = "expires_at"
+	jobErrorColumn        = "error"
+	jobCreatedAtColumn    = "created_at"
+	jobUpdatedAtColumn    = "updated_at"
+	jobFinishedAtColumn   = "finished_at"
+	reviewTokensTable     = "review_tokens"
+	tokenValueColumn      = "token_value"
+	tokenExpiresAtColumn  = "expires_at"
+	tokenBatchJobIDColumn = "batch_job_id"
+)
+
+var jobColumns = []string{
+	jobIDColumn,
+	jobPlaceIDColumn,
+	jobRequestedByColumn,
+	jobTotalColumn,
+	jobGeneratedColumn,
+	jobStatusColumn,
+	jobExpiresAtColumn,
+	jobErrorColumn,
+	jobCreatedAtColumn,
+	jobUpdatedAtColumn,
+	jobFinishedAtColumn,
+}
+
+type PostgresTokenJobRepository struct {
+	db      *pgxpool.Pool
+	builder sq.StatementBuilderType
+}
+
+func NewPostgresTokenJobRepository(db *pgxpool.Pool) *PostgresTokenJobRepository {
+	return &PostgresTokenJobRepository{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *PostgresTokenJobRepository) CreateJob(ctx context.Context, job *model.TokenBatchJob) error {
+	query, args, err := r.builder.
+		Insert(jobsTable).
+		Columns(
+			jobIDColumn,
+			jobPlaceIDColumn,
+			jobRequestedByColumn,
+			jobTotalColumn,
+			jobStatusColumn,
+			jobExpiresAtColumn,
+		).
+		Values(
+			job.ID,
+			job.PlaceID,
+			job.RequestedBy,
+			job.Total,
+			job.Status,
+			job.ExpiresAt,
+		).
+		Suffix("RETURNING " + jobCreatedAtColumn + ", " + jobUpdatedAtColumn).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build CreateJob query: %w", err)
+	}
+
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("exec CreateJob: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresTokenJobRepository) GetJob(ctx context.Context, jobID uuid.UUID) (*model.TokenBatchJob, error) {
+	query, args, err := r.builder.
+		Select(jobColumns...).
+		From(jobsTable).
+		Where(sq.Eq{jobIDColumn: jobID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build GetJob query: %w", err)
+	}
+
+	job, err := scanJob(transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("exec GetJob: %w", err)
+	}
+
+	return job, nil
+}
+
+// ClaimJob переводит в running самую старую ожидающую задачу либо задачу, застрявшую в running
+// дольше staleAfter (воркер упал посреди генерации). Параллельные воркеры получают разные задачи.
+func (r *PostgresTokenJobRepository) ClaimJob(ctx context.Context, staleAfter time.Duration) (*model.TokenBatchJob, error) {
+	// Подзапрос собирается с плейсхолдерами "?": внешний builder пронумерует их вместе со своими
+	sub, subArgs, err := sq.
+		Select(jobIDColumn).
+		From(jobsTable).
+		Where(sq.Or{
+			sq.Eq{jobStatusColumn: model.TokenJobStatusPending},
+			sq.And{
+				sq.Eq{jobStatusColumn: model.TokenJobStatusRunning},
+				sq.Expr(jobUpdatedAtColumn+" < now() - make_interval(secs => ?)", staleAfter.Seconds()),
+			},
+		}).
+		OrderBy(jobCreatedAtColumn + " ASC").
+		Limit(1).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build ClaimJob subquery: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Update(jobsTable).
+		Set(jobStatusColumn, model.TokenJobStatusRunning).
+		Set(jobUpdatedAtColumn, sq.Expr("now()")).
+		Where(sq.Expr(jobIDColumn+" = ("+sub+")", subArgs...)).
+		Suffix("RETURNING " + strings.Join(jobColumns, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build ClaimJob query: %w", err)
+	}
+
+	job, err := scanJob(transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("exec ClaimJob: %w", err)
+	}
+
+	return job, nil
+}
+
+// AddProgress увеличивает число созданных токенов задачи; вызывается в той же транзакции, что и вставка порции
+func (r *PostgresTokenJobRepository) AddProgress(ctx context.Context, jobID uuid.UUID, generated int) error {
+	query, args, err := r.builder.
+		Update(jobsTable).
+		Set(jobGeneratedColumn, sq.Expr(jobGeneratedColumn+" + ?", generated)).
+		Set(jobUpdatedAtColumn, sq.Expr("now()")).
+		Where(sq.Eq{jobIDColumn: jobID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build AddProgress query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec AddProgress: %w", err)
+	}
+
+	return nil
+}
+
+// FinishJob фиксирует итоговый статус задачи; errText заполняется только для failed
+func (r *PostgresTokenJobRepository) FinishJob(ctx context.Context, jobID uuid.UUID, status string, errText *string) error {
+	query, args, err := r.builder.
+		Update(jobsTable).
+		Set(jobStatusColumn, status).
+		Set(jobErrorColumn, errText).
+		Set(jobUpdatedAtColumn, sq.Expr("now()")).
+		Set(jobFinishedAtColumn, sq.Expr("now()")).
+		Where(sq.Eq{jobIDColumn: jobID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build FinishJob query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec FinishJob: %w", err)
+	}
+
+	return nil
+}
+
+// ListJobTokens возвращает токены задачи по возрастанию значения, начиная после after.
+// Выгрузка читает их порциями, чтобы не держать в памяти всю пачку.
+func (r *PostgresTokenJobRepository) ListJobTokens(ctx context.Context, jobID uuid.UUID, after string, limit int) ([]model.ReviewToken, error) {
+	query, args, err := r.builder.
+		Select(tokenValueColumn, tokenExpiresAtColumn).
+		From(reviewTokensTable).
+		Where(sq.Eq{tokenBatchJobIDColumn: jobID}).
+		Where(sq.Gt{tokenValueColumn: after}).
+		OrderBy(tokenValueColumn + " ASC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build ListJobTokens query: %w", err)
+	}
+
+	rows, err := transaction.Executor(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec ListJobTokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]model.ReviewToken, 0, limit)
+	for rows.Next() {
+		t := model.ReviewToken{BatchJobID: &jobID}
+		if err := rows.Scan(&t.Token, &t.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("scan ListJobTokens: %w", err)
+		}
+		tokens = append(tokens, t)
+	}
+
+	return tokens, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row rowScanner) (*model.TokenBatchJob, error) {
+	var job model.TokenBatchJob
+	err := row.Scan(
+		&job.ID,
+		&job.PlaceID,
+		&job.RequestedBy,
+		&job.Total,
+		&job.Generated,
+		&job.Status,
+		&job.ExpiresAt,
+		&job.Error,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
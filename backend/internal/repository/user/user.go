@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
 
 	sq "github.com/Masterminds/squirrel"
 )
@@ -65,7 +67,7 @@ func (r *PostgresUserRepository) FindByID(ctx context.Context, userID string) (*
 
 	var u model.User
 
-	err = r.db.QueryRow(ctx, query, args...).Scan(
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
 		&u.ID,
 		&u.Name,
 		&u.Email,
@@ -107,7 +109,7 @@ func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string)
 
 	var u model.User
 
-	err = r.db.QueryRow(ctx, query, args...).Scan(
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
 		&u.ID,
 		&u.Name,
 		&u.Email,
@@ -146,7 +148,7 @@ func (r *PostgresUserRepository) FindAnyByEmail(ctx context.Context, email strin
 
 	var u model.User
 
-	err = r.db.QueryRow(ctx, query, args...).Scan(
+	err = transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(
 		&u.ID,
 		&u.Name,
 		&u.Email,
@@ -192,7 +194,7 @@ func (r *PostgresUserRepository) CreateUser(ctx context.Context, user *model.Use
 		return fmt.Errorf("build CreateUser query: %w", err)
 	}
 
-	_, err = r.db.Exec(ctx, query, args...)
+	_, err = transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("exec CreateUser: %w", err)
 	}
@@ -214,7 +216,7 @@ func (r *PostgresUserRepository) UpdateUser(ctx context.Context, user *model.Use
 		return fmt.Errorf("build UpdateUser query: %w", err)
 	}
 
-	_, err = r.db.Exec(ctx, query, args...)
+	_, err = transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("exec UpdateUser: %w", err)
 	}
@@ -233,7 +235,7 @@ func (r *PostgresUserRepository) SoftDeleteUser(ctx context.Context, userID stri
 		return fmt.Errorf("build SoftDeleteUser query: %w", err)
 	}
 
-	_, err = r.db.Exec(ctx, query, args...)
+	_, err = transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
 
 	if err != nil {
 		return fmt.Errorf("exec SoftDeleteUser: %w", err)
@@ -257,7 +259,7 @@ func (r *PostgresUserRepository) AddPoints(ctx context.Context, userID string, p
 		return fmt.Errorf("build AddPoints query: %w", err)
 	}
 
-	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23514" {
 			return fmt.Errorf("points limit exceeded: %w", err)
@@ -268,6 +270,56 @@ func (r *PostgresUserRepository) AddPoints(ctx context.Context, userID string, p
 	return nil
 }
 
+// RevokePoints списывает баллы снятого с публикации отзыва. Потраченные на бонусы баллы
+// не вернуть, поэтому баланс не уходит ниже нуля.
+func (r *PostgresUserRepository) RevokePoints(ctx context.Context, userID string, points int) error {
+	uuidID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Update(userTable).
+		Set(userPointsColumn, sq.Expr("GREATEST(points - ?, 0)", points)).
+		Where(sq.Eq{userIDColumn: uuidID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build RevokePoints query: %w", err)
+	}
+
+	if _, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec RevokePoints: %w", err)
+	}
+
+	return nil
+}
+
+// LockUser блокирует строку пользователя до конца транзакции, выстраивая его параллельные
+// операции в очередь. Для неизвестного пользователя возвращает pgx.ErrNoRows.
+func (r *PostgresUserRepository) LockUser(ctx context.Context, userID string) error {
+	uuidID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Select("1").
+		From(userTable).
+		Where(sq.Eq{userIDColumn: uuidID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build LockUser query: %w", err)
+	}
+
+	var one int
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, args...).Scan(&one); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *PostgresUserRepository) RedeemPoints(ctx context.Context, userID string, points int) error {
 	uuidID, err := uuid.Parse(userID)
 	if err != nil {
@@ -286,7 +338,7 @@ func (r *PostgresUserRepository) RedeemPoints(ctx context.Context, userID string
 		return fmt.Errorf("build RedeemPoints query: %w", err)
 	}
 
-	result, err := r.db.Exec(ctx, query, args...)
+	result, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("exec RedeemPoints: %w", err)
 	}
@@ -297,3 +349,33 @@ func (r *PostgresUserRepository) RedeemPoints(ctx context.Context, userID string
 
 	return nil
 }
+
+func (r *PostgresUserRepository) UpdateRole(ctx context.Context, userID, role string) error {
+	uuidID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	query, args, err := r.builder.
+		Update(userTable).
+		Set(userRoleColumn, role).
+		Where(sq.Eq{
+			userIDColumn:        uuidID,
+			userIsDeletedColumn: false,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build UpdateRole query: %w", err)
+	}
+
+	res, err := transaction.Executor(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("exec UpdateRole: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package vote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
+)
+
+type PostgresVoteRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresVoteRepository(db *pgxpool.Pool) *PostgresVoteRepository {
+	return &PostgresVoteRepository{db: db}
+}
+
+// AddVote ставит отметку пользователя и возвращает новый счётчик отзыва. Повторная отметка ничего не меняет:
+// счётчик увеличивается на число действительно вставленных строк в том же запросе.
+func (r *PostgresVoteRepository) AddVote(ctx context.Context, reviewID, userID uuid.UUID) (int, error) {
+	query := `
+		WITH added AS (
+			INSERT INTO review_votes (review_id, user_id, created_at)
+			VALUES ($1, $2, now())
+			ON CONFLICT (review_id, user_id) DO NOTHING
+			RETURNING review_id
+		)
+		UPDATE reviews
+		SET helpful_count = helpful_count + (SELECT COUNT(*) FROM added)
+		WHERE id = $1
+		RETURNING helpful_count`
+
+	var count int
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, reviewID, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("exec AddVote: %w", err)
+	}
+
+	return count, nil
+}
+
+// RemoveVote снимает отметку пользователя и возвращает новый счётчик отзыва; снятие отсутствующей отметки ничего не меняет
+func (r *PostgresVoteRepository) RemoveVote(ctx context.Context, reviewID, userID uuid.UUID) (int, error) {
+	query := `
+		WITH removed AS (
+			DELETE FROM review_votes
+			WHERE review_id = $1 AND user_id = $2
+			RETURNING review_id
+		)
+		UPDATE reviews
+		SET helpful_count = helpful_count - (SELECT COUNT(*) FROM removed)
+		WHERE id = $1
+		RETURNING helpful_count`
+
+	var count int
+	if err := transaction.Executor(ctx, r.db).QueryRow(ctx, query, reviewID, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("exec RemoveVote: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,92 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model/rbac"
+	repo "github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// Level — минимальная роль в заведении, нужная для действия. Админ проходит любой уровень.
+type Level int
+
+const (
+	// AnyMember — любой сотрудник заведения, включая кассира
+	AnyMember Level = iota
+	// Manager — владелец или менеджер заведения
+	Manager
+	// Owner — только владелец заведения
+	Owner
+)
+
+// Checker проверяет доступ к заведению по роли актёра в нём
+type Checker struct {
+	placeRepo  repo.PlaceRepository
+	memberRepo repo.PlaceMemberRepository
+}
+
+func NewChecker(placeRepo repo.PlaceRepository, memberRepo repo.PlaceMemberRepository) *Checker {
+	return &Checker{
+		placeRepo:  placeRepo,
+		memberRepo: memberRepo,
+	}
+}
+
+// Place находит заведение и проверяет, что актёр — админ или сотрудник с ролью не ниже level
+func (c *Checker) Place(ctx context.Context, actorID, actorRole, placeID string, level Level) (*model.Place, error) {
+	if _, err := uuid.Parse(placeID); err != nil {
+		return nil, serviceErrors.ErrInvalidPlaceID
+	}
+
+	place, err := c.placeRepo.GetByID(ctx, placeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrPlaceNotFound
+		}
+		return nil, fmt.Errorf("check place existence: %w", err)
+	}
+
+	if err := c.Member(ctx, actorID, actorRole, placeID, level); err != nil {
+		return nil, err
+	}
+
+	return place, nil
+}
+
+// Member проверяет роль актёра в заведении, существование которого уже известно
+func (c *Checker) Member(ctx context.Context, actorID, actorRole, placeID string, level Level) error {
+	if actorRole == rbac.RoleAdmin {
+		return nil
+	}
+
+	member, err := c.memberRepo.GetMember(ctx, placeID, actorID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return serviceErrors.ErrAccessDenied
+		}
+		return fmt.Errorf("check membership: %w", err)
+	}
+
+	if !allows(member.Role, level) {
+		return serviceErrors.ErrAccessDenied
+	}
+
+	return nil
+}
+
+func allows(placeRole string, level Level) bool {
+	switch level {
+	case Owner:
+		return placeRole == rbac.PlaceRoleOwner
+	case Manager:
+		return placeRole == rbac.PlaceRoleOwner || placeRole == rbac.PlaceRoleManager
+	default:
+		return true
+	}
+}
@@ -2,31 +2,46 @@ package bonus
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/kulikovroman08/reviewlink-backend/configs"
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model/rbac"
 	"github.com/kulikovroman08/reviewlink-backend/internal/repository"
 	srvErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/qr"
 )
 
 var localRand = rand.New(rand.NewSource(time.Now().UnixNano()))
 
 type bonusService struct {
-	userRepo  repository.UserRepository
-	bonusRepo repository.BonusRepository
-	cfg       *configs.Config
+	userRepo   repository.UserRepository
+	bonusRepo  repository.BonusRepository
+	memberRepo repository.PlaceMemberRepository
+	renderer   *qr.Renderer
+	cfg        *configs.Config
 }
 
-func NewBonusService(userRepo repository.UserRepository, bonusRepo repository.BonusRepository, cfg *configs.Config) *bonusService {
+func NewBonusService(
+	userRepo repository.UserRepository,
+	bonusRepo repository.BonusRepository,
+	memberRepo repository.PlaceMemberRepository,
+	renderer *qr.Renderer,
+	cfg *configs.Config,
+) *bonusService {
 	return &bonusService{
-		userRepo:  userRepo,
-		bonusRepo: bonusRepo,
-		cfg:       cfg,
+		userRepo:   userRepo,
+		bonusRepo:  bonusRepo,
+		memberRepo: memberRepo,
+		renderer:   renderer,
+		cfg:        cfg,
 	}
 }
 
@@ -76,7 +91,10 @@ func (s *bonusService) GetUserBonuses(ctx context.Context, userID string) ([]mod
 	return bonuses, nil
 }
 
-func (s *bonusService) ValidateBonus(ctx context.Context, qrToken string) error {
+// ValidateBonus гасит бонус в заведении placeID. Бонус, привязанный к заведению,
+// принимается только там; бонус без привязки — в заведении, указанном сотрудником.
+// Сотрудник должен состоять в штате этого заведения (админам проверка не нужна).
+func (s *bonusService) ValidateBonus(ctx context.Context, validatorID, validatorRole, placeID, qrToken string) error {
 	bonusItem, err := s.bonusRepo.GetByQRToken(ctx, qrToken)
 	if err != nil {
 		return err
@@ -86,10 +104,36 @@ func (s *bonusService) ValidateBonus(ctx context.Context, qrToken string) error
 		return srvErrors.ErrBonusAlreadyUsed
 	}
 
-	if err := s.bonusRepo.MarkBonusUsed(ctx, qrToken); err != nil {
+	if bonusItem.PlaceID != nil {
+		if placeID != "" && placeID != bonusItem.PlaceID.String() {
+			return srvErrors.ErrBonusPlaceMismatch
+		}
+		placeID = bonusItem.PlaceID.String()
+	}
+
+	if _, err := uuid.Parse(placeID); err != nil {
+		return srvErrors.ErrInvalidPlaceID
+	}
+
+	if validatorRole != rbac.RoleAdmin {
+		if _, err := s.memberRepo.GetMember(ctx, placeID, validatorID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return srvErrors.ErrNotPlaceMember
+			}
+			return fmt.Errorf("check membership: %w", err)
+		}
+	}
+
+	if err := s.bonusRepo.MarkBonusUsed(ctx, qrToken, validatorID, placeID); err != nil {
 		return err
 	}
 
+	slog.Info("bonus validated",
+		"bonus_id", bonusItem.ID,
+		"validator_id", validatorID,
+		"place_id", placeID,
+	)
+
 	return nil
 }
 
@@ -101,3 +145,21 @@ func generateQRToken() string {
 	}
 	return string(b)
 }
+
+// RenderBonusQR рисует QR-код бонуса для показа кассиру. Доступен только владельцу бонуса.
+func (s *bonusService) RenderBonusQR(ctx context.Context, userID, qrToken, format string, size int) (*qr.Image, error) {
+	bonusItem, err := s.bonusRepo.GetByQRToken(ctx, qrToken)
+	if err != nil {
+		return nil, err
+	}
+	if bonusItem.UserID.String() != userID {
+		return nil, srvErrors.ErrBonusNotFound
+	}
+
+	img, err := s.renderer.Render(bonusItem.QRToken, format, size)
+	if err != nil {
+		return nil, fmt.Errorf("render bonus qr: %w", err)
+	}
+
+	return img, nil
+}
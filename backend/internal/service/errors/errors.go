@@ -3,20 +3,78 @@ package errors
 import "errors"
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrTokenExpired       = errors.New("token expired")
-	ErrInvalidToken       = errors.New("invalid token")
-	ErrUserNotFound       = errors.New("user not found")
-	ErrEmailAlreadyUsed   = errors.New("email already used")
-	ErrPlaceAlreadyExists = errors.New("place already exists")
-	ErrInvalidPlaceData   = errors.New("invalid place data")
-	ErrInvalidPlaceID     = errors.New("invalid place id")
-	ErrPlaceNotFound      = errors.New("place not found")
-	ErrInvalidRating      = errors.New("invalid rating value")
-	ErrReviewNotFound     = errors.New("review not found or access denied")
-	ErrNotEnoughPoints    = errors.New("not enough points to redeem bonus")
-	ErrBonusCreateFail    = errors.New("failed to create bonus")
-	ErrBonusNotFound      = errors.New("bonus not found")
-	ErrBonusAlreadyUsed   = errors.New("bonus already used")
-	ErrTooManyReviews     = errors.New("too many reviews today")
+	ErrInvalidCredentials     = errors.New("invalid credentials")
+	ErrTokenExpired           = errors.New("token expired")
+	ErrInvalidToken           = errors.New("invalid token")
+	ErrUserNotFound           = errors.New("user not found")
+	ErrEmailAlreadyUsed       = errors.New("email already used")
+	ErrPlaceAlreadyExists     = errors.New("place already exists")
+	ErrInvalidPlaceData       = errors.New("invalid place data")
+	ErrInvalidPlaceID         = errors.New("invalid place id")
+	ErrPlaceNotFound          = errors.New("place not found")
+	ErrInvalidRating          = errors.New("invalid rating value")
+	ErrReviewNotFound         = errors.New("review not found or access denied")
+	ErrNotEnoughPoints        = errors.New("not enough points to redeem bonus")
+	ErrBonusCreateFail        = errors.New("failed to create bonus")
+	ErrBonusNotFound          = errors.New("bonus not found")
+	ErrBonusAlreadyUsed       = errors.New("bonus already used")
+	ErrTooManyReviews         = errors.New("too many reviews today")
+	ErrRefreshTokenReused     = errors.New("refresh token reuse detected")
+	ErrSessionRevoked         = errors.New("session revoked")
+	ErrInvalidRole            = errors.New("invalid role")
+	ErrAccessDenied           = errors.New("access denied")
+	ErrMemberAlreadyExists    = errors.New("member already exists")
+	ErrMemberNotFound         = errors.New("member not found")
+	ErrNotPlaceMember         = errors.New("validator is not a member of the bonus place")
+	ErrBonusPlaceMismatch     = errors.New("bonus belongs to another place")
+	ErrTokenPlaceMismatch     = errors.New("token belongs to another place")
+	ErrTokenGenerationFailed  = errors.New("failed to generate unique tokens")
+	ErrTokenRevoked           = errors.New("token revoked")
+	ErrTokenNotFound          = errors.New("token not found")
+	ErrInvalidTokenStatus     = errors.New("invalid token status")
+	ErrKioskNotEnabled        = errors.New("kiosk mode is not enabled")
+	ErrInvalidKioskStep       = errors.New("invalid kiosk code step")
+	ErrKioskCodeUsed          = errors.New("kiosk code already used")
+	ErrInvalidTokenPolicy     = errors.New("invalid token policy")
+	ErrRefillInProgress       = errors.New("token refill already in progress")
+	ErrInvalidTokenCount      = errors.New("invalid token count")
+	ErrTokenJobNotFound       = errors.New("token job not found")
+	ErrTokenJobNotReady       = errors.New("token job is not finished")
+	ErrInvalidExportFormat    = errors.New("invalid export format")
+	ErrInvalidSignature       = errors.New("invalid signature")
+	ErrReceiptConflict        = errors.New("receipt already registered with different data")
+	ErrInvalidReceipt         = errors.New("invalid receipt")
+	ErrScanNotFound           = errors.New("scan not found")
+	ErrInvalidPeriod          = errors.New("invalid period")
+	ErrInvalidTokenLabels     = errors.New("invalid token labels")
+	ErrInvalidReportDimension = errors.New("invalid report dimension")
+	ErrTooManyPINAttempts     = errors.New("too many pin attempts")
+	ErrInvalidReviewStatus    = errors.New("invalid review status")
+	ErrInvalidModeration      = errors.New("review cannot be moved to this status")
+	ErrInvalidModerationMode  = errors.New("invalid moderation mode")
+	ErrInvalidModerationNote  = errors.New("invalid moderation reason")
+	ErrContentRejected        = errors.New("review content rejected")
+	ErrReplyNotFound          = errors.New("reply not found")
+	ErrReplyExists            = errors.New("review already has a reply")
+	ErrInvalidReply           = errors.New("invalid reply")
+	ErrReplyTemplateNotFound  = errors.New("reply template not found")
+	ErrReplyTemplateExists    = errors.New("reply template already exists")
+	ErrInvalidReplyTemplate   = errors.New("invalid reply template")
+	ErrPhotoNotFound          = errors.New("photo not found")
+	ErrInvalidPhoto           = errors.New("invalid photo")
+	ErrUnsupportedPhotoType   = errors.New("unsupported photo type")
+	ErrPhotoTooLarge          = errors.New("photo too large")
+	ErrTooManyPhotos          = errors.New("too many photos")
+	ErrOwnReviewVote          = errors.New("cannot vote for own review")
+	ErrInvalidReport          = errors.New("invalid report")
+	ErrReportExists           = errors.New("review already reported")
+	ErrOwnReviewReport        = errors.New("cannot report own review")
+	ErrReportNotFound         = errors.New("no open reports for review")
+	ErrInvalidReportAction    = errors.New("invalid report action")
+	ErrInvalidReportStatus    = errors.New("invalid report status")
+	ErrReviewsRestricted      = errors.New("posting reviews is restricted")
+	ErrReviewNotRestorable    = errors.New("review cannot be restored")
+	ErrInvalidReviewSort      = errors.New("invalid review sort")
+	ErrInvalidCursor          = errors.New("invalid cursor")
+	ErrInvalidUserID          = errors.New("invalid user id")
 )
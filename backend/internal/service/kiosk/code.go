@@ -0,0 +1,69 @@
+package kiosk
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/token"
+)
+
+const (
+	// CodePrefix отличает код киоска от напечатанного токена: в алфавите токенов нет дефиса
+	CodePrefix = "K-"
+
+	codeLength = 8
+)
+
+// IsCode сообщает, что значение похоже на код киоска, а не на токен из БД
+func IsCode(value string) bool {
+	return strings.HasPrefix(strings.ToUpper(value), CodePrefix)
+}
+
+// Window возвращает номер временного окна длиной step, в которое попадает t
+func Window(t time.Time, step time.Duration) int64 {
+	return t.Unix() / int64(step/time.Second)
+}
+
+// Code выводит код окна из секрета заведения по схеме TOTP (RFC 6238):
+// HMAC-SHA256 от номера окна и динамическое усечение, но в алфавите токенов вместо цифр
+func Code(secret []byte, window int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(window))
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	v := binary.BigEndian.Uint64(sum[offset:offset+8]) & 0x7fffffffffffffff
+
+	alphabet := token.DefaultAlphabet
+	n := uint64(len(alphabet))
+
+	var b strings.Builder
+	b.WriteString(CodePrefix)
+	for i := 0; i < codeLength; i++ {
+		b.WriteByte(alphabet[v%n])
+		v /= n
+	}
+
+	return b.String()
+}
+
+// Verify ищет окно, в котором выдан код, среди текущего и skew соседних окон в обе стороны
+func Verify(secret []byte, code string, now time.Time, step time.Duration, skew int) (int64, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	current := Window(now, step)
+
+	for d := -int64(skew); d <= int64(skew); d++ {
+		window := current + d
+		if hmac.Equal([]byte(Code(secret, window)), []byte(code)) {
+			return window, true
+		}
+	}
+
+	return 0, false
+}
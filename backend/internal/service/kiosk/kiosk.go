@@ -0,0 +1,182 @@
+package kiosk
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/configs"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	repo "github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/access"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/token"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/qr"
+)
+
+const (
+	MinStep = 10 * time.Second
+	MaxStep = 10 * time.Minute
+
+	secretSize = 32
+)
+
+type Service struct {
+	repo     repo.KioskRepository
+	access   *access.Checker
+	renderer *qr.Renderer
+	cfg      *configs.Config
+}
+
+func NewKioskService(
+	repo repo.KioskRepository,
+	placeRepo repo.PlaceRepository,
+	memberRepo repo.PlaceMemberRepository,
+	renderer *qr.Renderer,
+	cfg *configs.Config,
+) *Service {
+	return &Service{
+		repo:     repo,
+		access:   access.NewChecker(placeRepo, memberRepo),
+		renderer: renderer,
+		cfg:      cfg,
+	}
+}
+
+// EnableKiosk включает киоск с новым секретом. Повторное включение перевыпускает секрет,
+// и коды, показанные раньше, перестают приниматься. Нулевой step — шаг из конфига.
+func (s *Service) EnableKiosk(ctx context.Context, actorID, actorRole, placeID string, step time.Duration) (*model.Kiosk, error) {
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if step == 0 {
+		step = s.cfg.KioskCodeStep
+	}
+	if step < MinStep || step > MaxStep || step%time.Second != 0 {
+		return nil, serviceErrors.ErrInvalidKioskStep
+	}
+
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate kiosk secret: %w", err)
+	}
+
+	now := time.Now()
+	kiosk := &model.Kiosk{
+		PlaceID:   place.ID,
+		Secret:    secret,
+		Step:      step,
+		Enabled:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.UpsertKiosk(ctx, kiosk); err != nil {
+		return nil, fmt.Errorf("enable kiosk: %w", err)
+	}
+
+	return kiosk, nil
+}
+
+func (s *Service) DisableKiosk(ctx context.Context, actorID, actorRole, placeID string) error {
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Owner)
+	if err != nil {
+		return err
+	}
+
+	disabled, err := s.repo.DisableKiosk(ctx, place.ID)
+	if err != nil {
+		return fmt.Errorf("disable kiosk: %w", err)
+	}
+	if !disabled {
+		return serviceErrors.ErrKioskNotEnabled
+	}
+
+	return nil
+}
+
+// CurrentCode возвращает код текущего окна для показа на планшете. Доступен любому сотруднику заведения.
+func (s *Service) CurrentCode(ctx context.Context, actorID, actorRole, placeID string) (*model.KioskCode, error) {
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.AnyMember)
+	if err != nil {
+		return nil, err
+	}
+
+	kiosk, err := s.enabledKiosk(ctx, place.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	window := Window(time.Now(), kiosk.Step)
+	code := Code(kiosk.Secret, window)
+
+	return &model.KioskCode{
+		Code:       code,
+		URL:        token.ReviewFormURL(s.cfg.PublicBaseURL, code, placeID),
+		ValidUntil: time.Unix((window+1)*int64(kiosk.Step/time.Second), 0),
+		Step:       kiosk.Step,
+	}, nil
+}
+
+func (s *Service) RenderCodeQR(ctx context.Context, actorID, actorRole, placeID, format string, size int) (*qr.Image, error) {
+	code, err := s.CurrentCode(ctx, actorID, actorRole, placeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.renderer.Render(code.URL, format, size)
+}
+
+// VerifyCode проверяет код киоска и возвращает номер окна, в котором он выдан
+func (s *Service) VerifyCode(ctx context.Context, placeID uuid.UUID, code string) (int64, error) {
+	kiosk, err := s.repo.GetKiosk(ctx, placeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, serviceErrors.ErrInvalidToken
+		}
+		return 0, fmt.Errorf("get kiosk: %w", err)
+	}
+	if !kiosk.Enabled {
+		return 0, serviceErrors.ErrInvalidToken
+	}
+
+	window, ok := Verify(kiosk.Secret, code, time.Now(), kiosk.Step, s.cfg.KioskCodeSkew)
+	if !ok {
+		return 0, serviceErrors.ErrTokenExpired
+	}
+
+	return window, nil
+}
+
+// ClaimWindow не даёт одному пользователю отправить два отзыва по кодам одного окна
+func (s *Service) ClaimWindow(ctx context.Context, placeID, userID uuid.UUID, window int64) error {
+	claimed, err := s.repo.ClaimWindow(ctx, placeID, userID, window)
+	if err != nil {
+		return fmt.Errorf("claim kiosk window: %w", err)
+	}
+	if !claimed {
+		return serviceErrors.ErrKioskCodeUsed
+	}
+
+	return nil
+}
+
+func (s *Service) enabledKiosk(ctx context.Context, placeID uuid.UUID) (*model.Kiosk, error) {
+	kiosk, err := s.repo.GetKiosk(ctx, placeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrKioskNotEnabled
+		}
+		return nil, fmt.Errorf("get kiosk: %w", err)
+	}
+	if !kiosk.Enabled {
+		return nil, serviceErrors.ErrKioskNotEnabled
+	}
+
+	return kiosk, nil
+}
@@ -0,0 +1,175 @@
+package link
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/configs"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	repo "github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/access"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/token"
+)
+
+const (
+	// ScanQueryParam — параметр формы отзыва с ID перехода, по нему форма сообщает о входе пользователя
+	ScanQueryParam = "scan"
+	// ScanKeyQueryParam — параметр формы с ключом сессии перехода; без него вход не отмечается
+	ScanKeyQueryParam = "scan_key"
+
+	// DefaultFunnelPeriod — период воронки, если границы не заданы
+	DefaultFunnelPeriod = 30 * 24 * time.Hour
+
+	maxUserAgentLength = 512
+	sessionKeyBytes    = 16
+)
+
+type Service struct {
+	scanRepo repo.ScanRepository
+	access   *access.Checker
+	cfg      *configs.Config
+}
+
+func NewLinkService(
+	scanRepo repo.ScanRepository,
+	placeRepo repo.PlaceRepository,
+	memberRepo repo.PlaceMemberRepository,
+	cfg *configs.Config,
+) *Service {
+	return &Service{
+		scanRepo: scanRepo,
+		access:   access.NewChecker(placeRepo, memberRepo),
+		cfg:      cfg,
+	}
+}
+
+// Resolve записывает переход по короткой ссылке и возвращает адрес формы отзыва с ID перехода и ключом сессии.
+// Повторный переход того же клиента (IP и User-Agent) по тому же токену в окне ScanDedupWindow
+// не создаёт новой записи и возвращает прежний переход.
+// Состояние токена не проверяется: использованный или истёкший токен форма покажет сама.
+func (s *Service) Resolve(ctx context.Context, code, clientIP, userAgent string) (string, error) {
+	now := time.Now().UTC()
+	userAgent = truncate(userAgent, maxUserAgentLength)
+	client := clientKey(clientIP, userAgent)
+
+	scan, err := s.scanRepo.FindRecentScan(ctx, code, client, now.Add(-s.cfg.ScanDedupWindow))
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("find recent scan: %w", err)
+		}
+
+		sessionKey, err := newSessionKey()
+		if err != nil {
+			return "", err
+		}
+
+		scan = &model.TokenScan{
+			ID:         uuid.New(),
+			UserAgent:  userAgent,
+			ScannedAt:  now,
+			ClientKey:  client,
+			SessionKey: sessionKey,
+		}
+
+		if err := s.scanRepo.CreateScan(ctx, scan, code); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return "", serviceErrors.ErrInvalidToken
+			}
+			return "", fmt.Errorf("create scan: %w", err)
+		}
+	}
+
+	params := url.Values{}
+	params.Set(ScanQueryParam, scan.ID.String())
+	params.Set(ScanKeyQueryParam, scan.SessionKey)
+
+	return token.ReviewFormURL(s.cfg.PublicBaseURL, code, scan.PlaceID.String()) + "&" + params.Encode(), nil
+}
+
+// MarkOpened отмечает, что после перехода форму открыл вошедший пользователь.
+// sessionKey — ключ из адреса формы: чужой или подобранный ID перехода без него не засчитывается.
+func (s *Service) MarkOpened(ctx context.Context, userID, scanID, sessionKey string) error {
+	scanUID, err := uuid.Parse(scanID)
+	if err != nil || sessionKey == "" {
+		return serviceErrors.ErrScanNotFound
+	}
+
+	userUID, err := uuid.Parse(userID)
+	if err != nil {
+		return serviceErrors.ErrAccessDenied
+	}
+
+	if err := s.scanRepo.MarkOpened(ctx, scanUID, userUID, sessionKey); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return serviceErrors.ErrScanNotFound
+		}
+		return fmt.Errorf("mark scan opened: %w", err)
+	}
+
+	return nil
+}
+
+// GetFunnel возвращает воронку «переход → вход → отзыв → баллы» за период [from, to).
+// Без to берётся текущий момент, без from — DefaultFunnelPeriod до to.
+// Не-админ должен быть владельцем или менеджером заведения.
+func (s *Service) GetFunnel(ctx context.Context, actorID, actorRole, placeID string, from, to *time.Time) (*model.ScanFunnel, error) {
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Manager)
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now().UTC()
+	if to != nil {
+		end = *to
+	}
+	start := end.Add(-DefaultFunnelPeriod)
+	if from != nil {
+		start = *from
+	}
+	if !start.Before(end) {
+		return nil, serviceErrors.ErrInvalidPeriod
+	}
+
+	funnel, err := s.scanRepo.GetFunnel(ctx, place.ID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("get funnel: %w", err)
+	}
+
+	return funnel, nil
+}
+
+// clientKey склеивает IP и User-Agent в хеш, чтобы не хранить адрес клиента в открытом виде
+func clientKey(clientIP, userAgent string) string {
+	sum := sha256.Sum256([]byte(clientIP + "\n" + userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+func newSessionKey() (string, error) {
+	b := make([]byte, sessionKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate scan session key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// truncate обрезает строку до limit байт, не разрывая символ UTF-8
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	s = s[:limit]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
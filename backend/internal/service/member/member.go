@@ -0,0 +1,178 @@
+package member
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model/rbac"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/access"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+type memberService struct {
+	memberRepo repository.PlaceMemberRepository
+	userRepo   repository.UserRepository
+	access     *access.Checker
+}
+
+func NewMemberService(
+	memberRepo repository.PlaceMemberRepository,
+	userRepo repository.UserRepository,
+	placeRepo repository.PlaceRepository,
+) *memberService {
+	return &memberService{
+		memberRepo: memberRepo,
+		userRepo:   userRepo,
+		access:     access.NewChecker(placeRepo, memberRepo),
+	}
+}
+
+// AddMember приглашает пользователя в сотрудники заведения. Владельцев может назначать только админ.
+// Глобальная роль пользователя пересчитывается по всем его заведениям.
+func (s *memberService) AddMember(ctx context.Context, actorID, actorRole, placeID, email, role string) (*model.PlaceMember, error) {
+	if !rbac.IsValidPlaceRole(role) {
+		return nil, serviceErrors.ErrInvalidRole
+	}
+
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Owner)
+	if err != nil {
+		return nil, err
+	}
+	if role == rbac.PlaceRoleOwner && actorRole != rbac.RoleAdmin {
+		return nil, serviceErrors.ErrAccessDenied
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+
+	userUID, err := uuid.Parse(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+
+	member := &model.PlaceMember{
+		ID:        uuid.New(),
+		PlaceID:   place.ID,
+		UserID:    userUID,
+		UserName:  user.Name,
+		UserEmail: user.Email,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.memberRepo.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+
+	if err := s.syncGlobalRole(ctx, user.ID, user.Role); err != nil {
+		return nil, err
+	}
+
+	slog.Info("place member added",
+		"place_id", placeID,
+		"user_id", user.ID,
+		"role", role,
+		"by", actorID,
+	)
+
+	return member, nil
+}
+
+// RemoveMember исключает сотрудника. Глобальная роль пересчитывается по оставшимся заведениям:
+// владелец другого заведения остаётся place_owner, без заведений роль возвращается к user.
+func (s *memberService) RemoveMember(ctx context.Context, actorID, actorRole, placeID, userID string) error {
+	if _, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Owner); err != nil {
+		return err
+	}
+
+	if _, err := uuid.Parse(userID); err != nil {
+		return serviceErrors.ErrInvalidUserID
+	}
+
+	target, err := s.memberRepo.GetMember(ctx, placeID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return serviceErrors.ErrMemberNotFound
+		}
+		return fmt.Errorf("get member: %w", err)
+	}
+	if target.Role == rbac.PlaceRoleOwner && actorRole != rbac.RoleAdmin {
+		return serviceErrors.ErrAccessDenied
+	}
+
+	if err := s.memberRepo.RemoveMember(ctx, placeID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return serviceErrors.ErrMemberNotFound
+		}
+		return fmt.Errorf("remove member: %w", err)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("find user: %w", err)
+	}
+	if user != nil {
+		if err := s.syncGlobalRole(ctx, userID, user.Role); err != nil {
+			return err
+		}
+	}
+
+	slog.Info("place member removed",
+		"place_id", placeID,
+		"user_id", userID,
+		"by", actorID,
+	)
+
+	return nil
+}
+
+func (s *memberService) ListMembers(ctx context.Context, actorID, actorRole, placeID string) ([]model.PlaceMember, error) {
+	if _, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Owner); err != nil {
+		return nil, err
+	}
+
+	members, err := s.memberRepo.ListMembers(ctx, placeID)
+	if err != nil {
+		return nil, fmt.Errorf("list members: %w", err)
+	}
+
+	return members, nil
+}
+
+// syncGlobalRole приводит глобальную роль пользователя к его ролям во всех заведениях.
+// Роль admin назначается вручную и не пересчитывается.
+func (s *memberService) syncGlobalRole(ctx context.Context, userID, currentRole string) error {
+	if currentRole == rbac.RoleAdmin {
+		return nil
+	}
+
+	placeRoles, err := s.memberRepo.ListUserPlaceRoles(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("list member place roles: %w", err)
+	}
+
+	globalRole := rbac.GlobalRoleForPlaceRoles(placeRoles)
+	if globalRole == currentRole {
+		return nil
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, userID, globalRole); err != nil {
+		return fmt.Errorf("update member global role: %w", err)
+	}
+
+	return nil
+}
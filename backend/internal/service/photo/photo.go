@@ -0,0 +1,314 @@
+package photo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/configs"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	repo "github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/blobstore"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/imaging"
+)
+
+// keyPrefix — каталог фото отзывов в хранилище: reviews/{review_id}/{photo_id}.jpg
+const keyPrefix = "reviews"
+
+type Service struct {
+	photoRepo  repo.PhotoRepository
+	reviewRepo repo.ReviewRepository
+	store      blobstore.BlobStore
+	txManager  repo.Transactor
+	cfg        *configs.Config
+}
+
+func NewPhotoService(
+	photoRepo repo.PhotoRepository,
+	reviewRepo repo.ReviewRepository,
+	store blobstore.BlobStore,
+	txManager repo.Transactor,
+	cfg *configs.Config,
+) *Service {
+	return &Service{
+		photoRepo:  photoRepo,
+		reviewRepo: reviewRepo,
+		store:      store,
+		txManager:  txManager,
+		cfg:        cfg,
+	}
+}
+
+// UploadPhotos прикрепляет фото к отзыву автора. Каждый файл проверяется по размеру и содержимому,
+// перекодируется без метаданных (EXIF, GPS) и получает миниатюру. Всего у отзыва не больше MaxReviewPhotos фото;
+// если хоть один файл не прошёл проверку, не сохраняется ни один.
+func (s *Service) UploadPhotos(ctx context.Context, userID, reviewID string, uploads []model.PhotoUpload) ([]model.ReviewPhoto, error) {
+	review, err := s.authorReview(ctx, userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(uploads) == 0 {
+		return nil, serviceErrors.ErrInvalidPhoto
+	}
+	if len(uploads) > s.cfg.MaxReviewPhotos {
+		return nil, serviceErrors.ErrTooManyPhotos
+	}
+
+	processed := make([]*imaging.Result, 0, len(uploads))
+	for _, upload := range uploads {
+		result, err := s.process(upload)
+		if err != nil {
+			return nil, err
+		}
+		processed = append(processed, result)
+	}
+
+	now := time.Now().UTC()
+	photos := make([]model.ReviewPhoto, 0, len(processed))
+	for _, result := range processed {
+		id := uuid.New()
+		base := fmt.Sprintf("%s/%s/%s", keyPrefix, review.ID, id)
+
+		photo := model.ReviewPhoto{
+			ID:           id,
+			ReviewID:     review.ID,
+			StorageKey:   base + imaging.Ext(result.ContentType),
+			ThumbnailKey: base + "_thumb" + imaging.Ext(result.ContentType),
+			ContentType:  result.ContentType,
+			SizeBytes:    int64(len(result.Image)),
+			Width:        result.Width,
+			Height:       result.Height,
+			CreatedAt:    now,
+		}
+
+		// Файлы пишутся до строки в БД: при сбое остаются только файлы без ссылок, а не ссылки без файлов
+		err := s.put(ctx, photo.StorageKey, result.Image, result.ContentType)
+		if err == nil {
+			err = s.put(ctx, photo.ThumbnailKey, result.Thumbnail, result.ContentType)
+		}
+		if err != nil {
+			s.removeFiles(ctx, append(photos, photo))
+			return nil, fmt.Errorf("store photo: %w", err)
+		}
+
+		photos = append(photos, photo)
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		count, lastPosition, err := s.photoRepo.LockReviewPhotos(ctx, review.ID)
+		if err != nil {
+			return err
+		}
+		if count+len(photos) > s.cfg.MaxReviewPhotos {
+			return serviceErrors.ErrTooManyPhotos
+		}
+
+		for i := range photos {
+			photos[i].Position = lastPosition + 1 + i
+			if err := s.photoRepo.CreatePhoto(ctx, &photos[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.removeFiles(ctx, photos)
+		if errors.Is(err, serviceErrors.ErrTooManyPhotos) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("save photos: %w", err)
+	}
+
+	for i := range photos {
+		s.fillURLs(&photos[i])
+	}
+
+	slog.Info("review photos uploaded", "review_id", review.ID, "count", len(photos))
+
+	return photos, nil
+}
+
+// DeletePhoto удаляет фото отзыва автора вместе с файлами
+func (s *Service) DeletePhoto(ctx context.Context, userID, reviewID, photoID string) error {
+	review, err := s.authorReview(ctx, userID, reviewID)
+	if err != nil {
+		return err
+	}
+
+	photoUID, err := uuid.Parse(photoID)
+	if err != nil {
+		return serviceErrors.ErrPhotoNotFound
+	}
+
+	photo, err := s.photoRepo.DeletePhoto(ctx, review.ID, photoUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return serviceErrors.ErrPhotoNotFound
+		}
+		return fmt.Errorf("delete photo: %w", err)
+	}
+
+	s.removeFiles(ctx, []model.ReviewPhoto{*photo})
+
+	return nil
+}
+
+// AttachPhotos заполняет Photos у отзывов списка одним запросом
+func (s *Service) AttachPhotos(ctx context.Context, reviews []model.Review) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(reviews))
+	for _, r := range reviews {
+		ids = append(ids, r.ID)
+	}
+
+	photos, err := s.photoRepo.ListByReviews(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("list photos: %w", err)
+	}
+
+	byReview := make(map[uuid.UUID][]model.ReviewPhoto, len(reviews))
+	for _, p := range photos {
+		s.fillURLs(&p)
+		byReview[p.ReviewID] = append(byReview[p.ReviewID], p)
+	}
+	for i := range reviews {
+		reviews[i].Photos = byReview[reviews[i].ID]
+	}
+
+	return nil
+}
+
+// DeleteReviewPhotos помечает фото отзыва удалёнными в транзакции удаления отзыва. Строки и файлы
+// остаются на окно отмены удаления, потом их стирает PurgeWorker.
+func (s *Service) DeleteReviewPhotos(ctx context.Context, reviewID uuid.UUID, deletedAt time.Time) error {
+	if err := s.photoRepo.SoftDeleteByReview(ctx, reviewID, deletedAt); err != nil {
+		return fmt.Errorf("delete review photos: %w", err)
+	}
+	return nil
+}
+
+// RestoreReviewPhotos возвращает фото восстановленного отзыва
+func (s *Service) RestoreReviewPhotos(ctx context.Context, reviewID uuid.UUID) error {
+	if err := s.photoRepo.RestoreByReview(ctx, reviewID); err != nil {
+		return fmt.Errorf("restore review photos: %w", err)
+	}
+	return nil
+}
+
+// OpenMedia открывает файл из хранилища для отдачи по /media/{key}. Отдаются только фото
+// опубликованных неудалённых отзывов: фото скрытого, ожидающего или удалённого отзыва не найдено.
+func (s *Service) OpenMedia(ctx context.Context, key string) (io.ReadCloser, error) {
+	public, err := s.photoRepo.IsPublicKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("check media key: %w", err)
+	}
+	if !public {
+		return nil, serviceErrors.ErrPhotoNotFound
+	}
+
+	rc, err := s.store.Open(ctx, key)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) || errors.Is(err, blobstore.ErrInvalidKey) {
+			return nil, serviceErrors.ErrPhotoNotFound
+		}
+		return nil, fmt.Errorf("open media: %w", err)
+	}
+	return rc, nil
+}
+
+// MaxUploadSize — наибольший суммарный размер файлов одной загрузки
+func (s *Service) MaxUploadSize() int64 {
+	return int64(s.cfg.MaxReviewPhotos) * s.cfg.PhotoMaxSize
+}
+
+// authorReview возвращает неудалённый отзыв, если его автор — userID; чужой отзыв не отличается от отсутствующего
+func (s *Service) authorReview(ctx context.Context, userID, reviewID string) (*model.Review, error) {
+	reviewUID, err := uuid.Parse(reviewID)
+	if err != nil {
+		return nil, serviceErrors.ErrReviewNotFound
+	}
+
+	review, err := s.reviewRepo.GetReviewByID(ctx, reviewUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("get review: %w", err)
+	}
+
+	if review.UserID.String() != userID {
+		return nil, serviceErrors.ErrReviewNotFound
+	}
+
+	return review, nil
+}
+
+// process читает загруженный файл не больше PhotoMaxSize байт и перекодирует его
+func (s *Service) process(upload model.PhotoUpload) (*imaging.Result, error) {
+	if upload.Size > s.cfg.PhotoMaxSize {
+		return nil, serviceErrors.ErrPhotoTooLarge
+	}
+
+	f, err := upload.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open upload: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(f, s.cfg.PhotoMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read upload: %w", err)
+	}
+	if int64(len(data)) > s.cfg.PhotoMaxSize {
+		return nil, serviceErrors.ErrPhotoTooLarge
+	}
+
+	result, err := imaging.Process(data, imaging.Options{
+		MaxDimension:  s.cfg.PhotoMaxDimension,
+		ThumbnailSize: s.cfg.PhotoThumbnailSize,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrUnsupportedType):
+			return nil, serviceErrors.ErrUnsupportedPhotoType
+		case errors.Is(err, imaging.ErrInvalidImage):
+			return nil, serviceErrors.ErrInvalidPhoto
+		default:
+			return nil, fmt.Errorf("process photo %q: %w", upload.Filename, err)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Service) put(ctx context.Context, key string, data []byte, contentType string) error {
+	return s.store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType)
+}
+
+func (s *Service) removeFiles(ctx context.Context, photos []model.ReviewPhoto) {
+	for _, p := range photos {
+		for _, key := range []string{p.StorageKey, p.ThumbnailKey} {
+			if err := s.store.Delete(ctx, key); err != nil {
+				slog.Error("failed to delete photo file", "key", key, "error", err)
+			}
+		}
+	}
+}
+
+func (s *Service) fillURLs(p *model.ReviewPhoto) {
+	p.URL = s.store.URL(p.StorageKey)
+	p.ThumbnailURL = s.store.URL(p.ThumbnailKey)
+}
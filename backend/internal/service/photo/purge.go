@@ -0,0 +1,73 @@
+package photo
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kulikovroman08/reviewlink-backend/configs"
+)
+
+// purgeBatchSize — сколько фото стирается за один запрос
+const purgeBatchSize = 100
+
+// PurgeWorker стирает фото удалённых отзывов, когда окно отмены удаления прошло
+type PurgeWorker struct {
+	service  *Service
+	interval time.Duration
+	window   time.Duration
+}
+
+func NewPurgeWorker(service *Service, cfg *configs.Config) *PurgeWorker {
+	return &PurgeWorker{
+		service:  service,
+		interval: cfg.PhotoPurgeInterval,
+		window:   cfg.ReviewUndoDeleteWindow,
+	}
+}
+
+// Run запускает очистку каждые interval, пока не отменён ctx
+func (w *PurgeWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	slog.Info("photo purge worker started", "interval", w.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("photo purge worker stopped")
+			return
+		case <-ticker.C:
+			if _, err := w.RunOnce(ctx); err != nil {
+				slog.Error("photo purge failed", "error", err)
+			}
+		}
+	}
+}
+
+// RunOnce стирает строки и файлы фото, удалённых раньше окна отмены, и возвращает их число.
+// Файлы удаляются после строк: оставшийся файл ни на что не ссылается.
+func (w *PurgeWorker) RunOnce(ctx context.Context) (int, error) {
+	deletedBefore := time.Now().UTC().Add(-max(w.window, 0))
+
+	purged := 0
+	for {
+		photos, err := w.service.photoRepo.PurgeDeleted(ctx, deletedBefore, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		w.service.removeFiles(ctx, photos)
+		purged += len(photos)
+
+		if len(photos) < purgeBatchSize {
+			break
+		}
+	}
+
+	if purged > 0 {
+		slog.Info("purged photos of deleted reviews", "count", purged)
+	}
+	return purged, nil
+}
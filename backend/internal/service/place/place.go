@@ -2,12 +2,16 @@ package place
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/google/uuid"
-	"github.com/kulikovroman08/reviewlink-backend/configs"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model/rbac"
 	"github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
 	"github.com/kulikovroman08/reviewlink-backend/internal/service/token"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/pagination"
 
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
 )
@@ -15,14 +19,12 @@ import (
 type placeService struct {
 	placeRepo    repository.PlaceRepository
 	tokenService *token.Service
-	cfg          *configs.Config
 }
 
-func NewPlaceService(placeRepo repository.PlaceRepository, tokenService *token.Service, cfg *configs.Config) *placeService {
+func NewPlaceService(placeRepo repository.PlaceRepository, tokenService *token.Service) *placeService {
 	return &placeService{
 		placeRepo:    placeRepo,
 		tokenService: tokenService,
-		cfg:          cfg,
 	}
 }
 
@@ -35,20 +37,47 @@ func (s *placeService) CreatePlace(ctx context.Context, place model.Place) (*mod
 		return nil, fmt.Errorf("address is required")
 	}
 
+	if err := token.ValidatePolicy(place.TokenPolicy); err != nil {
+		return nil, err
+	}
+
 	place.ID = uuid.New()
 
 	if err := s.placeRepo.CreatePlace(ctx, &place); err != nil {
 		return nil, fmt.Errorf("failed to create place: %w", err)
 	}
 
-	count := s.cfg.TokensAutoCount
-	if _, err := s.tokenService.GenerateTokens(ctx, place.ID.String(), count); err != nil {
-		fmt.Printf("failed to auto-generate tokens for place %s: %v\n", place.ID, err)
+	// Стартовая пачка токенов по политике заведения; нулевое количество отключает автогенерацию
+	count := s.tokenService.ResolvePolicy(place.TokenPolicy).InitialCount
+	if count > 0 {
+		if _, err := s.tokenService.GenerateTokens(ctx, place.ID.String(), count, model.TokenLabels{}); err != nil {
+			token.RecordInitialFailure()
+			slog.Error("auto-generate tokens failed", "place_id", place.ID, "error", err)
+		}
 	}
 
 	return &place, nil
 }
 
-func (s *placeService) GetAllPlaces(ctx context.Context) ([]model.Place, error) {
-	return s.placeRepo.GetAllPlaces(ctx)
+// GetPlaces возвращает страницу заведений по названию. Админ видит все заведения,
+// остальные — только те, где они сотрудники.
+func (s *placeService) GetPlaces(ctx context.Context, actorID, actorRole string, page pagination.Params) (*pagination.Page[model.Place], error) {
+	var memberID *uuid.UUID
+	if actorRole != rbac.RoleAdmin {
+		actorUID, err := uuid.Parse(actorID)
+		if err != nil {
+			return nil, serviceErrors.ErrAccessDenied
+		}
+		memberID = &actorUID
+	}
+
+	places, err := s.placeRepo.GetPlaces(ctx, memberID, page.Normalize())
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			return nil, serviceErrors.ErrInvalidCursor
+		}
+		return nil, fmt.Errorf("get places: %w", err)
+	}
+
+	return places, nil
 }
@@ -0,0 +1,96 @@
+package pos
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/configs"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	repo "github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/access"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/token"
+)
+
+const (
+	// MaxReceiptIDLength — предел длины номера чека от кассовой системы
+	MaxReceiptIDLength = 128
+
+	secretSize = 32
+)
+
+type Service struct {
+	repo         repo.POSRepository
+	access       *access.Checker
+	tokenService *token.Service
+	cfg          *configs.Config
+}
+
+func NewPOSService(
+	repo repo.POSRepository,
+	placeRepo repo.PlaceRepository,
+	memberRepo repo.PlaceMemberRepository,
+	tokenService *token.Service,
+	cfg *configs.Config,
+) *Service {
+	return &Service{
+		repo:         repo,
+		access:       access.NewChecker(placeRepo, memberRepo),
+		tokenService: tokenService,
+		cfg:          cfg,
+	}
+}
+
+// RotateSecret подключает кассовую систему заведения или перевыпускает её секрет.
+// Секрет возвращается только здесь; события, подписанные старым секретом, перестают приниматься.
+func (s *Service) RotateSecret(ctx context.Context, actorID, actorRole, placeID string) (*model.POSIntegration, error) {
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate pos secret: %w", err)
+	}
+
+	now := time.Now()
+	integration := &model.POSIntegration{
+		PlaceID:   place.ID,
+		Secret:    secret,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.UpsertIntegration(ctx, integration); err != nil {
+		return nil, fmt.Errorf("upsert pos integration: %w", err)
+	}
+
+	return integration, nil
+}
+
+// IssueReceiptToken проверяет подпись события от кассы и выпускает токен, привязанный к чеку.
+// Заведение без подключённой кассы неотличимо от неверной подписи.
+func (s *Service) IssueReceiptToken(ctx context.Context, event model.ReceiptEvent) (*model.ReceiptToken, error) {
+	receipt := event.Receipt
+	if receipt.ID == "" || len(receipt.ID) > MaxReceiptIDLength || receipt.Amount < 0 || receipt.PurchasedAt.IsZero() {
+		return nil, serviceErrors.ErrInvalidReceipt
+	}
+
+	integration, err := s.repo.GetIntegration(ctx, event.PlaceID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrInvalidSignature
+		}
+		return nil, fmt.Errorf("get pos integration: %w", err)
+	}
+
+	if !Verify(integration.Secret, event.Timestamp, event.Payload, event.Signature, time.Now(), s.cfg.POSSignatureTolerance) {
+		return nil, serviceErrors.ErrInvalidSignature
+	}
+
+	return s.tokenService.IssueReceiptToken(ctx, event.PlaceID, receipt)
+}
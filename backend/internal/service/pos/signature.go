@@ -0,0 +1,41 @@
+package pos
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignaturePrefix — схема подписи в заголовке X-Reviewlink-Signature
+const SignaturePrefix = "sha256="
+
+// Sign подписывает тело события: HMAC-SHA256 от "<timestamp>.<payload>" в hex.
+// Метка времени входит в подпись, чтобы перехваченный запрос нельзя было повторить позже.
+func Sign(secret []byte, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись и то, что метка времени (unix-секунды) отличается от now не больше чем на tolerance
+func Verify(secret []byte, timestamp string, payload []byte, signature string, now time.Time, tolerance time.Duration) bool {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	if diff := now.Sub(time.Unix(unix, 0)); diff > tolerance || diff < -tolerance {
+		return false
+	}
+
+	if !strings.HasPrefix(signature, SignaturePrefix) {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
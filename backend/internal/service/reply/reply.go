@@ -0,0 +1,254 @@
+package reply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	repo "github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/access"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+const (
+	MaxReplyLength         = 1000
+	MaxTemplateTitleLength = 64
+
+	// DefaultReportPeriod — период отчёта по ответам, если границы не заданы
+	DefaultReportPeriod = 30 * 24 * time.Hour
+)
+
+type Service struct {
+	replyRepo  repo.ReplyRepository
+	reviewRepo repo.ReviewRepository
+	access     *access.Checker
+}
+
+func NewReplyService(
+	replyRepo repo.ReplyRepository,
+	reviewRepo repo.ReviewRepository,
+	placeRepo repo.PlaceRepository,
+	memberRepo repo.PlaceMemberRepository,
+) *Service {
+	return &Service{
+		replyRepo:  replyRepo,
+		reviewRepo: reviewRepo,
+		access:     access.NewChecker(placeRepo, memberRepo),
+	}
+}
+
+// CreateReply публикует ответ заведения на отзыв. Отвечать могут админ и любой сотрудник заведения отзыва.
+// Ответ виден в публичном списке вместе с опубликованным отзывом.
+func (s *Service) CreateReply(ctx context.Context, actorID, actorRole, reviewID, content string) (*model.ReviewReply, error) {
+	content, err := normalizeText(content, MaxReplyLength, serviceErrors.ErrInvalidReply)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.authorizeReview(ctx, actorID, actorRole, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &model.ReviewReply{
+		ID:        uuid.New(),
+		ReviewID:  review.ID,
+		PlaceID:   review.PlaceID,
+		Content:   content,
+		CreatedAt: time.Now().UTC(),
+	}
+	if authorID, err := uuid.Parse(actorID); err == nil {
+		reply.AuthorID = &authorID
+	}
+
+	if err := s.replyRepo.CreateReply(ctx, reply); err != nil {
+		if errors.Is(err, serviceErrors.ErrReplyExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("create reply: %w", err)
+	}
+
+	slog.Info("review reply created", "review_id", review.ID, "author_id", actorID)
+	return reply, nil
+}
+
+// UpdateReply меняет текст ответа на отзыв
+func (s *Service) UpdateReply(ctx context.Context, actorID, actorRole, reviewID, content string) (*model.ReviewReply, error) {
+	content, err := normalizeText(content, MaxReplyLength, serviceErrors.ErrInvalidReply)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.authorizeReview(ctx, actorID, actorRole, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := s.replyRepo.UpdateReply(ctx, review.ID, content)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrReplyNotFound
+		}
+		return nil, fmt.Errorf("update reply: %w", err)
+	}
+
+	return reply, nil
+}
+
+// DeleteReply удаляет ответ на отзыв; после этого на отзыв можно ответить заново
+func (s *Service) DeleteReply(ctx context.Context, actorID, actorRole, reviewID string) error {
+	review, err := s.authorizeReview(ctx, actorID, actorRole, reviewID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.replyRepo.DeleteReply(ctx, review.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return serviceErrors.ErrReplyNotFound
+		}
+		return fmt.Errorf("delete reply: %w", err)
+	}
+
+	return nil
+}
+
+// ListTemplates возвращает шаблоны ответов заведения; доступно любому сотруднику
+func (s *Service) ListTemplates(ctx context.Context, actorID, actorRole, placeID string) ([]model.ReplyTemplate, error) {
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.AnyMember)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := s.replyRepo.ListTemplates(ctx, place.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list reply templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+// CreateTemplate сохраняет шаблон ответа; название уникально в пределах заведения.
+// Шаблонами управляют админ, владелец и менеджер.
+func (s *Service) CreateTemplate(ctx context.Context, actorID, actorRole, placeID, title, content string) (*model.ReplyTemplate, error) {
+	title, err := normalizeText(title, MaxTemplateTitleLength, serviceErrors.ErrInvalidReplyTemplate)
+	if err != nil {
+		return nil, err
+	}
+	content, err = normalizeText(content, MaxReplyLength, serviceErrors.ErrInvalidReplyTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Manager)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &model.ReplyTemplate{
+		ID:        uuid.New(),
+		PlaceID:   place.ID,
+		Title:     title,
+		Content:   content,
+		CreatedAt: time.Now().UTC(),
+	}
+	if authorID, err := uuid.Parse(actorID); err == nil {
+		template.CreatedBy = &authorID
+	}
+
+	if err := s.replyRepo.CreateTemplate(ctx, template); err != nil {
+		if errors.Is(err, serviceErrors.ErrReplyTemplateExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("create reply template: %w", err)
+	}
+
+	return template, nil
+}
+
+// DeleteTemplate удаляет шаблон заведения
+func (s *Service) DeleteTemplate(ctx context.Context, actorID, actorRole, placeID, templateID string) error {
+	templateUID, err := uuid.Parse(templateID)
+	if err != nil {
+		return serviceErrors.ErrReplyTemplateNotFound
+	}
+
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Manager)
+	if err != nil {
+		return err
+	}
+
+	if err := s.replyRepo.DeleteTemplate(ctx, place.ID, templateUID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return serviceErrors.ErrReplyTemplateNotFound
+		}
+		return fmt.Errorf("delete reply template: %w", err)
+	}
+
+	return nil
+}
+
+// GetReplyStats возвращает долю отзывов с ответом и медиану времени ответа по отзывам за период [from, to).
+// Без to берётся текущий момент, без from — DefaultReportPeriod до to. Доступно админу, владельцу и менеджеру.
+func (s *Service) GetReplyStats(ctx context.Context, actorID, actorRole, placeID string, from, to *time.Time) (*model.ReplyStats, error) {
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Manager)
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now().UTC()
+	if to != nil {
+		end = *to
+	}
+	start := end.Add(-DefaultReportPeriod)
+	if from != nil {
+		start = *from
+	}
+	if !start.Before(end) {
+		return nil, serviceErrors.ErrInvalidPeriod
+	}
+
+	stats, err := s.replyRepo.GetReplyStats(ctx, place.ID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("get reply stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// authorizeReview находит отзыв и проверяет, что актёр — админ или сотрудник заведения отзыва
+func (s *Service) authorizeReview(ctx context.Context, actorID, actorRole, reviewID string) (*model.Review, error) {
+	reviewUID, err := uuid.Parse(reviewID)
+	if err != nil {
+		return nil, serviceErrors.ErrReviewNotFound
+	}
+
+	review, err := s.reviewRepo.GetReviewByID(ctx, reviewUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("get review: %w", err)
+	}
+
+	if err := s.access.Member(ctx, actorID, actorRole, review.PlaceID.String(), access.AnyMember); err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// normalizeText обрезает пробелы и проверяет, что текст не пуст и не длиннее limit символов
+func normalizeText(text string, limit int, invalid error) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > limit {
+		return "", invalid
+	}
+	return text, nil
+}
@@ -0,0 +1,314 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/configs"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	repo "github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+const (
+	defaultReportPageSize = 50
+	maxReportPageSize     = 200
+
+	maxReportCommentLength = 500
+
+	autoHideReasonFormat    = "auto-hidden after %d reports"
+	reportHideReason        = "hidden after reports review"
+	reviewBanRestrictReason = "review hidden after reports review"
+)
+
+type Service struct {
+	reportRepo      repo.ReportRepository
+	reviewRepo      repo.ReviewRepository
+	userRepo        repo.UserRepository
+	restrictionRepo repo.UserRestrictionRepository
+	txManager       repo.Transactor
+	cfg             *configs.Config
+}
+
+func NewReportService(
+	reportRepo repo.ReportRepository,
+	reviewRepo repo.ReviewRepository,
+	userRepo repo.UserRepository,
+	restrictionRepo repo.UserRestrictionRepository,
+	txManager repo.Transactor,
+	cfg *configs.Config,
+) *Service {
+	return &Service{
+		reportRepo:      reportRepo,
+		reviewRepo:      reviewRepo,
+		userRepo:        userRepo,
+		restrictionRepo: restrictionRepo,
+		txManager:       txManager,
+		cfg:             cfg,
+	}
+}
+
+// CreateReport сохраняет жалобу на опубликованный отзыв. Один пользователь — одна жалоба на отзыв,
+// на свой отзыв пожаловаться нельзя. Когда открытых жалоб становится не меньше
+// ReportAutoHideThreshold, отзыв скрывается до решения модератора.
+func (s *Service) CreateReport(ctx context.Context, userID, reviewID, reason, comment string) (*model.ReviewReport, error) {
+	userUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, serviceErrors.ErrAccessDenied
+	}
+
+	reviewUID, err := uuid.Parse(reviewID)
+	if err != nil {
+		return nil, serviceErrors.ErrReviewNotFound
+	}
+
+	if !isReportReason(reason) {
+		return nil, serviceErrors.ErrInvalidReport
+	}
+
+	var note *string
+	if comment = strings.TrimSpace(comment); comment != "" {
+		if utf8.RuneCountInString(comment) > maxReportCommentLength {
+			return nil, serviceErrors.ErrInvalidReport
+		}
+		note = &comment
+	}
+
+	report := &model.ReviewReport{
+		ID:         uuid.New(),
+		ReviewID:   reviewUID,
+		ReporterID: userUID,
+		Reason:     reason,
+		Comment:    note,
+		Status:     model.ReportStatusOpen,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	autoHidden := false
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Строка отзыва блокируется: параллельные жалобы считаются по очереди
+		// и каждая видит жалобы, зафиксированные до неё
+		review, err := s.reviewRepo.LockReviewByID(ctx, reviewUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return serviceErrors.ErrReviewNotFound
+			}
+			return fmt.Errorf("get review: %w", err)
+		}
+
+		if review.Status != model.ReviewStatusPublished {
+			return serviceErrors.ErrReviewNotFound
+		}
+		if review.UserID == userUID {
+			return serviceErrors.ErrOwnReviewReport
+		}
+
+		if err := s.reportRepo.CreateReport(ctx, report); err != nil {
+			if errors.Is(err, serviceErrors.ErrReportExists) {
+				return err
+			}
+			return fmt.Errorf("create report: %w", err)
+		}
+
+		threshold := s.cfg.ReportAutoHideThreshold
+		if threshold <= 0 {
+			return nil
+		}
+
+		count, err := s.reportRepo.CountOpenReports(ctx, reviewUID)
+		if err != nil {
+			return fmt.Errorf("count open reports: %w", err)
+		}
+		if count < threshold {
+			return nil
+		}
+
+		hideReason := fmt.Sprintf(autoHideReasonFormat, count)
+		err = s.reviewRepo.UpdateReviewStatus(ctx, reviewUID, model.ReviewStatusPublished, model.ReviewStatusHidden, nil, &hideReason)
+		if err != nil {
+			// Отзыв уже снял модератор — скрывать нечего
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("hide review: %w", err)
+		}
+		if err := s.revokePoints(ctx, review); err != nil {
+			return err
+		}
+		autoHidden = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if autoHidden {
+		slog.Info("review auto-hidden by reports", "review_id", reviewUID)
+	}
+	return report, nil
+}
+
+// ListReportedReviews возвращает очередь отзывов с жалобами в статусе filter.Status (по умолчанию — открытые)
+func (s *Service) ListReportedReviews(ctx context.Context, filter model.ReportFilter) ([]model.ReportedReview, error) {
+	switch filter.Status {
+	case "":
+		filter.Status = model.ReportStatusOpen
+	case model.ReportStatusOpen, model.ReportStatusResolved:
+	default:
+		return nil, serviceErrors.ErrInvalidReportStatus
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultReportPageSize
+	}
+	if filter.Limit > maxReportPageSize {
+		filter.Limit = maxReportPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	reviews, err := s.reportRepo.ListReportedReviews(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list reported reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+// ListReviewReports возвращает все жалобы на отзыв, включая разобранные
+func (s *Service) ListReviewReports(ctx context.Context, reviewID string) ([]model.ReviewReport, error) {
+	reviewUID, err := uuid.Parse(reviewID)
+	if err != nil {
+		return nil, serviceErrors.ErrReviewNotFound
+	}
+
+	if _, err := s.reviewRepo.GetReviewByID(ctx, reviewUID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("get review: %w", err)
+	}
+
+	reports, err := s.reportRepo.ListReports(ctx, reviewUID)
+	if err != nil {
+		return nil, fmt.Errorf("list reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// ResolveReports закрывает открытые жалобы на отзыв решением модератора:
+// dismiss — жалобы отклонены, отзыв не меняется (скрытый автоматически отзыв возвращает ApproveReview);
+// hide — опубликованный отзыв скрывается; hide_and_restrict — вдобавок автору
+// запрещается оставлять отзывы на ReportRestrictionPeriod.
+func (s *Service) ResolveReports(ctx context.Context, moderatorID, reviewID, action string) (*model.ReportResolution, error) {
+	moderatorUID, err := uuid.Parse(moderatorID)
+	if err != nil {
+		return nil, serviceErrors.ErrAccessDenied
+	}
+
+	reviewUID, err := uuid.Parse(reviewID)
+	if err != nil {
+		return nil, serviceErrors.ErrReviewNotFound
+	}
+
+	switch action {
+	case model.ReportActionDismiss, model.ReportActionHide, model.ReportActionHideAndRestrict:
+	default:
+		return nil, serviceErrors.ErrInvalidReportAction
+	}
+
+	result := &model.ReportResolution{ReviewID: reviewUID, Action: action}
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		review, err := s.reviewRepo.LockReviewByID(ctx, reviewUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return serviceErrors.ErrReviewNotFound
+			}
+			return fmt.Errorf("get review: %w", err)
+		}
+
+		resolved, err := s.reportRepo.ResolveReports(ctx, reviewUID, action, moderatorUID)
+		if err != nil {
+			return fmt.Errorf("resolve reports: %w", err)
+		}
+		if resolved == 0 {
+			return serviceErrors.ErrReportNotFound
+		}
+		result.Resolved = resolved
+		result.ReviewStatus = review.Status
+
+		if action == model.ReportActionDismiss {
+			return nil
+		}
+
+		if review.Status == model.ReviewStatusPublished {
+			reason := reportHideReason
+			err := s.reviewRepo.UpdateReviewStatus(ctx, reviewUID, review.Status, model.ReviewStatusHidden, &moderatorUID, &reason)
+			if err != nil {
+				return fmt.Errorf("hide review: %w", err)
+			}
+			if err := s.revokePoints(ctx, review); err != nil {
+				return err
+			}
+			result.ReviewStatus = model.ReviewStatusHidden
+		}
+
+		if action == model.ReportActionHideAndRestrict {
+			now := time.Now().UTC()
+			restriction := model.UserRestriction{
+				ID:              uuid.New(),
+				UserID:          review.UserID,
+				RestrictionType: model.RestrictionTypeReviewBan,
+				Reason:          reviewBanRestrictReason,
+				CreatedAt:       now,
+				ExpiresAt:       now.Add(s.cfg.ReportRestrictionPeriod),
+			}
+			// Действующий запрет не продлевается: в ответе срок из сохранённой записи
+			if err := s.restrictionRepo.CreateRestriction(ctx, &restriction); err != nil {
+				return fmt.Errorf("create restriction: %w", err)
+			}
+			result.RestrictedUntil = &restriction.ExpiresAt
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("review reports resolved",
+		"review_id", reviewUID,
+		"action", action,
+		"resolved", result.Resolved,
+		"moderator_id", moderatorUID,
+	)
+	return result, nil
+}
+
+func isReportReason(reason string) bool {
+	switch reason {
+	case model.ReportReasonSpam, model.ReportReasonOffensive, model.ReportReasonFake, model.ReportReasonOffTopic:
+		return true
+	}
+	return false
+}
+
+// revokePoints списывает баллы за скрытый отзыв: они есть у автора, только пока отзыв опубликован
+func (s *Service) revokePoints(ctx context.Context, review *model.Review) error {
+	if review.Points <= 0 {
+		return nil
+	}
+	if err := s.userRepo.RevokePoints(ctx, review.UserID.String(), review.Points); err != nil {
+		return fmt.Errorf("revoke points: %w", err)
+	}
+	return nil
+}
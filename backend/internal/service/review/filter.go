@@ -0,0 +1,60 @@
+package review
+
+import (
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/kulikovroman08/reviewlink-backend/pkg/contentfilter"
+
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+const (
+	// MaxReviewLength — предел длины текста отзыва в символах, как у колонки reviews.content VARCHAR(500).
+	// Проверяется до фильтра: набор правил может и не содержать правила длины.
+	MaxReviewLength = 500
+
+	// flaggedEditReasonFormat — причина модерации отзыва, снятого с публикации помеченной правкой
+	flaggedEditReasonFormat = "edit flagged by content filter rule %s"
+)
+
+// contentVerdict — итог проверки текста: сработавшее правило и нужна ли модерация
+type contentVerdict struct {
+	rule    *string
+	flagged bool
+}
+
+// filterContent проверяет текст отзыва фильтром. Правило reject возвращает ErrContentRejected,
+// flag отправляет отзыв модератору, allow пропускает, но имя правила всё равно запоминается.
+// Текст длиннее MaxReviewLength отклоняется при любом наборе правил.
+func (s *reviewService) filterContent(content string) (contentVerdict, error) {
+	if utf8.RuneCountInString(content) > MaxReviewLength {
+		return contentVerdict{}, serviceErrors.ErrContentRejected
+	}
+
+	verdict := s.contentFilter.Check(content)
+	if !verdict.Matched() {
+		return contentVerdict{}, nil
+	}
+
+	slog.Info("review content matched filter rule", "rule", verdict.Rule, "action", verdict.Action)
+
+	if verdict.Action == contentfilter.ActionReject {
+		return contentVerdict{}, serviceErrors.ErrContentRejected
+	}
+
+	return contentVerdict{
+		rule:    &verdict.Rule,
+		flagged: verdict.Action == contentfilter.ActionFlag,
+	}, nil
+}
+
+// editFlagReason возвращает причину модерации для помеченной правки; nil — правка не помечена
+func (v contentVerdict) editFlagReason() *string {
+	if !v.flagged || v.rule == nil {
+		return nil
+	}
+	reason := fmt.Sprintf(flaggedEditReasonFormat, *v.rule)
+	return &reason
+}
@@ -0,0 +1,24 @@
+package review
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/contentfilter"
+)
+
+func TestFilterContentEnforcesMaxLengthWithoutRule(t *testing.T) {
+	filter, err := contentfilter.New(contentfilter.Config{})
+	require.NoError(t, err)
+
+	s := &reviewService{contentFilter: filter}
+
+	_, err = s.filterContent(strings.Repeat("о", MaxReviewLength))
+	require.NoError(t, err)
+
+	_, err = s.filterContent(strings.Repeat("о", MaxReviewLength+1))
+	require.ErrorIs(t, err, serviceErrors.ErrContentRejected)
+}
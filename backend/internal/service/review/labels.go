@@ -0,0 +1,42 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/access"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// GetLabelReport — отзывы заведения в разрезе меток токенов (сотрудник, стол, кампания): число
+// и средняя оценка, лучшие первыми. В разрезе по сотрудникам это рейтинг персонала внутри заведения.
+// Не-админ должен быть владельцем или менеджером заведения.
+func (s *reviewService) GetLabelReport(
+	ctx context.Context,
+	actorID, actorRole, placeID, dimension string,
+	from, to *time.Time,
+) ([]model.LabelStat, error) {
+	switch dimension {
+	case model.LabelDimensionStaff, model.LabelDimensionTable, model.LabelDimensionCampaign:
+	default:
+		return nil, serviceErrors.ErrInvalidReportDimension
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, serviceErrors.ErrInvalidPeriod
+	}
+
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Manager)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.reviewRepo.GetLabelStats(ctx, place.ID, dimension, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get label stats: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,237 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/pagination"
+)
+
+const (
+	maxModerationReasonLength = 500
+)
+
+// moderationTransitions — из каких статусов можно перевести отзыв в целевой.
+// Отклонение окончательное: отклонённый отзыв вернуть нельзя.
+var moderationTransitions = map[string][]string{
+	model.ReviewStatusPublished: {model.ReviewStatusPending, model.ReviewStatusHidden},
+	model.ReviewStatusRejected:  {model.ReviewStatusPending, model.ReviewStatusPublished, model.ReviewStatusHidden},
+	model.ReviewStatusHidden:    {model.ReviewStatusPublished},
+}
+
+// GetModerationQueue возвращает страницу отзывов в статусе filter.Status (по умолчанию — ожидающие), старые первыми
+func (s *reviewService) GetModerationQueue(ctx context.Context, filter model.ModerationFilter) (*pagination.Page[model.Review], error) {
+	switch filter.Status {
+	case "":
+		filter.Status = model.ReviewStatusPending
+	case model.ReviewStatusPending, model.ReviewStatusPublished, model.ReviewStatusRejected, model.ReviewStatusHidden:
+	default:
+		return nil, serviceErrors.ErrInvalidReviewStatus
+	}
+
+	filter.Page = filter.Page.Normalize()
+
+	page, err := s.reviewRepo.FindModerationQueue(ctx, filter)
+	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			return nil, serviceErrors.ErrInvalidCursor
+		}
+		return nil, fmt.Errorf("find moderation queue: %w", err)
+	}
+
+	return page, nil
+}
+
+// ApproveReview публикует ожидающий отзыв или возвращает скрытый. Баллы автора начисляются
+// при каждой публикации и списываются, когда отзыв с неё снимают (см. moderate).
+func (s *reviewService) ApproveReview(ctx context.Context, moderatorID, reviewID string) (*model.Review, error) {
+	return s.moderate(ctx, moderatorID, reviewID, model.ReviewStatusPublished, nil)
+}
+
+// RejectReview окончательно отклоняет отзыв с обязательной причиной
+func (s *reviewService) RejectReview(ctx context.Context, moderatorID, reviewID, reason string) (*model.Review, error) {
+	note, err := moderationReason(reason)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, serviceErrors.ErrInvalidModerationNote
+	}
+
+	return s.moderate(ctx, moderatorID, reviewID, model.ReviewStatusRejected, note)
+}
+
+// HideReview снимает опубликованный отзыв с публикации; причина необязательна
+func (s *reviewService) HideReview(ctx context.Context, moderatorID, reviewID, reason string) (*model.Review, error) {
+	note, err := moderationReason(reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.moderate(ctx, moderatorID, reviewID, model.ReviewStatusHidden, note)
+}
+
+func (s *reviewService) moderate(ctx context.Context, moderatorID, reviewID, to string, reason *string) (*model.Review, error) {
+	reviewUID, err := uuid.Parse(reviewID)
+	if err != nil {
+		return nil, serviceErrors.ErrReviewNotFound
+	}
+
+	moderatorUID, err := uuid.Parse(moderatorID)
+	if err != nil {
+		return nil, serviceErrors.ErrAccessDenied
+	}
+
+	var review *model.Review
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		rev, err := s.reviewRepo.GetReviewByID(ctx, reviewUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return serviceErrors.ErrReviewNotFound
+			}
+			return fmt.Errorf("get review: %w", err)
+		}
+
+		from := rev.Status
+		if !canModerate(from, to) {
+			return serviceErrors.ErrInvalidModeration
+		}
+
+		// Статус сверяется ещё раз в самом UPDATE: параллельное решение другого модератора не перезапишется
+		if err := s.reviewRepo.UpdateReviewStatus(ctx, reviewUID, from, to, &moderatorUID, reason); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return serviceErrors.ErrInvalidModeration
+			}
+			return fmt.Errorf("update review status: %w", err)
+		}
+
+		// Баллы у автора, пока отзыв опубликован: при постмодерации они начислены при создании
+		if rev.Points > 0 {
+			switch {
+			case to == model.ReviewStatusPublished:
+				if err := s.userRepo.AddPoints(ctx, rev.UserID.String(), rev.Points); err != nil {
+					return fmt.Errorf("add points: %w", err)
+				}
+			case from == model.ReviewStatusPublished:
+				if err := s.userRepo.RevokePoints(ctx, rev.UserID.String(), rev.Points); err != nil {
+					return fmt.Errorf("revoke points: %w", err)
+				}
+			}
+		}
+
+		now := time.Now().UTC()
+		rev.Status = to
+		rev.ModerationReason = reason
+		rev.ModeratedBy = &moderatorUID
+		rev.ModeratedAt = &now
+		review = rev
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("review moderated", "review_id", reviewUID, "status", to, "moderator_id", moderatorUID)
+	return review, nil
+}
+
+// GetModerationSettings возвращает режим модерации заведения
+func (s *reviewService) GetModerationSettings(ctx context.Context, placeID string) (*model.ModerationSettings, error) {
+	if _, err := uuid.Parse(placeID); err != nil {
+		return nil, serviceErrors.ErrInvalidPlaceID
+	}
+
+	place, err := s.placeRepo.GetByID(ctx, placeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrPlaceNotFound
+		}
+		return nil, fmt.Errorf("get place: %w", err)
+	}
+
+	return s.moderationSettings(place.ModerationMode), nil
+}
+
+// UpdateModerationMode задаёт режим модерации заведения; nil возвращает режим из конфига.
+// Уже созданные отзывы не меняют статус.
+func (s *reviewService) UpdateModerationMode(ctx context.Context, placeID string, mode *string) (*model.ModerationSettings, error) {
+	if _, err := uuid.Parse(placeID); err != nil {
+		return nil, serviceErrors.ErrInvalidPlaceID
+	}
+
+	if mode != nil && !isModerationMode(*mode) {
+		return nil, serviceErrors.ErrInvalidModerationMode
+	}
+
+	if err := s.placeRepo.UpdateModerationMode(ctx, placeID, mode); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrPlaceNotFound
+		}
+		return nil, fmt.Errorf("update moderation mode: %w", err)
+	}
+
+	return s.moderationSettings(mode), nil
+}
+
+// initialStatus возвращает статус нового отзыва по режиму модерации заведения
+func (s *reviewService) initialStatus(ctx context.Context, placeID uuid.UUID) (string, error) {
+	place, err := s.placeRepo.GetByID(ctx, placeID.String())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", serviceErrors.ErrPlaceNotFound
+		}
+		return "", fmt.Errorf("get place: %w", err)
+	}
+
+	if s.moderationSettings(place.ModerationMode).Effective == model.ModerationModePre {
+		return model.ReviewStatusPending, nil
+	}
+	return model.ReviewStatusPublished, nil
+}
+
+// moderationSettings дополняет режим заведения режимом из конфига; неизвестный режим в конфиге считается post
+func (s *reviewService) moderationSettings(custom *string) *model.ModerationSettings {
+	effective := model.ModerationModePost
+	if isModerationMode(s.cfg.ReviewModerationMode) {
+		effective = s.cfg.ReviewModerationMode
+	}
+	if custom != nil {
+		effective = *custom
+	}
+
+	return &model.ModerationSettings{Custom: custom, Effective: effective}
+}
+
+func isModerationMode(mode string) bool {
+	return mode == model.ModerationModePre || mode == model.ModerationModePost
+}
+
+func canModerate(from, to string) bool {
+	for _, allowed := range moderationTransitions[to] {
+		if allowed == from {
+			return true
+		}
+	}
+	return false
+}
+
+// moderationReason обрезает пробелы; пустая причина — nil
+func moderationReason(reason string) (*string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, nil
+	}
+	if utf8.RuneCountInString(reason) > maxModerationReasonLength {
+		return nil, serviceErrors.ErrInvalidModerationNote
+	}
+	return &reason, nil
+}
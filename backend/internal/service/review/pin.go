@@ -0,0 +1,62 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// submitPINReview принимает отзыв по PIN-коду, набранному вручную. PIN короткий, поэтому каждая попытка
+// пишется в журнал до проверки: неудачные попытки за окно ограничены на пользователя и на IP,
+// а параллельные запросы не обходят лимит.
+func (s *reviewService) submitPINReview(ctx context.Context, review model.Review, pin, clientIP string) (*model.Review, error) {
+	// PIN уникален только внутри заведения
+	if review.PlaceID == uuid.Nil {
+		return nil, serviceErrors.ErrInvalidToken
+	}
+
+	now := time.Now()
+	attempt := &model.PINAttempt{
+		ID:        uuid.New(),
+		UserID:    review.UserID,
+		IP:        clientIP,
+		PlaceID:   review.PlaceID,
+		PIN:       pin,
+		CreatedAt: now,
+	}
+	if err := s.pinRepo.RecordAttempt(ctx, attempt); err != nil {
+		return nil, fmt.Errorf("record pin attempt: %w", err)
+	}
+
+	byUser, byIP, err := s.pinRepo.CountFailed(ctx, review.UserID, clientIP, now.Add(-s.cfg.PINAttemptWindow))
+	if err != nil {
+		return nil, fmt.Errorf("count pin attempts: %w", err)
+	}
+	if byUser > s.cfg.PINMaxAttemptsPerUser || byIP > s.cfg.PINMaxAttemptsPerIP {
+		slog.Warn("pin attempts limit exceeded",
+			"user_id", review.UserID, "ip", clientIP, "place_id", review.PlaceID,
+			"failed_by_user", byUser, "failed_by_ip", byIP)
+		return nil, serviceErrors.ErrTooManyPINAttempts
+	}
+
+	rt, err := s.reviewRepo.GetReviewTokenByPIN(ctx, review.PlaceID, pin)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("get token by pin: %w", err)
+	}
+
+	if err := s.pinRepo.MarkSucceeded(ctx, attempt.ID); err != nil {
+		return nil, fmt.Errorf("mark pin attempt: %w", err)
+	}
+
+	return s.submitTokenReview(ctx, review, rt)
+}
@@ -9,13 +9,19 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/configs"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/access"
 	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/kiosk"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/photo"
 	"github.com/kulikovroman08/reviewlink-backend/internal/service/token"
 
 	"github.com/kulikovroman08/reviewlink-backend/internal/repository"
 
 	"github.com/google/uuid"
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/contentfilter"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/pagination"
 )
 
 const (
@@ -30,133 +36,307 @@ type reviewService struct {
 	reviewRepo      repository.ReviewRepository
 	userRepo        repository.UserRepository
 	placeRepo       repository.PlaceRepository
+	access          *access.Checker
 	tokenService    *token.Service
+	kioskService    *kiosk.Service
 	restrictionRepo repository.UserRestrictionRepository
+	pinRepo         repository.PINAttemptRepository
+	contentFilter   *contentfilter.Filter
+	photoService    *photo.Service
+	revisionRepo    repository.RevisionRepository
+	txManager       repository.Transactor
+	cfg             *configs.Config
 }
 
 func NewReviewService(
 	reviewRepo repository.ReviewRepository,
 	userRepo repository.UserRepository,
 	placeRepo repository.PlaceRepository,
+	memberRepo repository.PlaceMemberRepository,
 	tokenService *token.Service,
+	kioskService *kiosk.Service,
 	restrictionRepo repository.UserRestrictionRepository,
+	pinRepo repository.PINAttemptRepository,
+	contentFilter *contentfilter.Filter,
+	photoService *photo.Service,
+	revisionRepo repository.RevisionRepository,
+	txManager repository.Transactor,
+	cfg *configs.Config,
 ) *reviewService {
 	return &reviewService{
 		reviewRepo:      reviewRepo,
 		userRepo:        userRepo,
 		placeRepo:       placeRepo,
+		access:          access.NewChecker(placeRepo, memberRepo),
 		tokenService:    tokenService,
+		kioskService:    kioskService,
 		restrictionRepo: restrictionRepo,
+		pinRepo:         pinRepo,
+		contentFilter:   contentFilter,
+		photoService:    photoService,
+		revisionRepo:    revisionRepo,
+		txManager:       txManager,
+		cfg:             cfg,
 	}
 }
 
-func (s *reviewService) SubmitReview(ctx context.Context, review model.Review, tokenStr string) error {
+// SubmitReview сохраняет отзыв по токену, коду киоска или PIN-коду и возвращает созданный отзыв.
+// Отзыв по токену, выпущенному кассой, получает ReceiptID — признак подтверждённой покупки.
+// clientIP нужен для лимита попыток ввода PIN. Текст проверяется фильтром до погашения токена:
+// отклонённый текст можно исправить и отправить по тому же токену.
+func (s *reviewService) SubmitReview(ctx context.Context, review model.Review, tokenStr, clientIP string) (*model.Review, error) {
 	if tokenStr == "" {
-		return serviceErrors.ErrInvalidCredentials
+		return nil, serviceErrors.ErrInvalidCredentials
 	}
 
 	if review.Rating < 1 || review.Rating > 5 {
-		return serviceErrors.ErrInvalidCredentials
+		return nil, serviceErrors.ErrInvalidCredentials
 	}
 
-	token, err := s.reviewRepo.GetReviewToken(ctx, tokenStr)
+	// Запрет выдаётся модератором по итогам жалоб; проверяется до погашения токена
+	banned, err := s.restrictionRepo.HasActiveRestriction(ctx, review.UserID.String(), model.RestrictionTypeReviewBan)
 	if err != nil {
-		return fmt.Errorf("get token: %w", err)
+		return nil, fmt.Errorf("check review ban: %w", err)
 	}
-	if token.IsUsed {
-		return serviceErrors.ErrInvalidCredentials
+	if banned {
+		return nil, serviceErrors.ErrReviewsRestricted
 	}
 
-	if token.ExpiresAt.Before(time.Now()) {
-		return serviceErrors.ErrTokenExpired
+	verdict, err := s.filterContent(review.Content)
+	if err != nil {
+		return nil, err
+	}
+	review.FilterRule = verdict.rule
+	if verdict.flagged {
+		review.Status = model.ReviewStatusPending
 	}
 
-	hasToday, err := s.reviewRepo.HasReviewToday(ctx, review.UserID.String(), token.PlaceID.String())
-	if err != nil {
-		return fmt.Errorf("check existing review: %w", err)
+	if kiosk.IsCode(tokenStr) {
+		return s.submitKioskReview(ctx, review, tokenStr)
 	}
-	if hasToday {
-		return serviceErrors.ErrTooManyReviews
+
+	if token.IsPIN(tokenStr, s.cfg.PINLength) {
+		return s.submitPINReview(ctx, review, tokenStr, clientIP)
 	}
 
-	isRestricted, err := s.restrictionRepo.HasActiveRestriction(
-		ctx, review.UserID.String(), RestrictionTypePointsFreeze)
-	if err != nil {
-		return fmt.Errorf("check restriction: %w", err)
+	// Опечатка в контрольном символе видна без запроса к базе
+	if !s.tokenService.ValidValue(tokenStr) {
+		return nil, serviceErrors.ErrInvalidCredentials
 	}
 
-	if !isRestricted && review.Rating == 1 {
-		count, err := s.reviewRepo.CountLowRatingReviews(
-			ctx, review.UserID.String(), ReviewPeriodDays)
-		if err != nil {
-			return fmt.Errorf("count low ratings: %w", err)
+	rt, err := s.reviewRepo.GetReviewToken(ctx, tokenStr)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrInvalidToken
 		}
+		return nil, fmt.Errorf("get token: %w", err)
+	}
 
-		if count >= LowRatingThreshold {
-			restriction := model.UserRestriction{
-				ID:              uuid.New(),
-				UserID:          review.UserID,
-				RestrictionType: RestrictionTypePointsFreeze,
-				Reason:          LowRatingRestrictionReason,
-				CreatedAt:       time.Now(),
-				ExpiresAt:       time.Now().AddDate(0, 0, FreezeDurationDays),
-			}
-			if err := s.restrictionRepo.CreateRestriction(ctx, &restriction); err != nil {
-				return fmt.Errorf("create restriction: %w", err)
+	return s.submitTokenReview(ctx, review, rt)
+}
+
+// submitTokenReview проверяет состояние найденного токена и сохраняет отзыв, погашая токен
+func (s *reviewService) submitTokenReview(ctx context.Context, review model.Review, rt *model.ReviewToken) (*model.Review, error) {
+	// Токен печатается для конкретного заведения и не может использоваться для другого
+	if review.PlaceID != uuid.Nil && review.PlaceID != rt.PlaceID {
+		return nil, serviceErrors.ErrTokenPlaceMismatch
+	}
+	review.PlaceID = rt.PlaceID
+
+	if rt.IsUsed {
+		return nil, serviceErrors.ErrInvalidCredentials
+	}
+
+	if rt.RevokedAt != nil {
+		return nil, serviceErrors.ErrTokenRevoked
+	}
+
+	if rt.ExpiresAt.Before(time.Now()) {
+		return nil, serviceErrors.ErrTokenExpired
+	}
+
+	review.TokenID = &rt.ID
+	review.Labels = rt.Labels
+	if rt.Receipt != nil {
+		review.ReceiptID = &rt.Receipt.ID
+	}
+
+	created, err := s.createReview(ctx, review, func(ctx context.Context) error {
+		if _, err := s.reviewRepo.ClaimReviewToken(ctx, rt.ID.String()); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return serviceErrors.ErrInvalidCredentials
 			}
+			return fmt.Errorf("claim token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-			slog.Info("created user restriction",
-				"user_id", review.UserID,
-				"type", RestrictionTypePointsFreeze,
-				"reason", restriction.Reason,
-				"expires_at", restriction.ExpiresAt,
-			)
+	// Догенерацию выполняет фоновый воркер. Задача ставится после коммита: строка очереди одна
+	// на заведение, и её блокировка в транзакции выстроила бы параллельные отзывы в очередь.
+	// Отзыв уже сохранён, поэтому ошибка постановки только логируется — следующий отзыв поставит задачу снова.
+	if err := s.tokenService.ScheduleRefill(ctx, rt.PlaceID); err != nil {
+		slog.Error("schedule token refill", "place_id", rt.PlaceID, "error", err)
+	}
+
+	return created, nil
+}
+
+// submitKioskReview принимает отзыв по коду с планшета заведения. Код не хранится в БД:
+// он проверяется по секрету заведения, а повтор в том же окне отсекается записью в kiosk_submissions.
+func (s *reviewService) submitKioskReview(ctx context.Context, review model.Review, code string) (*model.Review, error) {
+	if review.PlaceID == uuid.Nil {
+		return nil, serviceErrors.ErrInvalidToken
+	}
+
+	window, err := s.kioskService.VerifyCode(ctx, review.PlaceID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createReview(ctx, review, func(ctx context.Context) error {
+		return s.kioskService.ClaimWindow(ctx, review.PlaceID, review.UserID, window)
+	})
+}
+
+// createReview в одной транзакции проверяет дневной лимит, погашает право на отзыв через claim,
+// сохраняет отзыв и начисляет баллы. Статус отзыва зависит от режима модерации заведения.
+func (s *reviewService) createReview(ctx context.Context, review model.Review, claim func(ctx context.Context) error) (*model.Review, error) {
+	var err error
 
-			isRestricted = true
+	// Отзыв, помеченный фильтром, ждёт модератора при любом режиме заведения
+	if review.Status != model.ReviewStatusPending {
+		review.Status, err = s.initialStatus(ctx, review.PlaceID)
+		if err != nil {
+			return nil, err
 		}
 	}
 
 	review.ID = uuid.New()
-	review.TokenID = token.ID
 	review.CreatedAt = time.Now()
 
-	if err := s.reviewRepo.CreateReview(ctx, review); err != nil {
-		return fmt.Errorf("create review: %w", err)
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Блокировка пользователя выстраивает его параллельные отзывы в очередь:
+		// второй увидит первый и упрётся в дневной лимит
+		if err := s.userRepo.LockUser(ctx, review.UserID.String()); err != nil {
+			return fmt.Errorf("lock user: %w", err)
+		}
+
+		hasToday, err := s.reviewRepo.HasReviewToday(ctx, review.UserID.String(), review.PlaceID.String())
+		if err != nil {
+			return fmt.Errorf("check existing review: %w", err)
+		}
+		if hasToday {
+			return serviceErrors.ErrTooManyReviews
+		}
+
+		if err := claim(ctx); err != nil {
+			return err
+		}
+
+		isRestricted, err := s.applyLowRatingRestriction(ctx, review)
+		if err != nil {
+			return err
+		}
+
+		review.Points = 0
+		if isRestricted {
+			slog.Info("user has active restriction: skip points", "user_id", review.UserID)
+		} else {
+			review.Points = pointsForRating(review.Rating)
+		}
+
+		if err := s.reviewRepo.CreateReview(ctx, review); err != nil {
+			return fmt.Errorf("create review: %w", err)
+		}
+
+		if err := s.saveRevision(ctx, review.ID, review.Content, review.Rating); err != nil {
+			return err
+		}
+
+		// При премодерации баллы начисляются при одобрении отзыва
+		if review.Points > 0 && review.Status == model.ReviewStatusPublished {
+			if err := s.userRepo.AddPoints(ctx, review.UserID.String(), review.Points); err != nil {
+				return fmt.Errorf("add points: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.reviewRepo.MarkReviewTokenUsed(ctx, token.ID.String()); err != nil {
-		return fmt.Errorf("mark token used: %w", err)
+	return &review, nil
+}
+
+// applyLowRatingRestriction проверяет заморозку баллов и при необходимости создаёт её
+func (s *reviewService) applyLowRatingRestriction(ctx context.Context, review model.Review) (bool, error) {
+	isRestricted, err := s.restrictionRepo.HasActiveRestriction(
+		ctx, review.UserID.String(), RestrictionTypePointsFreeze)
+	if err != nil {
+		return false, fmt.Errorf("check restriction: %w", err)
 	}
 
-	if err := s.tokenService.CheckAndRefillTokens(ctx, token.PlaceID.String()); err != nil {
-		fmt.Printf("auto-refill tokens failed for place %s: %v\n", token.PlaceID, err)
+	if isRestricted || review.Rating != 1 {
+		return isRestricted, nil
 	}
 
-	if isRestricted {
-		fmt.Printf("User %s has active restriction: skip points\n", review.UserID)
-		return nil
+	count, err := s.reviewRepo.CountLowRatingReviews(
+		ctx, review.UserID.String(), ReviewPeriodDays)
+	if err != nil {
+		return false, fmt.Errorf("count low ratings: %w", err)
+	}
+
+	if count < LowRatingThreshold {
+		return false, nil
+	}
+
+	restriction := model.UserRestriction{
+		ID:              uuid.New(),
+		UserID:          review.UserID,
+		RestrictionType: RestrictionTypePointsFreeze,
+		Reason:          LowRatingRestrictionReason,
+		CreatedAt:       time.Now(),
+		ExpiresAt:       time.Now().AddDate(0, 0, FreezeDurationDays),
+	}
+	if err := s.restrictionRepo.CreateRestriction(ctx, &restriction); err != nil {
+		return false, fmt.Errorf("create restriction: %w", err)
 	}
 
-	var points int
-	switch review.Rating {
+	slog.Info("created user restriction",
+		"user_id", review.UserID,
+		"type", RestrictionTypePointsFreeze,
+		"reason", restriction.Reason,
+		"expires_at", restriction.ExpiresAt,
+	)
+
+	return true, nil
+}
+
+func pointsForRating(rating int) int {
+	switch rating {
 	case 5:
-		points = 10
+		return 10
 	case 4:
-		points = 5
+		return 5
 	default:
-		points = 0
+		return 0
 	}
+}
 
-	if points > 0 {
-		if err := s.userRepo.AddPoints(ctx, review.UserID.String(), points); err != nil {
-			return fmt.Errorf("add points: %w", err)
-		}
+// GetReviews возвращает страницу опубликованных отзывов заведения; пустой Sort означает сортировку по дате, новые первыми
+func (s *reviewService) GetReviews(ctx context.Context, placeID string, filter model.ReviewFilter) (*pagination.Page[model.Review], error) {
+	if filter.Sort == "" {
+		filter.Sort = model.ReviewSortDateDesc
 	}
+	if !isReviewSort(filter.Sort) {
+		return nil, serviceErrors.ErrInvalidReviewSort
+	}
+	filter.Page = filter.Page.Normalize()
 
-	return nil
-}
-
-func (s *reviewService) GetReviews(ctx context.Context, placeID string, filter model.ReviewFilter) ([]model.Review, error) {
 	_, err := s.placeRepo.GetByID(ctx, placeID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -165,20 +345,78 @@ func (s *reviewService) GetReviews(ctx context.Context, placeID string, filter m
 		return nil, fmt.Errorf("check place existence: %w", err)
 	}
 
-	reviews, err := s.reviewRepo.FindReviews(ctx, placeID, filter)
+	page, err := s.reviewRepo.FindReviews(ctx, placeID, filter)
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			return nil, serviceErrors.ErrInvalidCursor
+		}
 		return nil, fmt.Errorf("find reviews: %w", err)
 	}
 
-	return reviews, nil
+	if err := s.photoService.AttachPhotos(ctx, page.Items); err != nil {
+		return nil, err
+	}
+
+	return page, nil
 }
 
+func isReviewSort(sort string) bool {
+	switch sort {
+	case model.ReviewSortDateDesc, model.ReviewSortDateAsc,
+		model.ReviewSortRatingDesc, model.ReviewSortRatingAsc,
+		model.ReviewSortHelpful:
+		return true
+	}
+	return false
+}
+
+// UpdateReview меняет текст и оценку отзыва автора и сохраняет новую редакцию в той же транзакции
 func (s *reviewService) UpdateReview(ctx context.Context, reviewID, userID string, content string, rating int) error {
 	if rating < 1 || rating > 5 {
 		return serviceErrors.ErrInvalidRating
 	}
 
-	err := s.reviewRepo.UpdateReview(ctx, reviewID, userID, content, rating)
+	reviewUID, err := uuid.Parse(reviewID)
+	if err != nil {
+		return serviceErrors.ErrReviewNotFound
+	}
+
+	verdict, err := s.filterContent(content)
+	if err != nil {
+		return err
+	}
+
+	flagReason := verdict.editFlagReason()
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var current *model.Review
+		if flagReason != nil {
+			// Блокировка строки: статус и баллы не должны измениться до списания
+			rev, err := s.reviewRepo.LockReviewByID(ctx, reviewUID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return sql.ErrNoRows
+				}
+				return fmt.Errorf("lock review: %w", err)
+			}
+			current = rev
+		}
+
+		// Помеченная фильтром правка возвращает опубликованный отзыв в очередь ожидающих
+		// с отдельной причиной; баллы списываются до повторного одобрения
+		err := s.reviewRepo.UpdateReview(ctx, reviewID, userID, content, rating, verdict.rule, flagReason)
+		if err != nil {
+			return err
+		}
+
+		if current != nil && current.Status == model.ReviewStatusPublished && current.Points > 0 {
+			if err := s.userRepo.RevokePoints(ctx, current.UserID.String(), current.Points); err != nil {
+				return fmt.Errorf("revoke points: %w", err)
+			}
+		}
+
+		return s.saveRevision(ctx, reviewUID, content, rating)
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return serviceErrors.ErrReviewNotFound
@@ -189,13 +427,27 @@ func (s *reviewService) UpdateReview(ctx context.Context, reviewID, userID strin
 	return nil
 }
 
+// DeleteReview удаляет отзыв автора вместе с фотографиями. Фото только помечаются удалёнными:
+// до конца окна отмены их вернёт RestoreReview, потом их сотрёт photo.PurgeWorker.
 func (s *reviewService) DeleteReview(ctx context.Context, reviewID, userID string) error {
-	err := s.reviewRepo.DeleteReview(ctx, reviewID, userID)
+	reviewUID, err := uuid.Parse(reviewID)
+	if err != nil {
+		return serviceErrors.ErrReviewNotFound
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.reviewRepo.DeleteReview(ctx, reviewID, userID); err != nil {
+			return err
+		}
+
+		return s.photoService.DeleteReviewPhotos(ctx, reviewUID, time.Now().UTC())
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return serviceErrors.ErrReviewNotFound
 		}
 		return fmt.Errorf("delete review: %w", err)
 	}
+
 	return nil
 }
@@ -0,0 +1,93 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model/rbac"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// GetRevisions возвращает редакции отзыва от первой к последней. Доступно автору и админам.
+func (s *reviewService) GetRevisions(ctx context.Context, actorID, actorRole, reviewID string) ([]model.ReviewRevision, error) {
+	reviewUID, err := uuid.Parse(reviewID)
+	if err != nil {
+		return nil, serviceErrors.ErrReviewNotFound
+	}
+
+	review, err := s.reviewRepo.GetReviewByID(ctx, reviewUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("get review: %w", err)
+	}
+
+	if actorRole != rbac.RoleAdmin && review.UserID.String() != actorID {
+		return nil, serviceErrors.ErrAccessDenied
+	}
+
+	revisions, err := s.revisionRepo.ListRevisions(ctx, reviewUID)
+	if err != nil {
+		return nil, fmt.Errorf("list revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// RestoreReview отменяет удаление отзыва автором, если с удаления прошло не больше ReviewUndoDeleteWindow.
+// Статус модерации и баллы не меняются; фотографии возвращаются вместе с отзывом.
+func (s *reviewService) RestoreReview(ctx context.Context, reviewID, userID string) error {
+	reviewUID, err := uuid.Parse(reviewID)
+	if err != nil {
+		return serviceErrors.ErrReviewNotRestorable
+	}
+
+	userUID, err := uuid.Parse(userID)
+	if err != nil {
+		return serviceErrors.ErrAccessDenied
+	}
+
+	window := s.cfg.ReviewUndoDeleteWindow
+	if window <= 0 {
+		return serviceErrors.ErrReviewNotRestorable
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.reviewRepo.RestoreReview(ctx, reviewUID, userUID, time.Now().UTC().Add(-window)); err != nil {
+			return err
+		}
+
+		return s.photoService.RestoreReviewPhotos(ctx, reviewUID)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return serviceErrors.ErrReviewNotRestorable
+		}
+		return fmt.Errorf("restore review: %w", err)
+	}
+
+	slog.Info("review restored", "review_id", reviewUID, "user_id", userUID)
+	return nil
+}
+
+// saveRevision добавляет редакцию с текущим текстом и оценкой отзыва
+func (s *reviewService) saveRevision(ctx context.Context, reviewID uuid.UUID, content string, rating int) error {
+	rev := &model.ReviewRevision{
+		ID:        uuid.New(),
+		ReviewID:  reviewID,
+		Content:   content,
+		Rating:    rating,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.revisionRepo.CreateRevision(ctx, rev); err != nil {
+		return fmt.Errorf("create revision: %w", err)
+	}
+	return nil
+}
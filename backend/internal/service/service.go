@@ -2,36 +2,68 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/labels"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/pagination"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/qr"
 )
 
 //go:generate go run go.uber.org/mock/mockgen -source=service.go -destination=../tests/integration/mocks/service_mocks.go -package=mocks
 
 type UserService interface {
-	Signup(ctx context.Context, name, email, password string) (string, error)
-	Login(ctx context.Context, email, password string) (string, error)
+	Signup(ctx context.Context, name, email, password string) (*model.AuthTokens, error)
+	Login(ctx context.Context, email, password string) (*model.AuthTokens, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (*model.AuthTokens, error)
+	Logout(ctx context.Context, sessionID string) error
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
 	GetUser(ctx context.Context, userID string) (*model.User, error)
 	UpdateUser(ctx context.Context, user model.User, password string) (*model.User, error)
 	DeleteUser(ctx context.Context, userID string) error
 	GetUserStats(ctx context.Context, userID string) (*model.UserStats, error)
+	ChangeRole(ctx context.Context, userID, role string) error
 }
 
 type PlaceService interface {
 	CreatePlace(ctx context.Context, place model.Place) (*model.Place, error)
-	GetAllPlaces(ctx context.Context) ([]model.Place, error)
+	GetPlaces(ctx context.Context, actorID, actorRole string, page pagination.Params) (*pagination.Page[model.Place], error)
 }
 
 type ReviewService interface {
-	SubmitReview(ctx context.Context, review model.Review, token string) error
-	GetReviews(ctx context.Context, placeID string, filter model.ReviewFilter) ([]model.Review, error)
+	SubmitReview(ctx context.Context, review model.Review, token, clientIP string) (*model.Review, error)
+	GetReviews(ctx context.Context, placeID string, filter model.ReviewFilter) (*pagination.Page[model.Review], error)
 	UpdateReview(ctx context.Context, reviewID, userID string, content string, rating int) error
 	DeleteReview(ctx context.Context, reviewID, userID string) error
+	GetRevisions(ctx context.Context, actorID, actorRole, reviewID string) ([]model.ReviewRevision, error)
+	RestoreReview(ctx context.Context, reviewID, userID string) error
+	GetLabelReport(ctx context.Context, actorID, actorRole, placeID, dimension string, from, to *time.Time) ([]model.LabelStat, error)
+	GetModerationQueue(ctx context.Context, filter model.ModerationFilter) (*pagination.Page[model.Review], error)
+	ApproveReview(ctx context.Context, moderatorID, reviewID string) (*model.Review, error)
+	RejectReview(ctx context.Context, moderatorID, reviewID, reason string) (*model.Review, error)
+	HideReview(ctx context.Context, moderatorID, reviewID, reason string) (*model.Review, error)
+	GetModerationSettings(ctx context.Context, placeID string) (*model.ModerationSettings, error)
+	UpdateModerationMode(ctx context.Context, placeID string, mode *string) (*model.ModerationSettings, error)
 }
 
 type TokenService interface {
-	GenerateTokens(ctx context.Context, placeID string, count int) (*model.GenerateTokensResult, error)
-	CheckAndRefillTokens(ctx context.Context, placeID string) error
+	GenerateTokens(ctx context.Context, placeID string, count int, labels model.TokenLabels) (*model.GenerateTokensResult, error)
+	CheckAndRefillTokens(ctx context.Context, placeID string) (int, error)
+	GetTokenInfo(ctx context.Context, tokenValue string) (*model.ReviewTokenInfo, error)
+	RenderTokenQR(ctx context.Context, placeID, tokenValue, format string, size int) (*qr.Image, error)
+	PrintTokens(ctx context.Context, actorID, actorRole, placeID string, count int, layout labels.Layout) ([]byte, error)
+	ListTokens(ctx context.Context, placeID string, filter model.TokenFilter) (*model.TokenPage, error)
+	GetTokenStats(ctx context.Context, placeID string) (*model.TokenStats, error)
+	RevokeToken(ctx context.Context, tokenID string) error
+	AssignPIN(ctx context.Context, tokenID string) (string, error)
+	RevokeTokens(ctx context.Context, tokenIDs []string) (int, error)
+	ExtendTokens(ctx context.Context, tokenIDs []string, ttl time.Duration) (int, error)
+	GetTokenPolicy(ctx context.Context, placeID string) (*model.TokenPolicySettings, error)
+	UpdateTokenPolicy(ctx context.Context, placeID string, policy model.TokenPolicy) (*model.TokenPolicySettings, error)
+	CreateBatchJob(ctx context.Context, actorID, placeID string, count int) (*model.TokenBatchJob, error)
+	GetBatchJob(ctx context.Context, jobID string) (*model.TokenBatchJob, error)
+	ExportBatchJob(ctx context.Context, jobID, format string) (*model.TokenExport, error)
 }
 
 type AdminService interface {
@@ -47,5 +79,59 @@ type LeaderboardService interface {
 type BonusService interface {
 	RedeemBonus(ctx context.Context, userID, rewardType string) (*model.BonusReward, error)
 	GetUserBonuses(ctx context.Context, userID string) ([]model.BonusReward, error)
-	ValidateBonus(ctx context.Context, qrToken string) error
+	ValidateBonus(ctx context.Context, validatorID, validatorRole, placeID, qrToken string) error
+	RenderBonusQR(ctx context.Context, userID, qrToken, format string, size int) (*qr.Image, error)
+}
+
+type KioskService interface {
+	EnableKiosk(ctx context.Context, actorID, actorRole, placeID string, step time.Duration) (*model.Kiosk, error)
+	DisableKiosk(ctx context.Context, actorID, actorRole, placeID string) error
+	CurrentCode(ctx context.Context, actorID, actorRole, placeID string) (*model.KioskCode, error)
+	RenderCodeQR(ctx context.Context, actorID, actorRole, placeID, format string, size int) (*qr.Image, error)
+}
+
+type LinkService interface {
+	Resolve(ctx context.Context, code, clientIP, userAgent string) (string, error)
+	MarkOpened(ctx context.Context, userID, scanID, sessionKey string) error
+	GetFunnel(ctx context.Context, actorID, actorRole, placeID string, from, to *time.Time) (*model.ScanFunnel, error)
+}
+
+type ReplyService interface {
+	CreateReply(ctx context.Context, actorID, actorRole, reviewID, content string) (*model.ReviewReply, error)
+	UpdateReply(ctx context.Context, actorID, actorRole, reviewID, content string) (*model.ReviewReply, error)
+	DeleteReply(ctx context.Context, actorID, actorRole, reviewID string) error
+	ListTemplates(ctx context.Context, actorID, actorRole, placeID string) ([]model.ReplyTemplate, error)
+	CreateTemplate(ctx context.Context, actorID, actorRole, placeID, title, content string) (*model.ReplyTemplate, error)
+	DeleteTemplate(ctx context.Context, actorID, actorRole, placeID, templateID string) error
+	GetReplyStats(ctx context.Context, actorID, actorRole, placeID string, from, to *time.Time) (*model.ReplyStats, error)
+}
+
+type PhotoService interface {
+	UploadPhotos(ctx context.Context, userID, reviewID string, uploads []model.PhotoUpload) ([]model.ReviewPhoto, error)
+	DeletePhoto(ctx context.Context, userID, reviewID, photoID string) error
+	OpenMedia(ctx context.Context, key string) (io.ReadCloser, error)
+	MaxUploadSize() int64
+}
+
+type VoteService interface {
+	Vote(ctx context.Context, userID, reviewID string) (*model.ReviewVotes, error)
+	Unvote(ctx context.Context, userID, reviewID string) (*model.ReviewVotes, error)
+}
+
+type ReportService interface {
+	CreateReport(ctx context.Context, userID, reviewID, reason, comment string) (*model.ReviewReport, error)
+	ListReportedReviews(ctx context.Context, filter model.ReportFilter) ([]model.ReportedReview, error)
+	ListReviewReports(ctx context.Context, reviewID string) ([]model.ReviewReport, error)
+	ResolveReports(ctx context.Context, moderatorID, reviewID, action string) (*model.ReportResolution, error)
+}
+
+type POSService interface {
+	RotateSecret(ctx context.Context, actorID, actorRole, placeID string) (*model.POSIntegration, error)
+	IssueReceiptToken(ctx context.Context, event model.ReceiptEvent) (*model.ReceiptToken, error)
+}
+
+type MemberService interface {
+	AddMember(ctx context.Context, actorID, actorRole, placeID, email, role string) (*model.PlaceMember, error)
+	RemoveMember(ctx context.Context, actorID, actorRole, placeID, userID string) error
+	ListMembers(ctx context.Context, actorID, actorRole, placeID string) ([]model.PlaceMember, error)
 }
@@ -0,0 +1,133 @@
+package token
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// DefaultAlphabet — без похожих символов (0/O, 1/I/L), чтобы токен было проще ввести вручную
+	DefaultAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+	// maxTokenLength ограничен колонкой review_tokens.token_value VARCHAR(20)
+	maxTokenLength = 20
+)
+
+var ErrInvalidGeneratorConfig = errors.New("invalid token generator config")
+
+// Generator выдаёт значения токенов отзывов и проверяет формат значений, введённых вручную
+type Generator interface {
+	Generate() (string, error)
+	Valid(value string) bool
+}
+
+// RandomGenerator генерирует токены из crypto/rand с опциональным контрольным символом
+type RandomGenerator struct {
+	alphabet  []rune
+	index     map[rune]int
+	length    int
+	checkChar bool
+}
+
+func NewRandomGenerator(alphabet string, length int, checkChar bool) (*RandomGenerator, error) {
+	runes := []rune(alphabet)
+	if len(runes) < 2 || length < 1 {
+		return nil, ErrInvalidGeneratorConfig
+	}
+
+	total := length
+	if checkChar {
+		total++
+	}
+	if total > maxTokenLength {
+		return nil, fmt.Errorf("%w: token length %d exceeds %d", ErrInvalidGeneratorConfig, total, maxTokenLength)
+	}
+
+	index := make(map[rune]int, len(runes))
+	for i, r := range runes {
+		if _, dup := index[r]; dup {
+			return nil, fmt.Errorf("%w: duplicate symbol %q in alphabet", ErrInvalidGeneratorConfig, r)
+		}
+		index[r] = i
+	}
+
+	return &RandomGenerator{
+		alphabet:  runes,
+		index:     index,
+		length:    length,
+		checkChar: checkChar,
+	}, nil
+}
+
+func (g *RandomGenerator) Generate() (string, error) {
+	n := big.NewInt(int64(len(g.alphabet)))
+
+	var b strings.Builder
+	for i := 0; i < g.length; i++ {
+		k, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return "", fmt.Errorf("read random: %w", err)
+		}
+		b.WriteRune(g.alphabet[k.Int64()])
+	}
+
+	value := b.String()
+	if g.checkChar {
+		value += string(g.alphabet[g.checkIndex(value)])
+	}
+
+	return value, nil
+}
+
+// Valid проверяет контрольный символ токена, введённого вручную.
+// Без контрольного символа проверяются только длина и алфавит.
+func (g *RandomGenerator) Valid(value string) bool {
+	runes := []rune(value)
+
+	want := g.length
+	if g.checkChar {
+		want++
+	}
+	if len(runes) != want {
+		return false
+	}
+
+	for _, r := range runes {
+		if _, ok := g.index[r]; !ok {
+			return false
+		}
+	}
+
+	if !g.checkChar {
+		return true
+	}
+
+	body := string(runes[:g.length])
+	return g.alphabet[g.checkIndex(body)] == runes[g.length]
+}
+
+// checkIndex считает контрольный символ по алгоритму Луна mod N:
+// ловит любую одиночную опечатку и перестановку соседних символов
+func (g *RandomGenerator) checkIndex(body string) int {
+	n := len(g.alphabet)
+	runes := []rune(body)
+
+	factor := 2
+	sum := 0
+	for i := len(runes) - 1; i >= 0; i-- {
+		addend := factor * g.index[runes[i]]
+		addend = addend/n + addend%n
+		sum += addend
+
+		if factor == 2 {
+			factor = 1
+		} else {
+			factor = 2
+		}
+	}
+
+	return (n - sum%n) % n
+}
@@ -0,0 +1,116 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/configs"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	repo "github.com/kulikovroman08/reviewlink-backend/internal/repository"
+)
+
+const (
+	maxJobChunkSize = 5000
+	// staleJobAfter — через сколько без прогресса задача в running считается брошенной
+	staleJobAfter = 5 * time.Minute
+)
+
+// batchJobMetrics — счётчики воркера пачек, доступны в /debug/vars под ключом token_jobs
+var batchJobMetrics = expvar.NewMap("token_jobs")
+
+// BatchJobWorker по очереди выполняет задачи из token_batch_jobs
+type BatchJobWorker struct {
+	service   *Service
+	repo      repo.TokenJobRepository
+	interval  time.Duration
+	chunkSize int
+}
+
+func NewBatchJobWorker(service *Service, repo repo.TokenJobRepository, cfg *configs.Config) *BatchJobWorker {
+	chunkSize := cfg.TokenJobChunkSize
+	if chunkSize < 1 || chunkSize > maxJobChunkSize {
+		chunkSize = maxJobChunkSize
+	}
+
+	return &BatchJobWorker{
+		service:   service,
+		repo:      repo,
+		interval:  cfg.TokenJobPollInterval,
+		chunkSize: chunkSize,
+	}
+}
+
+// Run опрашивает очередь каждые interval, пока не отменён ctx
+func (w *BatchJobWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	slog.Info("token job worker started", "interval", w.interval, "chunk_size", w.chunkSize)
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("token job worker stopped")
+			return
+		case <-ticker.C:
+			if _, err := w.RunOnce(ctx); err != nil {
+				batchJobMetrics.Add("poll_errors", 1)
+				slog.Error("token job poll failed", "error", err)
+			}
+		}
+	}
+}
+
+// RunOnce выполняет задачи, пока в очереди есть свободные, и возвращает число обработанных
+func (w *BatchJobWorker) RunOnce(ctx context.Context) (int, error) {
+	processed := 0
+
+	for {
+		job, err := w.repo.ClaimJob(ctx, staleJobAfter)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return processed, nil
+			}
+			return processed, err
+		}
+
+		w.process(ctx, job)
+		processed++
+	}
+}
+
+func (w *BatchJobWorker) process(ctx context.Context, job *model.TokenBatchJob) {
+	started := job.Generated
+	slog.Info("token job started", "job_id", job.ID, "place_id", job.PlaceID, "total", job.Total, "generated", job.Generated)
+
+	runErr := w.service.runBatchJob(ctx, job, w.chunkSize)
+	batchJobMetrics.Add("tokens_generated", int64(job.Generated-started))
+
+	// При остановке сервиса задача остаётся в running и продолжится после staleJobAfter
+	if ctx.Err() != nil {
+		slog.Warn("token job interrupted", "job_id", job.ID, "generated", job.Generated)
+		return
+	}
+
+	status := model.TokenJobStatusDone
+	var errText *string
+	if runErr != nil {
+		status = model.TokenJobStatusFailed
+		msg := runErr.Error()
+		errText = &msg
+
+		batchJobMetrics.Add("jobs_failed", 1)
+		slog.Error("token job failed", "job_id", job.ID, "generated", job.Generated, "error", runErr)
+	} else {
+		batchJobMetrics.Add("jobs_done", 1)
+		slog.Info("token job done", "job_id", job.ID, "generated", job.Generated)
+	}
+
+	if err := w.repo.FinishJob(ctx, job.ID, status, errText); err != nil {
+		slog.Error("token job finish failed", "job_id", job.ID, "error", err)
+	}
+}
@@ -0,0 +1,239 @@
+package token
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/qr"
+)
+
+const (
+	// MaxBatchJobTokens — предел одной фоновой задачи генерации
+	MaxBatchJobTokens = 100000
+
+	ExportFormatCSV = "csv"
+	ExportFormatZIP = "zip"
+
+	exportPageSize = 1000
+)
+
+// CreateBatchJob ставит в очередь фоновую генерацию count токенов. Срок действия фиксируется
+// по политике заведения в момент постановки, чтобы все токены пачки истекали одновременно.
+func (s *Service) CreateBatchJob(ctx context.Context, actorID, placeID string, count int) (*model.TokenBatchJob, error) {
+	placeUID, err := uuid.Parse(placeID)
+	if err != nil {
+		return nil, serviceErrors.ErrInvalidPlaceID
+	}
+
+	if count < 1 || count > MaxBatchJobTokens {
+		return nil, serviceErrors.ErrInvalidTokenCount
+	}
+
+	policy, err := s.policyFor(ctx, placeID)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &model.TokenBatchJob{
+		ID:        uuid.New(),
+		PlaceID:   placeUID,
+		Total:     count,
+		Status:    model.TokenJobStatusPending,
+		ExpiresAt: time.Now().Add(policy.TTL),
+	}
+	if actorUID, err := uuid.Parse(actorID); err == nil {
+		job.RequestedBy = &actorUID
+	}
+
+	if err := s.jobRepo.CreateJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("create token job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (s *Service) GetBatchJob(ctx context.Context, jobID string) (*model.TokenBatchJob, error) {
+	uid, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, serviceErrors.ErrTokenJobNotFound
+	}
+
+	job, err := s.jobRepo.GetJob(ctx, uid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrTokenJobNotFound
+		}
+		return nil, fmt.Errorf("get token job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ExportBatchJob готовит выгрузку завершённой задачи: CSV со значениями и ссылками
+// либо ZIP с тем же CSV и PNG-изображением QR-кода на каждый токен
+func (s *Service) ExportBatchJob(ctx context.Context, jobID, format string) (*model.TokenExport, error) {
+	format = strings.ToLower(format)
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	if format != ExportFormatCSV && format != ExportFormatZIP {
+		return nil, serviceErrors.ErrInvalidExportFormat
+	}
+
+	job, err := s.GetBatchJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status != model.TokenJobStatusDone {
+		return nil, serviceErrors.ErrTokenJobNotReady
+	}
+
+	if format == ExportFormatZIP {
+		return &model.TokenExport{
+			Filename:    "tokens-" + job.ID.String() + ".zip",
+			ContentType: "application/zip",
+			Write: func(w io.Writer) error {
+				return s.writeZIP(ctx, job, w)
+			},
+		}, nil
+	}
+
+	return &model.TokenExport{
+		Filename:    "tokens-" + job.ID.String() + ".csv",
+		ContentType: "text/csv; charset=utf-8",
+		Write: func(w io.Writer) error {
+			return s.writeCSV(ctx, job, w)
+		},
+	}, nil
+}
+
+// runBatchJob догенерирует недостающие токены задачи порциями по chunkSize. Каждая порция
+// вставляется в одной транзакции с обновлением счётчика, поэтому после сбоя работа продолжается с места остановки.
+func (s *Service) runBatchJob(ctx context.Context, job *model.TokenBatchJob, chunkSize int) error {
+	emptyChunks := 0
+
+	for job.Generated < job.Total {
+		tokens, err := s.newTokens(job.PlaceID, min(chunkSize, job.Total-job.Generated), job.ExpiresAt)
+		if err != nil {
+			return fmt.Errorf("generate tokens: %w", err)
+		}
+		for i := range tokens {
+			tokens[i].BatchJobID = &job.ID
+		}
+
+		var inserted int
+		err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			values, err := s.repo.CreateTokens(ctx, tokens)
+			if err != nil {
+				return fmt.Errorf("create tokens: %w", err)
+			}
+			inserted = len(values)
+
+			return s.jobRepo.AddProgress(ctx, job.ID, inserted)
+		})
+		if err != nil {
+			return err
+		}
+
+		// Совпавшие значения просто догенерируются следующей порцией, но пустые порции подряд означают исчерпанный алфавит
+		if inserted == 0 {
+			emptyChunks++
+			if emptyChunks == maxGenerateAttempts {
+				return serviceErrors.ErrTokenGenerationFailed
+			}
+			continue
+		}
+		emptyChunks = 0
+
+		job.Generated += inserted
+	}
+
+	return nil
+}
+
+func (s *Service) writeCSV(ctx context.Context, job *model.TokenBatchJob, w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"token", "url", "expires_at"}); err != nil {
+		return err
+	}
+
+	err := s.eachJobToken(ctx, job, func(t model.ReviewToken) error {
+		return cw.Write([]string{
+			t.Token,
+			ShortURL(s.cfg.PublicBaseURL, t.Token),
+			t.ExpiresAt.Format(time.RFC3339),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeZIP пишет архив потоком: сначала tokens.csv, затем qr/<token>.png вторым проходом по токенам
+func (s *Service) writeZIP(ctx context.Context, job *model.TokenBatchJob, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	csvFile, err := zw.Create("tokens.csv")
+	if err != nil {
+		return err
+	}
+	if err := s.writeCSV(ctx, job, csvFile); err != nil {
+		return err
+	}
+
+	err = s.eachJobToken(ctx, job, func(t model.ReviewToken) error {
+		img, err := s.renderer.Render(ShortURL(s.cfg.PublicBaseURL, t.Token), qr.FormatPNG, 0)
+		if err != nil {
+			return fmt.Errorf("render token qr: %w", err)
+		}
+
+		// PNG уже сжат, повторное сжатие только тратит процессор
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: "qr/" + t.Token + ".png", Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(img.Data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func (s *Service) eachJobToken(ctx context.Context, job *model.TokenBatchJob, fn func(model.ReviewToken) error) error {
+	after := ""
+	for {
+		tokens, err := s.jobRepo.ListJobTokens(ctx, job.ID, after, exportPageSize)
+		if err != nil {
+			return fmt.Errorf("list job tokens: %w", err)
+		}
+
+		for _, t := range tokens {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+
+		if len(tokens) < exportPageSize {
+			return nil
+		}
+		after = tokens[len(tokens)-1].Token
+	}
+}
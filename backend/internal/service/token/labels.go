@@ -0,0 +1,57 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+const (
+	MaxTableLabelLength = 32
+	MaxCampaignLength   = 64
+)
+
+// normalizeLabels обрезает пробелы у меток, пустые превращает в nil и проверяет,
+// что сотрудник состоит в заведении
+func (s *Service) normalizeLabels(ctx context.Context, placeID string, labels model.TokenLabels) (model.TokenLabels, error) {
+	var err error
+	if labels.Table, err = normalizeLabel(labels.Table, MaxTableLabelLength); err != nil {
+		return model.TokenLabels{}, err
+	}
+	if labels.Campaign, err = normalizeLabel(labels.Campaign, MaxCampaignLength); err != nil {
+		return model.TokenLabels{}, err
+	}
+
+	if labels.StaffID != nil {
+		if _, err := s.memberRepo.GetMember(ctx, placeID, labels.StaffID.String()); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return model.TokenLabels{}, serviceErrors.ErrInvalidTokenLabels
+			}
+			return model.TokenLabels{}, fmt.Errorf("check staff membership: %w", err)
+		}
+	}
+
+	return labels, nil
+}
+
+func normalizeLabel(value *string, limit int) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if utf8.RuneCountInString(trimmed) > limit {
+		return nil, serviceErrors.ErrInvalidTokenLabels
+	}
+
+	return &trimmed, nil
+}
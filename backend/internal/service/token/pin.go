@@ -0,0 +1,124 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+const (
+	pinAlphabet = "0123456789"
+
+	// maxPINLength ограничен колонкой review_tokens.pin VARCHAR(8)
+	maxPINLength = 8
+)
+
+// IsPIN сообщает, что value — PIN-код длины length, а не значение токена
+func IsPIN(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidatePINLength проверяет длину PIN из конфигурации. PIN и значение токена приходят в одном поле
+// и различаются по длине (IsPIN), поэтому токен той же длины ушёл бы в ветку PIN — такую конфигурацию
+// отклоняем при старте.
+func ValidatePINLength(pinLength, tokenLength int, checkChar bool) error {
+	if pinLength < 1 || pinLength > maxPINLength {
+		return fmt.Errorf("%w: pin length %d", ErrInvalidGeneratorConfig, pinLength)
+	}
+
+	if checkChar {
+		tokenLength++
+	}
+	if pinLength == tokenLength {
+		return fmt.Errorf("%w: pin length %d equals token length", ErrInvalidGeneratorConfig, pinLength)
+	}
+
+	return nil
+}
+
+// AssignPIN выдаёт активному токену PIN для ручного ввода. Повторный вызов возвращает уже выданный PIN;
+// использованный, отозванный или несуществующий токен — ErrTokenNotFound.
+func (s *Service) AssignPIN(ctx context.Context, tokenID string) (string, error) {
+	id, err := uuid.Parse(tokenID)
+	if err != nil {
+		return "", serviceErrors.ErrTokenNotFound
+	}
+
+	return s.assignPIN(ctx, id)
+}
+
+// assignPIN подбирает PIN, свободный среди активных токенов заведения, не более maxGenerateAttempts раз.
+// PIN, занятый истёкшим токеном, освобождается и выдаётся заново.
+func (s *Service) assignPIN(ctx context.Context, tokenID uuid.UUID) (string, error) {
+	if s.cfg.PINLength < 1 || s.cfg.PINLength > maxPINLength {
+		return "", fmt.Errorf("%w: pin length %d", ErrInvalidGeneratorConfig, s.cfg.PINLength)
+	}
+
+	generator, err := NewRandomGenerator(pinAlphabet, s.cfg.PINLength, false)
+	if err != nil {
+		return "", fmt.Errorf("create pin generator: %w", err)
+	}
+
+	pin := ""
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		if pin == "" {
+			if pin, err = generator.Generate(); err != nil {
+				return "", fmt.Errorf("generate pin: %w", err)
+			}
+		}
+
+		assigned, ok, err := s.repo.SetPIN(ctx, tokenID, pin)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return "", serviceErrors.ErrTokenNotFound
+			}
+			return "", fmt.Errorf("set pin: %w", err)
+		}
+		if ok {
+			return assigned, nil
+		}
+
+		released, err := s.repo.ReleaseExpiredPIN(ctx, tokenID, pin)
+		if err != nil {
+			return "", fmt.Errorf("release expired pin: %w", err)
+		}
+		if released == 0 {
+			log.Printf("[tokens] pin collision for token %s, regenerating", tokenID)
+			pin = ""
+		}
+	}
+
+	return "", serviceErrors.ErrTokenGenerationFailed
+}
+
+// ensurePIN выдаёт PIN токену, у которого его ещё нет. Использованному или отозванному токену PIN не нужен.
+func (s *Service) ensurePIN(ctx context.Context, token *model.ReviewToken) error {
+	if token.PIN != nil {
+		return nil
+	}
+
+	pin, err := s.assignPIN(ctx, token.ID)
+	if err != nil {
+		if errors.Is(err, serviceErrors.ErrTokenNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token.PIN = &pin
+	return nil
+}
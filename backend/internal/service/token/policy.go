@@ -0,0 +1,125 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// Границы политики токенов заведения
+const (
+	MinTokenTTL        = time.Hour
+	MaxTokenTTL        = 365 * 24 * time.Hour
+	MaxInitialCount    = 100
+	MaxRefillThreshold = 1000
+	MaxRefillBatch     = 100
+)
+
+// ValidatePolicy проверяет заданные поля политики; пустые поля допустимы всегда
+func ValidatePolicy(policy model.TokenPolicy) error {
+	if ttl := policy.TTL; ttl != nil && (*ttl < MinTokenTTL || *ttl > MaxTokenTTL) {
+		return serviceErrors.ErrInvalidTokenPolicy
+	}
+	if n := policy.InitialCount; n != nil && (*n < 0 || *n > MaxInitialCount) {
+		return serviceErrors.ErrInvalidTokenPolicy
+	}
+	if n := policy.RefillThreshold; n != nil && (*n < 0 || *n > MaxRefillThreshold) {
+		return serviceErrors.ErrInvalidTokenPolicy
+	}
+	if n := policy.RefillBatch; n != nil && (*n < 1 || *n > MaxRefillBatch) {
+		return serviceErrors.ErrInvalidTokenPolicy
+	}
+	return nil
+}
+
+// ResolvePolicy дополняет политику заведения глобальными значениями из конфига
+func (s *Service) ResolvePolicy(policy model.TokenPolicy) model.ResolvedTokenPolicy {
+	resolved := model.ResolvedTokenPolicy{
+		TTL:             s.cfg.TokenTTL,
+		InitialCount:    s.cfg.TokensAutoCount,
+		RefillThreshold: s.cfg.TokensThreshold,
+		RefillBatch:     s.cfg.TokensBatchSize,
+	}
+
+	if policy.TTL != nil {
+		resolved.TTL = *policy.TTL
+	}
+	if policy.InitialCount != nil {
+		resolved.InitialCount = *policy.InitialCount
+	}
+	if policy.RefillThreshold != nil {
+		resolved.RefillThreshold = *policy.RefillThreshold
+	}
+	if policy.RefillBatch != nil {
+		resolved.RefillBatch = *policy.RefillBatch
+	}
+
+	return resolved
+}
+
+func (s *Service) GetTokenPolicy(ctx context.Context, placeID string) (*model.TokenPolicySettings, error) {
+	place, err := s.getPlace(ctx, placeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TokenPolicySettings{
+		Custom:    place.TokenPolicy,
+		Effective: s.ResolvePolicy(place.TokenPolicy),
+	}, nil
+}
+
+// UpdateTokenPolicy заменяет политику заведения целиком: не переданные поля снова берутся из конфига
+func (s *Service) UpdateTokenPolicy(ctx context.Context, placeID string, policy model.TokenPolicy) (*model.TokenPolicySettings, error) {
+	if _, err := uuid.Parse(placeID); err != nil {
+		return nil, serviceErrors.ErrInvalidPlaceID
+	}
+
+	if err := ValidatePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.placeRepo.UpdateTokenPolicy(ctx, placeID, policy); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrPlaceNotFound
+		}
+		return nil, fmt.Errorf("update token policy: %w", err)
+	}
+
+	return &model.TokenPolicySettings{
+		Custom:    policy,
+		Effective: s.ResolvePolicy(policy),
+	}, nil
+}
+
+// policyFor возвращает действующую политику заведения
+func (s *Service) policyFor(ctx context.Context, placeID string) (model.ResolvedTokenPolicy, error) {
+	place, err := s.getPlace(ctx, placeID)
+	if err != nil {
+		return model.ResolvedTokenPolicy{}, err
+	}
+
+	return s.ResolvePolicy(place.TokenPolicy), nil
+}
+
+func (s *Service) getPlace(ctx context.Context, placeID string) (*model.Place, error) {
+	if _, err := uuid.Parse(placeID); err != nil {
+		return nil, serviceErrors.ErrInvalidPlaceID
+	}
+
+	place, err := s.placeRepo.GetByID(ctx, placeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrPlaceNotFound
+		}
+		return nil, fmt.Errorf("get place: %w", err)
+	}
+
+	return place, nil
+}
@@ -0,0 +1,105 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+// IssueReceiptToken выпускает один токен на чек. Повторное событие о том же чеке возвращает
+// уже выпущенный токен с Created=false; чек с тем же номером, но другой суммой или временем покупки — ErrReceiptConflict.
+func (s *Service) IssueReceiptToken(ctx context.Context, placeID uuid.UUID, receipt model.Receipt) (*model.ReceiptToken, error) {
+	if existing, err := s.receiptToken(ctx, placeID, receipt); err == nil || !errors.Is(err, pgx.ErrNoRows) {
+		return existing, err
+	}
+
+	policy, err := s.policyFor(ctx, placeID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	receipt.PurchasedAt = receipt.PurchasedAt.UTC()
+
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		value, err := s.generator.Generate()
+		if err != nil {
+			return nil, fmt.Errorf("generate token: %w", err)
+		}
+
+		token := model.ReviewToken{
+			ID:        uuid.New(),
+			PlaceID:   placeID,
+			Token:     value,
+			ExpiresAt: time.Now().Add(policy.TTL),
+			Receipt:   &receipt,
+		}
+
+		inserted, err := s.repo.CreateReceiptToken(ctx, token)
+		if err != nil {
+			return nil, fmt.Errorf("create receipt token: %w", err)
+		}
+		if inserted {
+			if err := s.ensurePIN(ctx, &token); err != nil {
+				return nil, fmt.Errorf("assign receipt pin: %w", err)
+			}
+			return s.receiptTokenResult(&token, true), nil
+		}
+
+		// Вставки не было: либо тот же чек пришёл параллельно, либо совпало значение токена
+		existing, err := s.receiptToken(ctx, placeID, receipt)
+		if err == nil || !errors.Is(err, pgx.ErrNoRows) {
+			return existing, err
+		}
+	}
+
+	return nil, serviceErrors.ErrTokenGenerationFailed
+}
+
+// receiptToken ищет уже выпущенный по чеку токен; pgx.ErrNoRows — чек ещё не присылали
+func (s *Service) receiptToken(ctx context.Context, placeID uuid.UUID, receipt model.Receipt) (*model.ReceiptToken, error) {
+	token, err := s.repo.GetTokenByReceipt(ctx, placeID, receipt.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("get receipt token: %w", err)
+	}
+
+	if !sameReceipt(*token.Receipt, receipt) {
+		return nil, serviceErrors.ErrReceiptConflict
+	}
+
+	// Токен мог быть выпущен до появления PIN-кодов или без PIN из-за сбоя при выдаче
+	if err := s.ensurePIN(ctx, token); err != nil {
+		return nil, fmt.Errorf("assign receipt pin: %w", err)
+	}
+
+	return s.receiptTokenResult(token, false), nil
+}
+
+// sameReceipt сравнивает все подписанные поля чека. Время сравнивается с точностью до микросекунд:
+// точнее TIMESTAMP в Postgres не хранит.
+func sameReceipt(stored, incoming model.Receipt) bool {
+	return stored.ID == incoming.ID &&
+		stored.Amount == incoming.Amount &&
+		stored.PurchasedAt.Truncate(time.Microsecond).Equal(incoming.PurchasedAt.Truncate(time.Microsecond))
+}
+
+func (s *Service) receiptTokenResult(token *model.ReviewToken, created bool) *model.ReceiptToken {
+	result := &model.ReceiptToken{
+		Token:     token.Token,
+		URL:       ShortURL(s.cfg.PublicBaseURL, token.Token),
+		ExpiresAt: token.ExpiresAt,
+		Created:   created,
+	}
+	if token.PIN != nil {
+		result.PIN = *token.PIN
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log/slog"
+	"time"
+
+	"github.com/kulikovroman08/reviewlink-backend/configs"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	repo "github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+const (
+	refillJobsPerPoll = 20
+	maxRefillBackoff  = 10 * time.Minute
+)
+
+// refillMetrics — счётчики воркера, доступны в /debug/vars под ключом token_refill
+var refillMetrics = expvar.NewMap("token_refill")
+
+// RecordInitialFailure учитывает в token_refill стартовую пачку токенов, которую не удалось сгенерировать
+// при создании заведения: такие заведения остаются без токенов до ближайшей догенерации
+func RecordInitialFailure() {
+	refillMetrics.Add("initial_failures", 1)
+}
+
+// RefillWorker разбирает очередь token_refill_jobs и догенерирует токены заведений в фоне
+type RefillWorker struct {
+	service     *Service
+	repo        repo.TokenRefillRepository
+	interval    time.Duration
+	backoff     time.Duration
+	maxAttempts int
+}
+
+func NewRefillWorker(service *Service, repo repo.TokenRefillRepository, cfg *configs.Config) *RefillWorker {
+	return &RefillWorker{
+		service:     service,
+		repo:        repo,
+		interval:    cfg.TokenRefillInterval,
+		backoff:     cfg.TokenRefillBackoff,
+		maxAttempts: cfg.TokenRefillMaxAttempts,
+	}
+}
+
+// Run опрашивает очередь каждые interval, пока не отменён ctx
+func (w *RefillWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	slog.Info("token refill worker started", "interval", w.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("token refill worker stopped")
+			return
+		case <-ticker.C:
+			if _, err := w.RunOnce(ctx); err != nil {
+				refillMetrics.Add("poll_errors", 1)
+				slog.Error("token refill poll failed", "error", err)
+			}
+		}
+	}
+}
+
+// RunOnce обрабатывает задачи, время которых наступило, и возвращает число завершённых
+func (w *RefillWorker) RunOnce(ctx context.Context) (int, error) {
+	jobs, err := w.repo.ListDueJobs(ctx, refillJobsPerPoll)
+	if err != nil {
+		return 0, err
+	}
+
+	completed := 0
+	for _, job := range jobs {
+		if w.process(ctx, job) {
+			completed++
+		}
+	}
+
+	return completed, nil
+}
+
+func (w *RefillWorker) process(ctx context.Context, job model.RefillJob) bool {
+	placeID := job.PlaceID.String()
+
+	generated, err := w.service.CheckAndRefillTokens(ctx, placeID)
+	switch {
+	case errors.Is(err, serviceErrors.ErrRefillInProgress):
+		// Заведение обрабатывает другой воркер: задача останется в очереди до следующего опроса
+		refillMetrics.Add("jobs_locked", 1)
+		return false
+	case errors.Is(err, serviceErrors.ErrPlaceNotFound):
+		refillMetrics.Add("jobs_dropped", 1)
+		slog.Warn("token refill dropped: place not found", "place_id", placeID)
+		return w.complete(ctx, job)
+	case err != nil:
+		w.retry(ctx, job, err)
+		return false
+	}
+
+	if generated > 0 {
+		refillMetrics.Add("tokens_generated", int64(generated))
+		slog.Info("token refill generated tokens", "place_id", placeID, "count", generated)
+	}
+
+	refillMetrics.Add("jobs_completed", 1)
+	return w.complete(ctx, job)
+}
+
+func (w *RefillWorker) complete(ctx context.Context, job model.RefillJob) bool {
+	if err := w.repo.CompleteJob(ctx, job); err != nil {
+		slog.Error("token refill complete job failed", "place_id", job.PlaceID, "error", err)
+		return false
+	}
+	return true
+}
+
+func (w *RefillWorker) retry(ctx context.Context, job model.RefillJob, cause error) {
+	attempts := job.Attempts + 1
+
+	if attempts >= w.maxAttempts {
+		refillMetrics.Add("jobs_dropped", 1)
+		slog.Error("token refill failed, giving up",
+			"place_id", job.PlaceID,
+			"attempts", attempts,
+			"error", cause,
+		)
+		w.complete(ctx, job)
+		return
+	}
+
+	delay := w.backoffFor(attempts)
+
+	refillMetrics.Add("jobs_retried", 1)
+	slog.Warn("token refill failed, retrying",
+		"place_id", job.PlaceID,
+		"attempts", attempts,
+		"retry_in", delay,
+		"error", cause,
+	)
+
+	if err := w.repo.RetryJob(ctx, job.PlaceID, attempts, delay, cause.Error()); err != nil {
+		slog.Error("token refill reschedule failed", "place_id", job.PlaceID, "error", err)
+	}
+}
+
+// backoffFor удваивает задержку с каждой попыткой, но не больше maxRefillBackoff
+func (w *RefillWorker) backoffFor(attempts int) time.Duration {
+	delay := w.backoff
+	for i := 1; i < attempts && delay < maxRefillBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRefillBackoff)
+}
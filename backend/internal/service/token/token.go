@@ -2,80 +2,410 @@ package token
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/kulikovroman08/reviewlink-backend/configs"
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
 	repo "github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/access"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/labels"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/qr"
+)
+
+const (
+	maxGenerateAttempts = 5
+
+	defaultTokensPageSize = 50
+	maxTokensPageSize     = 200
 )
 
 type Service struct {
-	repo repo.TokenRepository
-	cfg  *configs.Config
+	repo       repo.TokenRepository
+	placeRepo  repo.PlaceRepository
+	memberRepo repo.PlaceMemberRepository
+	access     *access.Checker
+	refillRepo repo.TokenRefillRepository
+	jobRepo    repo.TokenJobRepository
+	txManager  repo.Transactor
+	renderer   *qr.Renderer
+	generator  Generator
+	cfg        *configs.Config
 }
 
-func NewTokenService(repo repo.TokenRepository, cfg *configs.Config) *Service {
+func NewTokenService(
+	repo repo.TokenRepository,
+	placeRepo repo.PlaceRepository,
+	memberRepo repo.PlaceMemberRepository,
+	refillRepo repo.TokenRefillRepository,
+	jobRepo repo.TokenJobRepository,
+	txManager repo.Transactor,
+	renderer *qr.Renderer,
+	generator Generator,
+	cfg *configs.Config,
+) *Service {
 	return &Service{
-		repo: repo,
-		cfg:  cfg,
+		repo:       repo,
+		placeRepo:  placeRepo,
+		memberRepo: memberRepo,
+		access:     access.NewChecker(placeRepo, memberRepo),
+		refillRepo: refillRepo,
+		jobRepo:    jobRepo,
+		txManager:  txManager,
+		renderer:   renderer,
+		generator:  generator,
+		cfg:        cfg,
 	}
 }
 
-func (s *Service) GenerateTokens(ctx context.Context, placeID string, count int) (*model.GenerateTokensResult, error) {
+// GenerateTokens создаёт ровно count токенов со сроком из политики заведения и метками labels. Совпавшие
+// с существующими значения пропускаются на вставке и догенерируются, но не более maxGenerateAttempts раз.
+func (s *Service) GenerateTokens(ctx context.Context, placeID string, count int, labels model.TokenLabels) (*model.GenerateTokensResult, error) {
+	placeUID, err := uuid.Parse(placeID)
+	if err != nil {
+		return nil, serviceErrors.ErrInvalidPlaceID
+	}
+
+	policy, err := s.policyFor(ctx, placeID)
+	if err != nil {
+		return nil, err
+	}
 
-	tokens, values, err := generateTokens(placeID, count)
+	labels, err = s.normalizeLabels(ctx, placeID, labels)
 	if err != nil {
-		return nil, fmt.Errorf("generate tokens: %w", err)
+		return nil, err
 	}
 
-	if err := s.repo.CreateTokens(ctx, tokens); err != nil {
-		return nil, fmt.Errorf("create tokens: %w", err)
+	expiresAt := time.Now().Add(policy.TTL)
+	values := make([]string, 0, count)
+
+	for attempt := 0; len(values) < count; attempt++ {
+		if attempt == maxGenerateAttempts {
+			return nil, serviceErrors.ErrTokenGenerationFailed
+		}
+
+		tokens, err := s.newTokens(placeUID, count-len(values), expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("generate tokens: %w", err)
+		}
+		for i := range tokens {
+			tokens[i].Labels = labels
+		}
+
+		inserted, err := s.repo.CreateTokens(ctx, tokens)
+		if err != nil {
+			return nil, fmt.Errorf("create tokens: %w", err)
+		}
+
+		if skipped := len(tokens) - len(inserted); skipped > 0 {
+			log.Printf("[tokens] %d duplicate values for %s, regenerating", skipped, placeID)
+		}
+
+		values = append(values, inserted...)
 	}
 
-	return &model.GenerateTokensResult{Tokens: values}, nil
+	return &model.GenerateTokensResult{Tokens: values, ExpiresAt: expiresAt}, nil
 }
 
-func (s *Service) CheckAndRefillTokens(ctx context.Context, placeID string) error {
-	activeCount, err := s.repo.CountActiveTokens(ctx, placeID)
+// GetTokenInfo возвращает состояние токена для публичной предпроверки перед входом
+func (s *Service) GetTokenInfo(ctx context.Context, tokenValue string) (*model.ReviewTokenInfo, error) {
+	info, err := s.repo.GetTokenInfo(ctx, tokenValue)
 	if err != nil {
-		return fmt.Errorf("count active tokens: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("get token info: %w", err)
+	}
+
+	return info, nil
+}
+
+// ValidValue отсекает опечатки в токене, введённом вручную, до поиска в базе. Значение сверяется
+// в верхнем регистре. Формат проверяется только при включённом контрольном символе; токен длины
+// TokenLength выпущен до его включения и уходит на поиск в базу без проверки.
+func (s *Service) ValidValue(value string) bool {
+	if !s.cfg.TokenCheckChar {
+		return true
 	}
 
-	if activeCount <= s.cfg.TokensThreshold {
-		log.Printf("[auto-refill] active tokens for %s = %d, generating +%d",
-			placeID, activeCount, s.cfg.TokensBatchSize)
+	value = strings.ToUpper(value)
+	if utf8.RuneCountInString(value) == s.cfg.TokenLength {
+		return true
+	}
+	return s.generator.Valid(value)
+}
+
+// RenderTokenQR рисует QR-код с короткой ссылкой токена заведения
+func (s *Service) RenderTokenQR(ctx context.Context, placeID, tokenValue, format string, size int) (*qr.Image, error) {
+	info, err := s.GetTokenInfo(ctx, tokenValue)
+	if err != nil {
+		return nil, err
+	}
+	if info.PlaceID.String() != placeID {
+		return nil, serviceErrors.ErrInvalidToken
+	}
 
-		if _, err := s.GenerateTokens(ctx, placeID, s.cfg.TokensBatchSize); err != nil {
-			log.Printf("[auto-refill] failed for %s: %v", placeID, err)
+	img, err := s.renderer.Render(ShortURL(s.cfg.PublicBaseURL, tokenValue), format, size)
+	if err != nil {
+		return nil, fmt.Errorf("render token qr: %w", err)
+	}
+
+	return img, nil
+}
+
+// PrintTokens генерирует count новых токенов и возвращает PDF с сеткой наклеек. Токены сохраняются,
+// только если PDF собран. Пустые поля layout берутся из конфигурации. Не-админ должен быть владельцем или менеджером заведения.
+func (s *Service) PrintTokens(
+	ctx context.Context,
+	actorID, actorRole, placeID string,
+	count int,
+	layout labels.Layout,
+) ([]byte, error) {
+	place, err := s.access.Place(ctx, actorID, actorRole, placeID, access.Manager)
+	if err != nil {
+		return nil, err
+	}
+
+	if layout.PageSize == "" {
+		layout.PageSize = s.cfg.LabelPageSize
+	}
+	if layout.Columns == 0 {
+		layout.Columns = s.cfg.LabelColumns
+	}
+	if layout.Rows == 0 {
+		layout.Rows = s.cfg.LabelRows
+	}
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Токены сохраняются в одной транзакции с рендером: если PDF не собрался,
+	// вставка откатывается и в базе не остаётся активных токенов без наклеек
+	var pdf []byte
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		result, err := s.GenerateTokens(ctx, placeID, count, model.TokenLabels{})
+		if err != nil {
 			return err
 		}
+
+		items := make([]labels.Label, 0, len(result.Tokens))
+		for _, value := range result.Tokens {
+			img, err := s.renderer.Render(ShortURL(s.cfg.PublicBaseURL, value), qr.FormatPNG, qr.DefaultSize)
+			if err != nil {
+				return fmt.Errorf("render token qr: %w", err)
+			}
+
+			items = append(items, labels.Label{
+				QRPNG:  img.Data,
+				Title:  place.Name,
+				Code:   value,
+				Footer: "до " + result.ExpiresAt.Format("02.01.2006"),
+			})
+		}
+
+		pdf, err = labels.RenderPDF(items, layout)
+		if err != nil {
+			return fmt.Errorf("render labels: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pdf, nil
+}
+
+// ListTokens возвращает страницу токенов заведения с фильтром по статусу
+func (s *Service) ListTokens(ctx context.Context, placeID string, filter model.TokenFilter) (*model.TokenPage, error) {
+	if _, err := s.getPlace(ctx, placeID); err != nil {
+		return nil, err
+	}
+
+	switch filter.Status {
+	case "", model.TokenStatusActive, model.TokenStatusUsed, model.TokenStatusExpired, model.TokenStatusRevoked:
+	default:
+		return nil, serviceErrors.ErrInvalidTokenStatus
 	}
 
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTokensPageSize
+	}
+	filter.Limit = min(filter.Limit, maxTokensPageSize)
+	filter.Offset = max(filter.Offset, 0)
+
+	tokens, total, err := s.repo.ListTokens(ctx, placeID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list tokens: %w", err)
+	}
+
+	return &model.TokenPage{Tokens: tokens, Total: total}, nil
+}
+
+func (s *Service) GetTokenStats(ctx context.Context, placeID string) (*model.TokenStats, error) {
+	if _, err := s.getPlace(ctx, placeID); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.GetTokenStats(ctx, placeID)
+	if err != nil {
+		return nil, fmt.Errorf("get token stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// RevokeToken отзывает один токен. Использованный или уже отозванный токен считается ненайденным.
+func (s *Service) RevokeToken(ctx context.Context, tokenID string) error {
+	affected, err := s.RevokeTokens(ctx, []string{tokenID})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return serviceErrors.ErrTokenNotFound
+	}
+	return nil
+}
+
+// RevokeTokens отзывает пачку токенов и возвращает число отозванных
+func (s *Service) RevokeTokens(ctx context.Context, tokenIDs []string) (int, error) {
+	ids, err := parseTokenIDs(tokenIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := s.repo.RevokeTokens(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("revoke tokens: %w", err)
+	}
+
+	log.Printf("[tokens] revoked %d of %d tokens", affected, len(ids))
+	return affected, nil
+}
+
+// ExtendTokens продлевает срок действия пачки токенов на ttl и возвращает число продлённых
+func (s *Service) ExtendTokens(ctx context.Context, tokenIDs []string, ttl time.Duration) (int, error) {
+	ids, err := parseTokenIDs(tokenIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := s.repo.ExtendTokens(ctx, ids, ttl)
+	if err != nil {
+		return 0, fmt.Errorf("extend tokens: %w", err)
+	}
+
+	return affected, nil
+}
+
+func parseTokenIDs(tokenIDs []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(tokenIDs))
+	for _, raw := range tokenIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, serviceErrors.ErrTokenNotFound
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// ShortURL — короткая ссылка токена для QR-кодов: переход по ней учитывается в воронке и ведёт на ReviewFormURL
+func ShortURL(baseURL, tokenValue string) string {
+	return strings.TrimRight(baseURL, "/") + "/r/" + url.PathEscape(tokenValue)
+}
+
+// ReviewFormURL — адрес формы отзыва, на который ведёт короткая ссылка
+func ReviewFormURL(baseURL, tokenValue, placeID string) string {
+	params := url.Values{}
+	params.Set("token", tokenValue)
+	params.Set("place_id", placeID)
+
+	return strings.TrimRight(baseURL, "/") + "/frontend/review-form.html?" + params.Encode()
+}
+
+// ScheduleRefill ставит заведение в очередь фоновой догенерации токенов.
+// Вызывается после коммита отзыва, а не в его транзакции: строка очереди одна на заведение.
+func (s *Service) ScheduleRefill(ctx context.Context, placeID uuid.UUID) error {
+	if err := s.refillRepo.EnqueueRefill(ctx, placeID); err != nil {
+		return fmt.Errorf("enqueue refill: %w", err)
+	}
 	return nil
 }
 
-func generateTokens(placeIDStr string, count int) ([]model.ReviewToken, []string, error) {
-	placeID, err := uuid.Parse(placeIDStr)
+// CheckAndRefillTokens догенерирует пачку токенов, когда активных остаётся не больше порога из политики заведения.
+// Подсчёт и вставка идут под advisory-блокировкой заведения, поэтому параллельные вызовы не создают лишних пачек.
+// Возвращает число созданных токенов или ErrRefillInProgress, если заведение уже обрабатывается.
+func (s *Service) CheckAndRefillTokens(ctx context.Context, placeID string) (int, error) {
+	placeUID, err := uuid.Parse(placeID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("invalid place_id: %w", err)
+		return 0, serviceErrors.ErrInvalidPlaceID
 	}
 
+	policy, err := s.policyFor(ctx, placeID)
+	if err != nil {
+		return 0, err
+	}
+
+	var generated int
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		locked, err := s.refillRepo.TryLockPlace(ctx, placeUID)
+		if err != nil {
+			return fmt.Errorf("lock place: %w", err)
+		}
+		if !locked {
+			return serviceErrors.ErrRefillInProgress
+		}
+
+		activeCount, err := s.repo.CountActiveTokens(ctx, placeID)
+		if err != nil {
+			return fmt.Errorf("count active tokens: %w", err)
+		}
+
+		if activeCount > policy.RefillThreshold {
+			return nil
+		}
+
+		if _, err := s.GenerateTokens(ctx, placeID, policy.RefillBatch, model.TokenLabels{}); err != nil {
+			return err
+		}
+		generated = policy.RefillBatch
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return generated, nil
+}
+
+func (s *Service) newTokens(placeID uuid.UUID, count int, expiresAt time.Time) ([]model.ReviewToken, error) {
 	tokens := make([]model.ReviewToken, 0, count)
-	values := make([]string, 0, count)
 
 	for i := 0; i < count; i++ {
-		token := model.ReviewToken{
+		value, err := s.generator.Generate()
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, model.ReviewToken{
 			ID:        uuid.New(),
 			PlaceID:   placeID,
-			Token:     uuid.New().String()[:8],
+			Token:     value,
 			IsUsed:    false,
-			ExpiresAt: time.Now().Add(72 * time.Hour),
-		}
-		tokens = append(tokens, token)
-		values = append(values, token.Token)
+			ExpiresAt: expiresAt,
+		})
 	}
-	return tokens, values, nil
+
+	return tokens, nil
 }
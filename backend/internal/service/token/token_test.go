@@ -0,0 +1,44 @@
+package token
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/configs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidValue(t *testing.T) {
+	random, err := NewRandomGenerator(DefaultAlphabet, 10, true)
+	require.NoError(t, err)
+
+	srv := NewTokenService(nil, nil, nil, nil, nil, nil, nil, random, &configs.Config{
+		TokenLength:    10,
+		TokenCheckChar: true,
+	})
+
+	value, err := random.Generate()
+	require.NoError(t, err)
+	require.True(t, srv.ValidValue(value))
+	require.True(t, srv.ValidValue(strings.ToLower(value)), "регистр не важен")
+
+	// Замена одного символа ломает контрольный символ
+	mistyped := []rune(value)
+	if mistyped[0] == 'A' {
+		mistyped[0] = 'B'
+	} else {
+		mistyped[0] = 'A'
+	}
+	require.False(t, srv.ValidValue(string(mistyped)))
+	require.False(t, srv.ValidValue(value+"A"))
+
+	// Токен без контрольного символа выпущен раньше и проверяется только в базе
+	require.True(t, srv.ValidValue(value[:len(value)-1]))
+
+	// Без контрольного символа формат не проверяется: старые токены остаются в силе
+	legacy := NewTokenService(nil, nil, nil, nil, nil, nil, nil, random, &configs.Config{
+		TokenLength:    10,
+		TokenCheckChar: false,
+	})
+	require.True(t, legacy.ValidValue("VALIDTOKEN123"))
+}
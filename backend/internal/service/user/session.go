@@ -0,0 +1,173 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model/claims"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+const refreshTokenBytes = 32
+
+// RefreshTokens обменивает refresh-токен на новую пару токенов.
+// Повторное предъявление уже использованного токена считается кражей:
+// вся цепочка (family) отзывается, и выданные по ней access-токены перестают работать.
+func (s *userService) RefreshTokens(ctx context.Context, refreshToken string) (*model.AuthTokens, error) {
+	if refreshToken == "" {
+		return nil, serviceErrors.ErrInvalidToken
+	}
+
+	stored, err := s.refreshRepo.GetByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("get refresh token: %w", err)
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, serviceErrors.ErrSessionRevoked
+	}
+
+	if stored.RotatedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, stored)
+	}
+
+	if stored.ExpiresAt.Before(time.Now()) {
+		return nil, serviceErrors.ErrTokenExpired
+	}
+
+	if err := s.refreshRepo.MarkRotated(ctx, stored.ID.String()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, s.revokeReusedFamily(ctx, stored)
+		}
+		return nil, fmt.Errorf("rotate refresh token: %w", err)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, stored.UserID.String())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, serviceErrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+
+	return s.issueTokens(ctx, user, stored.FamilyID)
+}
+
+func (s *userService) Logout(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		return serviceErrors.ErrInvalidToken
+	}
+
+	if err := s.refreshRepo.RevokeFamily(ctx, sessionID); err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+
+	return nil
+}
+
+func (s *userService) IsSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	revoked, err := s.refreshRepo.IsFamilyRevoked(ctx, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("check session: %w", err)
+	}
+	return revoked, nil
+}
+
+func (s *userService) revokeReusedFamily(ctx context.Context, stored *model.RefreshToken) error {
+	slog.Warn("refresh token reuse detected",
+		"user_id", stored.UserID,
+		"family_id", stored.FamilyID,
+	)
+
+	if err := s.refreshRepo.RevokeFamily(ctx, stored.FamilyID.String()); err != nil {
+		return fmt.Errorf("revoke token family: %w", err)
+	}
+
+	return serviceErrors.ErrRefreshTokenReused
+}
+
+func (s *userService) issueTokens(ctx context.Context, user *model.User, familyID uuid.UUID) (*model.AuthTokens, error) {
+	now := time.Now()
+	accessExpiresAt := now.Add(s.cfg.AccessTokenTTL)
+
+	accessToken, err := s.generateJWT(user, familyID, now, accessExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("sign access token: %w", err)
+	}
+
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("generate refresh token: %w", err)
+	}
+
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+
+	stored := &model.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: now.Add(s.cfg.RefreshTokenTTL),
+		CreatedAt: now,
+	}
+
+	if err := s.refreshRepo.CreateRefreshToken(ctx, stored); err != nil {
+		return nil, fmt.Errorf("store refresh token: %w", err)
+	}
+
+	return &model.AuthTokens{
+		AccessToken:     accessToken,
+		RefreshToken:    refreshToken,
+		AccessExpiresAt: accessExpiresAt,
+	}, nil
+}
+
+func (s *userService) generateJWT(user *model.User, familyID uuid.UUID, issuedAt, expiresAt time.Time) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+
+	claims := claims.Claims{
+		UserID:    user.ID,
+		Role:      user.Role,
+		SessionID: familyID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+func generateRefreshToken() (string, error) {
+	b := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,39 +2,45 @@ package user
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
-	"os"
 	"time"
 
+	"github.com/kulikovroman08/reviewlink-backend/configs"
 	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
 
 	"github.com/kulikovroman08/reviewlink-backend/internal/repository"
 
 	"github.com/jackc/pgx/v5"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/kulikovroman08/reviewlink-backend/internal/model"
-	"github.com/kulikovroman08/reviewlink-backend/internal/model/claims"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model/rbac"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type userService struct {
-	userRepo   repository.UserRepository
-	reviewRepo repository.ReviewRepository
-	bonusRepo  repository.BonusRepository
+	userRepo    repository.UserRepository
+	reviewRepo  repository.ReviewRepository
+	bonusRepo   repository.BonusRepository
+	refreshRepo repository.RefreshTokenRepository
+	cfg         *configs.Config
 }
 
 func NewUserService(
 	userRepo repository.UserRepository,
 	reviewRepo repository.ReviewRepository,
 	bonusRepo repository.BonusRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	cfg *configs.Config,
 ) *userService {
 	return &userService{
-		userRepo:   userRepo,
-		reviewRepo: reviewRepo,
-		bonusRepo:  bonusRepo,
+		userRepo:    userRepo,
+		reviewRepo:  reviewRepo,
+		bonusRepo:   bonusRepo,
+		refreshRepo: refreshRepo,
+		cfg:         cfg,
 	}
 }
 
@@ -50,18 +56,18 @@ func (s *userService) GetUser(ctx context.Context, userID string) (*model.User,
 	return user, nil
 }
 
-func (s *userService) Signup(ctx context.Context, name, email, password string) (string, error) {
+func (s *userService) Signup(ctx context.Context, name, email, password string) (*model.AuthTokens, error) {
 	existing, err := s.userRepo.FindAnyByEmail(ctx, email)
 	if err != nil {
 		if isUnexpectedErr(err) {
-			return "", fmt.Errorf("check existing user: %w", err)
+			return nil, fmt.Errorf("check existing user: %w", err)
 		}
 		existing = nil
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return "", fmt.Errorf("hash password: %w", err)
+		return nil, fmt.Errorf("hash password: %w", err)
 	}
 
 	if existing == nil {
@@ -70,17 +76,17 @@ func (s *userService) Signup(ctx context.Context, name, email, password string)
 			Name:         name,
 			Email:        email,
 			PasswordHash: string(hashedPassword),
-			Role:         "user",
+			Role:         rbac.RoleUser,
 			Points:       0,
 			CreatedAt:    time.Now(),
 			IsDeleted:    false,
 		}
 
 		if err := s.userRepo.CreateUser(ctx, user); err != nil {
-			return "", fmt.Errorf("create user: %w", err)
+			return nil, fmt.Errorf("create user: %w", err)
 		}
 
-		return s.generateJWT(user)
+		return s.issueTokens(ctx, user, uuid.New())
 	}
 
 	if existing.IsDeleted {
@@ -89,28 +95,28 @@ func (s *userService) Signup(ctx context.Context, name, email, password string)
 		existing.IsDeleted = false
 
 		if err := s.userRepo.UpdateUser(ctx, existing); err != nil {
-			return "", fmt.Errorf("restore user: %w", err)
+			return nil, fmt.Errorf("restore user: %w", err)
 		}
-		return s.generateJWT(existing)
+		return s.issueTokens(ctx, existing, uuid.New())
 	}
 
-	return "", serviceErrors.ErrEmailAlreadyUsed
+	return nil, serviceErrors.ErrEmailAlreadyUsed
 }
 
-func (s *userService) Login(ctx context.Context, email, password string) (string, error) {
+func (s *userService) Login(ctx context.Context, email, password string) (*model.AuthTokens, error) {
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return "", serviceErrors.ErrUserNotFound
+			return nil, serviceErrors.ErrUserNotFound
 		}
-		return "", fmt.Errorf("check existing user: %w", err)
+		return nil, fmt.Errorf("check existing user: %w", err)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return "", serviceErrors.ErrInvalidCredentials
+		return nil, serviceErrors.ErrInvalidCredentials
 	}
 
-	return s.generateJWT(user)
+	return s.issueTokens(ctx, user, uuid.New())
 }
 
 func (s *userService) UpdateUser(ctx context.Context, user model.User, password string) (*model.User, error) {
@@ -207,18 +213,19 @@ func (s *userService) GetUserStats(ctx context.Context, userID string) (*model.U
 	return stats, nil
 }
 
-func (s *userService) generateJWT(user *model.User) (string, error) {
-	secret := os.Getenv("JWT_SECRET")
+func (s *userService) ChangeRole(ctx context.Context, userID, role string) error {
+	if !rbac.IsValidRole(role) {
+		return serviceErrors.ErrInvalidRole
+	}
 
-	claims := claims.Claims{
-		UserID: user.ID,
-		Role:   user.Role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(72 * time.Hour)),
-		},
+	if err := s.userRepo.UpdateRole(ctx, userID, role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return serviceErrors.ErrUserNotFound
+		}
+		return fmt.Errorf("update role: %w", err)
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+
+	return nil
 }
 
 func (s *userService) shouldUpdateEmail(newEmail *string, currentEmail string) bool {
@@ -0,0 +1,85 @@
+package vote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	repo "github.com/kulikovroman08/reviewlink-backend/internal/repository"
+	serviceErrors "github.com/kulikovroman08/reviewlink-backend/internal/service/errors"
+)
+
+type Service struct {
+	voteRepo   repo.VoteRepository
+	reviewRepo repo.ReviewRepository
+}
+
+func NewVoteService(voteRepo repo.VoteRepository, reviewRepo repo.ReviewRepository) *Service {
+	return &Service{
+		voteRepo:   voteRepo,
+		reviewRepo: reviewRepo,
+	}
+}
+
+// Vote отмечает опубликованный отзыв как полезный. Один пользователь — одна отметка,
+// повторный вызов ничего не меняет. Автор не может отметить свой отзыв.
+func (s *Service) Vote(ctx context.Context, userID, reviewID string) (*model.ReviewVotes, error) {
+	review, userUID, err := s.review(ctx, userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Status != model.ReviewStatusPublished {
+		return nil, serviceErrors.ErrReviewNotFound
+	}
+	if review.UserID == userUID {
+		return nil, serviceErrors.ErrOwnReviewVote
+	}
+
+	count, err := s.voteRepo.AddVote(ctx, review.ID, userUID)
+	if err != nil {
+		return nil, fmt.Errorf("add vote: %w", err)
+	}
+
+	return &model.ReviewVotes{ReviewID: review.ID, HelpfulCount: count, Voted: true}, nil
+}
+
+// Unvote снимает отметку пользователя; если отметки не было, возвращает текущий счётчик
+func (s *Service) Unvote(ctx context.Context, userID, reviewID string) (*model.ReviewVotes, error) {
+	review, userUID, err := s.review(ctx, userID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.voteRepo.RemoveVote(ctx, review.ID, userUID)
+	if err != nil {
+		return nil, fmt.Errorf("remove vote: %w", err)
+	}
+
+	return &model.ReviewVotes{ReviewID: review.ID, HelpfulCount: count, Voted: false}, nil
+}
+
+func (s *Service) review(ctx context.Context, userID, reviewID string) (*model.Review, uuid.UUID, error) {
+	userUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, uuid.Nil, serviceErrors.ErrAccessDenied
+	}
+
+	reviewUID, err := uuid.Parse(reviewID)
+	if err != nil {
+		return nil, uuid.Nil, serviceErrors.ErrReviewNotFound
+	}
+
+	review, err := s.reviewRepo.GetReviewByID(ctx, reviewUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, uuid.Nil, serviceErrors.ErrReviewNotFound
+		}
+		return nil, uuid.Nil, fmt.Errorf("get review: %w", err)
+	}
+
+	return review, userUID, nil
+}
@@ -7,8 +7,6 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
 	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
 
@@ -35,20 +33,11 @@ func (s *AdminStatsTestSuite) TearDownSuite() {
 }
 
 func (s *AdminStatsTestSuite) SetupTest() {
-	db := stdlib.OpenDBFromPool(s.TS.DB)
-	defer db.Close()
-
-	fixture, err := testfixtures.New(
-		testfixtures.Database(db),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Files(
-			"../fixtures/users.yml",
-			"../fixtures/admin/reviews.yml",
-			"../fixtures/admin/bonus_rewards.yml",
-		),
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/admin/reviews.yml",
+		"../fixtures/admin/bonus_rewards.yml",
 	)
-	require.NoError(s.T(), err, "init fixtures failed")
-	require.NoError(s.T(), fixture.Load(), "load fixtures failed")
 
 	s.Token = s.TS.Login("admin@example.com", "securepass")
 }
@@ -0,0 +1,133 @@
+package reviewlink
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuthTestSuite struct {
+	suite.Suite
+	TS *integration.TestSetup
+}
+
+func TestAuthSuite(t *testing.T) {
+	suite.Run(t, new(AuthTestSuite))
+}
+
+func (s *AuthTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *AuthTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *AuthTestSuite) SetupTest() {
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+	)
+}
+
+func (s *AuthTestSuite) login() dto.AuthResponse {
+	body, _ := json.Marshal(map[string]string{
+		"email":    "bob@example.com",
+		"password": "password123",
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.AuthResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.NotEmpty(s.T(), resp.Token)
+	require.NotEmpty(s.T(), resp.RefreshToken)
+
+	return resp
+}
+
+func (s *AuthTestSuite) refresh(refreshToken string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]string{"refresh_token": refreshToken})
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func (s *AuthTestSuite) getUser(accessToken string) int {
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+
+	return rec.Code
+}
+
+func (s *AuthTestSuite) TestRefreshSuccess() {
+	initial := s.login()
+
+	rec := s.refresh(initial.RefreshToken)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.AuthResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.NotEmpty(s.T(), resp.Token)
+	require.NotEqual(s.T(), initial.RefreshToken, resp.RefreshToken)
+
+	require.Equal(s.T(), http.StatusOK, s.getUser(resp.Token))
+}
+
+func (s *AuthTestSuite) TestRefreshInvalidToken() {
+	rec := s.refresh("not-a-real-token")
+
+	require.Equal(s.T(), http.StatusUnauthorized, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "invalid refresh token")
+}
+
+func (s *AuthTestSuite) TestRefreshReuseRevokesFamily() {
+	initial := s.login()
+
+	rec := s.refresh(initial.RefreshToken)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var rotated dto.AuthResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &rotated))
+
+	reuse := s.refresh(initial.RefreshToken)
+	require.Equal(s.T(), http.StatusUnauthorized, reuse.Code)
+	require.Contains(s.T(), reuse.Body.String(), "refresh token reuse detected")
+
+	afterReuse := s.refresh(rotated.RefreshToken)
+	require.Equal(s.T(), http.StatusUnauthorized, afterReuse.Code)
+
+	require.Equal(s.T(), http.StatusUnauthorized, s.getUser(rotated.Token))
+}
+
+func (s *AuthTestSuite) TestLogoutRevokesAccessToken() {
+	tokens := s.login()
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.Token)
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	require.Equal(s.T(), http.StatusUnauthorized, s.getUser(tokens.Token))
+	require.Equal(s.T(), http.StatusUnauthorized, s.refresh(tokens.RefreshToken).Code)
+}
@@ -8,8 +8,6 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
@@ -34,20 +32,11 @@ func (s *BonusTestSuite) TearDownSuite() {
 }
 
 func (s *BonusTestSuite) SetupTest() {
-	db := stdlib.OpenDBFromPool(s.TS.DB)
-	defer db.Close()
-
-	fixture, err := testfixtures.New(
-		testfixtures.Database(db),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Files(
-			"../fixtures/users.yml",
-			"../fixtures/places.yml",
-			"../fixtures/bonuses/bonus_rewards.yml",
-		),
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/bonuses/bonus_rewards.yml",
 	)
-	require.NoError(s.T(), err)
-	require.NoError(s.T(), fixture.Load())
 }
 
 func (s *BonusTestSuite) TestGetUserBonuses() {
@@ -0,0 +1,149 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const filterPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+
+type ContentFilterTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+	UserToken  string
+}
+
+func TestContentFilterSuite(t *testing.T) {
+	suite.Run(t, new(ContentFilterTestSuite))
+}
+
+func (s *ContentFilterTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *ContentFilterTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *ContentFilterTestSuite) SetupTest() {
+	s.clear()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+	s.UserToken = s.TS.Login("bob@example.com", "password123")
+}
+
+func (s *ContentFilterTestSuite) TearDownTest() {
+	s.clear()
+}
+
+func (s *ContentFilterTestSuite) clear() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM token_refill_jobs")
+	require.NoError(s.T(), err)
+}
+
+func (s *ContentFilterTestSuite) submit(content string) *httptest.ResponseRecorder {
+	return s.TS.Do(http.MethodPost, "/reviews", s.UserToken, map[string]any{
+		"token":    "VALIDTOKEN123",
+		"place_id": filterPlaceID,
+		"rating":   5,
+		"content":  content,
+	})
+}
+
+func (s *ContentFilterTestSuite) filterRule(reviewID string) *string {
+	var rule *string
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT filter_rule FROM reviews WHERE id = $1", reviewID,
+	).Scan(&rule)
+	require.NoError(s.T(), err)
+	return rule
+}
+
+func (s *ContentFilterTestSuite) TestRejectKeepsToken() {
+	rec := s.submit("Официант сука")
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "review content rejected")
+
+	rec = s.submit(strings.Repeat("о", 501))
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	// Токен не погашен: исправленный отзыв принимается
+	rec = s.submit("Официант вежливый")
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var created dto.SubmitReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &created))
+	require.Equal(s.T(), "published", created.Status)
+	require.Nil(s.T(), s.filterRule(created.ID))
+}
+
+func (s *ContentFilterTestSuite) TestFlagSendsToModeration() {
+	rec := s.submit("Скидки тут: www.example.com")
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var created dto.SubmitReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &created))
+	require.Equal(s.T(), "pending", created.Status)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/reviews?place_id="+filterPlaceID, s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var queue []dto.ModeratedReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &queue))
+	require.Len(s.T(), queue, 1)
+	require.Equal(s.T(), created.ID, queue[0].ID)
+	require.NotNil(s.T(), queue[0].FilterRule)
+	require.Equal(s.T(), "links", *queue[0].FilterRule)
+}
+
+func (s *ContentFilterTestSuite) TestUpdateIsFiltered() {
+	rec := s.submit("Всё понравилось")
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var created dto.SubmitReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &created))
+
+	rec = s.TS.Do(http.MethodPatch, "/reviews/"+created.ID, s.UserToken, map[string]any{
+		"content": "Fucking great",
+		"rating":  5,
+	})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.TS.Do(http.MethodPatch, "/reviews/"+created.ID, s.UserToken, map[string]any{
+		"content": "Бронь по телефону +7 (999) 123-45-67",
+		"rating":  5,
+	})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	require.Equal(s.T(), "phone_numbers", *s.filterRule(created.ID))
+
+	// Помеченная правка попадает в очередь модерации по умолчанию с отдельной причиной
+	rec = s.TS.Do(http.MethodGet, "/admin/reviews?place_id="+filterPlaceID, s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var queue []dto.ModeratedReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &queue))
+	require.Len(s.T(), queue, 1)
+	require.Equal(s.T(), created.ID, queue[0].ID)
+	require.Equal(s.T(), "pending", queue[0].Status)
+	require.NotNil(s.T(), queue[0].ModerationReason)
+	require.Equal(s.T(), "edit flagged by content filter rule phone_numbers", *queue[0].ModerationReason)
+}
@@ -9,8 +9,6 @@ import (
 
 	"github.com/stretchr/testify/require"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
 
 	"github.com/stretchr/testify/suite"
@@ -35,23 +33,10 @@ func (s *CreatePlaceTestSuite) TearDownSuite() {
 }
 
 func (s *CreatePlaceTestSuite) SetupTest() {
-	db := stdlib.OpenDBFromPool(s.TS.DB)
-	defer func() {
-		if err := db.Close(); err != nil {
-			s.T().Logf("failed to close db: %v", err)
-		}
-	}()
-
-	fixture, err := testfixtures.New(
-		testfixtures.Database(db),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Files(
-			"../fixtures/users.yml",
-			"../fixtures/places.yml",
-		),
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
 	)
-	require.NoError(s.T(), err, "init fixtures failed")
-	require.NoError(s.T(), fixture.Load(), "load fixtures failed")
 
 	s.Token = s.TS.Login("admin@example.com", "securepass")
 }
@@ -5,8 +5,6 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
@@ -32,21 +30,12 @@ func (s *DeleteReviewTestSuite) TearDownSuite() {
 }
 
 func (s *DeleteReviewTestSuite) SetupTest() {
-	db := stdlib.OpenDBFromPool(s.TS.DB)
-	defer db.Close()
-
-	fixture, err := testfixtures.New(
-		testfixtures.Database(db),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Files(
-			"../fixtures/users.yml",
-			"../fixtures/places.yml",
-			"../fixtures/review_tokens.yml",
-			"../fixtures/delete_reviews/reviews.yml",
-		),
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/delete_reviews/reviews.yml",
 	)
-	require.NoError(s.T(), err)
-	require.NoError(s.T(), fixture.Load())
 
 	s.Token = s.TS.Login("bob@example.com", "password123")
 }
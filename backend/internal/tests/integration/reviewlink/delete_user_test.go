@@ -7,8 +7,6 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
 	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
 
@@ -35,22 +33,9 @@ func (s *DeleteUserTestSuite) TearDownSuite() {
 }
 
 func (s *DeleteUserTestSuite) SetupTest() {
-	db := stdlib.OpenDBFromPool(s.TS.DB)
-	defer func() {
-		if err := db.Close(); err != nil {
-			s.T().Logf("failed to close db: %v", err)
-		}
-	}()
-
-	fixture, err := testfixtures.New(
-		testfixtures.Database(db),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Files(
-			"../fixtures/users.yml",
-		),
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
 	)
-	require.NoError(s.T(), err, "init fixtures failed")
-	require.NoError(s.T(), fixture.Load(), "load fixtures failed")
 
 	s.Token = s.TS.Login("john@example.com", "securepass")
 }
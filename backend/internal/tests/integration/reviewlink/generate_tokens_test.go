@@ -2,15 +2,20 @@ package reviewlink
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/kulikovroman08/reviewlink-backend/configs"
+	"github.com/kulikovroman08/reviewlink-backend/internal/model"
+	repoPlace "github.com/kulikovroman08/reviewlink-backend/internal/repository/place"
+	repoToken "github.com/kulikovroman08/reviewlink-backend/internal/repository/token"
+	svcToken "github.com/kulikovroman08/reviewlink-backend/internal/service/token"
 	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
 
 	"github.com/stretchr/testify/suite"
@@ -35,23 +40,10 @@ func (s *GenerateTokensTestSuite) TearDownSuite() {
 }
 
 func (s *GenerateTokensTestSuite) SetupTest() {
-	db := stdlib.OpenDBFromPool(s.TS.DB)
-	defer func() {
-		if err := db.Close(); err != nil {
-			s.T().Logf("failed to close db: %v", err)
-		}
-	}()
-
-	fixture, err := testfixtures.New(
-		testfixtures.Database(db),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Files(
-			"../fixtures/users.yml",
-			"../fixtures/places.yml",
-		),
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
 	)
-	require.NoError(s.T(), err)
-	require.NoError(s.T(), fixture.Load())
 
 	s.Token = s.TS.Login("admin@example.com", "securepass")
 }
@@ -88,9 +80,9 @@ func (s *GenerateTokensTestSuite) TestGenerateTokensSuccess() {
 
 func (s *GenerateTokensTestSuite) TestGenerateTokensForbiddenForUser() {
 	const (
-		testPlaceID  = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
-		count        = 2
-		errOnlyAdmin = `{"error":"only admin can generate tokens"}`
+		testPlaceID     = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+		count           = 2
+		errAccessDenied = `{"error":"access denied"}`
 	)
 
 	s.Token = s.TS.Login("bob@example.com", "password123")
@@ -109,7 +101,7 @@ func (s *GenerateTokensTestSuite) TestGenerateTokensForbiddenForUser() {
 	s.TS.App.ServeHTTP(rec, req)
 
 	require.Equal(s.T(), http.StatusForbidden, rec.Code)
-	require.Equal(s.T(), errOnlyAdmin, rec.Body.String())
+	require.Equal(s.T(), errAccessDenied, rec.Body.String())
 }
 
 func (s *GenerateTokensTestSuite) TestGenerateTokensInvalidInput() {
@@ -135,3 +127,56 @@ func (s *GenerateTokensTestSuite) TestGenerateTokensInvalidInput() {
 	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
 	require.Equal(s.T(), errInvalidInp, rec.Body.String())
 }
+
+// collidingGenerator сначала отдаёт заранее заданные значения, затем — из вложенного генератора
+type collidingGenerator struct {
+	preset []string
+	next   svcToken.Generator
+}
+
+func (g *collidingGenerator) Generate() (string, error) {
+	if len(g.preset) > 0 {
+		value := g.preset[0]
+		g.preset = g.preset[1:]
+		return value, nil
+	}
+	return g.next.Generate()
+}
+
+func (g *collidingGenerator) Valid(value string) bool {
+	return g.next.Valid(value)
+}
+
+func (s *GenerateTokensTestSuite) TestGenerateTokensSkipsDuplicates() {
+	const (
+		testPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+		count       = 4
+	)
+
+	random, err := svcToken.NewRandomGenerator(svcToken.DefaultAlphabet, 10, true)
+	require.NoError(s.T(), err)
+
+	// Значение повторяется внутри пачки: второй экземпляр пропускается и догенерируется
+	gen := &collidingGenerator{preset: []string{"DUPLICATE01", "DUPLICATE01"}, next: random}
+
+	srv := svcToken.NewTokenService(
+		repoToken.NewPostgresTokenRepository(s.TS.DB),
+		repoPlace.NewPostgresPlaceRepository(s.TS.DB),
+		nil, nil, nil, nil, nil, gen,
+		&configs.Config{TokenTTL: 72 * time.Hour},
+	)
+
+	result, err := srv.GenerateTokens(context.Background(), testPlaceID, count, model.TokenLabels{})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), result.Tokens, count)
+
+	seen := make(map[string]bool, count)
+	for _, value := range result.Tokens {
+		require.False(s.T(), seen[value], "duplicate token %s", value)
+		seen[value] = true
+
+		if value != "DUPLICATE01" {
+			require.True(s.T(), random.Valid(value), "bad check char in %s", value)
+		}
+	}
+}
@@ -6,8 +6,6 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
@@ -32,21 +30,12 @@ func (s *GetReviewsTestSuite) TearDownSuite() {
 }
 
 func (s *GetReviewsTestSuite) SetupTest() {
-	db := stdlib.OpenDBFromPool(s.TS.DB)
-	defer db.Close()
-
-	fixture, err := testfixtures.New(
-		testfixtures.Database(db),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Files(
-			"../fixtures/users.yml",
-			"../fixtures/places.yml",
-			"../fixtures/review_tokens.yml",
-			"../fixtures/get_reviews/reviews.yml",
-		),
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/get_reviews/reviews.yml",
 	)
-	require.NoError(s.T(), err)
-	require.NoError(s.T(), fixture.Load())
 }
 
 // 1. Получение всех отзывов
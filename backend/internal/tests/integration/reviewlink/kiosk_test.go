@@ -0,0 +1,180 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/kiosk"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	kioskPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	kioskUserID  = "b2222222-3333-4444-5555-666666666666"
+)
+
+type KioskTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+}
+
+func TestKioskSuite(t *testing.T) {
+	suite.Run(t, new(KioskTestSuite))
+}
+
+func (s *KioskTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *KioskTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *KioskTestSuite) SetupTest() {
+	s.clearKiosks()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+}
+
+func (s *KioskTestSuite) TearDownTest() {
+	s.clearKiosks()
+}
+
+func (s *KioskTestSuite) clearKiosks() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM kiosk_submissions")
+	require.NoError(s.T(), err)
+	_, err = s.TS.DB.Exec(context.Background(), "DELETE FROM place_kiosks")
+	require.NoError(s.T(), err)
+}
+
+func (s *KioskTestSuite) enableKiosk() {
+	rec := s.TS.Do(http.MethodPut, "/places/"+kioskPlaceID+"/kiosk", s.AdminToken, map[string]any{"step_seconds": 60})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+}
+
+func (s *KioskTestSuite) currentCode() dto.KioskCodeResponse {
+	rec := s.TS.Do(http.MethodGet, "/places/"+kioskPlaceID+"/kiosk/code", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.KioskCodeResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func (s *KioskTestSuite) submit(token, code string) *httptest.ResponseRecorder {
+	return s.TS.Do(http.MethodPost, "/reviews", token, map[string]any{
+		"rating":   5,
+		"content":  "Отзыв с планшета",
+		"place_id": kioskPlaceID,
+		"token":    code,
+	})
+}
+
+func (s *KioskTestSuite) TestSubmitWithKioskCode() {
+	s.enableKiosk()
+
+	code := s.currentCode()
+	require.True(s.T(), kiosk.IsCode(code.Code))
+	require.Contains(s.T(), code.URL, "place_id="+kioskPlaceID)
+	require.True(s.T(), code.ValidUntil.After(time.Now()))
+
+	userToken := s.TS.Login("bob@example.com", "password123")
+	rec := s.submit(userToken, code.Code)
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var withoutToken int
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM reviews WHERE place_id = $1 AND token_id IS NULL", kioskPlaceID,
+	).Scan(&withoutToken)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, withoutToken)
+}
+
+func (s *KioskTestSuite) TestReplayInSameWindow() {
+	s.enableKiosk()
+	code := s.currentCode()
+
+	var secret []byte
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT secret FROM place_kiosks WHERE place_id = $1", kioskPlaceID,
+	).Scan(&secret)
+	require.NoError(s.T(), err)
+
+	window, ok := kiosk.Verify(secret, code.Code, time.Now(), time.Minute, 2)
+	require.True(s.T(), ok)
+
+	_, err = s.TS.DB.Exec(context.Background(),
+		"INSERT INTO kiosk_submissions (place_id, user_id, time_window) VALUES ($1, $2, $3)",
+		kioskPlaceID, kioskUserID, window,
+	)
+	require.NoError(s.T(), err)
+
+	userToken := s.TS.Login("update@example.com", "password123")
+	rec := s.submit(userToken, code.Code)
+	require.Equal(s.T(), http.StatusConflict, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "kiosk code already used")
+}
+
+func (s *KioskTestSuite) TestStaleAndForeignCodesRejected() {
+	s.enableKiosk()
+
+	var secret []byte
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT secret FROM place_kiosks WHERE place_id = $1", kioskPlaceID,
+	).Scan(&secret)
+	require.NoError(s.T(), err)
+
+	stale := kiosk.Code(secret, kiosk.Window(time.Now(), time.Minute)-10)
+	userToken := s.TS.Login("bob@example.com", "password123")
+
+	rec := s.submit(userToken, stale)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "token expired")
+
+	foreign := kiosk.Code([]byte("another place secret"), kiosk.Window(time.Now(), time.Minute))
+	rec = s.submit(userToken, foreign)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
+
+func (s *KioskTestSuite) TestDisabledKiosk() {
+	s.enableKiosk()
+	code := s.currentCode()
+
+	rec := s.TS.Do(http.MethodDelete, "/places/"+kioskPlaceID+"/kiosk", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	userToken := s.TS.Login("bob@example.com", "password123")
+	rec = s.submit(userToken, code.Code)
+	require.Equal(s.T(), http.StatusUnauthorized, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, "/places/"+kioskPlaceID+"/kiosk/code", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *KioskTestSuite) TestUserCannotDisplayKiosk() {
+	s.enableKiosk()
+
+	userToken := s.TS.Login("bob@example.com", "password123")
+
+	rec := s.TS.Do(http.MethodGet, "/places/"+kioskPlaceID+"/kiosk/code", userToken, nil)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+
+	rec = s.TS.Do(http.MethodPut, "/places/"+kioskPlaceID+"/kiosk", userToken, nil)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
@@ -6,8 +6,6 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
 	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
 
@@ -33,20 +31,11 @@ func (s *LeaderboardTestSuite) TearDownSuite() {
 }
 
 func (s *LeaderboardTestSuite) SetupTest() {
-	db := stdlib.OpenDBFromPool(s.TS.DB)
-	defer db.Close()
-
-	fixture, err := testfixtures.New(
-		testfixtures.Database(db),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Files(
-			"../fixtures/users.yml",
-			"../fixtures/places.yml",
-			"../fixtures/admin/reviews.yml",
-		),
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/admin/reviews.yml",
 	)
-	require.NoError(s.T(), err)
-	require.NoError(s.T(), fixture.Load())
 }
 
 func (s *LeaderboardTestSuite) TestSuccessGetUserLeaderboard() {
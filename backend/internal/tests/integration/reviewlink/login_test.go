@@ -7,8 +7,6 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
 
 	"github.com/stretchr/testify/require"
@@ -33,22 +31,9 @@ func (s *LoginTestSuite) TearDownSuite() {
 }
 
 func (s *LoginTestSuite) SetupTest() {
-	db := stdlib.OpenDBFromPool(s.TS.DB)
-	defer func() {
-		if err := db.Close(); err != nil {
-			s.T().Logf("failed to close db: %v", err)
-		}
-	}()
-
-	fixture, err := testfixtures.New(
-		testfixtures.Database(db),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Files(
-			"../fixtures/users.yml",
-		),
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
 	)
-	require.NoError(s.T(), err, "init fixtures failed")
-	require.NoError(s.T(), fixture.Load(), "load fixtures failed")
 }
 
 func (s *LoginTestSuite) TestLoginSuccess() {
@@ -0,0 +1,240 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	moderationPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	moderationUserID  = "fcb2f1a1-3c64-45d9-9f83-f89adf1f9b23" // Bob
+)
+
+type ModerationTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+	UserToken  string
+}
+
+func TestModerationSuite(t *testing.T) {
+	suite.Run(t, new(ModerationTestSuite))
+}
+
+func (s *ModerationTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *ModerationTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *ModerationTestSuite) SetupTest() {
+	s.clear()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+	s.UserToken = s.TS.Login("bob@example.com", "password123")
+}
+
+func (s *ModerationTestSuite) TearDownTest() {
+	s.clear()
+}
+
+func (s *ModerationTestSuite) clear() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM token_refill_jobs")
+	require.NoError(s.T(), err)
+}
+
+func (s *ModerationTestSuite) setMode(mode any) dto.ModerationSettingsResponse {
+	rec := s.TS.Do(http.MethodPut, "/admin/places/"+moderationPlaceID+"/moderation", s.AdminToken, map[string]any{"mode": mode})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.ModerationSettingsResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func (s *ModerationTestSuite) submit() dto.SubmitReviewResponse {
+	rec := s.TS.Do(http.MethodPost, "/reviews", s.UserToken, map[string]any{
+		"token":    "VALIDTOKEN123",
+		"place_id": moderationPlaceID,
+		"rating":   5,
+		"content":  "Отзыв на модерацию",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var resp dto.SubmitReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func (s *ModerationTestSuite) decide(reviewID, action string, payload any) *httptest.ResponseRecorder {
+	return s.TS.Do(http.MethodPost, "/admin/reviews/"+reviewID+"/"+action, s.AdminToken, payload)
+}
+
+func (s *ModerationTestSuite) points() int {
+	var points int
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT points FROM users WHERE id = $1", moderationUserID,
+	).Scan(&points)
+	require.NoError(s.T(), err)
+	return points
+}
+
+func (s *ModerationTestSuite) publicCount() int {
+	rec := s.TS.Do(http.MethodGet, "/places/"+moderationPlaceID+"/reviews", "", nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var reviews []dto.ReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &reviews))
+	return len(reviews)
+}
+
+func (s *ModerationTestSuite) TestPreModeration() {
+	settings := s.setMode("pre")
+	require.Equal(s.T(), "pre", settings.Effective)
+
+	pointsBefore := s.points()
+	publicBefore := s.publicCount()
+
+	created := s.submit()
+	require.Equal(s.T(), "pending", created.Status)
+
+	// Ожидающий отзыв не виден и баллов не приносит
+	require.Equal(s.T(), publicBefore, s.publicCount())
+	require.Equal(s.T(), pointsBefore, s.points())
+
+	rec := s.TS.Do(http.MethodGet, "/admin/reviews?place_id="+moderationPlaceID, s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var queue []dto.ModeratedReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &queue))
+	require.Len(s.T(), queue, 1)
+	require.Equal(s.T(), created.ID, queue[0].ID)
+	require.Equal(s.T(), "1", rec.Header().Get("X-Total-Count"))
+	require.Empty(s.T(), rec.Header().Get("X-Next-Cursor"))
+	require.Equal(s.T(), 10, queue[0].Points)
+
+	rec = s.decide(created.ID, "approve", nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var approved dto.ModeratedReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &approved))
+	require.Equal(s.T(), "published", approved.Status)
+	require.NotNil(s.T(), approved.ModeratedBy)
+
+	require.Equal(s.T(), publicBefore+1, s.publicCount())
+	require.Equal(s.T(), pointsBefore+10, s.points())
+
+	// Повторное одобрение не допускается
+	rec = s.decide(created.ID, "approve", nil)
+	require.Equal(s.T(), http.StatusConflict, rec.Code)
+}
+
+func (s *ModerationTestSuite) TestReject() {
+	s.setMode("pre")
+	pointsBefore := s.points()
+	created := s.submit()
+
+	rec := s.decide(created.ID, "reject", map[string]any{"reason": "   "})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.decide(created.ID, "reject", map[string]any{"reason": "Реклама"})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var rejected dto.ModeratedReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &rejected))
+	require.Equal(s.T(), "rejected", rejected.Status)
+	require.Equal(s.T(), "Реклама", *rejected.ModerationReason)
+
+	// Отклонение окончательное
+	rec = s.decide(created.ID, "approve", nil)
+	require.Equal(s.T(), http.StatusConflict, rec.Code)
+
+	require.Equal(s.T(), pointsBefore, s.points())
+}
+
+func (s *ModerationTestSuite) TestHideAndRestore() {
+	pointsBefore := s.points()
+	publicBefore := s.publicCount()
+
+	// По умолчанию постмодерация: отзыв опубликован сразу, баллы начислены
+	created := s.submit()
+	require.Equal(s.T(), "published", created.Status)
+	require.Equal(s.T(), pointsBefore+10, s.points())
+
+	// Скрытие списывает баллы, возврат начисляет их снова
+	rec := s.decide(created.ID, "hide", nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), publicBefore, s.publicCount())
+	require.Equal(s.T(), pointsBefore, s.points())
+
+	rec = s.decide(created.ID, "approve", nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), publicBefore+1, s.publicCount())
+	require.Equal(s.T(), pointsBefore+10, s.points())
+}
+
+func (s *ModerationTestSuite) TestRejectPublishedRevokesPoints() {
+	pointsBefore := s.points()
+
+	created := s.submit()
+	require.Equal(s.T(), pointsBefore+10, s.points())
+
+	rec := s.decide(created.ID, "reject", map[string]any{"reason": "Накрутка"})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), pointsBefore, s.points())
+}
+
+func (s *ModerationTestSuite) TestAdminStatsCountPublished() {
+	rec := s.TS.Do(http.MethodGet, "/admin/stats", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var before dto.AdminStatsResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &before))
+
+	s.setMode("pre")
+	s.submit()
+
+	rec = s.TS.Do(http.MethodGet, "/admin/stats", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var after dto.AdminStatsResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &after))
+	require.Equal(s.T(), before.TotalReviews, after.TotalReviews)
+	require.Equal(s.T(), before.PendingReviews+1, after.PendingReviews)
+}
+
+func (s *ModerationTestSuite) TestSettingsValidation() {
+	rec := s.TS.Do(http.MethodPut, "/admin/places/"+moderationPlaceID+"/moderation", s.AdminToken, map[string]any{"mode": "later"})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	settings := s.setMode(nil)
+	require.Nil(s.T(), settings.Mode)
+	require.Equal(s.T(), s.TS.Cfg.ReviewModerationMode, settings.Effective)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/reviews?status=unknown", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/reviews?cursor=broken", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/reviews", s.UserToken, nil)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
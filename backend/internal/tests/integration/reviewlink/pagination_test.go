@@ -0,0 +1,197 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const paginationPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+
+type PaginationTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+}
+
+func TestPaginationSuite(t *testing.T) {
+	suite.Run(t, new(PaginationTestSuite))
+}
+
+func (s *PaginationTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *PaginationTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *PaginationTestSuite) SetupTest() {
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/pagination/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/pagination/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+}
+
+func (s *PaginationTestSuite) get(path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+	return rec
+}
+
+// reviewPages проходит все страницы списка отзывов по курсору и возвращает номера отзывов по порядку
+func (s *PaginationTestSuite) reviewPages(sort string, limit string) ([]string, int) {
+	var (
+		ids    []string
+		pages  int
+		cursor string
+	)
+	for {
+		params := url.Values{"sort": {sort}, "limit": {limit}}
+		if cursor != "" {
+			params.Set("cursor", cursor)
+		}
+
+		rec := s.get("/places/"+paginationPlaceID+"/reviews?"+params.Encode(), "")
+		require.Equal(s.T(), http.StatusOK, rec.Code, rec.Body.String())
+		require.Equal(s.T(), "5", rec.Header().Get("X-Total-Count"))
+
+		var reviews []dto.ReviewResponse
+		require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &reviews))
+		for _, r := range reviews {
+			ids = append(ids, r.ID[len(r.ID)-1:])
+		}
+		pages++
+
+		cursor = rec.Header().Get("X-Next-Cursor")
+		if cursor == "" {
+			return ids, pages
+		}
+	}
+}
+
+func (s *PaginationTestSuite) TestReviewSorts() {
+	cases := map[string][]string{
+		"date_desc":   {"5", "4", "3", "2", "1"},
+		"date_asc":    {"1", "2", "3", "4", "5"},
+		"rating_desc": {"5", "1", "4", "2", "3"},
+		"rating_asc":  {"3", "2", "4", "5", "1"},
+		"helpful":     {"2", "4", "1", "5", "3"},
+	}
+
+	for sort, want := range cases {
+		ids, pages := s.reviewPages(sort, "2")
+		require.Equal(s.T(), want, ids, sort)
+		require.Equal(s.T(), 3, pages, sort)
+	}
+}
+
+func (s *PaginationTestSuite) TestDefaultPage() {
+	rec := s.get("/places/"+paginationPlaceID+"/reviews", "")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "5", rec.Header().Get("X-Total-Count"))
+	require.Empty(s.T(), rec.Header().Get("X-Next-Cursor"))
+
+	// Общее число учитывает фильтры
+	rec = s.get("/places/"+paginationPlaceID+"/reviews?rating=5&limit=1", "")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "2", rec.Header().Get("X-Total-Count"))
+	require.NotEmpty(s.T(), rec.Header().Get("X-Next-Cursor"))
+}
+
+func (s *PaginationTestSuite) TestInvalidParams() {
+	base := "/places/" + paginationPlaceID + "/reviews"
+
+	rec := s.get(base+"?sort=random", "")
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.get(base+"?limit=0", "")
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.get(base+"?cursor=not-a-cursor", "")
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	// Курсор выдаётся для конкретной сортировки
+	rec = s.get(base+"?sort=helpful&limit=1", "")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	cursor := rec.Header().Get("X-Next-Cursor")
+	require.NotEmpty(s.T(), cursor)
+
+	rec = s.get(base+"?sort=date_desc&cursor="+url.QueryEscape(cursor), "")
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (s *PaginationTestSuite) TestPlacesPages() {
+	rec := s.get("/places?limit=2", s.AdminToken)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "3", rec.Header().Get("X-Total-Count"))
+
+	var first []dto.PlaceResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &first))
+	require.Len(s.T(), first, 2)
+	require.Equal(s.T(), "Alpha Cafe", first[0].Name)
+	require.Equal(s.T(), "Beta Bar", first[1].Name)
+
+	cursor := rec.Header().Get("X-Next-Cursor")
+	require.NotEmpty(s.T(), cursor)
+
+	rec = s.get("/places?limit=2&cursor="+url.QueryEscape(cursor), s.AdminToken)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var second []dto.PlaceResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &second))
+	require.Len(s.T(), second, 1)
+	require.Equal(s.T(), "Test Place", second[0].Name)
+	require.Empty(s.T(), rec.Header().Get("X-Next-Cursor"))
+}
+
+func (s *PaginationTestSuite) TestPlacesScopedToOwner() {
+	ctx := context.Background()
+	_, err := s.TS.DB.Exec(ctx, `
+		INSERT INTO place_members (id, place_id, user_id, role)
+		VALUES (gen_random_uuid(), 'b1c2d3e4-0000-4000-8000-000000000002', 'b2222222-3333-4444-5555-666666666666', 'owner')`)
+	require.NoError(s.T(), err)
+	defer func() {
+		_, err := s.TS.DB.Exec(ctx, "DELETE FROM place_members")
+		require.NoError(s.T(), err)
+	}()
+	_, err = s.TS.DB.Exec(ctx, "UPDATE users SET role = 'place_owner' WHERE email = 'update@example.com'")
+	require.NoError(s.T(), err)
+
+	ownerToken := s.TS.Login("update@example.com", "password123")
+	rec := s.get("/places", ownerToken)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "1", rec.Header().Get("X-Total-Count"))
+
+	var places []dto.PlaceResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &places))
+	require.Len(s.T(), places, 1)
+	require.Equal(s.T(), "Beta Bar", places[0].Name)
+}
+
+func (s *PaginationTestSuite) TestPageHeadersExposedToCORS() {
+	rec := s.get("/places/"+paginationPlaceID+"/reviews?limit=2", "")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	exposed := rec.Header().Get("Access-Control-Expose-Headers")
+	require.Contains(s.T(), exposed, "X-Total-Count")
+	require.Contains(s.T(), exposed, "X-Next-Cursor")
+}
@@ -0,0 +1,342 @@
+package reviewlink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	photosPlaceID  = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	photosReviewID = "c4a7fdd2-6e9d-4f8a-9c59-44f88d66c7b0" // отзыв John
+)
+
+type PhotosTestSuite struct {
+	suite.Suite
+	TS          *integration.TestSetup
+	AuthorToken string
+	OtherToken  string
+}
+
+func TestPhotosSuite(t *testing.T) {
+	suite.Run(t, new(PhotosTestSuite))
+}
+
+func (s *PhotosTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *PhotosTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *PhotosTestSuite) SetupTest() {
+	s.clear()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AuthorToken = s.TS.Login("john@example.com", "securepass")
+	s.OtherToken = s.TS.Login("bob@example.com", "password123")
+}
+
+func (s *PhotosTestSuite) TearDownTest() {
+	s.clear()
+}
+
+func (s *PhotosTestSuite) clear() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM review_photos")
+	require.NoError(s.T(), err)
+}
+
+type photoFile struct {
+	name string
+	data []byte
+}
+
+func (s *PhotosTestSuite) upload(token string, files ...photoFile) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, f := range files {
+		part, err := w.CreateFormFile("photos", f.name)
+		require.NoError(s.T(), err)
+		_, err = part.Write(f.data)
+		require.NoError(s.T(), err)
+	}
+	require.NoError(s.T(), w.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/reviews/"+photosReviewID+"/photos", &body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *PhotosTestSuite) uploadOK(files ...photoFile) []dto.PhotoResponse {
+	rec := s.upload(s.AuthorToken, files...)
+	require.Equal(s.T(), http.StatusCreated, rec.Code, rec.Body.String())
+
+	var resp dto.UploadPhotosResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(s.T(), resp.Photos, len(files))
+	return resp.Photos
+}
+
+func (s *PhotosTestSuite) listPhotos() []dto.PhotoResponse {
+	rec := s.TS.Do(http.MethodGet, "/places/"+photosPlaceID+"/reviews", "", nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var reviews []dto.ReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &reviews))
+	for _, r := range reviews {
+		if r.ID == photosReviewID {
+			return r.Photos
+		}
+	}
+	s.T().Fatalf("review %s not found in list", photosReviewID)
+	return nil
+}
+
+// fetch скачивает файл по публичной ссылке через приложение
+func (s *PhotosTestSuite) fetch(link string) *httptest.ResponseRecorder {
+	u, err := url.Parse(link)
+	require.NoError(s.T(), err)
+	return s.TS.Do(http.MethodGet, u.Path, "", nil)
+}
+
+func pngFile(w, h int) photoFile {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return photoFile{name: "photo.png", data: buf.Bytes()}
+}
+
+// jpegWithGPS — JPEG с сегментом EXIF: ориентация 6 (поворот на 90°) и метка GPS
+func jpegWithGPS(w, h int) photoFile {
+	var buf bytes.Buffer
+	_ = jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h)), nil)
+	data := buf.Bytes()
+
+	tiff := []byte("II*\x00\x08\x00\x00\x00" + // заголовок TIFF, первый IFD со смещения 8
+		"\x02\x00" + // две записи
+		"\x12\x01\x03\x00\x01\x00\x00\x00\x06\x00\x00\x00" + // Orientation = 6
+		"\x25\x88\x04\x00\x01\x00\x00\x00\x26\x00\x00\x00" + // GPS IFD
+		"\x00\x00\x00\x00" +
+		"GPS 55.7558N 37.6173E")
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	size := len(segment) + 2
+
+	out := []byte{0xFF, 0xD8, 0xFF, 0xE1, byte(size >> 8), byte(size)}
+	out = append(out, segment...)
+	return photoFile{name: "photo.jpg", data: append(out, data[2:]...)}
+}
+
+func (s *PhotosTestSuite) TestUploadAndList() {
+	photos := s.uploadOK(pngFile(40, 30), jpegWithGPS(60, 20))
+
+	listed := s.listPhotos()
+	require.Len(s.T(), listed, 2)
+	require.Equal(s.T(), photos[0].ID, listed[0].ID)
+	require.Equal(s.T(), 40, listed[0].Width)
+
+	// Ориентация из EXIF применена: 60×20 стал 20×60
+	require.Equal(s.T(), 20, listed[1].Width)
+	require.Equal(s.T(), 60, listed[1].Height)
+
+	rec := s.fetch(listed[1].URL)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "image/jpeg", rec.Header().Get("Content-Type"))
+	require.NotContains(s.T(), rec.Body.String(), "Exif")
+	require.NotContains(s.T(), rec.Body.String(), "GPS")
+
+	rec = s.fetch(listed[0].ThumbnailURL)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "image/png", rec.Header().Get("Content-Type"))
+}
+
+func (s *PhotosTestSuite) TestThumbnailIsDownscaled() {
+	photos := s.uploadOK(pngFile(s.TS.Cfg.PhotoThumbnailSize*2, s.TS.Cfg.PhotoThumbnailSize))
+
+	rec := s.fetch(photos[0].ThumbnailURL)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	cfg, err := png.DecodeConfig(rec.Body)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), s.TS.Cfg.PhotoThumbnailSize, cfg.Width)
+	require.Equal(s.T(), s.TS.Cfg.PhotoThumbnailSize/2, cfg.Height)
+}
+
+func (s *PhotosTestSuite) TestValidation() {
+	// Тип определяется по содержимому, а не по имени файла
+	rec := s.upload(s.AuthorToken, photoFile{name: "photo.jpg", data: []byte("<html>not an image</html>")})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.upload(s.AuthorToken, photoFile{name: "photo.jpg", data: []byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 0}})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.upload(s.AuthorToken)
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	big := photoFile{name: "big.png", data: make([]byte, s.TS.Cfg.PhotoMaxSize+1)}
+	copy(big.data, pngFile(2, 2).data)
+	rec = s.upload(s.AuthorToken, big)
+	require.Equal(s.T(), http.StatusRequestEntityTooLarge, rec.Code)
+
+	// Чужой отзыв
+	rec = s.upload(s.OtherToken, pngFile(10, 10))
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+
+	require.Empty(s.T(), s.listPhotos())
+}
+
+func (s *PhotosTestSuite) TestPhotoLimit() {
+	limit := s.TS.Cfg.MaxReviewPhotos
+
+	files := make([]photoFile, 0, limit)
+	for i := 0; i < limit; i++ {
+		files = append(files, pngFile(10+i, 10))
+	}
+	s.uploadOK(files...)
+
+	rec := s.upload(s.AuthorToken, pngFile(10, 10))
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+	require.Len(s.T(), s.listPhotos(), limit)
+}
+
+func (s *PhotosTestSuite) TestDeletePhoto() {
+	photos := s.uploadOK(pngFile(10, 10), pngFile(20, 20))
+
+	rec := s.TS.Do(http.MethodDelete, "/reviews/"+photosReviewID+"/photos/"+photos[0].ID, s.OtherToken, nil)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+
+	rec = s.TS.Do(http.MethodDelete, "/reviews/"+photosReviewID+"/photos/"+photos[0].ID, s.AuthorToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	rec = s.TS.Do(http.MethodDelete, "/reviews/"+photosReviewID+"/photos/"+photos[0].ID, s.AuthorToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+
+	require.Equal(s.T(), http.StatusNotFound, s.fetch(photos[0].URL).Code)
+
+	listed := s.listPhotos()
+	require.Len(s.T(), listed, 1)
+	require.Equal(s.T(), photos[1].ID, listed[0].ID)
+}
+
+func (s *PhotosTestSuite) TestDeleteReviewKeepsPhotosForRestore() {
+	photos := s.uploadOK(pngFile(10, 10))
+
+	rec := s.TS.Do(http.MethodDelete, "/reviews/"+photosReviewID, s.AuthorToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	// Фото помечены удалёнными, файлы на месте до конца окна отмены
+	var deleted int
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM review_photos WHERE review_id = $1 AND deleted_at IS NOT NULL", photosReviewID,
+	).Scan(&deleted)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, deleted)
+
+	// Фото удалённого отзыва по ссылке не отдаются
+	require.Equal(s.T(), http.StatusNotFound, s.fetch(photos[0].URL).Code)
+
+	purged, err := s.TS.PurgeWorker.RunOnce(context.Background())
+	require.NoError(s.T(), err)
+	require.Zero(s.T(), purged)
+
+	rec = s.TS.Do(http.MethodPost, "/reviews/"+photosReviewID+"/restore", s.AuthorToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	restored := s.listPhotos()
+	require.Len(s.T(), restored, 1)
+	require.Equal(s.T(), photos[0].ID, restored[0].ID)
+	require.Equal(s.T(), http.StatusOK, s.fetch(restored[0].URL).Code)
+}
+
+func (s *PhotosTestSuite) TestPurgeAfterUndoWindow() {
+	photos := s.uploadOK(pngFile(10, 10))
+
+	rec := s.TS.Do(http.MethodDelete, "/reviews/"+photosReviewID, s.AuthorToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	// Окно отмены прошло
+	past := time.Now().UTC().Add(-s.TS.Cfg.ReviewUndoDeleteWindow - time.Minute)
+	_, err := s.TS.DB.Exec(context.Background(), "UPDATE reviews SET deleted_at = $1 WHERE id = $2", past, photosReviewID)
+	require.NoError(s.T(), err)
+	_, err = s.TS.DB.Exec(context.Background(), "UPDATE review_photos SET deleted_at = $1 WHERE review_id = $2", past, photosReviewID)
+	require.NoError(s.T(), err)
+
+	purged, err := s.TS.PurgeWorker.RunOnce(context.Background())
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, purged)
+
+	var count int
+	err = s.TS.DB.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM review_photos WHERE review_id = $1", photosReviewID,
+	).Scan(&count)
+	require.NoError(s.T(), err)
+	require.Zero(s.T(), count)
+
+	require.Equal(s.T(), http.StatusNotFound, s.fetch(photos[0].URL).Code)
+	require.Equal(s.T(), http.StatusNotFound, s.fetch(photos[0].ThumbnailURL).Code)
+
+	entries, err := os.ReadDir(filepath.Join(s.TS.Cfg.MediaDir, "reviews", photosReviewID))
+	if err == nil {
+		require.Empty(s.T(), entries, fmt.Sprintf("files left: %v", entries))
+	}
+}
+
+func (s *PhotosTestSuite) TestMediaOnlyForPublishedReviews() {
+	photos := s.uploadOK(pngFile(10, 10))
+	require.Equal(s.T(), http.StatusOK, s.fetch(photos[0].URL).Code)
+
+	for _, status := range []string{"pending", "hidden"} {
+		_, err := s.TS.DB.Exec(context.Background(), "UPDATE reviews SET status = $1 WHERE id = $2", status, photosReviewID)
+		require.NoError(s.T(), err)
+
+		require.Equal(s.T(), http.StatusNotFound, s.fetch(photos[0].URL).Code, status)
+		require.Equal(s.T(), http.StatusNotFound, s.fetch(photos[0].ThumbnailURL).Code, status)
+	}
+
+	_, err := s.TS.DB.Exec(context.Background(), "UPDATE reviews SET status = 'published' WHERE id = $1", photosReviewID)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), http.StatusOK, s.fetch(photos[0].ThumbnailURL).Code)
+}
+
+func (s *PhotosTestSuite) TestMediaKeyTraversal() {
+	rec := s.TS.Do(http.MethodGet, "/media/../../etc/passwd", "", nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, "/media/reviews/%2e%2e/secret", "", nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,220 @@
+package reviewlink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	svcToken "github.com/kulikovroman08/reviewlink-backend/internal/service/token"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	pinPlaceID        = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	pinTokenID        = "6d8f07a2-9d91-4a6a-bc73-8c7a6e8a1f01" // VALIDTOKEN123
+	pinExpiredTokenID = "8a2f0e4c-75aa-482d-931a-2d6c3b214c9a" // EXPIRED00001
+)
+
+type PINTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+	UserToken  string
+}
+
+func TestPINSuite(t *testing.T) {
+	suite.Run(t, new(PINTestSuite))
+}
+
+func (s *PINTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *PINTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *PINTestSuite) SetupTest() {
+	s.clear()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+	s.UserToken = s.TS.Login("bob@example.com", "password123")
+}
+
+func (s *PINTestSuite) TearDownTest() {
+	s.clear()
+}
+
+func (s *PINTestSuite) clear() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM pin_attempts")
+	require.NoError(s.T(), err)
+	_, err = s.TS.DB.Exec(context.Background(), "DELETE FROM token_refill_jobs")
+	require.NoError(s.T(), err)
+}
+
+func (s *PINTestSuite) assign(tokenID string) string {
+	rec := s.TS.Do(http.MethodPut, "/admin/tokens/"+tokenID+"/pin", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.TokenPINResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.PIN
+}
+
+func (s *PINTestSuite) submit(pin string) *httptest.ResponseRecorder {
+	return s.TS.Do(http.MethodPost, "/reviews", s.UserToken, map[string]any{
+		"token":    pin,
+		"place_id": pinPlaceID,
+		"rating":   5,
+		"content":  "Отзыв по коду с чека",
+	})
+}
+
+// wrongPIN возвращает PIN нужной длины, не совпадающий с pin
+func (s *PINTestSuite) wrongPIN(pin string) string {
+	wrong := strings.Repeat("1", s.TS.Cfg.PINLength)
+	if wrong == pin {
+		wrong = strings.Repeat("2", s.TS.Cfg.PINLength)
+	}
+	return wrong
+}
+
+func (s *PINTestSuite) TestAssignPIN() {
+	pin := s.assign(pinTokenID)
+	require.Len(s.T(), pin, s.TS.Cfg.PINLength)
+
+	// Повторная выдача возвращает тот же PIN
+	require.Equal(s.T(), pin, s.assign(pinTokenID))
+
+	rec := s.TS.Do(http.MethodPut, "/admin/tokens/00000000-0000-0000-0000-000000000000/pin", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+
+	rec = s.TS.Do(http.MethodPut, "/admin/tokens/"+pinTokenID+"/pin", s.UserToken, nil)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
+
+func (s *PINTestSuite) TestSubmitByPIN() {
+	pin := s.assign(pinTokenID)
+
+	rec := s.submit(pin)
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var isUsed bool
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT is_used FROM review_tokens WHERE id = $1", pinTokenID,
+	).Scan(&isUsed)
+	require.NoError(s.T(), err)
+	require.True(s.T(), isUsed)
+
+	var succeeded bool
+	err = s.TS.DB.QueryRow(context.Background(),
+		"SELECT succeeded FROM pin_attempts WHERE pin = $1", pin,
+	).Scan(&succeeded)
+	require.NoError(s.T(), err)
+	require.True(s.T(), succeeded)
+
+	// Использованный токен больше не находится по PIN
+	rec = s.submit(pin)
+	require.Equal(s.T(), http.StatusUnauthorized, rec.Code)
+}
+
+func (s *PINTestSuite) TestExpiredPIN() {
+	pin := s.wrongPIN("")
+	_, err := s.TS.DB.Exec(context.Background(),
+		"UPDATE review_tokens SET pin = $1 WHERE id = $2", pin, pinExpiredTokenID,
+	)
+	require.NoError(s.T(), err)
+
+	rec := s.submit(pin)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
+
+func (s *PINTestSuite) TestLockoutAfterFailedAttempts() {
+	pin := s.assign(pinTokenID)
+	wrong := s.wrongPIN(pin)
+
+	for i := 0; i < s.TS.Cfg.PINMaxAttemptsPerUser; i++ {
+		rec := s.submit(wrong)
+		require.Equal(s.T(), http.StatusUnauthorized, rec.Code)
+	}
+
+	// После лимита не проходит даже верный PIN
+	rec := s.submit(pin)
+	require.Equal(s.T(), http.StatusTooManyRequests, rec.Code)
+
+	var failed int
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM pin_attempts WHERE succeeded = false AND place_id = $1", pinPlaceID,
+	).Scan(&failed)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), s.TS.Cfg.PINMaxAttemptsPerUser+1, failed)
+
+	// Токен не погашен
+	var isUsed bool
+	err = s.TS.DB.QueryRow(context.Background(),
+		"SELECT is_used FROM review_tokens WHERE id = $1", pinTokenID,
+	).Scan(&isUsed)
+	require.NoError(s.T(), err)
+	require.False(s.T(), isUsed)
+}
+
+func (s *PINTestSuite) TestForwardedForIgnoredWithoutTrustedProxies() {
+	pin := s.assign(pinTokenID)
+	wrong := s.wrongPIN(pin)
+
+	// Подменённый X-Forwarded-For не меняет IP клиента: лимит по IP не обойти
+	for _, forwarded := range []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"} {
+		data, _ := json.Marshal(map[string]any{
+			"token":    wrong,
+			"place_id": pinPlaceID,
+			"rating":   5,
+			"content":  "Отзыв по коду с чека",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/reviews", bytes.NewReader(data))
+		req.Header.Set("Authorization", "Bearer "+s.UserToken)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwarded)
+
+		rec := httptest.NewRecorder()
+		s.TS.App.ServeHTTP(rec, req)
+		require.Equal(s.T(), http.StatusUnauthorized, rec.Code)
+	}
+
+	var ips []string
+	rows, err := s.TS.DB.Query(context.Background(), "SELECT DISTINCT ip FROM pin_attempts")
+	require.NoError(s.T(), err)
+	defer rows.Close()
+	for rows.Next() {
+		var ip string
+		require.NoError(s.T(), rows.Scan(&ip))
+		ips = append(ips, ip)
+	}
+	require.NoError(s.T(), rows.Err())
+	require.Equal(s.T(), []string{"192.0.2.1"}, ips)
+}
+
+func (s *PINTestSuite) TestValidatePINLength() {
+	require.NoError(s.T(), svcToken.ValidatePINLength(6, 10, true))
+	require.ErrorIs(s.T(), svcToken.ValidatePINLength(0, 10, true), svcToken.ErrInvalidGeneratorConfig)
+	require.ErrorIs(s.T(), svcToken.ValidatePINLength(9, 10, true), svcToken.ErrInvalidGeneratorConfig)
+
+	// Токен той же длины, что и PIN, неотличим от PIN
+	require.ErrorIs(s.T(), svcToken.ValidatePINLength(8, 8, false), svcToken.ErrInvalidGeneratorConfig)
+	require.ErrorIs(s.T(), svcToken.ValidatePINLength(8, 7, true), svcToken.ErrInvalidGeneratorConfig)
+	require.NoError(s.T(), svcToken.ValidatePINLength(8, 8, true))
+}
@@ -0,0 +1,192 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	membersTestPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	membersBobID       = "fcb2f1a1-3c64-45d9-9f83-f89adf1f9b23"
+)
+
+type PlaceMembersTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+}
+
+func TestPlaceMembersSuite(t *testing.T) {
+	suite.Run(t, new(PlaceMembersTestSuite))
+}
+
+func (s *PlaceMembersTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *PlaceMembersTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *PlaceMembersTestSuite) SetupTest() {
+	s.clearMembers()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/bonuses/bonus_rewards.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+}
+
+func (s *PlaceMembersTestSuite) TearDownTest() {
+	s.clearMembers()
+}
+
+func (s *PlaceMembersTestSuite) clearMembers() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM place_members")
+	require.NoError(s.T(), err)
+}
+
+func (s *PlaceMembersTestSuite) addMember(token, placeID, email, role string) *httptest.ResponseRecorder {
+	return s.TS.Do(http.MethodPost, "/places/"+placeID+"/members", token, map[string]any{
+		"email": email,
+		"role":  role,
+	})
+}
+
+func (s *PlaceMembersTestSuite) TestCashierValidatesBonusAtOwnPlace() {
+	rec := s.addMember(s.AdminToken, membersTestPlaceID, "bob@example.com", "cashier")
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	cashierToken := s.TS.Login("bob@example.com", "password123")
+
+	rec = s.TS.Do(http.MethodPost, "/bonuses/validate", cashierToken, map[string]any{"qr_token": "bonus123qr"})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var validatedBy, validatedPlace string
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT validated_by::text, validated_place_id::text FROM bonus_rewards WHERE qr_token = $1", "bonus123qr",
+	).Scan(&validatedBy, &validatedPlace)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), membersBobID, validatedBy)
+	require.Equal(s.T(), membersTestPlaceID, validatedPlace)
+}
+
+func (s *PlaceMembersTestSuite) TestCashierOfAnotherPlaceCannotValidate() {
+	rec := s.TS.Do(http.MethodPost, "/places", s.AdminToken, map[string]any{
+		"name":    "Other Place",
+		"address": "1 Other St",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var place dto.CreatePlaceResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &place))
+
+	rec = s.addMember(s.AdminToken, place.ID, "bob@example.com", "cashier")
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	cashierToken := s.TS.Login("bob@example.com", "password123")
+
+	rec = s.TS.Do(http.MethodPost, "/bonuses/validate", cashierToken, map[string]any{"qr_token": "bonus123qr"})
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/bonuses/validate", cashierToken, map[string]any{
+		"qr_token": "bonus123qr",
+		"place_id": place.ID,
+	})
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "bonus belongs to another place")
+}
+
+func (s *PlaceMembersTestSuite) TestOwnerManagesMembers() {
+	rec := s.addMember(s.AdminToken, membersTestPlaceID, "update@example.com", "owner")
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	ownerToken := s.TS.Login("update@example.com", "password123")
+
+	rec = s.addMember(ownerToken, membersTestPlaceID, "bob@example.com", "cashier")
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	rec = s.addMember(ownerToken, membersTestPlaceID, "john@example.com", "owner")
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, "/places/"+membersTestPlaceID+"/members", ownerToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var members []dto.PlaceMemberResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &members))
+	require.Len(s.T(), members, 2)
+
+	rec = s.TS.Do(http.MethodDelete, "/places/"+membersTestPlaceID+"/members/"+membersBobID, ownerToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var role string
+	err := s.TS.DB.QueryRow(context.Background(), "SELECT role FROM users WHERE id = $1", membersBobID).Scan(&role)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "user", role)
+}
+
+func (s *PlaceMembersTestSuite) TestDuplicateMember() {
+	rec := s.addMember(s.AdminToken, membersTestPlaceID, "bob@example.com", "cashier")
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	rec = s.addMember(s.AdminToken, membersTestPlaceID, "bob@example.com", "manager")
+	require.Equal(s.T(), http.StatusConflict, rec.Code)
+}
+
+func (s *PlaceMembersTestSuite) TestUserCannotManageMembers() {
+	token := s.TS.Login("bob@example.com", "password123")
+
+	rec := s.addMember(token, membersTestPlaceID, "john@example.com", "cashier")
+
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
+
+func (s *PlaceMembersTestSuite) TestRemoveMemberKeepsRoleFromOtherPlaces() {
+	rec := s.TS.Do(http.MethodPost, "/places", s.AdminToken, map[string]any{
+		"name":    "Second Place",
+		"address": "2 Other St",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var place dto.CreatePlaceResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &place))
+
+	rec = s.addMember(s.AdminToken, place.ID, "bob@example.com", "owner")
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+	rec = s.addMember(s.AdminToken, membersTestPlaceID, "bob@example.com", "cashier")
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	rec = s.TS.Do(http.MethodDelete, "/places/"+membersTestPlaceID+"/members/"+membersBobID, s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var role string
+	err := s.TS.DB.QueryRow(context.Background(), "SELECT role FROM users WHERE id = $1", membersBobID).Scan(&role)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "place_owner", role)
+
+	rec = s.TS.Do(http.MethodDelete, "/places/"+place.ID+"/members/"+membersBobID, s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	err = s.TS.DB.QueryRow(context.Background(), "SELECT role FROM users WHERE id = $1", membersBobID).Scan(&role)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "user", role)
+}
+
+func (s *PlaceMembersTestSuite) TestRemoveMemberInvalidUserID() {
+	rec := s.TS.Do(http.MethodDelete, "/places/"+membersTestPlaceID+"/members/not-a-uuid", s.AdminToken, nil)
+
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "invalid user_id")
+}
@@ -0,0 +1,216 @@
+package reviewlink
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/service/pos"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const posPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+
+type POSTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+	UserToken  string
+}
+
+func TestPOSSuite(t *testing.T) {
+	suite.Run(t, new(POSTestSuite))
+}
+
+func (s *POSTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *POSTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *POSTestSuite) SetupTest() {
+	s.clearPOS()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+	s.UserToken = s.TS.Login("update@example.com", "password123")
+}
+
+func (s *POSTestSuite) TearDownTest() {
+	s.clearPOS()
+}
+
+func (s *POSTestSuite) clearPOS() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM place_pos_integrations")
+	require.NoError(s.T(), err)
+	_, err = s.TS.DB.Exec(context.Background(), "DELETE FROM token_refill_jobs")
+	require.NoError(s.T(), err)
+}
+
+func (s *POSTestSuite) rotateSecret() []byte {
+	rec := s.TS.Do(http.MethodPut, "/places/"+posPlaceID+"/pos", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.POSIntegrationResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+
+	secret, err := hex.DecodeString(resp.Secret)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), secret, 32)
+	return secret
+}
+
+func (s *POSTestSuite) sendReceipt(secret []byte, ts time.Time, receiptID string, amount int64) *httptest.ResponseRecorder {
+	return s.sendReceiptAt(secret, ts, receiptID, amount, "2025-12-19T12:30:00Z")
+}
+
+func (s *POSTestSuite) sendReceiptAt(secret []byte, ts time.Time, receiptID string, amount int64, purchasedAt string) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(map[string]any{
+		"place_id":     posPlaceID,
+		"receipt_id":   receiptID,
+		"amount":       amount,
+		"purchased_at": purchasedAt,
+	})
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+
+	req := httptest.NewRequest(http.MethodPost, "/pos/receipts", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Reviewlink-Timestamp", timestamp)
+	req.Header.Set("X-Reviewlink-Signature", pos.Sign(secret, timestamp, payload))
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *POSTestSuite) TestReceiptTokenIsIdempotent() {
+	secret := s.rotateSecret()
+
+	rec := s.sendReceipt(secret, time.Now(), "R-1001", 125000)
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var first dto.ReceiptTokenResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &first))
+	require.NotEmpty(s.T(), first.Token)
+	require.Contains(s.T(), first.URL, first.Token)
+
+	rec = s.sendReceipt(secret, time.Now(), "R-1001", 125000)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var replay dto.ReceiptTokenResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &replay))
+	require.Equal(s.T(), first.Token, replay.Token)
+
+	var count int
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM review_tokens WHERE receipt_id = 'R-1001'",
+	).Scan(&count)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, count)
+}
+
+func (s *POSTestSuite) TestReceiptConflict() {
+	secret := s.rotateSecret()
+
+	rec := s.sendReceipt(secret, time.Now(), "R-2002", 5000)
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	rec = s.sendReceipt(secret, time.Now(), "R-2002", 7000)
+	require.Equal(s.T(), http.StatusConflict, rec.Code)
+}
+
+func (s *POSTestSuite) TestReceiptConflictOnPurchaseTime() {
+	secret := s.rotateSecret()
+
+	rec := s.sendReceipt(secret, time.Now(), "R-2003", 5000)
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	// Тот же момент в другом часовом поясе — повтор события, а не другой чек
+	rec = s.sendReceiptAt(secret, time.Now(), "R-2003", 5000, "2025-12-19T15:30:00+03:00")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	rec = s.sendReceiptAt(secret, time.Now(), "R-2003", 5000, "2025-12-19T12:45:00Z")
+	require.Equal(s.T(), http.StatusConflict, rec.Code)
+}
+
+func (s *POSTestSuite) TestInvalidSignature() {
+	secret := s.rotateSecret()
+
+	rec := s.sendReceipt([]byte("wrong-secret"), time.Now(), "R-3003", 1000)
+	require.Equal(s.T(), http.StatusUnauthorized, rec.Code)
+
+	rec = s.sendReceipt(secret, time.Now().Add(-time.Hour), "R-3003", 1000)
+	require.Equal(s.T(), http.StatusUnauthorized, rec.Code)
+
+	// После перевыпуска старый секрет больше не принимается
+	s.rotateSecret()
+	rec = s.sendReceipt(secret, time.Now(), "R-3003", 1000)
+	require.Equal(s.T(), http.StatusUnauthorized, rec.Code)
+}
+
+func (s *POSTestSuite) TestPlaceWithoutIntegration() {
+	rec := s.sendReceipt([]byte("any"), time.Now(), "R-4004", 1000)
+	require.Equal(s.T(), http.StatusUnauthorized, rec.Code)
+}
+
+func (s *POSTestSuite) TestVerifiedPurchaseReview() {
+	secret := s.rotateSecret()
+
+	rec := s.sendReceipt(secret, time.Now(), "R-5005", 99900)
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var issued dto.ReceiptTokenResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &issued))
+
+	rec = s.TS.Do(http.MethodPost, "/reviews", s.UserToken, map[string]any{
+		"token":    issued.Token,
+		"place_id": posPlaceID,
+		"rating":   5,
+		"content":  "Отзыв по чеку",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var created dto.SubmitReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &created))
+	require.True(s.T(), created.VerifiedPurchase)
+	require.NotNil(s.T(), created.ReceiptID)
+	require.Equal(s.T(), "R-5005", *created.ReceiptID)
+
+	rec = s.TS.Do(http.MethodGet, "/places/"+posPlaceID+"/reviews", "", nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var reviews []dto.ReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &reviews))
+
+	verified := 0
+	for _, r := range reviews {
+		if r.VerifiedPurchase {
+			verified++
+			require.Equal(s.T(), "Отзыв по чеку", r.Content)
+		}
+	}
+	require.Equal(s.T(), 1, verified)
+}
+
+func (s *POSTestSuite) TestRotateSecretForbidden() {
+	rec := s.TS.Do(http.MethodPut, "/places/"+posPlaceID+"/pos", s.UserToken, nil)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
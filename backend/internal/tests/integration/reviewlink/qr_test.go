@@ -0,0 +1,177 @@
+package reviewlink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const qrTestPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+
+type QRTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+}
+
+func TestQRSuite(t *testing.T) {
+	suite.Run(t, new(QRTestSuite))
+}
+
+func (s *QRTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *QRTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *QRTestSuite) SetupTest() {
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/bonuses/bonus_rewards.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+}
+
+func (s *QRTestSuite) TearDownTest() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM place_members")
+	require.NoError(s.T(), err)
+}
+
+func (s *QRTestSuite) get(path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *QRTestSuite) TestTokenQRPNG() {
+	rec := s.get("/admin/places/"+qrTestPlaceID+"/tokens/VALIDTOKEN123/qr", s.AdminToken)
+
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "image/png", rec.Header().Get("Content-Type"))
+	require.True(s.T(), bytes.HasPrefix(rec.Body.Bytes(), []byte("\x89PNG")))
+}
+
+func (s *QRTestSuite) TestTokenQRSVG() {
+	rec := s.get("/admin/places/"+qrTestPlaceID+"/tokens/VALIDTOKEN123/qr?format=svg&size=128", s.AdminToken)
+
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "image/svg+xml", rec.Header().Get("Content-Type"))
+	require.Contains(s.T(), rec.Body.String(), `width="128"`)
+}
+
+func (s *QRTestSuite) TestTokenQRInvalidParams() {
+	rec := s.get("/admin/places/"+qrTestPlaceID+"/tokens/VALIDTOKEN123/qr?format=gif", s.AdminToken)
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.get("/admin/places/"+qrTestPlaceID+"/tokens/VALIDTOKEN123/qr?size=5000", s.AdminToken)
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (s *QRTestSuite) TestTokenQRWrongPlace() {
+	rec := s.get("/admin/places/0b0e5c1d-1111-4a4a-9b9b-222222222222/tokens/VALIDTOKEN123/qr", s.AdminToken)
+
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *QRTestSuite) TestBonusQROnlyForOwner() {
+	johnToken := s.TS.Login("john@example.com", "securepass")
+	rec := s.get("/bonuses/bonus123qr/qr", johnToken)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "image/png", rec.Header().Get("Content-Type"))
+
+	bobToken := s.TS.Login("bob@example.com", "password123")
+	rec = s.get("/bonuses/bonus123qr/qr", bobToken)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *QRTestSuite) print(token string, payload map[string]any) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(payload)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/places/"+qrTestPlaceID+"/tokens/print", bytes.NewReader(data))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *QRTestSuite) TestPrintTokensPDF() {
+	var before int
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM review_tokens WHERE place_id = $1", qrTestPlaceID).Scan(&before)
+	require.NoError(s.T(), err)
+
+	rec := s.print(s.AdminToken, map[string]any{"count": 4, "page_size": "Letter", "columns": 2, "rows": 2})
+
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "application/pdf", rec.Header().Get("Content-Type"))
+	require.True(s.T(), bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF")))
+
+	var after int
+	err = s.TS.DB.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM review_tokens WHERE place_id = $1", qrTestPlaceID).Scan(&after)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), before+4, after)
+}
+
+func (s *QRTestSuite) TestPrintTokensInvalidLayout() {
+	rec := s.print(s.AdminToken, map[string]any{"count": 4, "columns": 20})
+
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (s *QRTestSuite) TestPrintTokensForbiddenForUser() {
+	token := s.TS.Login("bob@example.com", "password123")
+
+	rec := s.print(token, map[string]any{"count": 4})
+
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
+
+func (s *QRTestSuite) addMember(email, role string) {
+	data, _ := json.Marshal(map[string]any{"email": email, "role": role})
+
+	req := httptest.NewRequest(http.MethodPost, "/places/"+qrTestPlaceID+"/members", bytes.NewReader(data))
+	req.Header.Set("Authorization", "Bearer "+s.AdminToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+}
+
+func (s *QRTestSuite) TestPrintTokensForManager() {
+	s.addMember("bob@example.com", "manager")
+	token := s.TS.Login("bob@example.com", "password123")
+
+	rec := s.print(token, map[string]any{"count": 2})
+
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "application/pdf", rec.Header().Get("Content-Type"))
+}
+
+func (s *QRTestSuite) TestPrintTokensForbiddenForCashier() {
+	s.addMember("bob@example.com", "cashier")
+	token := s.TS.Login("bob@example.com", "password123")
+
+	rec := s.print(token, map[string]any{"count": 2})
+
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
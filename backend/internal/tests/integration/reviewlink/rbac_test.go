@@ -0,0 +1,127 @@
+package reviewlink
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RBACTestSuite struct {
+	suite.Suite
+	TS *integration.TestSetup
+}
+
+func TestRBACSuite(t *testing.T) {
+	suite.Run(t, new(RBACTestSuite))
+}
+
+func (s *RBACTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *RBACTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *RBACTestSuite) SetupTest() {
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/bonuses/bonus_rewards.yml",
+	)
+}
+
+func (s *RBACTestSuite) validateBonus(token string) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(map[string]any{"qr_token": "bonus123qr"})
+
+	req := httptest.NewRequest(http.MethodPost, "/bonuses/validate", bytes.NewReader(data))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *RBACTestSuite) changeRole(adminToken, userID, role string) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(map[string]any{"role": role})
+
+	req := httptest.NewRequest(http.MethodPatch, "/admin/users/"+userID+"/role", bytes.NewReader(data))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *RBACTestSuite) TestValidateBonusForbiddenForUser() {
+	token := s.TS.Login("bob@example.com", "password123")
+
+	rec := s.validateBonus(token)
+
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+	require.Equal(s.T(), `{"error":"access denied"}`, rec.Body.String())
+}
+
+func (s *RBACTestSuite) TestStaffWithoutMembershipCannotValidateBonus() {
+	const bobID = "fcb2f1a1-3c64-45d9-9f83-f89adf1f9b23"
+
+	adminToken := s.TS.Login("admin@example.com", "securepass")
+
+	rec := s.changeRole(adminToken, bobID, "staff")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	staffToken := s.TS.Login("bob@example.com", "password123")
+
+	rec = s.validateBonus(staffToken)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "not a member")
+}
+
+func (s *RBACTestSuite) TestStaffCannotCreatePlace() {
+	const bobID = "fcb2f1a1-3c64-45d9-9f83-f89adf1f9b23"
+
+	adminToken := s.TS.Login("admin@example.com", "securepass")
+	require.Equal(s.T(), http.StatusOK, s.changeRole(adminToken, bobID, "staff").Code)
+
+	staffToken := s.TS.Login("bob@example.com", "password123")
+
+	data, _ := json.Marshal(map[string]any{"name": "Staff Place", "address": "Nowhere"})
+	req := httptest.NewRequest(http.MethodPost, "/places", bytes.NewReader(data))
+	req.Header.Set("Authorization", "Bearer "+staffToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
+
+func (s *RBACTestSuite) TestChangeRoleInvalidRole() {
+	const bobID = "fcb2f1a1-3c64-45d9-9f83-f89adf1f9b23"
+
+	adminToken := s.TS.Login("admin@example.com", "securepass")
+
+	rec := s.changeRole(adminToken, bobID, "superuser")
+
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "invalid role")
+}
+
+func (s *RBACTestSuite) TestChangeRoleForbiddenForUser() {
+	const johnID = "a1111111-2222-3333-4444-555555555555"
+
+	token := s.TS.Login("bob@example.com", "password123")
+
+	rec := s.changeRole(token, johnID, "admin")
+
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,148 @@
+package reviewlink
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	repoRefill "github.com/kulikovroman08/reviewlink-backend/internal/repository/refill"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const refillPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+
+type RefillWorkerTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+}
+
+func TestRefillWorkerSuite(t *testing.T) {
+	suite.Run(t, new(RefillWorkerTestSuite))
+}
+
+func (s *RefillWorkerTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *RefillWorkerTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *RefillWorkerTestSuite) SetupTest() {
+	s.clearJobs()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+
+	// Порог заведомо выше числа активных токенов в фикстурах, чтобы каждая задача догенерировала пачку
+	rec := s.TS.Do(http.MethodPut, "/admin/places/"+refillPlaceID+"/token-policy", s.AdminToken, map[string]any{
+		"refill_threshold": 3,
+		"refill_batch":     5,
+	})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+}
+
+func (s *RefillWorkerTestSuite) TearDownTest() {
+	s.clearJobs()
+}
+
+func (s *RefillWorkerTestSuite) clearJobs() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM token_refill_jobs")
+	require.NoError(s.T(), err)
+}
+
+func (s *RefillWorkerTestSuite) countTokens() int {
+	var count int
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM review_tokens WHERE place_id = $1", refillPlaceID,
+	).Scan(&count)
+	require.NoError(s.T(), err)
+	return count
+}
+
+func (s *RefillWorkerTestSuite) countJobs() int {
+	var count int
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM token_refill_jobs WHERE place_id = $1", refillPlaceID,
+	).Scan(&count)
+	require.NoError(s.T(), err)
+	return count
+}
+
+func (s *RefillWorkerTestSuite) TestReviewEnqueuesRefill() {
+	before := s.countTokens()
+
+	userToken := s.TS.Login("bob@example.com", "password123")
+	rec := s.TS.Do(http.MethodPost, "/reviews", userToken, map[string]any{
+		"rating":   5,
+		"content":  "Фоновая догенерация",
+		"place_id": refillPlaceID,
+		"token":    "VALIDTOKEN123",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	// Запрос отзыва не генерирует токены сам, а только ставит задачу
+	require.Equal(s.T(), before, s.countTokens())
+	require.Equal(s.T(), 1, s.countJobs())
+
+	completed, err := s.TS.RefillWorker.RunOnce(context.Background())
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, completed)
+
+	require.Equal(s.T(), before+5, s.countTokens())
+	require.Equal(s.T(), 0, s.countJobs())
+}
+
+func (s *RefillWorkerTestSuite) TestConcurrentWorkersGenerateSingleBatch() {
+	repo := repoRefill.NewPostgresRefillRepository(s.TS.DB)
+	require.NoError(s.T(), repo.EnqueueRefill(context.Background(), uuid.MustParse(refillPlaceID)))
+
+	before := s.countTokens()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = s.TS.RefillWorker.RunOnce(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	// Проигравшие блокировку воркеры оставляют задачу; повторный проход видит пополненный запас
+	_, err := s.TS.RefillWorker.RunOnce(context.Background())
+	require.NoError(s.T(), err)
+
+	require.Equal(s.T(), before+5, s.countTokens())
+	require.Equal(s.T(), 0, s.countJobs())
+}
+
+func (s *RefillWorkerTestSuite) TestRequestDuringProcessingKeepsJob() {
+	ctx := context.Background()
+	repo := repoRefill.NewPostgresRefillRepository(s.TS.DB)
+	placeID := uuid.MustParse(refillPlaceID)
+
+	require.NoError(s.T(), repo.EnqueueRefill(ctx, placeID))
+
+	jobs, err := repo.ListDueJobs(ctx, 10)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), jobs, 1)
+
+	// Новый отзыв пришёл, пока воркер обрабатывал задачу
+	require.NoError(s.T(), repo.EnqueueRefill(ctx, placeID))
+
+	require.NoError(s.T(), repo.CompleteJob(ctx, jobs[0]))
+	require.Equal(s.T(), 1, s.countJobs())
+}
@@ -0,0 +1,228 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	repliesPlaceID  = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	repliesReviewID = "c4a7fdd2-6e9d-4f8a-9c59-44f88d66c7b0" // отзыв John
+)
+
+type RepliesTestSuite struct {
+	suite.Suite
+	TS           *integration.TestSetup
+	AdminToken   string
+	OwnerToken   string
+	CashierToken string
+	UserToken    string
+}
+
+func TestRepliesSuite(t *testing.T) {
+	suite.Run(t, new(RepliesTestSuite))
+}
+
+func (s *RepliesTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *RepliesTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *RepliesTestSuite) SetupTest() {
+	s.clear()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+
+	for email, role := range map[string]string{"update@example.com": "owner", "bob@example.com": "cashier"} {
+		rec := s.TS.Do(http.MethodPost, "/places/"+repliesPlaceID+"/members", s.AdminToken, map[string]any{
+			"email": email,
+			"role":  role,
+		})
+		require.Equal(s.T(), http.StatusCreated, rec.Code)
+	}
+
+	s.OwnerToken = s.TS.Login("update@example.com", "password123")
+	s.CashierToken = s.TS.Login("bob@example.com", "password123")
+	s.UserToken = s.TS.Login("john@example.com", "securepass")
+}
+
+func (s *RepliesTestSuite) TearDownTest() {
+	s.clear()
+}
+
+func (s *RepliesTestSuite) clear() {
+	for _, table := range []string{"review_replies", "reply_templates", "place_members"} {
+		_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM "+table)
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *RepliesTestSuite) reply(method, token string, payload any) *httptest.ResponseRecorder {
+	return s.TS.Do(method, "/reviews/"+repliesReviewID+"/reply", token, payload)
+}
+
+func (s *RepliesTestSuite) publicReview() dto.ReviewResponse {
+	rec := s.TS.Do(http.MethodGet, "/places/"+repliesPlaceID+"/reviews", "", nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var reviews []dto.ReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &reviews))
+	for _, r := range reviews {
+		if r.ID == repliesReviewID {
+			return r
+		}
+	}
+	s.T().Fatalf("review %s not found in public list", repliesReviewID)
+	return dto.ReviewResponse{}
+}
+
+func (s *RepliesTestSuite) TestOwnerReplyLifecycle() {
+	rec := s.reply(http.MethodPost, s.OwnerToken, map[string]any{"content": " Спасибо, ждём снова! "})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var created dto.ReplyResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &created))
+	require.Equal(s.T(), "Спасибо, ждём снова!", created.Content)
+
+	// Второй ответ на тот же отзыв не допускается
+	rec = s.reply(http.MethodPost, s.OwnerToken, map[string]any{"content": "Ещё раз спасибо"})
+	require.Equal(s.T(), http.StatusConflict, rec.Code)
+
+	review := s.publicReview()
+	require.NotNil(s.T(), review.Reply)
+	require.Equal(s.T(), created.ID, review.Reply.ID)
+
+	rec = s.reply(http.MethodPut, s.OwnerToken, map[string]any{"content": "Спасибо за отзыв!"})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var updated dto.ReplyResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &updated))
+	require.Equal(s.T(), created.ID, updated.ID)
+	require.NotNil(s.T(), updated.UpdatedAt)
+	require.Equal(s.T(), "Спасибо за отзыв!", s.publicReview().Reply.Content)
+
+	rec = s.reply(http.MethodDelete, s.OwnerToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Nil(s.T(), s.publicReview().Reply)
+
+	rec = s.reply(http.MethodDelete, s.OwnerToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *RepliesTestSuite) TestReplyAccess() {
+	// Автор отзыва не сотрудник заведения
+	rec := s.reply(http.MethodPost, s.UserToken, map[string]any{"content": "Сам себе отвечу"})
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+
+	rec = s.reply(http.MethodPost, s.CashierToken, map[string]any{"content": "   "})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/reviews/00000000-0000-0000-0000-000000000000/reply", s.CashierToken, map[string]any{"content": "Спасибо"})
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+
+	// Кассир отвечает, но шаблонами не управляет
+	rec = s.reply(http.MethodPost, s.CashierToken, map[string]any{"content": "Спасибо"})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/places/"+repliesPlaceID+"/reply-templates", s.CashierToken, map[string]any{
+		"title":   "Благодарность",
+		"content": "Спасибо за отзыв!",
+	})
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
+
+func (s *RepliesTestSuite) TestTemplates() {
+	path := "/places/" + repliesPlaceID + "/reply-templates"
+	payload := map[string]any{"title": "Благодарность", "content": "Спасибо за отзыв!"}
+
+	rec := s.TS.Do(http.MethodPost, path, s.OwnerToken, payload)
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var created dto.ReplyTemplateResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &created))
+
+	rec = s.TS.Do(http.MethodPost, path, s.OwnerToken, payload)
+	require.Equal(s.T(), http.StatusConflict, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, path, s.CashierToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var templates []dto.ReplyTemplateResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &templates))
+	require.Len(s.T(), templates, 1)
+	require.Equal(s.T(), "Спасибо за отзыв!", templates[0].Content)
+
+	rec = s.TS.Do(http.MethodDelete, path+"/"+created.ID, s.OwnerToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	rec = s.TS.Do(http.MethodDelete, path+"/"+created.ID, s.OwnerToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *RepliesTestSuite) TestReplyReport() {
+	now := time.Now()
+	query := "?from=" + now.AddDate(0, 0, -1).Format("2006-01-02") + "&to=" + now.AddDate(0, 0, 1).Format("2006-01-02")
+	path := "/places/" + repliesPlaceID + "/reports/replies" + query
+
+	rec := s.TS.Do(http.MethodGet, path, s.OwnerToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var before dto.ReplyStatsResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &before))
+	require.Equal(s.T(), 1, before.Reviews)
+	require.Zero(s.T(), before.Replied)
+	require.Nil(s.T(), before.MedianResponseSeconds)
+
+	rec = s.reply(http.MethodPost, s.OwnerToken, map[string]any{"content": "Спасибо!"})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, path, s.OwnerToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var after dto.ReplyStatsResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &after))
+	require.Equal(s.T(), 1, after.Replied)
+	require.InDelta(s.T(), 1.0, after.ReplyRate, 0.0001)
+	require.NotNil(s.T(), after.MedianResponseSeconds)
+
+	rec = s.TS.Do(http.MethodGet, path, s.CashierToken, nil)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
+
+func (s *RepliesTestSuite) TestReplyReportForPlaceManager() {
+	rec := s.TS.Do(http.MethodDelete, "/places/"+repliesPlaceID+"/members/fcb2f1a1-3c64-45d9-9f83-f89adf1f9b23", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/places/"+repliesPlaceID+"/members", s.AdminToken, map[string]any{
+		"email": "bob@example.com",
+		"role":  "manager",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	managerToken := s.TS.Login("bob@example.com", "password123")
+	rec = s.TS.Do(http.MethodGet, "/places/"+repliesPlaceID+"/reports/replies", managerToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, "/places/"+repliesPlaceID+"/reports/replies", s.UserToken, nil)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,251 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	reportsPlaceID  = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	reportsReviewID = "c4a7fdd2-6e9d-4f8a-9c59-44f88d66c7b0" // отзыв John
+)
+
+type ReportsTestSuite struct {
+	suite.Suite
+	TS          *integration.TestSetup
+	AdminToken  string
+	AuthorToken string
+	BobToken    string
+	OtherToken  string
+}
+
+func TestReportsSuite(t *testing.T) {
+	suite.Run(t, new(ReportsTestSuite))
+}
+
+func (s *ReportsTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *ReportsTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *ReportsTestSuite) SetupTest() {
+	s.clear()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+	s.AuthorToken = s.TS.Login("john@example.com", "securepass")
+	s.BobToken = s.TS.Login("bob@example.com", "password123")
+	s.OtherToken = s.TS.Login("update@example.com", "password123")
+}
+
+func (s *ReportsTestSuite) TearDownTest() {
+	s.clear()
+}
+
+func (s *ReportsTestSuite) clear() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM review_reports")
+	require.NoError(s.T(), err)
+	_, err = s.TS.DB.Exec(context.Background(), "DELETE FROM user_restrictions")
+	require.NoError(s.T(), err)
+}
+
+func (s *ReportsTestSuite) report(token, reason string) *httptest.ResponseRecorder {
+	return s.TS.Do(http.MethodPost, "/reviews/"+reportsReviewID+"/reports", token, map[string]any{
+		"reason":  reason,
+		"comment": "Похоже на накрутку",
+	})
+}
+
+func (s *ReportsTestSuite) resolve(action string) *httptest.ResponseRecorder {
+	return s.TS.Do(http.MethodPost, "/admin/reviews/"+reportsReviewID+"/reports/resolve", s.AdminToken, map[string]any{
+		"action": action,
+	})
+}
+
+func (s *ReportsTestSuite) reviewStatus() string {
+	var status string
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT status FROM reviews WHERE id = $1", reportsReviewID,
+	).Scan(&status)
+	require.NoError(s.T(), err)
+	return status
+}
+
+func (s *ReportsTestSuite) TestCreateReport() {
+	rec := s.report(s.BobToken, "fake")
+	require.Equal(s.T(), http.StatusCreated, rec.Code, rec.Body.String())
+
+	var resp dto.ReportResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(s.T(), reportsReviewID, resp.ReviewID)
+	require.Equal(s.T(), "fake", resp.Reason)
+	require.Equal(s.T(), "open", resp.Status)
+
+	// Повторная жалоба того же пользователя
+	rec = s.report(s.BobToken, "spam")
+	require.Equal(s.T(), http.StatusConflict, rec.Code)
+
+	require.Equal(s.T(), "published", s.reviewStatus())
+}
+
+func (s *ReportsTestSuite) TestInvalidReports() {
+	rec := s.report(s.AuthorToken, "spam")
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+
+	rec = s.report(s.BobToken, "boring")
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/reviews/"+reportsReviewID+"/reports", s.BobToken, map[string]any{
+		"reason":  "spam",
+		"comment": strings.Repeat("я", 501),
+	})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/reviews/00000000-0000-0000-0000-000000000000/reports", s.BobToken, map[string]any{
+		"reason": "spam",
+	})
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *ReportsTestSuite) TestAutoHideAtThreshold() {
+	require.Equal(s.T(), http.StatusCreated, s.report(s.BobToken, "spam").Code)
+	require.Equal(s.T(), http.StatusCreated, s.report(s.OtherToken, "fake").Code)
+	require.Equal(s.T(), "published", s.reviewStatus())
+
+	require.Equal(s.T(), http.StatusCreated, s.report(s.AdminToken, "spam").Code)
+	require.Equal(s.T(), "hidden", s.reviewStatus())
+
+	rec := s.TS.Do(http.MethodGet, "/places/"+reportsPlaceID+"/reviews", "", nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.NotContains(s.T(), rec.Body.String(), reportsReviewID)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/reports", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var queue []dto.ReportedReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &queue))
+	require.Len(s.T(), queue, 1)
+	require.Equal(s.T(), reportsReviewID, queue[0].Review.ID)
+	require.Equal(s.T(), 3, queue[0].Reports)
+	require.Equal(s.T(), map[string]int{"spam": 2, "fake": 1}, queue[0].Reasons)
+}
+
+func (s *ReportsTestSuite) TestResolveDismiss() {
+	require.Equal(s.T(), http.StatusCreated, s.report(s.BobToken, "offensive").Code)
+
+	rec := s.resolve("dismiss")
+	require.Equal(s.T(), http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp dto.ReportResolutionResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(s.T(), 1, resp.Resolved)
+	require.Equal(s.T(), "published", resp.ReviewStatus)
+	require.Nil(s.T(), resp.RestrictedUntil)
+
+	// Открытых жалоб не осталось
+	rec = s.resolve("hide")
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/reviews/"+reportsReviewID+"/reports", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var reports []dto.ReportResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &reports))
+	require.Len(s.T(), reports, 1)
+	require.Equal(s.T(), "resolved", reports[0].Status)
+	require.NotNil(s.T(), reports[0].Resolution)
+	require.Equal(s.T(), "dismiss", *reports[0].Resolution)
+}
+
+func (s *ReportsTestSuite) TestResolveHideAndRestrict() {
+	_, err := s.TS.DB.Exec(context.Background(), "UPDATE reviews SET points = 10 WHERE id = $1", reportsReviewID)
+	require.NoError(s.T(), err)
+
+	require.Equal(s.T(), http.StatusCreated, s.report(s.BobToken, "fake").Code)
+
+	rec := s.resolve("hide_and_restrict")
+	require.Equal(s.T(), http.StatusOK, rec.Code, rec.Body.String())
+
+	// Баллы за скрытый отзыв списаны: у John было 50
+	var points int
+	err = s.TS.DB.QueryRow(context.Background(),
+		"SELECT points FROM users WHERE email = $1", "john@example.com",
+	).Scan(&points)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 40, points)
+
+	var resp dto.ReportResolutionResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(s.T(), "hidden", resp.ReviewStatus)
+	require.NotNil(s.T(), resp.RestrictedUntil)
+	require.Equal(s.T(), "hidden", s.reviewStatus())
+
+	// Автору запрещено оставлять отзывы
+	rec = s.TS.Do(http.MethodPost, "/reviews", s.AuthorToken, map[string]any{
+		"token":    "VALIDTOKEN123",
+		"place_id": reportsPlaceID,
+		"rating":   5,
+		"content":  "Новый отзыв",
+	})
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "posting reviews is restricted")
+}
+
+func (s *ReportsTestSuite) TestResolveKeepsActiveRestriction() {
+	// У автора уже есть запрет дольше ReportRestrictionPeriod: он не сокращается
+	_, err := s.TS.DB.Exec(context.Background(), `
+		INSERT INTO user_restrictions (id, user_id, restriction_type, reason, created_at, expires_at)
+		SELECT gen_random_uuid(), id, 'review_ban', 'earlier ban', now(), now() + interval '365 days'
+		FROM users WHERE email = $1`, "john@example.com",
+	)
+	require.NoError(s.T(), err)
+
+	require.Equal(s.T(), http.StatusCreated, s.report(s.BobToken, "fake").Code)
+
+	rec := s.resolve("hide_and_restrict")
+	require.Equal(s.T(), http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp dto.ReportResolutionResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.NotNil(s.T(), resp.RestrictedUntil)
+
+	var stored time.Time
+	err = s.TS.DB.QueryRow(context.Background(), `
+		SELECT r.expires_at FROM user_restrictions r JOIN users u ON u.id = r.user_id
+		WHERE u.email = $1 AND r.restriction_type = 'review_ban'`, "john@example.com",
+	).Scan(&stored)
+	require.NoError(s.T(), err)
+	require.WithinDuration(s.T(), stored, *resp.RestrictedUntil, time.Millisecond)
+	require.True(s.T(), resp.RestrictedUntil.After(time.Now().Add(300*24*time.Hour)))
+}
+
+func (s *ReportsTestSuite) TestReportsAdminOnly() {
+	rec := s.TS.Do(http.MethodGet, "/admin/reports", s.BobToken, nil)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+
+	rec = s.resolve("ban")
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/reports?status=closed", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+}
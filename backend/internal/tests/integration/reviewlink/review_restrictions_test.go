@@ -8,8 +8,6 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
 
 	"github.com/stretchr/testify/require"
@@ -34,21 +32,12 @@ func (s *ReviewRestrictionsTestSuite) TearDownSuite() {
 }
 
 func (s *ReviewRestrictionsTestSuite) SetupTest() {
-	db := stdlib.OpenDBFromPool(s.TS.DB)
-	defer db.Close()
-
-	fixture, err := testfixtures.New(
-		testfixtures.Database(db),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Files(
-			"../fixtures/restrictions/users.yml",
-			"../fixtures/restrictions/places.yml",
-			"../fixtures/restrictions/review_tokens.yml",
-			"../fixtures/restrictions/user_restrictions.yml",
-		),
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/restrictions/users.yml",
+		"../fixtures/restrictions/places.yml",
+		"../fixtures/restrictions/review_tokens.yml",
+		"../fixtures/restrictions/user_restrictions.yml",
 	)
-	require.NoError(s.T(), err)
-	require.NoError(s.T(), fixture.Load())
 	_, _ = s.TS.DB.Exec(context.Background(), "DELETE FROM reviews")
 }
 
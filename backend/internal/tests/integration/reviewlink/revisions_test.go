@@ -0,0 +1,177 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	revisionsPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	revisionsToken   = "VALIDTOKEN123"
+)
+
+type RevisionsTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+	BobToken   string
+	OtherToken string
+}
+
+func TestRevisionsSuite(t *testing.T) {
+	suite.Run(t, new(RevisionsTestSuite))
+}
+
+func (s *RevisionsTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *RevisionsTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *RevisionsTestSuite) SetupTest() {
+	s.clear()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+	s.BobToken = s.TS.Login("bob@example.com", "password123")
+	s.OtherToken = s.TS.Login("update@example.com", "password123")
+}
+
+func (s *RevisionsTestSuite) TearDownTest() {
+	s.clear()
+}
+
+func (s *RevisionsTestSuite) clear() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM review_revisions")
+	require.NoError(s.T(), err)
+}
+
+// submit оставляет отзыв Bob и возвращает его ID
+func (s *RevisionsTestSuite) submit() string {
+	rec := s.TS.Do(http.MethodPost, "/reviews", s.BobToken, map[string]any{
+		"token":    revisionsToken,
+		"place_id": revisionsPlaceID,
+		"rating":   5,
+		"content":  "Всё понравилось",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code, rec.Body.String())
+
+	var resp dto.SubmitReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.ID
+}
+
+func (s *RevisionsTestSuite) revisions(token, reviewID string) *httptest.ResponseRecorder {
+	return s.TS.Do(http.MethodGet, "/reviews/"+reviewID+"/revisions", token, nil)
+}
+
+func (s *RevisionsTestSuite) find(reviewID string) *dto.ReviewResponse {
+	rec := s.TS.Do(http.MethodGet, "/places/"+revisionsPlaceID+"/reviews", "", nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var reviews []dto.ReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &reviews))
+	for i := range reviews {
+		if reviews[i].ID == reviewID {
+			return &reviews[i]
+		}
+	}
+	return nil
+}
+
+func (s *RevisionsTestSuite) TestEditCreatesRevision() {
+	reviewID := s.submit()
+
+	review := s.find(reviewID)
+	require.NotNil(s.T(), review)
+	require.False(s.T(), review.Edited)
+
+	rec := s.TS.Do(http.MethodPatch, "/reviews/"+reviewID, s.BobToken, map[string]any{
+		"content": "Стало хуже",
+		"rating":  2,
+	})
+	require.Equal(s.T(), http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = s.revisions(s.BobToken, reviewID)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var revisions []dto.ReviewRevisionResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &revisions))
+	require.Len(s.T(), revisions, 2)
+	require.Equal(s.T(), 1, revisions[0].Revision)
+	require.Equal(s.T(), "Всё понравилось", revisions[0].Content)
+	require.Equal(s.T(), 5, revisions[0].Rating)
+	require.Equal(s.T(), 2, revisions[1].Revision)
+	require.Equal(s.T(), "Стало хуже", revisions[1].Content)
+
+	review = s.find(reviewID)
+	require.NotNil(s.T(), review)
+	require.True(s.T(), review.Edited)
+	require.NotNil(s.T(), review.EditedAt)
+}
+
+func (s *RevisionsTestSuite) TestRevisionsAccess() {
+	reviewID := s.submit()
+
+	rec := s.revisions(s.AdminToken, reviewID)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	rec = s.revisions(s.OtherToken, reviewID)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+
+	rec = s.revisions(s.BobToken, "00000000-0000-0000-0000-000000000000")
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *RevisionsTestSuite) TestUndoDelete() {
+	reviewID := s.submit()
+
+	rec := s.TS.Do(http.MethodDelete, "/reviews/"+reviewID, s.BobToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Nil(s.T(), s.find(reviewID))
+
+	// Отменить удаление может только автор
+	rec = s.TS.Do(http.MethodPost, "/reviews/"+reviewID+"/restore", s.OtherToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/reviews/"+reviewID+"/restore", s.BobToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code, rec.Body.String())
+	require.NotNil(s.T(), s.find(reviewID))
+
+	// Неудалённый отзыв восстанавливать нечего
+	rec = s.TS.Do(http.MethodPost, "/reviews/"+reviewID+"/restore", s.BobToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *RevisionsTestSuite) TestUndoDeleteWindowExpired() {
+	reviewID := s.submit()
+
+	rec := s.TS.Do(http.MethodDelete, "/reviews/"+reviewID, s.BobToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	_, err := s.TS.DB.Exec(context.Background(),
+		"UPDATE reviews SET deleted_at = now() - INTERVAL '30 days' WHERE id = $1", reviewID,
+	)
+	require.NoError(s.T(), err)
+
+	rec = s.TS.Do(http.MethodPost, "/reviews/"+reviewID+"/restore", s.BobToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+	require.Nil(s.T(), s.find(reviewID))
+}
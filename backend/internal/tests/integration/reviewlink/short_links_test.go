@@ -0,0 +1,268 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	linkPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	linkToken   = "VALIDTOKEN123"
+)
+
+type ShortLinkTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+	UserToken  string
+}
+
+func TestShortLinkSuite(t *testing.T) {
+	suite.Run(t, new(ShortLinkTestSuite))
+}
+
+func (s *ShortLinkTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *ShortLinkTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *ShortLinkTestSuite) SetupTest() {
+	s.clearScans()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+	s.UserToken = s.TS.Login("bob@example.com", "password123")
+}
+
+func (s *ShortLinkTestSuite) TearDownTest() {
+	s.clearScans()
+}
+
+func (s *ShortLinkTestSuite) clearScans() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM token_scans")
+	require.NoError(s.T(), err)
+	_, err = s.TS.DB.Exec(context.Background(), "DELETE FROM place_members")
+	require.NoError(s.T(), err)
+	_, err = s.TS.DB.Exec(context.Background(), "DELETE FROM token_refill_jobs")
+	require.NoError(s.T(), err)
+}
+
+// follow переходит по короткой ссылке и возвращает ID перехода и ключ сессии из адреса формы
+func (s *ShortLinkTestSuite) follow(code string) (string, string) {
+	return s.followFrom(code, "TestPhone/1.0")
+}
+
+func (s *ShortLinkTestSuite) followFrom(code, userAgent string) (string, string) {
+	req := httptest.NewRequest(http.MethodGet, "/r/"+code, nil)
+	req.Header.Set("User-Agent", userAgent)
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+	require.Equal(s.T(), http.StatusFound, rec.Code)
+
+	location, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(s.T(), err)
+	require.Contains(s.T(), location.Path, "/frontend/review-form.html")
+	require.Equal(s.T(), code, location.Query().Get("token"))
+	require.Equal(s.T(), linkPlaceID, location.Query().Get("place_id"))
+
+	scanID := location.Query().Get("scan")
+	scanKey := location.Query().Get("scan_key")
+	require.NotEmpty(s.T(), scanID)
+	require.NotEmpty(s.T(), scanKey)
+	return scanID, scanKey
+}
+
+func (s *ShortLinkTestSuite) open(token, scanID, scanKey string) *httptest.ResponseRecorder {
+	return s.TS.Do(http.MethodPost, "/scans/"+scanID+"/open", token, map[string]any{"key": scanKey})
+}
+
+func (s *ShortLinkTestSuite) funnel(token, query string) *httptest.ResponseRecorder {
+	return s.TS.Do(http.MethodGet, "/places/"+linkPlaceID+"/funnel"+query, token, nil)
+}
+
+func (s *ShortLinkTestSuite) TestFollowRecordsScan() {
+	scanID, _ := s.follow(linkToken)
+
+	var userAgent string
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT user_agent FROM token_scans WHERE id = $1", scanID,
+	).Scan(&userAgent)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "TestPhone/1.0", userAgent)
+}
+
+func (s *ShortLinkTestSuite) TestFollowUnknownCode() {
+	rec := s.TS.Do(http.MethodGet, "/r/NOSUCHTOKEN", "", nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *ShortLinkTestSuite) TestFollowDeduplicatesSameClient() {
+	firstID, firstKey := s.follow(linkToken)
+	secondID, secondKey := s.follow(linkToken)
+	require.Equal(s.T(), firstID, secondID)
+	require.Equal(s.T(), firstKey, secondKey)
+
+	otherID, _ := s.followFrom(linkToken, "OtherPhone/2.0")
+	require.NotEqual(s.T(), firstID, otherID)
+
+	var count int
+	err := s.TS.DB.QueryRow(context.Background(), "SELECT COUNT(*) FROM token_scans").Scan(&count)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 2, count)
+}
+
+func (s *ShortLinkTestSuite) TestMarkOpenedRequiresSessionKey() {
+	scanID, _ := s.follow(linkToken)
+
+	rec := s.open(s.UserToken, scanID, "0123456789abcdef0123456789abcdef")
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/scans/"+scanID+"/open", s.UserToken, nil)
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	var opened bool
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT opened_at IS NOT NULL FROM token_scans WHERE id = $1", scanID,
+	).Scan(&opened)
+	require.NoError(s.T(), err)
+	require.False(s.T(), opened)
+}
+
+func (s *ShortLinkTestSuite) TestMarkOpened() {
+	scanID, scanKey := s.follow(linkToken)
+
+	rec := s.open(s.UserToken, scanID, scanKey)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	// Повторный вызов не перезаписывает первый вход
+	rec = s.open(s.AdminToken, scanID, scanKey)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var userEmail string
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT u.email FROM token_scans s JOIN users u ON u.id = s.user_id WHERE s.id = $1", scanID,
+	).Scan(&userEmail)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "bob@example.com", userEmail)
+
+	rec = s.open(s.UserToken, "00000000-0000-0000-0000-000000000000", scanKey)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *ShortLinkTestSuite) TestFunnel() {
+	s.followFrom(linkToken, "OtherPhone/2.0")
+	scanID, scanKey := s.follow(linkToken)
+
+	rec := s.open(s.UserToken, scanID, scanKey)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/reviews", s.UserToken, map[string]any{
+		"token":    linkToken,
+		"place_id": linkPlaceID,
+		"rating":   5,
+		"content":  "Отзыв по короткой ссылке",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	rec = s.funnel(s.AdminToken, "")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.FunnelResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(s.T(), 2, resp.Scans)
+	require.Equal(s.T(), 1, resp.ScannedTokens)
+	require.Equal(s.T(), 1, resp.Logins)
+	require.Equal(s.T(), 1, resp.Reviews)
+	require.Equal(s.T(), 10, resp.Points)
+	require.InDelta(s.T(), 1.0, resp.ReviewRate, 0.0001)
+}
+
+func (s *ShortLinkTestSuite) TestFunnelPointsOnlyForPublished() {
+	s.follow(linkToken)
+
+	rec := s.TS.Do(http.MethodPost, "/reviews", s.UserToken, map[string]any{
+		"token":    linkToken,
+		"place_id": linkPlaceID,
+		"rating":   5,
+		"content":  "Отзыв по короткой ссылке",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var created dto.SubmitReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &created))
+
+	rec = s.TS.Do(http.MethodPost, "/admin/reviews/"+created.ID+"/hide", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	rec = s.funnel(s.AdminToken, "")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	// Скрытый отзыв учитывается в воронке, но баллов не приносит
+	var resp dto.FunnelResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(s.T(), 1, resp.Reviews)
+	require.Zero(s.T(), resp.Points)
+}
+
+func (s *ShortLinkTestSuite) TestFunnelEmptyPeriod() {
+	s.follow(linkToken)
+
+	rec := s.funnel(s.AdminToken, "?from=2020-01-01&to=2020-01-31")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.FunnelResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Zero(s.T(), resp.Scans)
+	require.Zero(s.T(), resp.ReviewRate)
+}
+
+func (s *ShortLinkTestSuite) TestFunnelInvalidPeriod() {
+	rec := s.funnel(s.AdminToken, "?from=2025-02-01&to=2025-01-01")
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (s *ShortLinkTestSuite) TestFunnelForbidden() {
+	rec := s.funnel(s.UserToken, "")
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
+
+func (s *ShortLinkTestSuite) TestFunnelPlaceStaff() {
+	rec := s.TS.Do(http.MethodPost, "/places/"+linkPlaceID+"/members", s.AdminToken, map[string]any{
+		"email": "bob@example.com",
+		"role":  "manager",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+	rec = s.TS.Do(http.MethodPost, "/places/"+linkPlaceID+"/members", s.AdminToken, map[string]any{
+		"email": "john@example.com",
+		"role":  "cashier",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	managerToken := s.TS.Login("bob@example.com", "password123")
+	rec = s.funnel(managerToken, "")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	cashierToken := s.TS.Login("john@example.com", "securepass")
+	rec = s.funnel(cashierToken, "")
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
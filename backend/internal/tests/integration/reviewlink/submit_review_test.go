@@ -2,15 +2,15 @@ package reviewlink
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
-	"github.com/go-testfixtures/testfixtures/v3"
-	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
 
 	"github.com/stretchr/testify/suite"
@@ -35,27 +35,13 @@ func (s *SubmitReviewTestSuite) TearDownSuite() {
 }
 
 func (s *SubmitReviewTestSuite) SetupTest() {
-	db := stdlib.OpenDBFromPool(s.TS.DB)
-	defer func() {
-		if err := db.Close(); err != nil {
-			s.T().Logf("failed to close db: %v", err)
-		}
-	}()
-
-	fixture, err := testfixtures.New(
-		testfixtures.Database(db),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Files(
-			"../fixtures/users.yml",
-			"../fixtures/places.yml",
-			"../fixtures/review_tokens.yml",
-			"../fixtures/reviews.yml",
-		),
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
 	)
 
-	require.NoError(s.T(), err, "init fixtures failed")
-	require.NoError(s.T(), fixture.Load(), "load fixtures failed")
-
 	s.Token = s.TS.Login("bob@example.com", "password123")
 }
 
@@ -140,3 +126,91 @@ func (s *SubmitReviewTestSuite) TestSubmitReviewExpiredToken() {
 	require.Equal(s.T(), http.StatusForbidden, rec.Code)
 	require.Contains(s.T(), rec.Body.String(), "token expired")
 }
+
+func (s *SubmitReviewTestSuite) TestSubmitReviewConcurrentSameToken() {
+	const (
+		testPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+		testToken   = "VALIDTOKEN123"
+		testTokenID = "6d8f07a2-9d91-4a6a-bc73-8c7a6e8a1f01"
+		bobID       = "fcb2f1a1-3c64-45d9-9f83-f89adf1f9b23"
+		attempts    = 10
+	)
+
+	ctx := context.Background()
+
+	var pointsBefore int
+	err := s.TS.DB.QueryRow(ctx, "SELECT points FROM users WHERE id = $1", bobID).Scan(&pointsBefore)
+	require.NoError(s.T(), err)
+
+	body, _ := json.Marshal(map[string]any{
+		"rating":   5,
+		"content":  "Параллельная отправка",
+		"place_id": testPlaceID,
+		"token":    testToken,
+	})
+
+	codes := make([]int, attempts)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			req := httptest.NewRequest(http.MethodPost, "/reviews", bytes.NewReader(body))
+			req.Header.Set("Authorization", "Bearer "+s.Token)
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			s.TS.App.ServeHTTP(rec, req)
+			codes[i] = rec.Code
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for _, code := range codes {
+		if code == http.StatusCreated {
+			created++
+		}
+	}
+	require.Equal(s.T(), 1, created, "only one submission must win, got codes %v", codes)
+
+	var reviews int
+	err = s.TS.DB.QueryRow(ctx, "SELECT COUNT(*) FROM reviews WHERE token_id = $1", testTokenID).Scan(&reviews)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, reviews)
+
+	var pointsAfter int
+	err = s.TS.DB.QueryRow(ctx, "SELECT points FROM users WHERE id = $1", bobID).Scan(&pointsAfter)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), pointsBefore+10, pointsAfter)
+}
+
+func (s *SubmitReviewTestSuite) TestSubmitReviewTokenFromAnotherPlace() {
+	const (
+		otherPlaceID = "0b0e5c1d-1111-4a4a-9b9b-222222222222"
+		testToken    = "VALIDTOKEN123"
+	)
+
+	payload := map[string]any{
+		"rating":   5,
+		"content":  "Чужое заведение",
+		"place_id": otherPlaceID,
+		"token":    testToken,
+	}
+	body, _ := json.Marshal(payload)
+
+	req := httptest.NewRequest(http.MethodPost, "/reviews", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "token belongs to another place")
+}
@@ -0,0 +1,77 @@
+package reviewlink
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TokenInfoTestSuite struct {
+	suite.Suite
+	TS *integration.TestSetup
+}
+
+func TestTokenInfoSuite(t *testing.T) {
+	suite.Run(t, new(TokenInfoTestSuite))
+}
+
+func (s *TokenInfoTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *TokenInfoTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *TokenInfoTestSuite) SetupTest() {
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+	)
+}
+
+func (s *TokenInfoTestSuite) getTokenInfo(value string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/tokens/"+value, nil)
+
+	rec := httptest.NewRecorder()
+	s.TS.App.ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *TokenInfoTestSuite) TestValidToken() {
+	rec := s.getTokenInfo("VALIDTOKEN123")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var info dto.TokenInfoResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &info))
+	require.Equal(s.T(), "a8c52b0c-8f11-4b9c-9c3f-123456789abc", info.PlaceID)
+	require.Equal(s.T(), "Test Place", info.PlaceName)
+	require.True(s.T(), info.Valid)
+	require.False(s.T(), info.Used)
+	require.False(s.T(), info.Expired)
+}
+
+func (s *TokenInfoTestSuite) TestExpiredToken() {
+	rec := s.getTokenInfo("EXPIRED00001")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var info dto.TokenInfoResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &info))
+	require.False(s.T(), info.Valid)
+	require.True(s.T(), info.Expired)
+}
+
+func (s *TokenInfoTestSuite) TestUnknownToken() {
+	rec := s.getTokenInfo("NOSUCHTOKEN")
+
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "invalid token")
+}
@@ -0,0 +1,176 @@
+package reviewlink
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const jobsPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+
+type TokenJobsTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+}
+
+func TestTokenJobsSuite(t *testing.T) {
+	suite.Run(t, new(TokenJobsTestSuite))
+}
+
+func (s *TokenJobsTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *TokenJobsTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *TokenJobsTestSuite) SetupTest() {
+	s.clearJobs()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+}
+
+func (s *TokenJobsTestSuite) TearDownTest() {
+	s.clearJobs()
+}
+
+func (s *TokenJobsTestSuite) clearJobs() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM review_tokens WHERE batch_job_id IS NOT NULL")
+	require.NoError(s.T(), err)
+	_, err = s.TS.DB.Exec(context.Background(), "DELETE FROM token_batch_jobs")
+	require.NoError(s.T(), err)
+}
+
+func (s *TokenJobsTestSuite) createJob(count int) dto.TokenJobResponse {
+	rec := s.TS.Do(http.MethodPost, "/admin/token-jobs", s.AdminToken, map[string]any{
+		"place_id": jobsPlaceID,
+		"count":    count,
+	})
+	require.Equal(s.T(), http.StatusAccepted, rec.Code)
+
+	var job dto.TokenJobResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &job))
+	return job
+}
+
+func (s *TokenJobsTestSuite) getJob(id string) dto.TokenJobResponse {
+	rec := s.TS.Do(http.MethodGet, "/admin/token-jobs/"+id, s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var job dto.TokenJobResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &job))
+	return job
+}
+
+func (s *TokenJobsTestSuite) TestJobGeneratesInChunksAndExportsCSV() {
+	job := s.createJob(2500)
+	require.Equal(s.T(), "pending", job.Status)
+	require.Equal(s.T(), 0, job.Generated)
+
+	rec := s.TS.Do(http.MethodGet, "/admin/token-jobs/"+job.ID+"/export", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusConflict, rec.Code)
+
+	processed, err := s.TS.JobWorker.RunOnce(context.Background())
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, processed)
+
+	job = s.getJob(job.ID)
+	require.Equal(s.T(), "done", job.Status)
+	require.Equal(s.T(), 2500, job.Generated)
+	require.NotNil(s.T(), job.FinishedAt)
+
+	var stored int
+	err = s.TS.DB.QueryRow(context.Background(),
+		"SELECT COUNT(DISTINCT token_value) FROM review_tokens WHERE batch_job_id = $1", job.ID,
+	).Scan(&stored)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 2500, stored)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/token-jobs/"+job.ID+"/export?format=csv", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Contains(s.T(), rec.Header().Get("Content-Type"), "text/csv")
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(s.T(), err)
+	require.Len(s.T(), records, 2501)
+	require.Equal(s.T(), []string{"token", "url", "expires_at"}, records[0])
+	require.Contains(s.T(), records[1][1], "/r/"+records[1][0])
+}
+
+func (s *TokenJobsTestSuite) TestExportZIP() {
+	job := s.createJob(3)
+
+	_, err := s.TS.JobWorker.RunOnce(context.Background())
+	require.NoError(s.T(), err)
+
+	rec := s.TS.Do(http.MethodGet, "/admin/token-jobs/"+job.ID+"/export?format=zip", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.Equal(s.T(), "application/zip", rec.Header().Get("Content-Type"))
+
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	require.NoError(s.T(), err)
+
+	var images int
+	var hasCSV bool
+	for _, f := range archive.File {
+		switch {
+		case f.Name == "tokens.csv":
+			hasCSV = true
+		case strings.HasPrefix(f.Name, "qr/") && strings.HasSuffix(f.Name, ".png"):
+			images++
+		}
+	}
+	require.True(s.T(), hasCSV)
+	require.Equal(s.T(), 3, images)
+}
+
+func (s *TokenJobsTestSuite) TestInvalidRequests() {
+	rec := s.TS.Do(http.MethodPost, "/admin/token-jobs", s.AdminToken, map[string]any{
+		"place_id": jobsPlaceID,
+		"count":    100001,
+	})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/admin/token-jobs", s.AdminToken, map[string]any{
+		"place_id": "00000000-0000-0000-0000-000000000000",
+		"count":    10,
+	})
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/token-jobs/00000000-0000-0000-0000-000000000000", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+
+	job := s.createJob(1)
+	rec = s.TS.Do(http.MethodGet, "/admin/token-jobs/"+job.ID+"/export?format=pdf", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (s *TokenJobsTestSuite) TestUserCannotCreateJob() {
+	userToken := s.TS.Login("bob@example.com", "password123")
+
+	rec := s.TS.Do(http.MethodPost, "/admin/token-jobs", userToken, map[string]any{
+		"place_id": jobsPlaceID,
+		"count":    10,
+	})
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
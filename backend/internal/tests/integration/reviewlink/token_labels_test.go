@@ -0,0 +1,193 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	labelsPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	labelsStaffID = "b2222222-3333-4444-5555-666666666666" // UpdateUser
+)
+
+type TokenLabelsTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+	BobToken   string
+	StaffToken string
+}
+
+func TestTokenLabelsSuite(t *testing.T) {
+	suite.Run(t, new(TokenLabelsTestSuite))
+}
+
+func (s *TokenLabelsTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *TokenLabelsTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *TokenLabelsTestSuite) SetupTest() {
+	s.clear()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	_, err := s.TS.DB.Exec(context.Background(),
+		"INSERT INTO place_members (id, place_id, user_id, role) VALUES (gen_random_uuid(), $1, $2, 'cashier')",
+		labelsPlaceID, labelsStaffID,
+	)
+	require.NoError(s.T(), err)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+	s.BobToken = s.TS.Login("bob@example.com", "password123")
+	s.StaffToken = s.TS.Login("update@example.com", "password123")
+}
+
+func (s *TokenLabelsTestSuite) TearDownTest() {
+	s.clear()
+}
+
+func (s *TokenLabelsTestSuite) clear() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM place_members")
+	require.NoError(s.T(), err)
+	_, err = s.TS.DB.Exec(context.Background(), "DELETE FROM token_refill_jobs")
+	require.NoError(s.T(), err)
+}
+
+func (s *TokenLabelsTestSuite) generate(labels map[string]any) string {
+	payload := map[string]any{"place_id": labelsPlaceID, "count": 1}
+	for k, v := range labels {
+		payload[k] = v
+	}
+
+	rec := s.TS.Do(http.MethodPost, "/admin/tokens", s.AdminToken, payload)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp struct {
+		Tokens []string `json:"tokens"`
+	}
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(s.T(), resp.Tokens, 1)
+	return resp.Tokens[0]
+}
+
+func (s *TokenLabelsTestSuite) submit(userToken, reviewToken string, rating int) {
+	rec := s.TS.Do(http.MethodPost, "/reviews", userToken, map[string]any{
+		"token":    reviewToken,
+		"place_id": labelsPlaceID,
+		"rating":   rating,
+		"content":  "Отзыв по метке",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+}
+
+func (s *TokenLabelsTestSuite) report(token, query string) *httptest.ResponseRecorder {
+	return s.TS.Do(http.MethodGet, "/places/"+labelsPlaceID+"/reports/labels"+query, token, nil)
+}
+
+func (s *TokenLabelsTestSuite) TestReviewInheritsLabels() {
+	value := s.generate(map[string]any{"staff_id": labelsStaffID, "table": " 5 ", "campaign": "winter"})
+	s.submit(s.BobToken, value, 5)
+
+	var staffID, table, campaign string
+	err := s.TS.DB.QueryRow(context.Background(), `
+		SELECT r.staff_id::text, r.table_label, r.campaign
+		FROM reviews r JOIN review_tokens t ON t.id = r.token_id
+		WHERE t.token_value = $1`, value,
+	).Scan(&staffID, &table, &campaign)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), labelsStaffID, staffID)
+	require.Equal(s.T(), "5", table)
+	require.Equal(s.T(), "winter", campaign)
+}
+
+func (s *TokenLabelsTestSuite) TestLabelReport() {
+	s.submit(s.BobToken, s.generate(map[string]any{"staff_id": labelsStaffID, "table": "5"}), 5)
+	s.submit(s.StaffToken, s.generate(map[string]any{"table": "7"}), 3)
+
+	rec := s.report(s.AdminToken, "?by=table")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var byTable []dto.LabelStatResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &byTable))
+	require.Len(s.T(), byTable, 2)
+	require.Equal(s.T(), "5", byTable[0].Label)
+	require.InDelta(s.T(), 5.0, byTable[0].AverageRating, 0.001)
+	require.Equal(s.T(), "7", byTable[1].Label)
+	require.Equal(s.T(), 1, byTable[1].Reviews)
+
+	rec = s.report(s.AdminToken, "?by=staff")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var byStaff []dto.LabelStatResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &byStaff))
+	require.Len(s.T(), byStaff, 1)
+	require.Equal(s.T(), labelsStaffID, byStaff[0].Label)
+	require.Equal(s.T(), "UpdateUser", byStaff[0].StaffName)
+
+	rec = s.report(s.AdminToken, "?by=campaign")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.JSONEq(s.T(), "[]", rec.Body.String())
+}
+
+func (s *TokenLabelsTestSuite) TestInvalidLabels() {
+	// Сотрудник должен состоять в заведении
+	rec := s.TS.Do(http.MethodPost, "/admin/tokens", s.AdminToken, map[string]any{
+		"place_id": labelsPlaceID,
+		"count":    1,
+		"staff_id": "fcb2f1a1-3c64-45d9-9f83-f89adf1f9b23",
+	})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/admin/tokens", s.AdminToken, map[string]any{
+		"place_id": labelsPlaceID,
+		"count":    1,
+		"table":    strings.Repeat("9", 33),
+	})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (s *TokenLabelsTestSuite) TestReportValidation() {
+	rec := s.report(s.AdminToken, "?by=waiter")
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.report(s.AdminToken, "?by=table&from=2025-02-01&to=2025-01-01")
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.report(s.BobToken, "?by=table")
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
+
+func (s *TokenLabelsTestSuite) TestReportForPlaceManager() {
+	rec := s.TS.Do(http.MethodPost, "/places/"+labelsPlaceID+"/members", s.AdminToken, map[string]any{
+		"email": "bob@example.com",
+		"role":  "manager",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	managerToken := s.TS.Login("bob@example.com", "password123")
+	rec = s.report(managerToken, "?by=table")
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	// Кассиру заведения отчёт недоступен
+	rec = s.report(s.StaffToken, "?by=table")
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,142 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	lifecyclePlaceID      = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	lifecycleValidTokenID = "6d8f07a2-9d91-4a6a-bc73-8c7a6e8a1f01"
+	lifecycleExpiredID    = "8a2f0e4c-75aa-482d-931a-2d6c3b214c9a"
+)
+
+type TokenLifecycleTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+}
+
+func TestTokenLifecycleSuite(t *testing.T) {
+	suite.Run(t, new(TokenLifecycleTestSuite))
+}
+
+func (s *TokenLifecycleTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *TokenLifecycleTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *TokenLifecycleTestSuite) SetupTest() {
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+}
+
+func (s *TokenLifecycleTestSuite) TestListTokensByStatus() {
+	rec := s.TS.Do(http.MethodGet, "/admin/places/"+lifecyclePlaceID+"/tokens?status=expired", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.TokenListResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(s.T(), 1, resp.Total)
+	require.Len(s.T(), resp.Tokens, 1)
+	require.Equal(s.T(), "EXPIRED00001", resp.Tokens[0].Token)
+	require.Equal(s.T(), "expired", resp.Tokens[0].Status)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/places/"+lifecyclePlaceID+"/tokens?limit=1", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(s.T(), 3, resp.Total)
+	require.Len(s.T(), resp.Tokens, 1)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/places/"+lifecyclePlaceID+"/tokens?status=unknown", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (s *TokenLifecycleTestSuite) TestTokenStats() {
+	rec := s.TS.Do(http.MethodDelete, "/admin/tokens/"+lifecycleValidTokenID, s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	rec = s.TS.Do(http.MethodGet, "/admin/places/"+lifecyclePlaceID+"/tokens/stats", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var stats dto.TokenStatsResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &stats))
+	require.Equal(s.T(), 3, stats.Issued)
+	require.Equal(s.T(), 1, stats.Active)
+	require.Equal(s.T(), 1, stats.Expired)
+	require.Equal(s.T(), 1, stats.Revoked)
+	require.Equal(s.T(), 0, stats.Used)
+}
+
+func (s *TokenLifecycleTestSuite) TestRevokedTokenCannotBeUsed() {
+	rec := s.TS.Do(http.MethodPost, "/admin/tokens/revoke", s.AdminToken, map[string]any{
+		"ids": []string{lifecycleValidTokenID},
+	})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.BulkTokensResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(s.T(), 1, resp.Affected)
+
+	userToken := s.TS.Login("bob@example.com", "password123")
+	rec = s.TS.Do(http.MethodPost, "/reviews", userToken, map[string]any{
+		"rating":   5,
+		"content":  "Отозванный токен",
+		"place_id": lifecyclePlaceID,
+		"token":    "VALIDTOKEN123",
+	})
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+	require.Contains(s.T(), rec.Body.String(), "token revoked")
+
+	rec = s.TS.Do(http.MethodDelete, "/admin/tokens/"+lifecycleValidTokenID, s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *TokenLifecycleTestSuite) TestExtendExpiredToken() {
+	rec := s.TS.Do(http.MethodPost, "/admin/tokens/extend", s.AdminToken, map[string]any{
+		"ids":   []string{lifecycleExpiredID},
+		"hours": 24,
+	})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.BulkTokensResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(s.T(), 1, resp.Affected)
+
+	var expiresAt time.Time
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT expires_at FROM review_tokens WHERE id = $1", lifecycleExpiredID,
+	).Scan(&expiresAt)
+	require.NoError(s.T(), err)
+	require.True(s.T(), expiresAt.After(time.Now().Add(23*time.Hour)))
+}
+
+func (s *TokenLifecycleTestSuite) TestUserCannotManageTokens() {
+	userToken := s.TS.Login("bob@example.com", "password123")
+
+	rec := s.TS.Do(http.MethodGet, "/admin/places/"+lifecyclePlaceID+"/tokens", userToken, nil)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/admin/tokens/revoke", userToken, map[string]any{
+		"ids": []string{lifecycleValidTokenID},
+	})
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
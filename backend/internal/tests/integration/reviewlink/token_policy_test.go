@@ -0,0 +1,170 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const policyPlaceID = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+
+type TokenPolicyTestSuite struct {
+	suite.Suite
+	TS         *integration.TestSetup
+	AdminToken string
+}
+
+func TestTokenPolicySuite(t *testing.T) {
+	suite.Run(t, new(TokenPolicyTestSuite))
+}
+
+func (s *TokenPolicyTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *TokenPolicyTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *TokenPolicyTestSuite) SetupTest() {
+	s.clearRefillJobs()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AdminToken = s.TS.Login("admin@example.com", "securepass")
+}
+
+func (s *TokenPolicyTestSuite) TearDownTest() {
+	s.clearRefillJobs()
+}
+
+func (s *TokenPolicyTestSuite) clearRefillJobs() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM token_refill_jobs")
+	require.NoError(s.T(), err)
+}
+
+func (s *TokenPolicyTestSuite) countTokens(placeID string) int {
+	var count int
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM review_tokens WHERE place_id = $1", placeID,
+	).Scan(&count)
+	require.NoError(s.T(), err)
+	return count
+}
+
+func (s *TokenPolicyTestSuite) TestDefaultPolicyFallsBackToConfig() {
+	rec := s.TS.Do(http.MethodGet, "/admin/places/"+policyPlaceID+"/token-policy", s.AdminToken, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.TokenPolicyResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Nil(s.T(), resp.Custom.TTLHours)
+	require.Nil(s.T(), resp.Custom.RefillBatch)
+	require.Positive(s.T(), resp.Effective.TTLHours)
+	require.Positive(s.T(), resp.Effective.RefillBatch)
+}
+
+func (s *TokenPolicyTestSuite) TestPolicyAppliesToGenerateAndRefill() {
+	rec := s.TS.Do(http.MethodPut, "/admin/places/"+policyPlaceID+"/token-policy", s.AdminToken, map[string]any{
+		"ttl_hours":        2,
+		"refill_threshold": 100,
+		"refill_batch":     3,
+	})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var resp dto.TokenPolicyResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(s.T(), 2, resp.Effective.TTLHours)
+	require.Equal(s.T(), 3, resp.Effective.RefillBatch)
+	require.Nil(s.T(), resp.Custom.InitialCount)
+
+	rec = s.TS.Do(http.MethodPost, "/admin/tokens", s.AdminToken, map[string]any{
+		"place_id": policyPlaceID,
+		"count":    1,
+	})
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var latest time.Time
+	err := s.TS.DB.QueryRow(context.Background(),
+		"SELECT MAX(expires_at) FROM review_tokens WHERE place_id = $1", policyPlaceID,
+	).Scan(&latest)
+	require.NoError(s.T(), err)
+	require.WithinDuration(s.T(), time.Now().Add(2*time.Hour), latest, 5*time.Minute)
+
+	before := s.countTokens(policyPlaceID)
+
+	userToken := s.TS.Login("bob@example.com", "password123")
+	rec = s.TS.Do(http.MethodPost, "/reviews", userToken, map[string]any{
+		"rating":   5,
+		"content":  "Политика токенов",
+		"place_id": policyPlaceID,
+		"token":    "VALIDTOKEN123",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	completed, err := s.TS.RefillWorker.RunOnce(context.Background())
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, completed)
+	require.Equal(s.T(), before+3, s.countTokens(policyPlaceID))
+}
+
+func (s *TokenPolicyTestSuite) TestCreatePlaceWithPolicy() {
+	rec := s.TS.Do(http.MethodPost, "/places", s.AdminToken, map[string]any{
+		"name":    "Hotel",
+		"address": "1 Hotel St",
+		"token_policy": map[string]any{
+			"initial_count": 2,
+			"ttl_hours":     720,
+		},
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var hotel dto.CreatePlaceResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &hotel))
+	require.Equal(s.T(), 2, s.countTokens(hotel.ID))
+
+	rec = s.TS.Do(http.MethodPost, "/places", s.AdminToken, map[string]any{
+		"name":         "Quiet Place",
+		"address":      "2 Quiet St",
+		"token_policy": map[string]any{"initial_count": 0},
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var quiet dto.CreatePlaceResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &quiet))
+	require.Equal(s.T(), 0, s.countTokens(quiet.ID))
+}
+
+func (s *TokenPolicyTestSuite) TestInvalidPolicy() {
+	rec := s.TS.Do(http.MethodPut, "/admin/places/"+policyPlaceID+"/token-policy", s.AdminToken, map[string]any{
+		"refill_batch": 0,
+	})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+
+	rec = s.TS.Do(http.MethodPut, "/admin/places/"+policyPlaceID+"/token-policy", s.AdminToken, map[string]any{
+		"ttl_hours": 100000,
+	})
+	require.Equal(s.T(), http.StatusBadRequest, rec.Code)
+}
+
+func (s *TokenPolicyTestSuite) TestUserCannotEditPolicy() {
+	userToken := s.TS.Login("bob@example.com", "password123")
+
+	rec := s.TS.Do(http.MethodPut, "/admin/places/"+policyPlaceID+"/token-policy", userToken, map[string]any{
+		"refill_batch": 5,
+	})
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+}
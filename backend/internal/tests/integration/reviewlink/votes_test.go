@@ -0,0 +1,154 @@
+package reviewlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/controller/dto"
+	"github.com/kulikovroman08/reviewlink-backend/internal/tests/integration"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	votesPlaceID  = "a8c52b0c-8f11-4b9c-9c3f-123456789abc"
+	votesReviewID = "c4a7fdd2-6e9d-4f8a-9c59-44f88d66c7b0" // отзыв John
+)
+
+type VotesTestSuite struct {
+	suite.Suite
+	TS          *integration.TestSetup
+	AuthorToken string
+	BobToken    string
+	OtherToken  string
+}
+
+func TestVotesSuite(t *testing.T) {
+	suite.Run(t, new(VotesTestSuite))
+}
+
+func (s *VotesTestSuite) SetupSuite() {
+	s.TS = integration.NewTestSetup()
+}
+
+func (s *VotesTestSuite) TearDownSuite() {
+	s.TS.Close()
+}
+
+func (s *VotesTestSuite) SetupTest() {
+	s.clear()
+
+	s.TS.LoadFixtures(s.T(),
+		"../fixtures/users.yml",
+		"../fixtures/places.yml",
+		"../fixtures/review_tokens.yml",
+		"../fixtures/reviews.yml",
+	)
+
+	s.AuthorToken = s.TS.Login("john@example.com", "securepass")
+	s.BobToken = s.TS.Login("bob@example.com", "password123")
+	s.OtherToken = s.TS.Login("update@example.com", "password123")
+}
+
+func (s *VotesTestSuite) TearDownTest() {
+	s.clear()
+}
+
+func (s *VotesTestSuite) clear() {
+	_, err := s.TS.DB.Exec(context.Background(), "DELETE FROM review_votes")
+	require.NoError(s.T(), err)
+}
+
+func (s *VotesTestSuite) vote(method, token, reviewID string) dto.ReviewVoteResponse {
+	rec := s.TS.Do(method, "/reviews/"+reviewID+"/helpful", token, nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp dto.ReviewVoteResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(s.T(), reviewID, resp.ReviewID)
+	return resp
+}
+
+func (s *VotesTestSuite) list(query string) []dto.ReviewResponse {
+	rec := s.TS.Do(http.MethodGet, "/places/"+votesPlaceID+"/reviews"+query, "", nil)
+	require.Equal(s.T(), http.StatusOK, rec.Code)
+
+	var reviews []dto.ReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &reviews))
+	return reviews
+}
+
+func (s *VotesTestSuite) TestVoteAndUnvote() {
+	resp := s.vote(http.MethodPost, s.BobToken, votesReviewID)
+	require.Equal(s.T(), 1, resp.HelpfulCount)
+	require.True(s.T(), resp.Voted)
+
+	// Повторная отметка не увеличивает счётчик
+	resp = s.vote(http.MethodPost, s.BobToken, votesReviewID)
+	require.Equal(s.T(), 1, resp.HelpfulCount)
+
+	resp = s.vote(http.MethodPost, s.OtherToken, votesReviewID)
+	require.Equal(s.T(), 2, resp.HelpfulCount)
+
+	reviews := s.list("")
+	require.Len(s.T(), reviews, 1)
+	require.Equal(s.T(), 2, reviews[0].HelpfulCount)
+
+	resp = s.vote(http.MethodDelete, s.BobToken, votesReviewID)
+	require.Equal(s.T(), 1, resp.HelpfulCount)
+	require.False(s.T(), resp.Voted)
+
+	// Снятие отсутствующей отметки ничего не меняет
+	resp = s.vote(http.MethodDelete, s.BobToken, votesReviewID)
+	require.Equal(s.T(), 1, resp.HelpfulCount)
+}
+
+func (s *VotesTestSuite) TestAuthorCannotVote() {
+	rec := s.TS.Do(http.MethodPost, "/reviews/"+votesReviewID+"/helpful", s.AuthorToken, nil)
+	require.Equal(s.T(), http.StatusForbidden, rec.Code)
+	require.Zero(s.T(), s.list("")[0].HelpfulCount)
+}
+
+func (s *VotesTestSuite) TestUnpublishedAndUnknownReview() {
+	_, err := s.TS.DB.Exec(context.Background(), "UPDATE reviews SET status = 'hidden' WHERE id = $1", votesReviewID)
+	require.NoError(s.T(), err)
+
+	rec := s.TS.Do(http.MethodPost, "/reviews/"+votesReviewID+"/helpful", s.BobToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/reviews/00000000-0000-0000-0000-000000000000/helpful", s.BobToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+
+	rec = s.TS.Do(http.MethodPost, "/reviews/not-a-uuid/helpful", s.BobToken, nil)
+	require.Equal(s.T(), http.StatusNotFound, rec.Code)
+}
+
+func (s *VotesTestSuite) TestSortByHelpful() {
+	rec := s.TS.Do(http.MethodPost, "/reviews", s.BobToken, map[string]any{
+		"token":    "VALIDTOKEN123",
+		"place_id": votesPlaceID,
+		"rating":   4,
+		"content":  "Свежий отзыв Bob",
+	})
+	require.Equal(s.T(), http.StatusCreated, rec.Code)
+
+	var created dto.SubmitReviewResponse
+	require.NoError(s.T(), json.Unmarshal(rec.Body.Bytes(), &created))
+
+	s.vote(http.MethodPost, s.BobToken, votesReviewID)
+	s.vote(http.MethodPost, s.OtherToken, votesReviewID)
+	s.vote(http.MethodPost, s.AuthorToken, created.ID)
+
+	byDate := s.list("?sort=date_desc")
+	require.Len(s.T(), byDate, 2)
+	require.Equal(s.T(), created.ID, byDate[0].ID)
+
+	byHelpful := s.list("?sort=helpful")
+	require.Len(s.T(), byHelpful, 2)
+	require.Equal(s.T(), votesReviewID, byHelpful[0].ID)
+	require.Equal(s.T(), 2, byHelpful[0].HelpfulCount)
+	require.Equal(s.T(), 1, byHelpful[1].HelpfulCount)
+}
@@ -8,35 +8,68 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"testing"
 	"time"
 
 	"github.com/kulikovroman08/reviewlink-backend/configs"
 	"github.com/kulikovroman08/reviewlink-backend/internal/repository/place"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-testfixtures/testfixtures/v3"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/require"
 
 	"github.com/kulikovroman08/reviewlink-backend/internal/controller"
 	repoAdmin "github.com/kulikovroman08/reviewlink-backend/internal/repository/admin"
 	bonusRepo "github.com/kulikovroman08/reviewlink-backend/internal/repository/bonus"
+	repoKiosk "github.com/kulikovroman08/reviewlink-backend/internal/repository/kiosk"
 	repoLeaderboard "github.com/kulikovroman08/reviewlink-backend/internal/repository/leaderboard"
+	repoMember "github.com/kulikovroman08/reviewlink-backend/internal/repository/member"
+	repoPhoto "github.com/kulikovroman08/reviewlink-backend/internal/repository/photo"
+	repoPIN "github.com/kulikovroman08/reviewlink-backend/internal/repository/pin"
+	repoPOS "github.com/kulikovroman08/reviewlink-backend/internal/repository/pos"
+	repoRefill "github.com/kulikovroman08/reviewlink-backend/internal/repository/refill"
+	repoRefresh "github.com/kulikovroman08/reviewlink-backend/internal/repository/refresh"
+	repoReply "github.com/kulikovroman08/reviewlink-backend/internal/repository/reply"
+	repoReport "github.com/kulikovroman08/reviewlink-backend/internal/repository/report"
 	restrictionRepo "github.com/kulikovroman08/reviewlink-backend/internal/repository/restriction"
 	reviewRepo "github.com/kulikovroman08/reviewlink-backend/internal/repository/review"
+	repoRevision "github.com/kulikovroman08/reviewlink-backend/internal/repository/revision"
+	repoScan "github.com/kulikovroman08/reviewlink-backend/internal/repository/scan"
 	tokenRepo "github.com/kulikovroman08/reviewlink-backend/internal/repository/token"
+	repoTokenJob "github.com/kulikovroman08/reviewlink-backend/internal/repository/tokenjob"
+	"github.com/kulikovroman08/reviewlink-backend/internal/repository/transaction"
 	"github.com/kulikovroman08/reviewlink-backend/internal/repository/user"
+	repoVote "github.com/kulikovroman08/reviewlink-backend/internal/repository/vote"
 	adminService "github.com/kulikovroman08/reviewlink-backend/internal/service/admin"
 	svcBonus "github.com/kulikovroman08/reviewlink-backend/internal/service/bonus"
+	svcKiosk "github.com/kulikovroman08/reviewlink-backend/internal/service/kiosk"
 	svcLeaderboard "github.com/kulikovroman08/reviewlink-backend/internal/service/leaderboard"
+	svcLink "github.com/kulikovroman08/reviewlink-backend/internal/service/link"
+	svcMember "github.com/kulikovroman08/reviewlink-backend/internal/service/member"
+	svcPhoto "github.com/kulikovroman08/reviewlink-backend/internal/service/photo"
 	placeService "github.com/kulikovroman08/reviewlink-backend/internal/service/place"
+	svcPOS "github.com/kulikovroman08/reviewlink-backend/internal/service/pos"
+	svcReply "github.com/kulikovroman08/reviewlink-backend/internal/service/reply"
+	svcReport "github.com/kulikovroman08/reviewlink-backend/internal/service/report"
 	reviewService "github.com/kulikovroman08/reviewlink-backend/internal/service/review"
 	tokenService "github.com/kulikovroman08/reviewlink-backend/internal/service/token"
 	userService "github.com/kulikovroman08/reviewlink-backend/internal/service/user"
+	svcVote "github.com/kulikovroman08/reviewlink-backend/internal/service/vote"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/blobstore"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/contentfilter"
+	"github.com/kulikovroman08/reviewlink-backend/pkg/qr"
 )
 
 type TestSetup struct {
-	App *gin.Engine
-	DB  *pgxpool.Pool
+	App          *gin.Engine
+	DB           *pgxpool.Pool
+	RefillWorker *tokenService.RefillWorker
+	JobWorker    *tokenService.BatchJobWorker
+	PurgeWorker  *svcPhoto.PurgeWorker
+	Cfg          configs.Config
 }
 
 func NewTestSetup() *TestSetup {
@@ -67,14 +100,84 @@ func NewTestSetup() *TestSetup {
 	leaderboardRepo := repoLeaderboard.NewRepository(db)
 	bonusRepo := bonusRepo.NewPostgresBonusRepository(db)
 	restrictionRepo := restrictionRepo.NewPostgresUserRestrictionRepository(db)
+	refreshRepo := repoRefresh.NewPostgresRefreshTokenRepository(db)
+	memberRepo := repoMember.NewPostgresMemberRepository(db)
+	kioskRepo := repoKiosk.NewPostgresKioskRepository(db)
+	refillRepo := repoRefill.NewPostgresRefillRepository(db)
+	tokenJobRepo := repoTokenJob.NewPostgresTokenJobRepository(db)
+	posRepo := repoPOS.NewPostgresPOSRepository(db)
+	scanRepo := repoScan.NewPostgresScanRepository(db)
+	pinRepo := repoPIN.NewPostgresPINAttemptRepository(db)
+	replyRepo := repoReply.NewPostgresReplyRepository(db)
+	photoRepo := repoPhoto.NewPostgresPhotoRepository(db)
+	voteRepo := repoVote.NewPostgresVoteRepository(db)
+	reportRepo := repoReport.NewPostgresReportRepository(db)
+	revisionRepo := repoRevision.NewPostgresRevisionRepository(db)
+	txManager := transaction.NewManager(db)
 
-	tokSrv := tokenService.NewTokenService(tokRepo, &cfg)
-	userSrv := userService.NewUserService(userRepo, reviewRepo, bonusRepo)
-	placeSrv := placeService.NewPlaceService(placeRepo, tokSrv, &cfg)
-	reviewSrv := reviewService.NewReviewService(reviewRepo, userRepo, placeRepo, tokSrv, restrictionRepo)
+	qrRenderer, err := qr.NewRenderer(cfg.QRRecoveryLevel)
+	if err != nil {
+		log.Fatalf("failed to create qr renderer: %v", err)
+	}
+
+	contentFilter, err := contentfilter.Load(cfg.ContentFilterRules)
+	if err != nil {
+		log.Fatalf("failed to load content filter rules: %v", err)
+	}
+
+	// Фото в тестах пишутся во временный каталог, который удаляет Close
+	cfg.PhotoStorage = blobstore.BackendLocal
+	cfg.MediaDir, err = os.MkdirTemp("", "reviewlink-media-*")
+	if err != nil {
+		log.Fatalf("failed to create media dir: %v", err)
+	}
+
+	photoStore, err := blobstore.New(blobstore.Config{
+		Backend:      cfg.PhotoStorage,
+		LocalDir:     cfg.MediaDir,
+		LocalBaseURL: cfg.PublicBaseURL + "/media",
+		S3: blobstore.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PublicURL: cfg.S3PublicURL,
+		},
+	})
+	if err != nil {
+		log.Fatalf("failed to create photo store: %v", err)
+	}
+
+	tokenGenerator, err := tokenService.NewRandomGenerator(cfg.TokenAlphabet, cfg.TokenLength, cfg.TokenCheckChar)
+	if err != nil {
+		log.Fatalf("failed to create token generator: %v", err)
+	}
+
+	if err := tokenService.ValidatePINLength(cfg.PINLength, cfg.TokenLength, cfg.TokenCheckChar); err != nil {
+		log.Fatalf("failed to validate pin config: %v", err)
+	}
+
+	tokSrv := tokenService.NewTokenService(tokRepo, placeRepo, memberRepo, refillRepo, tokenJobRepo, txManager, qrRenderer, tokenGenerator, &cfg)
+	kioskService := svcKiosk.NewKioskService(kioskRepo, placeRepo, memberRepo, qrRenderer, &cfg)
+	userSrv := userService.NewUserService(userRepo, reviewRepo, bonusRepo, refreshRepo, &cfg)
+	placeSrv := placeService.NewPlaceService(placeRepo, tokSrv)
+	photoService := svcPhoto.NewPhotoService(photoRepo, reviewRepo, photoStore, txManager, &cfg)
+	reviewSrv := reviewService.NewReviewService(reviewRepo, userRepo, placeRepo, memberRepo, tokSrv, kioskService, restrictionRepo, pinRepo, contentFilter, photoService, revisionRepo, txManager, &cfg)
 	adminSrv := adminService.NewAdminService(adminRepo)
 	leaderboardService := svcLeaderboard.NewService(leaderboardRepo)
-	bonusService := svcBonus.NewBonusService(userRepo, bonusRepo, &cfg)
+	bonusService := svcBonus.NewBonusService(userRepo, bonusRepo, memberRepo, qrRenderer, &cfg)
+	memberService := svcMember.NewMemberService(memberRepo, userRepo, placeRepo)
+	posService := svcPOS.NewPOSService(posRepo, placeRepo, memberRepo, tokSrv, &cfg)
+	linkService := svcLink.NewLinkService(scanRepo, placeRepo, memberRepo, &cfg)
+	replyService := svcReply.NewReplyService(replyRepo, reviewRepo, placeRepo, memberRepo)
+	voteService := svcVote.NewVoteService(voteRepo, reviewRepo)
+	reportService := svcReport.NewReportService(reportRepo, reviewRepo, userRepo, restrictionRepo, txManager, &cfg)
+
+	// Фоновые воркеры в тестах не запускаются: тесты вызывают RunOnce сами
+	refillWorker := tokenService.NewRefillWorker(tokSrv, refillRepo, &cfg)
+	batchJobWorker := tokenService.NewBatchJobWorker(tokSrv, tokenJobRepo, &cfg)
+	photoPurgeWorker := svcPhoto.NewPurgeWorker(photoService, &cfg)
 
 	app := controller.NewApplication(userSrv,
 		placeSrv,
@@ -83,18 +186,34 @@ func NewTestSetup() *TestSetup {
 		adminSrv,
 		leaderboardService,
 		bonusService,
+		memberService,
+		kioskService,
+		posService,
+		linkService,
+		replyService,
+		photoService,
+		voteService,
+		reportService,
 	)
 
-	r := controller.SetupRouter(app)
+	r, err := controller.SetupRouter(app, cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("failed to setup router: %v", err)
+	}
 
 	return &TestSetup{
-		App: r,
-		DB:  db,
+		App:          r,
+		DB:           db,
+		RefillWorker: refillWorker,
+		JobWorker:    batchJobWorker,
+		PurgeWorker:  photoPurgeWorker,
+		Cfg:          cfg,
 	}
 }
 
 func (ts *TestSetup) Close() {
 	ts.DB.Close()
+	_ = os.RemoveAll(ts.Cfg.MediaDir)
 }
 
 func (ts *TestSetup) Login(email, password string) string {
@@ -114,3 +233,44 @@ func (ts *TestSetup) Login(email, password string) string {
 
 	return resp["token"]
 }
+
+// LoadFixtures загружает YAML-фикстуры; пути указываются от каталога тестов, например ../fixtures/users.yml
+func (ts *TestSetup) LoadFixtures(t testing.TB, files ...string) {
+	t.Helper()
+
+	db := stdlib.OpenDBFromPool(ts.DB)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("failed to close db: %v", err)
+		}
+	}()
+
+	fixture, err := testfixtures.New(
+		testfixtures.Database(db),
+		testfixtures.Dialect("postgres"),
+		testfixtures.Files(files...),
+	)
+	require.NoError(t, err, "init fixtures failed")
+	require.NoError(t, fixture.Load(), "load fixtures failed")
+}
+
+// Do отправляет запрос в роутер; payload кодируется в JSON, пустой token означает запрос без авторизации
+func (ts *TestSetup) Do(method, path, token string, payload any) *httptest.ResponseRecorder {
+	var body *bytes.Reader
+	if payload != nil {
+		data, _ := json.Marshal(payload)
+		body = bytes.NewReader(data)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, body)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	ts.App.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id          UUID PRIMARY KEY,
+    user_id     UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id   UUID        NOT NULL,
+    token_hash  VARCHAR(64) NOT NULL UNIQUE,
+    expires_at  TIMESTAMP   NOT NULL,
+    created_at  TIMESTAMP   NOT NULL DEFAULT now(),
+    rotated_at  TIMESTAMP   NULL,
+    revoked_at  TIMESTAMP   NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family
+    ON refresh_tokens(family_id);
@@ -0,0 +1,5 @@
+ALTER TABLE bonus_rewards
+    DROP COLUMN validated_by,
+    DROP COLUMN validated_place_id;
+
+DROP TABLE IF EXISTS place_members;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS place_members (
+    id         UUID PRIMARY KEY,
+    place_id   UUID        NOT NULL REFERENCES places(id) ON DELETE CASCADE,
+    user_id    UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role       VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'manager', 'cashier')),
+    created_at TIMESTAMP   NOT NULL DEFAULT now()
+);
+
+ALTER TABLE place_members
+    ADD CONSTRAINT uniq_place_member UNIQUE (place_id, user_id);
+
+CREATE INDEX IF NOT EXISTS idx_place_members_user
+    ON place_members(user_id);
+
+ALTER TABLE bonus_rewards
+    ADD COLUMN validated_by       UUID NULL REFERENCES users(id),
+    ADD COLUMN validated_place_id UUID NULL REFERENCES places(id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_review_tokens_place_id;
+
+ALTER TABLE review_tokens
+    DROP COLUMN created_at,
+    DROP COLUMN revoked_at;
@@ -0,0 +1,5 @@
+ALTER TABLE review_tokens
+    ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT now(),
+    ADD COLUMN revoked_at TIMESTAMP NULL;
+
+CREATE INDEX idx_review_tokens_place_id ON review_tokens (place_id);
@@ -0,0 +1,7 @@
+DELETE FROM reviews WHERE token_id IS NULL;
+
+ALTER TABLE reviews
+    ALTER COLUMN token_id SET NOT NULL;
+
+DROP TABLE IF EXISTS kiosk_submissions;
+DROP TABLE IF EXISTS place_kiosks;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS place_kiosks (
+    place_id     UUID PRIMARY KEY REFERENCES places(id) ON DELETE CASCADE,
+    secret       BYTEA     NOT NULL,
+    step_seconds INTEGER   NOT NULL CHECK (step_seconds >= 10),
+    enabled      BOOLEAN   NOT NULL DEFAULT true,
+    created_at   TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Одна отправка по коду киоска на пользователя в каждом временном окне
+CREATE TABLE IF NOT EXISTS kiosk_submissions (
+    place_id    UUID      NOT NULL REFERENCES places(id) ON DELETE CASCADE,
+    user_id     UUID      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    time_window BIGINT    NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (place_id, user_id, time_window)
+);
+
+-- Отзывы по коду киоска не привязаны к строке review_tokens
+ALTER TABLE reviews
+    ALTER COLUMN token_id DROP NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE places
+    DROP COLUMN token_ttl_seconds,
+    DROP COLUMN tokens_initial_count,
+    DROP COLUMN tokens_refill_threshold,
+    DROP COLUMN tokens_refill_batch;
@@ -0,0 +1,6 @@
+-- Политика токенов заведения; NULL — значение из глобального конфига
+ALTER TABLE places
+    ADD COLUMN token_ttl_seconds       INTEGER NULL CHECK (token_ttl_seconds > 0),
+    ADD COLUMN tokens_initial_count    INTEGER NULL CHECK (tokens_initial_count >= 0),
+    ADD COLUMN tokens_refill_threshold INTEGER NULL CHECK (tokens_refill_threshold >= 0),
+    ADD COLUMN tokens_refill_batch     INTEGER NULL CHECK (tokens_refill_batch > 0);
@@ -0,0 +1,12 @@
+-- Очередь догенерации токенов: не больше одной задачи на заведение.
+-- requests растёт при каждом новом запросе, чтобы воркер не удалил задачу, пришедшую во время обработки.
+CREATE TABLE IF NOT EXISTS token_refill_jobs (
+    place_id   UUID      PRIMARY KEY REFERENCES places(id) ON DELETE CASCADE,
+    requests   INTEGER   NOT NULL DEFAULT 1,
+    attempts   INTEGER   NOT NULL DEFAULT 0,
+    run_at     TIMESTAMP NOT NULL DEFAULT now(),
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_refill_jobs_run_at ON token_refill_jobs (run_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_review_tokens_batch_job_id;
+
+ALTER TABLE review_tokens
+    DROP COLUMN IF EXISTS batch_job_id;
+
+DROP TABLE IF EXISTS token_batch_jobs;
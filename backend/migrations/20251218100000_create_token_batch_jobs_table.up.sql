@@ -0,0 +1,23 @@
+-- Фоновая генерация больших пачек токенов. generated обновляется вместе с каждой вставленной порцией,
+-- поэтому прерванная задача продолжается с того же места.
+CREATE TABLE IF NOT EXISTS token_batch_jobs (
+    id           UUID PRIMARY KEY,
+    place_id     UUID      NOT NULL REFERENCES places(id) ON DELETE CASCADE,
+    requested_by UUID      REFERENCES users(id) ON DELETE SET NULL,
+    total        INTEGER   NOT NULL CHECK (total BETWEEN 1 AND 100000),
+    generated    INTEGER   NOT NULL DEFAULT 0,
+    status       TEXT      NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'done', 'failed')),
+    expires_at   TIMESTAMP NOT NULL,
+    error        TEXT,
+    created_at   TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMP NOT NULL DEFAULT now(),
+    finished_at  TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_batch_jobs_status ON token_batch_jobs (status, created_at);
+
+ALTER TABLE review_tokens
+    ADD COLUMN batch_job_id UUID REFERENCES token_batch_jobs(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_review_tokens_batch_job_id ON review_tokens (batch_job_id);
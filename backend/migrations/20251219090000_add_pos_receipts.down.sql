@@ -0,0 +1,11 @@
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS receipt_id;
+
+DROP INDEX IF EXISTS idx_review_tokens_place_receipt;
+
+ALTER TABLE review_tokens
+    DROP COLUMN IF EXISTS purchased_at,
+    DROP COLUMN IF EXISTS receipt_amount,
+    DROP COLUMN IF EXISTS receipt_id;
+
+DROP TABLE IF EXISTS place_pos_integrations;
@@ -0,0 +1,21 @@
+-- Секрет, которым кассовая система заведения подписывает события о чеках
+CREATE TABLE IF NOT EXISTS place_pos_integrations (
+    place_id   UUID PRIMARY KEY REFERENCES places(id) ON DELETE CASCADE,
+    secret     BYTEA     NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Токен, выпущенный по чеку, хранит ссылку на покупку; один чек — один токен
+ALTER TABLE review_tokens
+    ADD COLUMN receipt_id     TEXT,
+    ADD COLUMN receipt_amount BIGINT,
+    ADD COLUMN purchased_at   TIMESTAMP;
+
+CREATE UNIQUE INDEX idx_review_tokens_place_receipt
+    ON review_tokens (place_id, receipt_id)
+    WHERE receipt_id IS NOT NULL;
+
+-- Отзыв копирует чек из токена: по нему показывается отметка «подтверждённая покупка»
+ALTER TABLE reviews
+    ADD COLUMN receipt_id TEXT;
@@ -0,0 +1,13 @@
+-- Переходы по короткой ссылке /r/<token>; user_id и opened_at заполняются, когда форму открыл вошедший пользователь
+CREATE TABLE IF NOT EXISTS token_scans (
+    id         UUID PRIMARY KEY,
+    token_id   UUID      NOT NULL REFERENCES review_tokens(id) ON DELETE CASCADE,
+    place_id   UUID      NOT NULL REFERENCES places(id) ON DELETE CASCADE,
+    user_agent TEXT      NOT NULL DEFAULT '',
+    scanned_at TIMESTAMP NOT NULL DEFAULT now(),
+    user_id    UUID REFERENCES users(id) ON DELETE SET NULL,
+    opened_at  TIMESTAMP
+);
+
+CREATE INDEX idx_token_scans_place_scanned ON token_scans (place_id, scanned_at);
+CREATE INDEX idx_token_scans_token ON token_scans (token_id);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_reviews_place_campaign;
+DROP INDEX IF EXISTS idx_reviews_place_table;
+DROP INDEX IF EXISTS idx_reviews_place_staff;
+
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS campaign,
+    DROP COLUMN IF EXISTS table_label,
+    DROP COLUMN IF EXISTS staff_id;
+
+ALTER TABLE review_tokens
+    DROP COLUMN IF EXISTS campaign,
+    DROP COLUMN IF EXISTS table_label,
+    DROP COLUMN IF EXISTS staff_id;
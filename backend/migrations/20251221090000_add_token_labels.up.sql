@@ -0,0 +1,14 @@
+-- Атрибуция токенов: сотрудник, стол и рекламная кампания. Отзыв копирует метки токена.
+ALTER TABLE review_tokens
+    ADD COLUMN staff_id    UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN table_label TEXT,
+    ADD COLUMN campaign    TEXT;
+
+ALTER TABLE reviews
+    ADD COLUMN staff_id    UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN table_label TEXT,
+    ADD COLUMN campaign    TEXT;
+
+CREATE INDEX idx_reviews_place_staff ON reviews (place_id, staff_id) WHERE staff_id IS NOT NULL;
+CREATE INDEX idx_reviews_place_table ON reviews (place_id, table_label) WHERE table_label IS NOT NULL;
+CREATE INDEX idx_reviews_place_campaign ON reviews (place_id, campaign) WHERE campaign IS NOT NULL;
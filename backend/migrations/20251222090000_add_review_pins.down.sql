@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS pin_attempts;
+
+DROP INDEX IF EXISTS idx_review_tokens_place_pin_active;
+
+ALTER TABLE review_tokens
+    DROP COLUMN IF EXISTS pin;
@@ -0,0 +1,22 @@
+-- Числовой PIN для ручного ввода вместо QR-кода. Уникален только среди активных токенов заведения:
+-- после отзыва или отзыва токена тот же PIN можно выдать снова.
+ALTER TABLE review_tokens
+    ADD COLUMN pin VARCHAR(8);
+
+CREATE UNIQUE INDEX idx_review_tokens_place_pin_active
+    ON review_tokens (place_id, pin)
+    WHERE pin IS NOT NULL AND is_used = false AND revoked_at IS NULL;
+
+-- Журнал попыток ввода PIN: по нему считаются лимиты на пользователя и IP и разбираются подборы
+CREATE TABLE pin_attempts (
+    id         UUID PRIMARY KEY,
+    user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip         TEXT NOT NULL,
+    place_id   UUID NOT NULL REFERENCES places(id) ON DELETE CASCADE,
+    pin        VARCHAR(8) NOT NULL,
+    succeeded  BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_pin_attempts_user_created ON pin_attempts (user_id, created_at);
+CREATE INDEX idx_pin_attempts_ip_created ON pin_attempts (ip, created_at);
@@ -0,0 +1,10 @@
+ALTER TABLE places
+    DROP COLUMN IF EXISTS moderation_mode;
+
+DROP INDEX IF EXISTS idx_reviews_status_created;
+
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS moderated_at,
+    DROP COLUMN IF EXISTS moderated_by,
+    DROP COLUMN IF EXISTS moderation_reason,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,14 @@
+-- Модерация отзывов. Уже опубликованные отзывы остаются опубликованными.
+ALTER TABLE reviews
+    ADD COLUMN status            VARCHAR(16) NOT NULL DEFAULT 'published'
+        CHECK (status IN ('pending', 'published', 'rejected', 'hidden')),
+    ADD COLUMN moderation_reason TEXT,
+    ADD COLUMN moderated_by      UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN moderated_at      TIMESTAMP;
+
+CREATE INDEX idx_reviews_status_created ON reviews (status, created_at);
+
+-- Режим модерации заведения: pre — отзыв ждёт одобрения, post — публикуется сразу.
+-- NULL — режим из глобального конфига.
+ALTER TABLE places
+    ADD COLUMN moderation_mode VARCHAR(8) CHECK (moderation_mode IN ('pre', 'post'));
@@ -0,0 +1,3 @@
+-- Правило автоматического фильтра текста, сработавшее на отзыве; NULL — текст прошёл без замечаний
+ALTER TABLE reviews
+    ADD COLUMN filter_rule VARCHAR(64);
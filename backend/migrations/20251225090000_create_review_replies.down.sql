@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reply_templates;
+
+DROP TABLE IF EXISTS review_replies;
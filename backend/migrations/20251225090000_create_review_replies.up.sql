@@ -0,0 +1,23 @@
+-- Публичный ответ заведения на отзыв: не больше одного на отзыв
+CREATE TABLE IF NOT EXISTS review_replies (
+    id         UUID PRIMARY KEY,
+    review_id  UUID          NOT NULL UNIQUE REFERENCES reviews(id) ON DELETE CASCADE,
+    place_id   UUID          NOT NULL REFERENCES places(id) ON DELETE CASCADE,
+    author_id  UUID REFERENCES users(id) ON DELETE SET NULL,
+    content    VARCHAR(1000) NOT NULL,
+    created_at TIMESTAMP     NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX idx_review_replies_place_created ON review_replies (place_id, created_at);
+
+-- Сохранённые шаблоны ответов заведения
+CREATE TABLE IF NOT EXISTS reply_templates (
+    id         UUID PRIMARY KEY,
+    place_id   UUID          NOT NULL REFERENCES places(id) ON DELETE CASCADE,
+    title      VARCHAR(64)   NOT NULL,
+    content    VARCHAR(1000) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP     NOT NULL DEFAULT now(),
+    UNIQUE (place_id, title)
+);
@@ -0,0 +1,15 @@
+-- Фотографии к отзыву: файлы лежат в хранилище (pkg/blobstore), здесь — ключи и размеры
+CREATE TABLE IF NOT EXISTS review_photos (
+    id            UUID PRIMARY KEY,
+    review_id     UUID         NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    storage_key   VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL,
+    content_type  VARCHAR(32)  NOT NULL,
+    size_bytes    BIGINT       NOT NULL,
+    width         INT          NOT NULL,
+    height        INT          NOT NULL,
+    position      INT          NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_review_photos_review ON review_photos (review_id, position);
@@ -0,0 +1,4 @@
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS helpful_count;
+
+DROP TABLE IF EXISTS review_votes;
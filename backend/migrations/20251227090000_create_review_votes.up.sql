@@ -0,0 +1,11 @@
+-- Отметки «полезный отзыв»: не больше одной от пользователя на отзыв
+CREATE TABLE IF NOT EXISTS review_votes (
+    review_id  UUID      NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    user_id    UUID      NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (review_id, user_id)
+);
+
+-- Счётчик отметок хранится в отзыве, чтобы сортировать список без подсчёта голосов
+ALTER TABLE reviews
+    ADD COLUMN helpful_count INT NOT NULL DEFAULT 0;
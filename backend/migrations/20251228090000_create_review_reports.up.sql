@@ -0,0 +1,16 @@
+-- Жалобы пользователей на отзывы: одна жалоба от пользователя на отзыв
+CREATE TABLE IF NOT EXISTS review_reports (
+    id          UUID PRIMARY KEY,
+    review_id   UUID        NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    reporter_id UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason      VARCHAR(16) NOT NULL CHECK (reason IN ('spam', 'offensive', 'fake', 'off_topic')),
+    comment     VARCHAR(500),
+    status      VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+    resolution  VARCHAR(32) CHECK (resolution IN ('dismiss', 'hide', 'hide_and_restrict')),
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP,
+    created_at  TIMESTAMP   NOT NULL DEFAULT now(),
+    UNIQUE (review_id, reporter_id)
+);
+
+CREATE INDEX idx_review_reports_status_review ON review_reports (status, review_id);
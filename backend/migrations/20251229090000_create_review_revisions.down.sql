@@ -0,0 +1,4 @@
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS deleted_at;
+
+DROP TABLE IF EXISTS review_revisions;
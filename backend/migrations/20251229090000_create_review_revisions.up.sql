@@ -0,0 +1,19 @@
+-- Редакции отзыва: первая — текст при создании, каждая правка автора добавляет следующую
+CREATE TABLE IF NOT EXISTS review_revisions (
+    id         UUID PRIMARY KEY,
+    review_id  UUID      NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    revision   INT       NOT NULL,
+    content    TEXT      NOT NULL,
+    rating     INT       NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (review_id, revision)
+);
+
+-- У существующих отзывов исходный текст не сохранился: первой редакцией становится текущий
+INSERT INTO review_revisions (id, review_id, revision, content, rating, created_at)
+SELECT gen_random_uuid(), id, 1, content, rating, COALESCE(updated_at, created_at)
+FROM reviews;
+
+-- Момент удаления отзыва: от него отсчитывается окно отмены
+ALTER TABLE reviews
+    ADD COLUMN deleted_at TIMESTAMP;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_token_scans_client;
+
+ALTER TABLE token_scans
+    DROP COLUMN IF EXISTS client_key,
+    DROP COLUMN IF EXISTS session_key;
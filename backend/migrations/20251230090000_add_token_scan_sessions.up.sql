@@ -0,0 +1,7 @@
+-- client_key — хеш IP и User-Agent: повторные переходы одного клиента в окне склеиваются в один.
+-- session_key выдаётся в адресе формы и подтверждает, что вход отмечает тот, кто перешёл по ссылке
+ALTER TABLE token_scans
+    ADD COLUMN client_key  TEXT NOT NULL DEFAULT '',
+    ADD COLUMN session_key TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_token_scans_client ON token_scans (token_id, client_key, scanned_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_review_photos_deleted;
+
+ALTER TABLE review_photos
+    DROP COLUMN IF EXISTS deleted_at;
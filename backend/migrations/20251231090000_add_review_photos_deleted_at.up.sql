@@ -0,0 +1,6 @@
+-- Фото удалённого отзыва помечаются удалёнными и возвращаются при отмене удаления;
+-- строки и файлы стираются фоновой очисткой после окна отмены
+ALTER TABLE review_photos
+    ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_review_photos_deleted ON review_photos (deleted_at) WHERE deleted_at IS NOT NULL;
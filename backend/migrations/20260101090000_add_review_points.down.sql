@@ -0,0 +1,2 @@
+ALTER TABLE reviews
+    DROP COLUMN IF EXISTS points;
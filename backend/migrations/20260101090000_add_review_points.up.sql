@@ -0,0 +1,17 @@
+-- Баллы, начисленные автору за отзыв; принадлежат автору, пока отзыв опубликован.
+-- Для старых отзывов восстанавливаются по оценке: только опубликованные отзывы
+-- и только если в момент отзыва у автора не было заморозки баллов или запрета на отзывы
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS points INTEGER NOT NULL DEFAULT 0;
+
+UPDATE reviews r
+SET points = CASE r.rating WHEN 5 THEN 10 WHEN 4 THEN 5 ELSE 0 END
+WHERE r.status = 'published'
+  AND NOT EXISTS (
+      SELECT 1
+      FROM user_restrictions ur
+      WHERE ur.user_id = r.user_id
+        AND ur.restriction_type IN ('review_points_freeze', 'review_ban')
+        AND r.created_at >= ur.created_at
+        AND r.created_at < ur.expires_at
+  );
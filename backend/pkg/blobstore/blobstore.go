@@ -0,0 +1,64 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore хранит файлы по ключу вида "reviews/<id>/<file>". URL возвращает публичный адрес файла:
+// для локального хранилища его отдаёт само приложение, для S3 — бакет или CDN перед ним.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// Config — выбор и параметры хранилища
+type Config struct {
+	Backend string
+
+	// LocalDir — каталог локального хранилища, LocalBaseURL — адрес, по которому приложение отдаёт файлы
+	LocalDir     string
+	LocalBaseURL string
+
+	S3 S3Config
+}
+
+// New создаёт хранилище по cfg.Backend
+func New(cfg Config) (BlobStore, error) {
+	switch cfg.Backend {
+	case BackendLocal, "":
+		return NewLocalStore(cfg.LocalDir, cfg.LocalBaseURL)
+	case BackendS3:
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store backend %q", cfg.Backend)
+	}
+}
+
+// validateKey пропускает только относительные ключи без "..", пустых и скрытых сегментов
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || strings.HasPrefix(part, ".") {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package contentfilter
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Действия правил
+const (
+	ActionAllow  = "allow"
+	ActionFlag   = "flag"
+	ActionReject = "reject"
+)
+
+var ErrInvalidRules = errors.New("invalid content filter rules")
+
+//go:embed default_rules.json
+var defaultRules []byte
+
+// Verdict — решение фильтра по тексту. Rule — имя сработавшего правила, пустое, если не сработало ни одно.
+type Verdict struct {
+	Action string
+	Rule   string
+}
+
+// Matched сообщает, сработало ли какое-либо правило
+func (v Verdict) Matched() bool {
+	return v.Rule != ""
+}
+
+// Rule проверяет текст на одно нарушение
+type Rule interface {
+	Match(text string) bool
+}
+
+type namedRule struct {
+	name   string
+	action string
+	rule   Rule
+}
+
+// Filter проверяет текст правилами по порядку: решение принимает первое сработавшее.
+// Поэтому правила-исключения с действием allow ставят выше тех, которые они перекрывают.
+type Filter struct {
+	rules []namedRule
+}
+
+// RuleConfig — описание правила в JSON. Параметры, не относящиеся к типу правила, игнорируются.
+type RuleConfig struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Action string `json:"action"`
+
+	// words: слова целиком; «*» в конце слова — любое окончание
+	Words []string `json:"words,omitempty"`
+	// url: доменные зоны, адреса в которых считаются ссылками и без http:// или www.
+	TLDs []string `json:"tlds,omitempty"`
+	// phone: минимальное число цифр в номере
+	MinDigits int `json:"min_digits,omitempty"`
+	// repeat: наибольшее допустимое число одинаковых символов подряд
+	MaxRun int `json:"max_run,omitempty"`
+	// caps: доля заглавных среди букв и минимальное число букв, с которого правило применяется
+	MaxRatio   float64 `json:"max_ratio,omitempty"`
+	MinLetters int     `json:"min_letters,omitempty"`
+	// length: наибольшая длина текста в символах
+	Max int `json:"max,omitempty"`
+}
+
+// Config — набор правил в порядке проверки
+type Config struct {
+	Rules []RuleConfig `json:"rules"`
+}
+
+// Load читает правила из JSON-файла path; пустой path — встроенные правила по умолчанию
+func Load(path string) (*Filter, error) {
+	data := defaultRules
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read content filter rules: %w", err)
+		}
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRules, err)
+	}
+
+	return New(cfg)
+}
+
+// New собирает фильтр из описаний правил
+func New(cfg Config) (*Filter, error) {
+	f := &Filter{rules: make([]namedRule, 0, len(cfg.Rules))}
+	seen := make(map[string]bool, len(cfg.Rules))
+
+	for i, rc := range cfg.Rules {
+		if rc.Name == "" {
+			return nil, fmt.Errorf("%w: rule %d has no name", ErrInvalidRules, i)
+		}
+		if seen[rc.Name] {
+			return nil, fmt.Errorf("%w: duplicate rule %q", ErrInvalidRules, rc.Name)
+		}
+		seen[rc.Name] = true
+
+		switch rc.Action {
+		case ActionAllow, ActionFlag, ActionReject:
+		default:
+			return nil, fmt.Errorf("%w: rule %q has unknown action %q", ErrInvalidRules, rc.Name, rc.Action)
+		}
+
+		rule, err := buildRule(rc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %q: %v", ErrInvalidRules, rc.Name, err)
+		}
+
+		f.rules = append(f.rules, namedRule{name: rc.Name, action: rc.Action, rule: rule})
+	}
+
+	return f, nil
+}
+
+// Check возвращает решение первого сработавшего правила; если не сработало ни одно — allow без имени правила
+func (f *Filter) Check(text string) Verdict {
+	for _, r := range f.rules {
+		if r.rule.Match(text) {
+			return Verdict{Action: r.action, Rule: r.name}
+		}
+	}
+	return Verdict{Action: ActionAllow}
+}
+
+func buildRule(rc RuleConfig) (Rule, error) {
+	switch rc.Type {
+	case "words":
+		return newWordsRule(rc.Words)
+	case "url":
+		return newURLRule(rc.TLDs)
+	case "phone":
+		return newPhoneRule(rc.MinDigits)
+	case "repeat":
+		return newRepeatRule(rc.MaxRun)
+	case "caps":
+		return newCapsRule(rc.MaxRatio, rc.MinLetters)
+	case "length":
+		return newLengthRule(rc.Max)
+	default:
+		return nil, fmt.Errorf("unknown type %q", rc.Type)
+	}
+}
@@ -0,0 +1,228 @@
+package contentfilter
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultTLDs — зоны, в которых адрес без http:// и www. считается ссылкой
+var defaultTLDs = []string{
+	"ru", "рф", "su", "by", "kz", "ua", "com", "net", "org", "info", "biz", "io", "me",
+	"xyz", "online", "site", "shop", "store", "club", "pro", "ly", "to", "cc", "gg",
+}
+
+// homoglyphs — латинские буквы, которыми подменяют кириллицу в обход словаря
+var homoglyphs = strings.NewReplacer(
+	"a", "а", "b", "в", "c", "с", "e", "е", "h", "н", "k", "к", "m", "м",
+	"o", "о", "p", "р", "t", "т", "x", "х", "y", "у", "ё", "е",
+)
+
+// wordsRule срабатывает на слово из словаря. Сравнение без учёта регистра, «ё» равна «е»,
+// а в словах с кириллицей латинские двойники букв читаются как кириллица.
+type wordsRule struct {
+	exact    map[string]struct{}
+	prefixes []string
+}
+
+func newWordsRule(words []string) (Rule, error) {
+	if len(words) == 0 {
+		return nil, errors.New("words must not be empty")
+	}
+
+	r := &wordsRule{exact: make(map[string]struct{}, len(words))}
+	for _, w := range words {
+		w = normalizeWord(strings.TrimSpace(w))
+		if prefix, ok := strings.CutSuffix(w, "*"); ok {
+			if prefix == "" {
+				return nil, errors.New("empty word prefix")
+			}
+			r.prefixes = append(r.prefixes, prefix)
+			continue
+		}
+		if w == "" {
+			return nil, errors.New("empty word")
+		}
+		r.exact[w] = struct{}{}
+	}
+
+	return r, nil
+}
+
+func (r *wordsRule) Match(text string) bool {
+	for _, word := range splitWords(text) {
+		word = normalizeWord(word)
+		if _, ok := r.exact[word]; ok {
+			return true
+		}
+		for _, prefix := range r.prefixes {
+			if strings.HasPrefix(word, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func splitWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func normalizeWord(word string) string {
+	word = strings.ToLower(word)
+	if strings.IndexFunc(word, isCyrillic) >= 0 {
+		return homoglyphs.Replace(word)
+	}
+	return strings.ReplaceAll(word, "ё", "е")
+}
+
+func isCyrillic(r rune) bool {
+	return unicode.Is(unicode.Cyrillic, r)
+}
+
+// urlRule срабатывает на ссылку: со схемой, с www. или домен в одной из зон tlds
+type urlRule struct {
+	re *regexp.Regexp
+}
+
+func newURLRule(tlds []string) (Rule, error) {
+	if len(tlds) == 0 {
+		tlds = defaultTLDs
+	}
+
+	quoted := make([]string, 0, len(tlds))
+	for _, tld := range tlds {
+		tld = strings.TrimPrefix(strings.TrimSpace(tld), ".")
+		if tld == "" {
+			return nil, errors.New("empty tld")
+		}
+		quoted = append(quoted, regexp.QuoteMeta(tld))
+	}
+
+	re, err := regexp.Compile(
+		`(?i)(?:https?://|www\.)\S+` +
+			`|(?:^|[^\p{L}\p{N}.-])(?:[\p{L}\p{N}-]+\.)+(?:` + strings.Join(quoted, "|") + `)(?:$|[^\p{L}\p{N}-])`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &urlRule{re: re}, nil
+}
+
+func (r *urlRule) Match(text string) bool {
+	return r.re.MatchString(text)
+}
+
+// phoneCandidate — цифры с пробелами, дефисами и скобками между ними; точки не входят, чтобы не ловить даты
+var phoneCandidate = regexp.MustCompile(`\+?\d[\d\s()-]{4,}\d`)
+
+const maxPhoneDigits = 15
+
+// phoneRule срабатывает на последовательность из minDigits–15 цифр, похожую на номер телефона
+type phoneRule struct {
+	minDigits int
+}
+
+func newPhoneRule(minDigits int) (Rule, error) {
+	if minDigits <= 0 {
+		minDigits = 10
+	}
+	if minDigits > maxPhoneDigits {
+		return nil, errors.New("min_digits is too large")
+	}
+	return &phoneRule{minDigits: minDigits}, nil
+}
+
+func (r *phoneRule) Match(text string) bool {
+	for _, candidate := range phoneCandidate.FindAllString(text, -1) {
+		digits := 0
+		for _, c := range candidate {
+			if c >= '0' && c <= '9' {
+				digits++
+			}
+		}
+		if digits >= r.minDigits && digits <= maxPhoneDigits {
+			return true
+		}
+	}
+	return false
+}
+
+// repeatRule срабатывает, если один символ повторяется подряд больше maxRun раз (без учёта регистра, кроме пробелов)
+type repeatRule struct {
+	maxRun int
+}
+
+func newRepeatRule(maxRun int) (Rule, error) {
+	if maxRun <= 0 {
+		return nil, errors.New("max_run must be positive")
+	}
+	return &repeatRule{maxRun: maxRun}, nil
+}
+
+func (r *repeatRule) Match(text string) bool {
+	var prev rune
+	run := 0
+	for _, c := range strings.ToLower(text) {
+		if c == prev && !unicode.IsSpace(c) {
+			run++
+		} else {
+			prev, run = c, 1
+		}
+		if run > r.maxRun {
+			return true
+		}
+	}
+	return false
+}
+
+// capsRule срабатывает, если в тексте хотя бы minLetters букв и доля заглавных больше maxRatio
+type capsRule struct {
+	maxRatio   float64
+	minLetters int
+}
+
+func newCapsRule(maxRatio float64, minLetters int) (Rule, error) {
+	if maxRatio <= 0 || maxRatio >= 1 {
+		return nil, errors.New("max_ratio must be between 0 and 1")
+	}
+	if minLetters <= 0 {
+		minLetters = 1
+	}
+	return &capsRule{maxRatio: maxRatio, minLetters: minLetters}, nil
+}
+
+func (r *capsRule) Match(text string) bool {
+	letters, upper := 0, 0
+	for _, c := range text {
+		if !unicode.IsLetter(c) {
+			continue
+		}
+		letters++
+		if unicode.IsUpper(c) {
+			upper++
+		}
+	}
+	return letters >= r.minLetters && float64(upper)/float64(letters) > r.maxRatio
+}
+
+// lengthRule срабатывает на текст длиннее max символов
+type lengthRule struct {
+	max int
+}
+
+func newLengthRule(max int) (Rule, error) {
+	if max <= 0 {
+		return nil, errors.New("max must be positive")
+	}
+	return &lengthRule{max: max}, nil
+}
+
+func (r *lengthRule) Match(text string) bool {
+	return utf8.RuneCountInString(text) > r.max
+}
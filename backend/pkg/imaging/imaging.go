@@ -0,0 +1,174 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // регистрирует декодер WebP для image.Decode
+)
+
+// Поддерживаемые форматы загрузки
+const (
+	MIMEJPEG = "image/jpeg"
+	MIMEPNG  = "image/png"
+	MIMEWebP = "image/webp"
+)
+
+const (
+	jpegQuality = 85
+
+	// maxPixels ограничивает размер декодируемого изображения: маленький файл может описывать огромный кадр
+	maxPixels = 40_000_000
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrInvalidImage    = errors.New("invalid image")
+)
+
+// Options — ограничения обработки: MaxDimension — наибольшая сторона сохраняемого изображения, ThumbnailSize — миниатюры
+type Options struct {
+	MaxDimension  int
+	ThumbnailSize int
+}
+
+// Result — перекодированное изображение и миниатюра в одном формате ContentType
+type Result struct {
+	ContentType string
+	Image       []byte
+	Thumbnail   []byte
+	Width       int
+	Height      int
+}
+
+// DetectType определяет формат по содержимому файла, а не по имени или заголовку запроса
+func DetectType(data []byte) (string, error) {
+	switch mime := http.DetectContentType(data); mime {
+	case MIMEJPEG, MIMEPNG, MIMEWebP:
+		return mime, nil
+	default:
+		return "", ErrUnsupportedType
+	}
+}
+
+// Process проверяет изображение, разворачивает его по EXIF-ориентации, уменьшает и делает миниатюру.
+// Изображение перекодируется заново, поэтому EXIF, GPS и прочие метаданные в результат не попадают.
+// PNG остаётся PNG (ради прозрачности), JPEG и WebP сохраняются в JPEG.
+func Process(data []byte, opts Options) (*Result, error) {
+	mime, err := DetectType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrInvalidImage
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	if mime == MIMEJPEG {
+		src = orient(src, jpegOrientation(data))
+	}
+
+	outType := MIMEJPEG
+	if mime == MIMEPNG {
+		outType = MIMEPNG
+	}
+
+	full := fit(src, opts.MaxDimension)
+	thumb := fit(full, opts.ThumbnailSize)
+
+	fullData, err := encode(full, outType)
+	if err != nil {
+		return nil, err
+	}
+	thumbData, err := encode(thumb, outType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		ContentType: outType,
+		Image:       fullData,
+		Thumbnail:   thumbData,
+		Width:       full.Bounds().Dx(),
+		Height:      full.Bounds().Dy(),
+	}, nil
+}
+
+// Ext — расширение файла для формата результата
+func Ext(contentType string) string {
+	if contentType == MIMEPNG {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// fit вписывает изображение в квадрат max×max с сохранением пропорций; меньшие изображения не увеличиваются
+func fit(src image.Image, max int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if max <= 0 || (w <= max && h <= max) {
+		return src
+	}
+
+	if w >= h {
+		h = h * max / w
+		w = max
+	} else {
+		w = w * max / h
+		h = max
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst
+}
+
+func encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+	if contentType == MIMEPNG {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode image: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// flatten кладёт изображение на белый фон: у JPEG нет прозрачности, и без фона она станет чёрной
+func flatten(img image.Image) image.Image {
+	if _, ok := img.(*image.YCbCr); ok {
+		return img
+	}
+
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
@@ -0,0 +1,142 @@
+package labels
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	PageA4     = "A4"
+	PageLetter = "Letter"
+
+	MaxColumns = 6
+	MaxRows    = 12
+
+	marginMM   = 8.0
+	paddingMM  = 2.0
+	lineMM     = 4.0
+	textLines  = 3
+	fontSizePt = 8.0
+)
+
+var ErrInvalidLayout = errors.New("invalid label layout")
+
+// Label — одна наклейка: QR-код и подписи под ним
+type Label struct {
+	QRPNG  []byte
+	Title  string
+	Code   string
+	Footer string
+}
+
+// Layout — формат страницы и сетка наклеек
+type Layout struct {
+	PageSize string
+	Columns  int
+	Rows     int
+}
+
+// Validate нормализует формат страницы и проверяет размеры сетки
+func (l *Layout) Validate() error {
+	switch strings.ToLower(l.PageSize) {
+	case "", "a4":
+		l.PageSize = PageA4
+	case "letter":
+		l.PageSize = PageLetter
+	default:
+		return ErrInvalidLayout
+	}
+
+	if l.Columns < 1 || l.Columns > MaxColumns || l.Rows < 1 || l.Rows > MaxRows {
+		return ErrInvalidLayout
+	}
+
+	return nil
+}
+
+// RenderPDF раскладывает наклейки сеткой по страницам и рисует пунктир для резки
+func RenderPDF(items []Label, layout Layout) ([]byte, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+
+	pdf := gofpdf.New("P", "mm", layout.PageSize, "")
+	pdf.SetMargins(marginMM, marginMM, marginMM)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddUTF8FontFromBytes("go", "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes("go", "B", gobold.TTF)
+	pdf.AddUTF8FontFromBytes("gomono", "", gomono.TTF)
+
+	pageW, pageH := pdf.GetPageSize()
+	cellW := (pageW - 2*marginMM) / float64(layout.Columns)
+	cellH := (pageH - 2*marginMM) / float64(layout.Rows)
+
+	qrSize := min(cellW, cellH-textLines*lineMM) - 2*paddingMM
+	if qrSize <= 0 {
+		return nil, ErrInvalidLayout
+	}
+
+	perPage := layout.Columns * layout.Rows
+	for i, item := range items {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+
+		pos := i % perPage
+		x := marginMM + float64(pos%layout.Columns)*cellW
+		y := marginMM + float64(pos/layout.Columns)*cellH
+
+		pdf.SetDrawColor(180, 180, 180)
+		pdf.SetDashPattern([]float64{1, 1}, 0)
+		pdf.Rect(x, y, cellW, cellH, "D")
+		pdf.SetDashPattern([]float64{}, 0)
+
+		imgName := fmt.Sprintf("qr-%d", i)
+		opts := gofpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(imgName, opts, bytes.NewReader(item.QRPNG))
+		pdf.ImageOptions(imgName, x+(cellW-qrSize)/2, y+paddingMM, qrSize, qrSize, false, opts, 0, "")
+
+		textY := y + paddingMM + qrSize
+		textW := cellW - 2*paddingMM
+
+		pdf.SetFont("go", "B", fontSizePt)
+		pdf.SetXY(x+paddingMM, textY)
+		pdf.CellFormat(textW, lineMM, fitText(pdf, item.Title, textW), "", 0, "C", false, 0, "")
+
+		pdf.SetFont("gomono", "", fontSizePt)
+		pdf.SetXY(x+paddingMM, textY+lineMM)
+		pdf.CellFormat(textW, lineMM, item.Code, "", 0, "C", false, 0, "")
+
+		pdf.SetFont("go", "", fontSizePt-1)
+		pdf.SetXY(x+paddingMM, textY+2*lineMM)
+		pdf.CellFormat(textW, lineMM, item.Footer, "", 0, "C", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render pdf: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// fitText обрезает строку с многоточием, чтобы она поместилась в ширину ячейки
+func fitText(pdf *gofpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+
+	return string(runes) + "…"
+}
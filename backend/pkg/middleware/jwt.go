@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
@@ -12,7 +13,12 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// SessionChecker проверяет, не отозвана ли сессия, к которой привязан access-токен.
+type SessionChecker interface {
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
+func AuthMiddleware(sessions SessionChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenStr := c.GetHeader("Authorization")
 		if tokenStr == "" {
@@ -31,8 +37,28 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if claims.SessionID == "" {
+			c.JSON(401, gin.H{"error": "Invalid or expired token"})
+			c.Abort()
+			return
+		}
+
+		revoked, err := sessions.IsSessionRevoked(c.Request.Context(), claims.SessionID)
+		if err != nil {
+			slog.Error("session check failed", "error", err)
+			c.JSON(500, gin.H{"error": "internal error"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(401, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
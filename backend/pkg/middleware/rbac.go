@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kulikovroman08/reviewlink-backend/internal/model/rbac"
+)
+
+// RequirePermission пропускает запрос, только если роль из токена имеет право perm.
+// Должен подключаться после AuthMiddleware.
+func RequirePermission(perm rbac.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if !rbac.HasPermission(role, perm) {
+			slog.Warn("access denied",
+				"user_id", c.GetString("user_id"),
+				"role", role,
+				"permission", perm,
+			)
+			c.JSON(403, gin.H{"error": "access denied"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
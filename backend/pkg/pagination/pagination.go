@@ -0,0 +1,121 @@
+// Package pagination — keyset-пагинация списков: параметры страницы, непрозрачный курсор
+// и условие «после курсора» для squirrel.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Params — запрошенная страница: размер и курсор из next_cursor предыдущей страницы (пустой — первая страница)
+type Params struct {
+	Limit  int
+	Cursor string
+}
+
+// Normalize подставляет DefaultLimit вместо неположительного размера и ограничивает его MaxLimit
+func (p Params) Normalize() Params {
+	if p.Limit <= 0 {
+		p.Limit = DefaultLimit
+	}
+	if p.Limit > MaxLimit {
+		p.Limit = MaxLimit
+	}
+	return p
+}
+
+// Page — страница списка. NextCursor пуст на последней странице, Total — число строк без учёта страниц.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	Total      int
+}
+
+// Key — столбец порядка keyset-пагинации. Последний ключ должен быть уникальным (обычно id).
+type Key struct {
+	Column string
+	Desc   bool
+}
+
+// OrderBy возвращает выражения ORDER BY для ключей
+func OrderBy(keys []Key) []string {
+	order := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k.Desc {
+			order = append(order, k.Column+" DESC")
+		} else {
+			order = append(order, k.Column+" ASC")
+		}
+	}
+	return order
+}
+
+// After возвращает условие «строка идёт после строки со значениями values» для порядка keys.
+// Направления ключей могут различаться, поэтому условие раскрывается в цепочку OR,
+// а не сравнение кортежей.
+func After(keys []Key, values []any) sq.Sqlizer {
+	or := sq.Or{}
+	for i, k := range keys {
+		and := sq.And{}
+		for j := 0; j < i; j++ {
+			and = append(and, sq.Eq{keys[j].Column: values[j]})
+		}
+		if k.Desc {
+			and = append(and, sq.Lt{k.Column: values[i]})
+		} else {
+			and = append(and, sq.Gt{k.Column: values[i]})
+		}
+		or = append(or, and)
+	}
+	return or
+}
+
+// Cursor — значения ключей последней строки страницы
+type Cursor []json.RawMessage
+
+// EncodeCursor упаковывает значения ключей в непрозрачную строку для next_cursor
+func EncodeCursor(values ...any) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor распаковывает курсор; повреждённая строка даёт ErrInvalidCursor
+func DecodeCursor(s string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Scan раскладывает значения курсора по dest в том же порядке, в каком они кодировались.
+// Несовпадение числа или типа значений даёт ErrInvalidCursor.
+func (c Cursor) Scan(dest ...any) error {
+	if len(c) != len(dest) {
+		return ErrInvalidCursor
+	}
+	for i := range c {
+		if err := json.Unmarshal(c[i], dest[i]); err != nil {
+			return ErrInvalidCursor
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 1024
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported qr format")
+	ErrInvalidSize       = errors.New("invalid qr size")
+)
+
+// Image — отрисованный QR-код с MIME-типом для ответа
+type Image struct {
+	Data        []byte
+	ContentType string
+}
+
+// Renderer рисует QR-коды с заданным уровнем коррекции ошибок.
+// Используется и для токенов отзывов, и для бонусных QR-токенов.
+type Renderer struct {
+	level qrcode.RecoveryLevel
+}
+
+func NewRenderer(level string) (*Renderer, error) {
+	lvl, err := parseRecoveryLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	return &Renderer{level: lvl}, nil
+}
+
+// Render кодирует content в PNG или SVG размером size×size пикселей.
+// Нулевой size означает размер по умолчанию.
+func (r *Renderer) Render(content, format string, size int) (*Image, error) {
+	if size == 0 {
+		size = DefaultSize
+	}
+	if size < MinSize || size > MaxSize {
+		return nil, ErrInvalidSize
+	}
+
+	code, err := qrcode.New(content, r.level)
+	if err != nil {
+		return nil, fmt.Errorf("encode qr: %w", err)
+	}
+
+	switch strings.ToLower(format) {
+	case "", FormatPNG:
+		data, err := code.PNG(size)
+		if err != nil {
+			return nil, fmt.Errorf("render png: %w", err)
+		}
+		return &Image{Data: data, ContentType: "image/png"}, nil
+
+	case FormatSVG:
+		return &Image{Data: renderSVG(code.Bitmap(), size), ContentType: "image/svg+xml"}, nil
+
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// renderSVG строит SVG из матрицы модулей: один path, модуль — квадрат 1×1 в координатах viewBox
+func renderSVG(bitmap [][]bool, size int) []byte {
+	n := len(bitmap)
+
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, n, n)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`, n, n)
+	fmt.Fprintf(&b, `<path d="%s" fill="#000000"/>`, path.String())
+	b.WriteString(`</svg>`)
+
+	return []byte(b.String())
+}
+
+func parseRecoveryLevel(level string) (qrcode.RecoveryLevel, error) {
+	switch strings.ToLower(level) {
+	case "low", "l":
+		return qrcode.Low, nil
+	case "", "medium", "m":
+		return qrcode.Medium, nil
+	case "high", "q":
+		return qrcode.High, nil
+	case "highest", "h":
+		return qrcode.Highest, nil
+	default:
+		return 0, fmt.Errorf("unknown qr recovery level %q", level)
+	}
+}
@@ -4,10 +4,64 @@ const API_BASE = (window.location.port === "5500" || window.location.port === "8
 
 let BEARER_TOKEN = "";
 
-// Генерация QR без place_id
-function generateQRCode(token, placeId) {
-    const reviewUrl = `${API_BASE}/frontend/review-form.html?token=${token}&place_id=${placeId}`;
-    return `https://api.qrserver.com/v1/create-qr-code/?size=150x150&data=${encodeURIComponent(reviewUrl)}`;
+// Запрос к API с токеном админа. На 401 один раз обновляет пару токенов по refresh-токену
+// и повторяет запрос; если обновить не удалось, возвращает исходный ответ
+async function adminFetch(url, options = {}) {
+    const send = () => fetch(url, {
+        ...options,
+        headers: { ...options.headers, "Authorization": "Bearer " + BEARER_TOKEN }
+    });
+
+    const res = await send();
+    if (res.status !== 401 || !(await refreshAdminSession())) {
+        return res;
+    }
+    return send();
+}
+
+// Параллельные запросы (QR-коды грузятся пачкой) ждут одно обновление:
+// повторное использование refresh-токена сервер считает кражей и отзывает сессию
+let refreshPromise = null;
+
+function refreshAdminSession() {
+    if (!refreshPromise) {
+        refreshPromise = (async () => {
+            const refreshToken = localStorage.getItem("adminRefreshToken");
+            if (!refreshToken) return false;
+
+            try {
+                const res = await fetch(`${API_BASE}/auth/refresh`, {
+                    method: "POST",
+                    headers: { "Content-Type": "application/json" },
+                    body: JSON.stringify({ refresh_token: refreshToken })
+                });
+                if (!res.ok) return false;
+
+                const data = await res.json();
+                BEARER_TOKEN = data.token;
+                localStorage.setItem("adminToken", data.token);
+                localStorage.setItem("adminRefreshToken", data.refresh_token);
+                return true;
+            } catch {
+                return false;
+            }
+        })().finally(() => {
+            refreshPromise = null;
+        });
+    }
+    return refreshPromise;
+}
+
+// Загрузка QR токена с сервера: картинка защищена авторизацией, поэтому через blob
+async function loadQRCode(img) {
+    const { token, placeId } = img.dataset;
+    try {
+        const res = await adminFetch(`${API_BASE}/admin/places/${placeId}/tokens/${encodeURIComponent(token)}/qr?format=svg&size=150`);
+        if (!res.ok) throw new Error();
+        img.src = URL.createObjectURL(await res.blob());
+    } catch {
+        img.alt = "Не удалось загрузить QR";
+    }
 }
 
 // Авторизация
@@ -38,6 +92,7 @@ document.getElementById("loginBtn").onclick = async function () {
 
         BEARER_TOKEN = data.token;
         localStorage.setItem("adminToken", BEARER_TOKEN);
+        localStorage.setItem("adminRefreshToken", data.refresh_token);
         localStorage.setItem("adminEmail", email);
 
         statusDiv.innerHTML = '<div class="text-success">Успешный вход!</div>';
@@ -72,14 +127,19 @@ async function loadPlaces() {
     select.innerHTML = `<option>Загрузка...</option>`;
 
     try {
-        const response = await fetch(`${API_BASE}/places`, {
-            method: "GET",
-            headers: {
-                "Authorization": "Bearer " + BEARER_TOKEN
-            }
-        });
+        // Список отдаётся страницами: идём по курсору из X-Next-Cursor, пока он есть
+        const places = [];
+        let cursor = "";
+        do {
+            const params = new URLSearchParams({ limit: "100" });
+            if (cursor) params.set("cursor", cursor);
+
+            const response = await adminFetch(`${API_BASE}/places?${params}`);
+            if (!response.ok) throw new Error("failed to load places");
 
-        const places = await response.json();
+            places.push(...await response.json());
+            cursor = response.headers.get("X-Next-Cursor") || "";
+        } while (cursor);
 
         select.innerHTML = "";
         places.forEach(place => {
@@ -110,11 +170,10 @@ document.getElementById("generateBtn").onclick = async function () {
     `;
 
     try {
-        const response = await fetch(`${API_BASE}/admin/tokens`, {
+        const response = await adminFetch(`${API_BASE}/admin/tokens`, {
             method: "POST",
             headers: {
-                "Content-Type": "application/json",
-                "Authorization": "Bearer " + BEARER_TOKEN
+                "Content-Type": "application/json"
             },
             body: JSON.stringify({
                 place_id: placeId,
@@ -160,7 +219,6 @@ function showResults(data, container, placeId) {
 
     tokens.forEach((token, i) => {
         const url = `${API_BASE}/frontend/review-form.html?token=${token}&place_id=${placeId}`;
-        const qr = generateQRCode(token);
 
         html += `
             <div class="mb-4 p-3 border rounded">
@@ -173,7 +231,7 @@ function showResults(data, container, placeId) {
                 </div>
 
                 <div class="text-center mt-3">
-                    <img src="${qr}" class="img-fluid border rounded" />
+                    <img data-token="${token}" data-place-id="${placeId}" class="img-fluid border rounded token-qr" />
                 </div>
             </div>
         `;
@@ -181,4 +239,5 @@ function showResults(data, container, placeId) {
 
     html += `</div></div>`;
     container.innerHTML = html;
+    container.querySelectorAll(".token-qr").forEach(loadQRCode);
 }
@@ -14,6 +14,56 @@ function checkAuth() {
     return token;
 }
 
+// Обновление пары токенов по refresh-токену. Параллельные запросы ждут одно обновление:
+// refresh-токен одноразовый, повторное использование сервер считает кражей и отзывает сессию
+let refreshPromise = null;
+
+function refreshSession() {
+    if (!refreshPromise) {
+        refreshPromise = (async () => {
+            const refreshToken = localStorage.getItem('refreshToken');
+            if (!refreshToken) return false;
+
+            try {
+                const res = await fetch(`${API_BASE}/auth/refresh`, {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({ refresh_token: refreshToken })
+                });
+                if (!res.ok) return false;
+
+                const data = await res.json();
+                localStorage.setItem('userToken', data.token);
+                localStorage.setItem('refreshToken', data.refresh_token);
+                return true;
+            } catch {
+                return false;
+            }
+        })().finally(() => {
+            refreshPromise = null;
+        });
+    }
+    return refreshPromise;
+}
+
+// Запрос к API с access-токеном. На 401 один раз обновляет токены и повторяет запрос;
+// если обновить не удалось, возвращает исходный ответ 401
+async function authFetch(url, options = {}) {
+    const send = () => fetch(url, {
+        ...options,
+        headers: {
+            ...options.headers,
+            'Authorization': 'Bearer ' + localStorage.getItem('userToken')
+        }
+    });
+
+    const res = await send();
+    if (res.status !== 401 || !(await refreshSession())) {
+        return res;
+    }
+    return send();
+}
+
 // Показ ошибок
 function showError(message, container = document.body) {
     const alert = document.createElement('div');
@@ -63,22 +113,38 @@ function showSuccess(message, container = document.body) {
 
 // Выход из системы
 function logout() {
+    const token = localStorage.getItem('userToken');
+    if (token) {
+        // Отзываем сессию на сервере, результат не ждём
+        fetch(`${API_BASE}/auth/logout`, {
+            method: 'POST',
+            headers: { 'Authorization': `Bearer ${token}` },
+            keepalive: true
+        }).catch(() => {});
+    }
+
     localStorage.removeItem('userToken');
+    localStorage.removeItem('refreshToken');
     localStorage.removeItem('userEmail');
     const currentUrl = window.location.href;
     const newUrl = currentUrl.replace('/frontend/dashboard.html', '/frontend/login.html');
     window.location.href = newUrl;
 }
 
-// Генерация QR кода
-function generateQRCode(data, size = 200) {
-    return `https://api.qrserver.com/v1/create-qr-code/?size=${size}x${size}&data=${encodeURIComponent(data)}`;
+// Загрузка QR кода бонуса с сервера (картинка требует авторизации, поэтому через blob)
+async function generateQRCode(qrToken, size = 200) {
+    const res = await authFetch(`${API_BASE}/bonuses/${encodeURIComponent(qrToken)}/qr?format=svg&size=${size}`);
+    if (!res.ok) {
+        throw new Error('Не удалось загрузить QR код');
+    }
+    return URL.createObjectURL(await res.blob());
 }
 
 // Экспортируем для использования в других файлах
 window.AppCommon = {
     API_BASE,
     checkAuth,
+    authFetch,
     showError,
     showSuccess,
     logout,
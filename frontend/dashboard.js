@@ -1,9 +1,8 @@
 // dashboard.js - логика личного кабинета
 document.addEventListener('DOMContentLoaded', function () {
-    const { API_BASE, checkAuth, showError, showSuccess, logout, generateQRCode } = window.AppCommon;
+    const { API_BASE, checkAuth, authFetch, showError, showSuccess, logout, generateQRCode } = window.AppCommon;
 
-    let USER_TOKEN = checkAuth();
-    if (!USER_TOKEN) return;
+    if (!checkAuth()) return;
 
     // Инициализация
     initDashboard();
@@ -39,10 +38,9 @@ document.addEventListener('DOMContentLoaded', function () {
     // Загрузка статистики
     async function loadUserStats() {
         try {
-            const response = await fetch(`${API_BASE}/users/stats`, {
+            const response = await authFetch(`${API_BASE}/users/stats`, {
                 method: "GET",
                 headers: {
-                    "Authorization": "Bearer " + USER_TOKEN,
                     "Content-Type": "application/json"
                 }
             });
@@ -88,10 +86,9 @@ document.addEventListener('DOMContentLoaded', function () {
     async function loadBonuses() {
         try {
             console.log('Загрузка бонусов...');
-            const response = await fetch(`${API_BASE}/bonuses`, {
+            const response = await authFetch(`${API_BASE}/bonuses`, {
                 method: "GET",
                 headers: {
-                    "Authorization": "Bearer " + USER_TOKEN,
                     "Content-Type": "application/json"
                 }
             });
@@ -191,8 +188,14 @@ document.addEventListener('DOMContentLoaded', function () {
     }
 
     // Показ QR кода бонуса
-    function showBonusQR(qrToken) {
-        const qrUrl = generateQRCode(qrToken);
+    async function showBonusQR(qrToken) {
+        let qrUrl;
+        try {
+            qrUrl = await generateQRCode(qrToken);
+        } catch (err) {
+            showError(err.message);
+            return;
+        }
 
         document.getElementById('qrCodeImage').src = qrUrl;
         document.getElementById('bonusDescription').textContent = `QR токен: ${qrToken}`;
@@ -213,10 +216,9 @@ document.addEventListener('DOMContentLoaded', function () {
         messageDiv.innerHTML = '';
 
         try {
-            const response = await fetch(`${API_BASE}/bonuses/redeem`, {
+            const response = await authFetch(`${API_BASE}/bonuses/redeem`, {
                 method: "POST",
                 headers: {
-                    "Authorization": "Bearer " + USER_TOKEN,
                     "Content-Type": "application/json"
                 },
                 body: JSON.stringify({
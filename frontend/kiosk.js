@@ -0,0 +1,52 @@
+// kiosk.js - планшет заведения: QR-код отзыва, который меняется каждые несколько секунд
+document.addEventListener("DOMContentLoaded", () => {
+    const { API_BASE, checkAuth, authFetch, showError } = window.AppCommon;
+
+    if (!checkAuth()) return;
+
+    const placeId = new URLSearchParams(window.location.search).get("place_id");
+    const qrImage = document.getElementById("kioskQR");
+    const codeText = document.getElementById("kioskCode");
+    const countdown = document.getElementById("kioskCountdown");
+    const message = document.getElementById("kioskMessage");
+
+    if (!placeId) {
+        showError("В ссылке отсутствует place_id.", message);
+        return;
+    }
+
+    let validUntil = 0;
+
+    async function refresh() {
+        try {
+            const res = await authFetch(`${API_BASE}/places/${placeId}/kiosk/code`);
+            const data = await res.json().catch(() => ({}));
+            if (!res.ok) {
+                throw new Error(data.error || "Не удалось получить код");
+            }
+
+            const qrRes = await authFetch(`${API_BASE}/places/${placeId}/kiosk/qr?format=svg&size=512`);
+            if (!qrRes.ok) {
+                throw new Error("Не удалось загрузить QR-код");
+            }
+
+            if (qrImage.src) URL.revokeObjectURL(qrImage.src);
+            qrImage.src = URL.createObjectURL(await qrRes.blob());
+            codeText.textContent = data.code;
+            message.innerHTML = "";
+
+            validUntil = new Date(data.valid_until).getTime();
+            setTimeout(refresh, Math.max(validUntil - Date.now(), 1000));
+        } catch (err) {
+            showError(err.message, message);
+            setTimeout(refresh, 10000);
+        }
+    }
+
+    setInterval(() => {
+        const left = Math.max(0, Math.ceil((validUntil - Date.now()) / 1000));
+        countdown.textContent = validUntil ? `Код сменится через ${left} с` : "";
+    }, 1000);
+
+    refresh();
+});
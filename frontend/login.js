@@ -45,6 +45,7 @@ document.addEventListener('DOMContentLoaded', function () {
                 const data = await response.json();
 
                 localStorage.setItem('userToken', data.token);
+                localStorage.setItem('refreshToken', data.refresh_token);
                 localStorage.setItem('userEmail', email);
 
                 // Показываем сообщение
@@ -1,5 +1,5 @@
-document.addEventListener("DOMContentLoaded", () => {
-    const { API_BASE, showError, showSuccess } = window.AppCommon;
+document.addEventListener("DOMContentLoaded", async () => {
+    const { API_BASE, authFetch, showError, showSuccess } = window.AppCommon;
 
     const urlParams = new URLSearchParams(window.location.search);
     const token = urlParams.get("token");
@@ -22,37 +22,81 @@ document.addEventListener("DOMContentLoaded", () => {
         return;
     }
 
-    // Если токена нет — показываем сообщение
-    if (!token) {
-        document.body.innerHTML = `
-            <div class="container mt-5">
-                <div class="card shadow-sm">
-                    <div class="card-body text-center">
-                        <h4 class="text-danger mb-3">Токен не найден</h4>
-                        <p class="text-muted">
-                            Страница доступна только при переходе через QR-код.
-                        </p>
-                        <a href="dashboard.html" class="btn btn-primary">Перейти в личный кабинет</a>
+    // Без токена в ссылке отзыв оставляют по PIN-коду с чека — поле ввода показывается в форме
+    const manualEntry = !token;
+
+    // Коды с планшета (K-…) меняются каждую минуту и не хранятся в БД — их проверяет только отправка
+    const isKioskCode = !manualEntry && token.toUpperCase().startsWith("K-");
+
+    // Предпроверка токена — до авторизации, чтобы не отправлять на вход по негодному QR
+    if (!manualEntry && !isKioskCode) {
+        try {
+            const res = await fetch(`${API_BASE}/tokens/${encodeURIComponent(token)}`);
+            const info = await res.json().catch(() => ({}));
+
+            let problem = "";
+            if (!res.ok) {
+                problem = "QR-код не распознан.";
+            } else if (info.place_id !== placeId) {
+                problem = "QR-код относится к другому заведению.";
+            } else if (info.revoked) {
+                problem = "QR-код отозван заведением.";
+            } else if (info.used) {
+                problem = "По этому QR-коду уже оставлен отзыв.";
+            } else if (info.expired) {
+                problem = "Срок действия QR-кода истёк.";
+            }
+
+            if (problem) {
+                document.body.innerHTML = `
+                <div class="container mt-5">
+                    <div class="card shadow-sm">
+                        <div class="card-body text-center">
+                            <h4 class="text-danger mb-3">Отзыв недоступен</h4>
+                            <p class="text-muted">
+                                ${problem}<br>
+                                Попросите у продавца новый QR-код.
+                            </p>
+                            <a href="dashboard.html" class="btn btn-primary">Перейти в личный кабинет</a>
+                        </div>
                     </div>
-                </div>
-            </div>
-        `;
-        return;
+                </div>`;
+                return;
+            }
+        } catch {
+            // Сеть недоступна — не блокируем форму, сервер проверит токен при отправке
+        }
     }
 
     // Проверка авторизации
-    const userToken = localStorage.getItem("userToken");
-    if (!userToken) {
+    if (!localStorage.getItem("userToken")) {
         window.location.href = `login.html?redirect=${encodeURIComponent(window.location.href)}`;
         return;
     }
 
+    // Переход по короткой ссылке: сообщаем, что QR-код открыл вошедший пользователь (для воронки заведения)
+    const scanId = urlParams.get("scan");
+    const scanKey = urlParams.get("scan_key");
+    if (scanId && scanKey) {
+        authFetch(`${API_BASE}/scans/${encodeURIComponent(scanId)}/open`, {
+            method: "POST",
+            headers: { "Content-Type": "application/json" },
+            body: JSON.stringify({ key: scanKey })
+        }).catch(() => {});
+    }
+
     let selectedRating = 5;
     let isSubmitting = false;
 
     const ratingStars = document.getElementById("ratingStars");
     const submitBtn = document.getElementById("submitBtn");
     const formMessage = document.getElementById("formMessage");
+    const pinInput = document.getElementById("pinInput");
+    const photoInput = document.getElementById("photoInput");
+
+    if (manualEntry) {
+        document.getElementById("pinBlock").classList.remove("d-none");
+    }
 
     // Звёздочки
     ratingStars.addEventListener("click", (e) => {
@@ -78,6 +122,13 @@ document.addEventListener("DOMContentLoaded", () => {
 
     async function submitReview() {
         if (isSubmitting) return;
+
+        const reviewToken = manualEntry ? pinInput.value.trim() : token;
+        if (!reviewToken) {
+            showError("Введите код с чека.", formMessage);
+            return;
+        }
+
         isSubmitting = true;
 
         submitBtn.disabled = true;
@@ -85,15 +136,16 @@ document.addEventListener("DOMContentLoaded", () => {
             `<div class="spinner-border spinner-border-sm me-2"></div>Отправка...`;
         formMessage.innerHTML = "";
 
+        let canRetry = manualEntry;
+
         try {
-            const res = await fetch(`${API_BASE}/reviews`, {
+            const res = await authFetch(`${API_BASE}/reviews`, {
                 method: "POST",
                 headers: {
-                    "Authorization": "Bearer " + userToken,
                     "Content-Type": "application/json"
                 },
                 body: JSON.stringify({
-                    token: token,
+                    token: reviewToken,
                     place_id: placeId,
                     rating: selectedRating,
                     content: document.getElementById("reviewContent").value
@@ -119,10 +171,37 @@ document.addEventListener("DOMContentLoaded", () => {
                     return;
                 }
 
+                if (isKioskCode && data.error === "token expired") {
+                    throw new Error("Код на планшете уже сменился. Отсканируйте QR-код ещё раз.");
+                }
+                if (data.error === "kiosk code already used") {
+                    throw new Error("Вы уже отправили отзыв по этому коду.");
+                }
+                if (manualEntry && data.error === "invalid token") {
+                    throw new Error("Код не найден. Проверьте цифры на чеке.");
+                }
+                if (data.error === "too many pin attempts") {
+                    throw new Error("Слишком много неверных кодов. Попробуйте позже.");
+                }
+                if (data.error === "review content rejected") {
+                    canRetry = true;
+                    throw new Error("Текст отзыва не прошёл проверку: уберите нецензурные слова и сократите до 500 символов.");
+                }
+
                 throw new Error(data.error || "Ошибка отправки");
             }
 
-            showSuccess("Спасибо! Ваш отзыв отправлен.", formMessage);
+            // Фото прикрепляются к уже созданному отзыву: их ошибка не отменяет отзыв
+            const photosSaved = await uploadPhotos(data.id);
+
+            // При премодерации отзыв появится после проверки, баллы начислятся тогда же
+            let message = data.status === "pending"
+                ? "Спасибо! Отзыв отправлен на модерацию и появится после проверки."
+                : "Спасибо! Ваш отзыв отправлен.";
+            if (!photosSaved) {
+                message += " Фото загрузить не удалось.";
+            }
+            showSuccess(message, formMessage);
             submitBtn.style.display = "none";
 
             setTimeout(() => {
@@ -131,11 +210,36 @@ document.addEventListener("DOMContentLoaded", () => {
 
         } catch (err) {
             showError(err.message, formMessage);
+
+            // Код с чека и текст отзыва можно исправить и отправить снова
+            if (canRetry) {
+                submitBtn.disabled = false;
+                submitBtn.innerHTML = "Отправить";
+            }
         } finally {
             isSubmitting = false;
         }
     }
 
+    async function uploadPhotos(reviewId) {
+        if (!photoInput.files.length) return true;
+
+        const form = new FormData();
+        for (const file of photoInput.files) {
+            form.append("photos", file);
+        }
+
+        try {
+            const res = await authFetch(`${API_BASE}/reviews/${encodeURIComponent(reviewId)}/photos`, {
+                method: "POST",
+                body: form
+            });
+            return res.ok;
+        } catch {
+            return false;
+        }
+    }
+
     async function safeParseJSON(res) {
         try {
             return await res.json();